@@ -0,0 +1,147 @@
+package jsjson
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// -------------------- Anonymization --------------------
+
+// AnonymizeOptions controls Anonymize
+type AnonymizeOptions struct {
+	// Seed selects the fake values and is required. The same seed always
+	// gives the same output for the same input. Keep it secret: anyone who
+	// knows it can recompute the fakes for candidate values and so recover
+	// originals from small domains such as ages or zip codes.
+	Seed string
+	// Keep lists path patterns, as in Pick, of values left unchanged, such
+	// as enum-like fields whose values are not sensitive ("type",
+	// "**.status"). A matched object or array is kept whole.
+	Keep []string
+}
+
+// Anonymize returns a copy of the value with every string and number
+// replaced by a fake value of the same shape, so production payloads can be
+// turned into shareable test fixtures:
+//
+//	fixture := payload.Anonymize(jsjson.AnonymizeOptions{Seed: "fixtures-v1", Keep: []string{"**.type"}})
+//
+// The structure, keys, booleans and nulls are kept. In strings, letters are
+// replaced by letters of the same case and digits by digits, and everything
+// else is kept, so "ann.lee@example.com" may become "mxw.rkl@svlhxfa.mmq".
+// In numbers, non-zero digits are replaced by non-zero digits, keeping the
+// magnitude, sign and number of decimals. Replacements are deterministic:
+// equal values get equal fakes throughout a document and across documents
+// anonymized with the same seed, so IDs still join up. An empty seed is an
+// error.
+func (j JSONValue) Anonymize(opts AnonymizeOptions) JSONValue {
+	if j.err != nil {
+		return j
+	}
+	if opts.Seed == "" {
+		return JSONValue{err: &JSONError{Op: "Anonymize", Err: errors.New("a non-empty Seed is required")}}
+	}
+	data, err := normalize(j.data)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "Anonymize", Err: err}}
+	}
+	a := anonymizer{seed: []byte(opts.Seed), keep: splitPatterns(opts.Keep)}
+	return JSONValue{data: a.tree(data, nil)}
+}
+
+type anonymizer struct {
+	seed []byte
+	keep [][]string
+}
+
+// tree anonymizes v in place and returns it
+func (a *anonymizer) tree(v interface{}, keys []string) interface{} {
+	if len(keys) > 0 && matchAny(a.keep, keys) {
+		return v
+	}
+	switch c := v.(type) {
+	case map[string]interface{}:
+		for k, child := range c {
+			c[k] = a.tree(child, append(keys, k))
+		}
+	case []interface{}:
+		for i, child := range c {
+			c[i] = a.tree(child, append(keys, strconv.Itoa(i)))
+		}
+	case string:
+		return a.text(c)
+	case json.Number:
+		return json.Number(a.number(string(c)))
+	case float64:
+		if f, err := strconv.ParseFloat(a.number(strconv.FormatFloat(c, 'g', -1, 64)), 64); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+// text replaces the letters and digits of s
+func (a *anonymizer) text(s string) string {
+	r := a.stream("s", s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, c := range s {
+		switch {
+		case unicode.IsUpper(c):
+			b.WriteByte('A' + r.next()%26)
+		case unicode.IsLetter(c):
+			b.WriteByte('a' + r.next()%26)
+		case c >= '0' && c <= '9':
+			b.WriteByte('0' + r.next()%10)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// number replaces the non-zero mantissa digits of the number text s
+func (a *anonymizer) number(s string) string {
+	mantissa, exp := s, ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exp = s[:i], s[i:]
+	}
+	r := a.stream("n", s)
+	out := []byte(mantissa)
+	for i, c := range out {
+		if c >= '1' && c <= '9' {
+			out[i] = '1' + r.next()%9
+		}
+	}
+	return string(out) + exp
+}
+
+// stream returns the pseudo-random bytes for one value
+func (a *anonymizer) stream(kind, s string) *anonStream {
+	mac := hmac.New(sha256.New, a.seed)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(s))
+	return &anonStream{block: mac.Sum(nil)}
+}
+
+// anonStream extends a hash into as many bytes as a value needs
+type anonStream struct {
+	block []byte
+	pos   int
+}
+
+func (r *anonStream) next() byte {
+	if r.pos == len(r.block) {
+		sum := sha256.Sum256(r.block)
+		r.block, r.pos = sum[:], 0
+	}
+	b := r.block[r.pos]
+	r.pos++
+	return b
+}
@@ -0,0 +1,111 @@
+package jsjson_test
+
+import (
+	"reflect"
+	"testing"
+	"unicode"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestAnonymize(t *testing.T) {
+	input := `{
+		"id": "usr_8f3K2",
+		"email": "ann.lee@example.com",
+		"type": "customer",
+		"age": 42,
+		"balance": -1250.75,
+		"active": true,
+		"manager": null,
+		"orders": [{"id": "ord_1", "user": "usr_8f3K2", "total": 100}]
+	}`
+	jv := JSON.Parse(input)
+	opts := JSON.AnonymizeOptions{Seed: "s1", Keep: []string{"type"}}
+	got := jv.Anonymize(opts)
+	if err := got.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	sameShape := func(a, b string) bool {
+		ra, rb := []rune(a), []rune(b)
+		if len(ra) != len(rb) {
+			return false
+		}
+		for i := range ra {
+			switch {
+			case unicode.IsUpper(ra[i]):
+				if !unicode.IsUpper(rb[i]) {
+					return false
+				}
+			case unicode.IsLetter(ra[i]):
+				if !unicode.IsLower(rb[i]) {
+					return false
+				}
+			case unicode.IsDigit(ra[i]):
+				if !unicode.IsDigit(rb[i]) {
+					return false
+				}
+			default:
+				if ra[i] != rb[i] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	t.Run("strings keep their shape", func(t *testing.T) {
+		for _, key := range []string{"id", "email"} {
+			orig, fake := jv.Get(key).StringOr(""), got.Get(key).StringOr("")
+			if orig == fake || !sameShape(orig, fake) {
+				t.Errorf("%s: %q became %q", key, orig, fake)
+			}
+		}
+	})
+
+	t.Run("numbers keep magnitude and sign", func(t *testing.T) {
+		age := got.Get("age").Float64Or(0)
+		if age == 42 || age < 10 || age >= 100 || age != float64(int(age)) {
+			t.Errorf("Unexpected age %v", age)
+		}
+		balance := got.Get("balance").Float64Or(0)
+		if balance == -1250.75 || balance > -1000 || balance <= -10000 {
+			t.Errorf("Unexpected balance %v", balance)
+		}
+		if total := got.Get("orders", 0, "total").Float64Or(0); total < 100 || total >= 1000 || int(total)%100 != 0 {
+			t.Errorf("Expected zeros to be kept, got %v", total)
+		}
+	})
+
+	t.Run("kept values and non-text types", func(t *testing.T) {
+		if got.Get("type").StringOr("") != "customer" {
+			t.Error("Expected type to be kept")
+		}
+		if !got.Get("active").BoolOr(false) || !got.Get("manager").IsNull() {
+			t.Error("Expected booleans and nulls to be kept")
+		}
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		if got.Get("id").StringOr("") != got.Get("orders", 0, "user").StringOr("-") {
+			t.Error("Expected equal values to get equal fakes")
+		}
+		if !reflect.DeepEqual(got.Raw(), jv.Anonymize(opts).Raw()) {
+			t.Error("Expected the same output for the same seed")
+		}
+		other := jv.Anonymize(JSON.AnonymizeOptions{Seed: "s2"})
+		if other.Get("email").StringOr("") == got.Get("email").StringOr("") {
+			t.Error("Expected a different seed to give different fakes")
+		}
+	})
+
+	t.Run("empty seed", func(t *testing.T) {
+		if jv.Anonymize(JSON.AnonymizeOptions{}).IsValid() {
+			t.Error("Expected an error without a seed")
+		}
+	})
+
+	if !reflect.DeepEqual(jv.Raw(), JSON.Parse(input).Raw()) {
+		t.Error("Expected the original to be unchanged")
+	}
+}
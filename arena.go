@@ -0,0 +1,177 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// -------------------- Arena Allocation --------------------
+//
+// Go maps cannot be carved out of a slab, but most of the small allocations
+// behind a parsed tree can: string bytes, the boxes holding float64, string
+// and json.Number values inside interface{}, array backing storage and the
+// slice headers boxing arrays inside interface{}. In
+// arena mode those come from a few large chunks that are recycled through a
+// pool when the document is released, and maps are created at their exact
+// size instead of growing.
+
+const (
+	minSlabChunk = 256
+	maxSlabChunk = 16 << 10
+)
+
+// slab hands out slices of T carved from larger chunks
+type slab[T any] struct {
+	chunk []T
+}
+
+// alloc returns a slice of n zero values. Requests larger than a chunk get
+// their own allocation.
+func (s *slab[T]) alloc(n int) []T {
+	if n == 0 {
+		return []T{}
+	}
+	if n > maxSlabChunk/4 {
+		return make([]T, n)
+	}
+	if cap(s.chunk)-len(s.chunk) < n {
+		size := min(max(2*cap(s.chunk), minSlabChunk), maxSlabChunk)
+		s.chunk = make([]T, 0, size)
+	}
+	start := len(s.chunk)
+	s.chunk = s.chunk[:start+n]
+	return s.chunk[start : start+n : start+n]
+}
+
+// reset makes the current chunk available again, dropping its references
+func (s *slab[T]) reset() {
+	clear(s.chunk)
+	s.chunk = s.chunk[:0]
+}
+
+// arena owns the chunks backing one parsed document
+type arena struct {
+	gen    atomic.Uint64
+	bytes  slab[byte]
+	floats slab[float64]
+	strs   slab[string]
+	ifaces slab[interface{}]
+	arrays slab[[]interface{}]
+}
+
+var arenaPool = sync.Pool{
+	New: func() interface{} { return new(arena) },
+}
+
+// arenaHandle ties a root JSONValue to one use of an arena, so a second
+// Release (or a Release after the arena was reused) is a no-op
+type arenaHandle struct {
+	a   *arena
+	gen uint64
+}
+
+// WithArena parses the document into an arena: strings, scalar boxes and
+// array storage are allocated from a few recycled chunks instead of
+// individually, which cuts GC work for high-throughput ingestion. Call
+// Release on the returned value once it is no longer needed. Parse switches
+// to the built-in parser in this mode, regardless of SetCodec.
+func WithArena() ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.useArena = true })
+}
+
+// Release returns the memory of a document parsed with WithArena to the pool.
+// After Release the value, every value obtained from it and every string read
+// from it must no longer be used: their memory is reused by later parses.
+// Release is a no-op for values not parsed in arena mode, for values
+// obtained through Get, and when called more than once.
+func (j JSONValue) Release() {
+	h := j.arena
+	if h == nil || !h.a.gen.CompareAndSwap(h.gen, h.gen+1) {
+		return
+	}
+	h.a.bytes.reset()
+	h.a.floats.reset()
+	h.a.strs.reset()
+	h.a.ifaces.reset()
+	h.a.arrays.reset()
+	arenaPool.Put(h.a)
+}
+
+// getArena takes an arena from the pool
+func getArena() *arena {
+	return arenaPool.Get().(*arena)
+}
+
+// handle returns the release handle for the arena's current use
+func (a *arena) handle() *arenaHandle {
+	if a == nil {
+		return nil
+	}
+	return &arenaHandle{a: a, gen: a.gen.Load()}
+}
+
+// discard returns an arena whose parse failed to the pool
+func (a *arena) discard() {
+	if a != nil {
+		JSONValue{arena: a.handle()}.Release()
+	}
+}
+
+// copyString copies b into the arena
+func (a *arena) copyString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	dst := a.bytes.alloc(len(b))
+	copy(dst, b)
+	return unsafeString(dst)
+}
+
+// eface is the runtime layout of an empty interface
+type eface struct {
+	typ  unsafe.Pointer
+	data unsafe.Pointer
+}
+
+func typeOf(v interface{}) unsafe.Pointer {
+	return (*eface)(unsafe.Pointer(&v)).typ
+}
+
+var (
+	float64Type = typeOf(float64(0))
+	stringType  = typeOf("")
+	numberType  = typeOf(json.Number(""))
+	arrayType   = typeOf([]interface{}(nil))
+)
+
+// box builds an interface{} of the given type whose data lives in the arena
+func box(typ, data unsafe.Pointer) (v interface{}) {
+	e := (*eface)(unsafe.Pointer(&v))
+	e.typ, e.data = typ, data
+	return v
+}
+
+// boxFloat returns f as an interface{} without a separate allocation
+func (a *arena) boxFloat(f float64) interface{} {
+	p := &a.floats.alloc(1)[0]
+	*p = f
+	return box(float64Type, unsafe.Pointer(p))
+}
+
+// boxString returns s (as a string or json.Number) in an interface{}
+// without a separate allocation
+func (a *arena) boxString(typ unsafe.Pointer, s string) interface{} {
+	p := &a.strs.alloc(1)[0]
+	*p = s
+	return box(typ, unsafe.Pointer(p))
+}
+
+// boxArray returns arr in an interface{} without a separate allocation for
+// its slice header
+func (a *arena) boxArray(arr []interface{}) interface{} {
+	p := &a.arrays.alloc(1)[0]
+	*p = arr
+	return box(arrayType, unsafe.Pointer(p))
+}
@@ -0,0 +1,95 @@
+package jsjson_test
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestArenaMatchesParse(t *testing.T) {
+	for _, input := range parserCorpus {
+		t.Run(input, func(t *testing.T) {
+			want := JSON.Parse(input)
+			got := JSON.Parse(input, JSON.WithArena())
+			defer got.Release()
+			if got.Error() != nil || !reflect.DeepEqual(got.Raw(), want.Raw()) {
+				t.Errorf("Expected %#v, got %#v (err: %v)", want.Raw(), got.Raw(), got.Error())
+			}
+
+			wantNum := JSON.Parse(input, JSON.ParseWithNumbers())
+			gotNum := JSON.ParseNoCopy([]byte(input), JSON.WithArena(), JSON.ParseWithNumbers())
+			defer gotNum.Release()
+			if !reflect.DeepEqual(gotNum.Raw(), wantNum.Raw()) {
+				t.Errorf("With numbers: expected %#v, got %#v", wantNum.Raw(), gotNum.Raw())
+			}
+		})
+	}
+}
+
+func TestArenaSurvivesGC(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < 5000; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"id":%d,"name":"user-%d","score":%d.5}`, i, i, i)
+	}
+	b.WriteString("]")
+
+	doc := JSON.Parse(b.String(), JSON.WithArena())
+	defer doc.Release()
+	runtime.GC()
+
+	for _, i := range []int{0, 1234, 4999} {
+		item := doc.Get(i)
+		if item.Get("id").IntOr(-1) != i || item.Get("name").StringOr("") != fmt.Sprintf("user-%d", i) ||
+			item.Get("score").Float64Or(0) != float64(i)+0.5 {
+			t.Errorf("Unexpected item %d: %v", i, item.Raw())
+		}
+	}
+}
+
+func TestArenaRelease(t *testing.T) {
+	doc := JSON.Parse(`{"a":[1,2,3],"b":"text"}`, JSON.WithArena())
+	if doc.Error() != nil {
+		t.Fatal(doc.Error())
+	}
+	doc.Release()
+	// Releasing twice, releasing a sub-value or a non-arena value is a no-op
+	doc.Release()
+	doc.Get("a").Release()
+	JSON.Parse(`{}`).Release()
+
+	// Failed parses return their arena and report the error
+	if err := JSON.Parse(`{"a":`, JSON.WithArena()).Error(); err == nil {
+		t.Error("Expected syntax error")
+	}
+}
+
+func TestArenaReducesAllocations(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < 200; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `[%d, %d.25, "s%d", true]`, i, i, i)
+	}
+	b.WriteString("]")
+	input := []byte(b.String())
+
+	plain := testing.AllocsPerRun(20, func() {
+		JSON.ParseNoCopy(input)
+	})
+	arena := testing.AllocsPerRun(20, func() {
+		JSON.ParseNoCopy(input, JSON.WithArena()).Release()
+	})
+	if arena*4 > plain {
+		t.Errorf("Expected arena mode to allocate far less: %.0f allocs vs %.0f", arena, plain)
+	}
+}
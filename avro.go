@@ -0,0 +1,465 @@
+package jsjson
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// -------------------- Avro --------------------
+
+// avroType is a compiled Avro schema node
+type avroType struct {
+	kind    string // primitive name, or record, enum, array, map, union, fixed
+	logical string
+	fields  []avroField // record
+	symbols []string    // enum
+	items   *avroType   // array items, map values
+	union   []*avroType
+	size    int // fixed
+	scale   int // decimal
+}
+
+type avroField struct {
+	name string
+	typ  *avroType
+}
+
+// ParseAvro decodes one Avro binary-encoded datum written with schema, such
+// as the value of a Kafka record, into a JSONValue. The schema is the usual
+// Avro JSON schema document. Records and maps become objects, enums their
+// symbol, bytes and fixed standard base64, and a union its selected branch
+// without a type wrapper. Longs beyond 2^53 stay exact. The logical types
+// decimal, date and timestamp-millis/-micros become values the Decimal and
+// Time accessors read. Confluent-framed messages start with a 5-byte header
+// that must be stripped first. WithMaxArrayElements bounds the length of
+// arrays; other options are ignored.
+func ParseAvro(data []byte, schema JSONValue, opts ...ParseOption) JSONValue {
+	if schema.err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseAvro", Err: schema.err}}
+	}
+	c := avroCompiler{named: make(map[string]*avroType)}
+	t, err := c.compile(schema.data, "")
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseAvro", Err: fmt.Errorf("invalid Avro schema: %w", err)}}
+	}
+
+	o := newParseOptions(opts)
+	d := avroDecoder{buf: data, maxItems: o.maxArrayElements}
+	v, err := d.value(t, 0)
+	if err == nil && d.pos != len(d.buf) {
+		err = fmt.Errorf("%w: %d trailing bytes", ErrSyntax, len(d.buf)-d.pos)
+	}
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseAvro", Err: err}}
+	}
+	return JSONValue{data: v}
+}
+
+// -------------------- Schema --------------------
+
+type avroCompiler struct {
+	named map[string]*avroType
+}
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+func (c *avroCompiler) compile(s interface{}, namespace string) (*avroType, error) {
+	switch v := s.(type) {
+	case string:
+		if avroPrimitives[v] {
+			return &avroType{kind: v}, nil
+		}
+		if t := c.lookup(v, namespace); t != nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("unknown type %q", v)
+	case []interface{}:
+		t := &avroType{kind: "union"}
+		for _, branch := range v {
+			bt, err := c.compile(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+			t.union = append(t.union, bt)
+		}
+		return t, nil
+	case map[string]interface{}:
+		return c.compileObject(v, namespace)
+	}
+	return nil, fmt.Errorf("%w: schema node must be a string, array or object, got %T", ErrTypeMismatch, s)
+}
+
+func (c *avroCompiler) compileObject(s map[string]interface{}, namespace string) (*avroType, error) {
+	kind, _ := s["type"].(string)
+	logical, _ := s["logicalType"].(string)
+
+	switch kind {
+	case "record", "error", "enum", "fixed":
+		name, _ := s["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("%s without a name", kind)
+		}
+		if ns, ok := s["namespace"].(string); ok {
+			namespace = ns
+		}
+		fullname := name
+		if !strings.Contains(name, ".") && namespace != "" {
+			fullname = namespace + "." + name
+		}
+		if i := strings.LastIndexByte(fullname, '.'); i >= 0 {
+			namespace = fullname[:i]
+		}
+		if c.named[fullname] != nil {
+			return nil, fmt.Errorf("type %q defined twice", fullname)
+		}
+
+		t := &avroType{kind: kind, logical: logical}
+		if kind == "error" {
+			t.kind = "record"
+		}
+		// Register before compiling fields so records can refer to themselves
+		c.named[fullname] = t
+
+		switch kind {
+		case "record", "error":
+			fields, ok := s["fields"].([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("record %q without fields", fullname)
+			}
+			for _, f := range fields {
+				fo, _ := f.(map[string]interface{})
+				fname, _ := fo["name"].(string)
+				if fname == "" {
+					return nil, fmt.Errorf("record %q has a field without a name", fullname)
+				}
+				ft, err := c.compile(fo["type"], namespace)
+				if err != nil {
+					return nil, fmt.Errorf("field %s.%s: %w", fullname, fname, err)
+				}
+				t.fields = append(t.fields, avroField{name: fname, typ: ft})
+			}
+		case "enum":
+			symbols, _ := s["symbols"].([]interface{})
+			for _, sym := range symbols {
+				str, ok := sym.(string)
+				if !ok {
+					return nil, fmt.Errorf("enum %q has a non-string symbol", fullname)
+				}
+				t.symbols = append(t.symbols, str)
+			}
+		case "fixed":
+			size, ok := numberValue(s["size"])
+			if !ok || size < 0 || size != math.Trunc(size) {
+				return nil, fmt.Errorf("fixed %q without a valid size", fullname)
+			}
+			t.size = int(size)
+			t.scale = avroScale(s)
+		}
+		return t, nil
+	case "array", "map":
+		key := "items"
+		if kind == "map" {
+			key = "values"
+		}
+		items, err := c.compile(s[key], namespace)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", kind, key, err)
+		}
+		return &avroType{kind: kind, items: items}, nil
+	case "":
+		// {"type": {...}} wraps a full schema
+		if nested, ok := s["type"]; ok {
+			return c.compile(nested, namespace)
+		}
+		return nil, fmt.Errorf("schema object without a type")
+	}
+
+	if !avroPrimitives[kind] {
+		if t := c.lookup(kind, namespace); t != nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("unknown type %q", kind)
+	}
+	return &avroType{kind: kind, logical: logical, scale: avroScale(s)}, nil
+}
+
+// lookup resolves a named type reference relative to namespace
+func (c *avroCompiler) lookup(name, namespace string) *avroType {
+	if !strings.Contains(name, ".") && namespace != "" {
+		if t := c.named[namespace+"."+name]; t != nil {
+			return t
+		}
+	}
+	return c.named[name]
+}
+
+func avroScale(s map[string]interface{}) int {
+	scale, _ := numberValue(s["scale"])
+	return int(scale)
+}
+
+// -------------------- Decoding --------------------
+
+type avroDecoder struct {
+	buf      []byte
+	pos      int
+	maxItems int // array length limit, 0 for none
+}
+
+// avroMaxDepth bounds nesting of recursive schemas on untrusted input
+const avroMaxDepth = 10000
+
+// avroMaxEmptyItems bounds arrays of items encoded in zero bytes, such as
+// nulls, whose length the size of the input does not bound
+const avroMaxEmptyItems = 1 << 20
+
+func (d *avroDecoder) truncated() error {
+	return fmt.Errorf("%w: truncated Avro data at offset %d", ErrSyntax, d.pos)
+}
+
+func (d *avroDecoder) long() (int64, error) {
+	u, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		if n == 0 {
+			return 0, d.truncated()
+		}
+		return 0, fmt.Errorf("%w: invalid varint at offset %d", ErrSyntax, d.pos)
+	}
+	d.pos += n
+	// Zig-zag decoding
+	return int64(u>>1) ^ -int64(u&1), nil
+}
+
+func (d *avroDecoder) take(n int64) ([]byte, error) {
+	if n < 0 || int64(len(d.buf)-d.pos) < n {
+		return nil, d.truncated()
+	}
+	b := d.buf[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+func (d *avroDecoder) bytes() ([]byte, error) {
+	n, err := d.long()
+	if err != nil {
+		return nil, err
+	}
+	return d.take(n)
+}
+
+// blockCount reads the item count of the next array or map block
+func (d *avroDecoder) blockCount() (int64, error) {
+	n, err := d.long()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		// A negative count is followed by the block's size in bytes
+		if _, err := d.long(); err != nil {
+			return 0, err
+		}
+		n = -n
+	}
+	return n, nil
+}
+
+func (d *avroDecoder) value(t *avroType, depth int) (interface{}, error) {
+	if depth > avroMaxDepth {
+		return nil, fmt.Errorf("%w: nesting exceeds %d levels", ErrLimitExceeded, avroMaxDepth)
+	}
+
+	switch t.kind {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := d.take(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		n, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == "int" && (n < math.MinInt32 || n > math.MaxInt32) {
+			return nil, fmt.Errorf("%w: int out of range at offset %d", ErrSyntax, d.pos)
+		}
+		return avroInteger(n, t.logical), nil
+	case "float":
+		b, err := d.take(4)
+		if err != nil {
+			return nil, err
+		}
+		return floatValue(float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))), nil
+	case "double":
+		b, err := d.take(8)
+		if err != nil {
+			return nil, err
+		}
+		return floatValue(math.Float64frombits(binary.LittleEndian.Uint64(b))), nil
+	case "bytes", "fixed":
+		var b []byte
+		var err error
+		if t.kind == "fixed" {
+			b, err = d.take(int64(t.size))
+		} else {
+			b, err = d.bytes()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if t.logical == "decimal" {
+			coef := avroUnscaled(b)
+			return json.Number(Decimal{Coefficient: coef, Exponent: int32(-t.scale)}.String()), nil
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	case "string":
+		b, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case "record":
+		obj := make(map[string]interface{}, len(t.fields))
+		for _, f := range t.fields {
+			v, err := d.value(f.typ, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			obj[f.name] = v
+		}
+		return obj, nil
+	case "enum":
+		n, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 || n >= int64(len(t.symbols)) {
+			return nil, fmt.Errorf("%w: enum index %d out of range", ErrSyntax, n)
+		}
+		return t.symbols[n], nil
+	case "array":
+		items := make([]interface{}, 0)
+		empty := avroZeroWidth(t.items, map[*avroType]bool{})
+		for {
+			n, err := d.blockCount()
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return items, nil
+			}
+			total := int64(len(items))
+			switch {
+			case d.maxItems > 0 && n > int64(d.maxItems)-total:
+				return nil, fmt.Errorf("%w: array has more than %d elements", ErrLimitExceeded, d.maxItems)
+			case empty && n > avroMaxEmptyItems-total:
+				return nil, fmt.Errorf("%w: array of empty items has more than %d elements", ErrLimitExceeded, avroMaxEmptyItems)
+			case !empty && n > int64(len(d.buf)-d.pos):
+				// Every item takes at least one byte
+				return nil, d.truncated()
+			}
+			for ; n > 0; n-- {
+				v, err := d.value(t.items, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, v)
+			}
+		}
+	case "map":
+		obj := make(map[string]interface{})
+		for {
+			n, err := d.blockCount()
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return obj, nil
+			}
+			if n > int64(len(d.buf)-d.pos) {
+				// Every entry takes at least the byte of its key's length
+				return nil, d.truncated()
+			}
+			for ; n > 0; n-- {
+				k, err := d.bytes()
+				if err != nil {
+					return nil, err
+				}
+				v, err := d.value(t.items, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				obj[string(k)] = v
+			}
+		}
+	case "union":
+		n, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 || n >= int64(len(t.union)) {
+			return nil, fmt.Errorf("%w: union branch %d out of range", ErrSyntax, n)
+		}
+		return d.value(t.union[n], depth+1)
+	}
+	return nil, fmt.Errorf("%w: unsupported Avro type %q", ErrTypeMismatch, t.kind)
+}
+
+// avroZeroWidth reports whether values of t are encoded in zero bytes
+func avroZeroWidth(t *avroType, seen map[*avroType]bool) bool {
+	switch t.kind {
+	case "null":
+		return true
+	case "fixed":
+		return t.size == 0
+	case "record":
+		if seen[t] {
+			return false
+		}
+		seen[t] = true
+		for _, f := range t.fields {
+			if !avroZeroWidth(f.typ, seen) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// avroInteger converts an int or long, applying its logical type
+func avroInteger(n int64, logical string) interface{} {
+	switch logical {
+	case "date":
+		return time.Unix(n*86400, 0).UTC().Format("2006-01-02")
+	case "timestamp-millis", "local-timestamp-millis":
+		return time.UnixMilli(n).UTC().Format(time.RFC3339Nano)
+	case "timestamp-micros", "local-timestamp-micros":
+		return time.UnixMicro(n).UTC().Format(time.RFC3339Nano)
+	}
+	if n < -maxSafeInteger || n > maxSafeInteger {
+		return json.Number(strconv.FormatInt(n, 10))
+	}
+	return float64(n)
+}
+
+// avroUnscaled decodes a big-endian two's-complement integer
+func avroUnscaled(b []byte) *big.Int {
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	return n
+}
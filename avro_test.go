@@ -0,0 +1,166 @@
+package jsjson_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+const avroSchema = `{
+	"type": "record", "name": "Order", "namespace": "shop",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "customer", "type": "string"},
+		{"name": "paid", "type": "boolean"},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["NEW", "SHIPPED"]}},
+		{"name": "total", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}},
+		{"name": "placed", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "lines", "type": {"type": "array", "items": {
+			"type": "record", "name": "Line",
+			"fields": [{"name": "sku", "type": "string"}, {"name": "qty", "type": "int"}, {"name": "weight", "type": "float"}]
+		}}},
+		{"name": "attrs", "type": {"type": "map", "values": "string"}},
+		{"name": "note", "type": ["null", "string"]},
+		{"name": "hash", "type": {"type": "fixed", "name": "MD5", "size": 2}},
+		{"name": "parent", "type": ["null", "shop.Order"]}
+	]
+}`
+
+// avro appends Avro encodings of Go values: int64 as a zig-zag long, string
+// as length-prefixed bytes, []byte as raw bytes
+func avro(parts ...interface{}) []byte {
+	var b []byte
+	for _, p := range parts {
+		switch v := p.(type) {
+		case int:
+			b = binary.AppendUvarint(b, uint64(int64(v)<<1^int64(v)>>63))
+		case int64:
+			b = binary.AppendUvarint(b, uint64(v<<1^v>>63))
+		case string:
+			b = binary.AppendUvarint(b, uint64(len(v))<<1)
+			b = append(b, v...)
+		case []byte:
+			b = append(b, v...)
+		}
+	}
+	return b
+}
+
+func TestParseAvro(t *testing.T) {
+	weight := binary.LittleEndian.AppendUint32(nil, math.Float32bits(1.5))
+	line := func(sku string, qty int) []byte { return append(avro(sku, qty), weight...) }
+
+	data := avro(
+		int64(1)<<60, "Ada", []byte{1}, 1,
+		2, []byte{0x04, 0xe2}, // 1250 -> 12.50
+		int64(1705048200123),
+		-2, 16, line("A1", 2), line("B2", -1), 0, // one block of two lines, with its byte size
+		1, "gift", "yes", 0,
+		1, "leave at door",
+		[]byte{0xca, 0xfe},
+		1, int64(2), "Bob", []byte{0}, 0, 2, []byte{0xff, 0x38}, int64(0), 0, 0, 0, []byte{0, 0}, 0,
+	)
+
+	got := JSON.ParseAvro(data, JSON.Parse(avroSchema))
+	if got.Error() != nil {
+		t.Fatalf("ParseAvro failed: %v", got.Error())
+	}
+
+	if n, err := got.Get("id").Int64(); err != nil || n != 1<<60 {
+		t.Errorf("Expected 2^60, got %d (%v)", n, err)
+	}
+	if got.Get("customer").StringOr("") != "Ada" || !got.Get("paid").BoolOr(false) || got.Get("status").StringOr("") != "SHIPPED" {
+		t.Errorf("Unexpected scalars %v", got.Raw())
+	}
+	if d, err := got.Get("total").Decimal(); err != nil || d.String() != "12.50" {
+		t.Errorf("Unexpected decimal %v (%v)", d, err)
+	}
+	if placed, err := got.Get("placed").Time(); err != nil || placed.UnixMilli() != 1705048200123 {
+		t.Errorf("Unexpected timestamp %v (%v)", placed, err)
+	}
+	if got.Get("lines", 1, "sku").StringOr("") != "B2" || got.Get("lines", 1, "qty").IntOr(0) != -1 || got.Get("lines", 0, "weight").Float64Or(0) != 1.5 {
+		t.Errorf("Unexpected lines %v", got.Get("lines").Raw())
+	}
+	if got.Get("attrs", "gift").StringOr("") != "yes" || got.Get("note").StringOr("") != "leave at door" {
+		t.Errorf("Unexpected map or union %v", got.Raw())
+	}
+	if b, _ := got.Get("hash").Bytes(); string(b) != "\xca\xfe" {
+		t.Errorf("Unexpected fixed %q", b)
+	}
+	if d, _ := got.Get("parent", "total").Decimal(); d.String() != "-2.00" || !got.Get("parent", "parent").IsNull() {
+		t.Errorf("Unexpected recursive record %v", got.Get("parent").Raw())
+	}
+}
+
+func TestParseAvroErrors(t *testing.T) {
+	schema := JSON.Parse(`{"type": "record", "name": "R", "fields": [{"name": "s", "type": "string"}, {"name": "e", "type": {"type": "enum", "name": "E", "symbols": ["A"]}}]}`)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		schema JSON.JSONValue
+		want   error
+	}{
+		{"truncated string", avro(5)[:1], schema, JSON.ErrSyntax},
+		{"enum index", avro("x", 3), schema, JSON.ErrSyntax},
+		{"trailing bytes", append(avro("x", 0), 0), schema, JSON.ErrSyntax},
+		{"unknown type", nil, JSON.Parse(`{"type": "record", "name": "R", "fields": [{"name": "a", "type": "Missing"}]}`), nil},
+		{"invalid schema", nil, JSON.Parse(`{bad`), JSON.ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JSON.ParseAvro(tt.data, tt.schema).Error()
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if got := JSON.ParseAvro(avro(-3), JSON.Parse(`"long"`)); got.IntOr(0) != -3 {
+		t.Errorf("Expected a primitive schema to decode, got %v", got.Raw())
+	}
+}
+
+func TestParseAvroBlockCounts(t *testing.T) {
+	nulls := JSON.Parse(`{"type": "array", "items": "null"}`)
+	longs := JSON.Parse(`{"type": "array", "items": "long"}`)
+	strMap := JSON.Parse(`{"type": "map", "values": "string"}`)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		schema JSON.JSONValue
+		opts   []JSON.ParseOption
+		want   error
+	}{
+		{"huge count of nulls", avro(1<<40, 0), nulls, nil, JSON.ErrLimitExceeded},
+		{"huge negative count of nulls", avro(-(1 << 40), 0, 0), nulls, nil, JSON.ErrLimitExceeded},
+		{"count beyond data", avro(1000, 1, 0), longs, nil, JSON.ErrSyntax},
+		{"map count beyond data", avro(1<<40, 0), strMap, nil, JSON.ErrSyntax},
+		{"max elements", avro(3, 1, 2, 3, 0), longs, []JSON.ParseOption{JSON.WithMaxArrayElements(2)}, JSON.ErrLimitExceeded},
+		{"max elements across blocks", avro(2, 1, 2, 1, 3, 0), longs, []JSON.ParseOption{JSON.WithMaxArrayElements(2)}, JSON.ErrLimitExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JSON.ParseAvro(tt.data, tt.schema, tt.opts...).Error()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	got := JSON.ParseAvro(avro(3, 0), nulls)
+	if arr, err := got.Array(); err != nil || len(arr) != 3 {
+		t.Errorf("Expected three nulls, got %v (%v)", got.Raw(), err)
+	}
+	got = JSON.ParseAvro(avro(2, 1, 2, 0), longs, JSON.WithMaxArrayElements(2))
+	if arr, err := got.Array(); err != nil || len(arr) != 2 {
+		t.Errorf("Expected two longs within the limit, got %v (%v)", got.Raw(), err)
+	}
+}
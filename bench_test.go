@@ -193,6 +193,14 @@ func BenchmarkGet_ArrayOfObjects(b *testing.B) {
 	}
 }
 
+func BenchmarkGet_CompiledPath(b *testing.B) {
+	path := CompilePath("users", 0, "preferences", "privacy", "public")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		path.Get(largeObj)
+	}
+}
+
 // ==================== TYPE CONVERSION BENCHMARKS ====================
 
 func BenchmarkString_Conversion(b *testing.B) {
@@ -702,4 +710,85 @@ func BenchmarkLibraryComparison_Scenario_Gjson(b *testing.B) {
 		_ = score
 		_ = active
 	}
-}
\ No newline at end of file
+}
+
+// ==================== PARALLEL DECODING BENCHMARKS ====================
+
+type benchSliceItem struct {
+	ID      int                    `json:"id"`
+	Name    string                 `json:"name"`
+	Email   string                 `json:"email"`
+	Score   float64                `json:"score"`
+	Tags    []string               `json:"tags"`
+	Details map[string]interface{} `json:"metadata"`
+}
+
+// benchArrayJSON is a large top-level array for the parallel decoding benchmarks
+var benchArrayJSON = func() []byte {
+	b := []byte("[")
+	for i := 0; i < 5000; i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, mediumJSON...)
+	}
+	return append(b, ']')
+}()
+
+func BenchmarkParseIntoSlice_Parallel(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var items []benchSliceItem
+		ParseIntoSlice(benchArrayJSON, &items)
+	}
+}
+
+func BenchmarkParseIntoSlice_StdLib(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var items []benchSliceItem
+		json.Unmarshal(benchArrayJSON, &items)
+	}
+}
+
+func BenchmarkParseParallel(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ParseParallel(benchArrayJSON)
+	}
+}
+
+func BenchmarkParseParallel_Sequential(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Parse(benchArrayJSON)
+	}
+}
+
+// ==================== STRUCT BINDING BENCHMARKS ====================
+
+func BenchmarkTo_Struct(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var item benchSliceItem
+		mediumObj.To(&item)
+	}
+}
+
+func BenchmarkParseInto_Struct(b *testing.B) {
+	data := []byte(mediumJSON)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var item benchSliceItem
+		ParseInto(data, &item)
+	}
+}
+
+func BenchmarkParseStruct_StdLib(b *testing.B) {
+	data := []byte(mediumJSON)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var item benchSliceItem
+		json.Unmarshal(data, &item)
+	}
+}
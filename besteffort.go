@@ -0,0 +1,143 @@
+package jsjson
+
+import "errors"
+
+// -------------------- Partial Documents --------------------
+
+// ParseBestEffort parses data and, if it is cut off or broken part way
+// through, returns the part before the problem instead of nothing. Open
+// arrays and objects are closed after their last complete member, so a
+// truncated log line or network body still yields what arrived:
+//
+//	jv, err := jsjson.ParseBestEffort([]byte(`{"events": [{"id": 1}, {"id": 2}, {"id"`))
+//	// jv is {"events": [{"id": 1}, {"id": 2}, {}]}
+//	// err is a *SyntaxError at line 1, column 41
+//
+// The error, a *SyntaxError matching ErrSyntax, says where parsing stopped;
+// it is nil when data is valid. A member is kept only when its value is
+// complete: a string must have its closing quote, and a number at the point
+// of the problem is dropped because more digits may have been cut off. An
+// array or object that was opened is kept, even if empty. When no
+// complete value precedes the problem, the returned value holds the error as
+// well. Errors other than syntax errors, such as exceeded limits, are not
+// recovered from. opts apply as in Parse.
+func ParseBestEffort(data []byte, opts ...ParseOption) (JSONValue, error) {
+	// Error offsets refer to the UTF-8 text
+	o := newParseOptions(opts)
+	data, err := prepareText(data, &o)
+	if err != nil {
+		err = &JSONError{Op: "ParseBestEffort", Err: err}
+		return JSONValue{err: err}, err
+	}
+	jv := parseSequential(data, opts)
+	if jv.err == nil {
+		return jv, nil
+	}
+	err = withOp("ParseBestEffort", jv.err)
+	var serr *SyntaxError
+	if !errors.As(err, &serr) {
+		return JSONValue{err: err}, err
+	}
+
+	prefix := completePrefix(data[:serr.Offset])
+	if prefix == nil {
+		return JSONValue{err: err}, err
+	}
+	partial := parseSequential(prefix, opts)
+	if partial.err != nil {
+		return JSONValue{err: err}, err
+	}
+	return partial, err
+}
+
+// completePrefix returns the longest prefix of data, which must be valid
+// JSON text as far as it goes, that ends after a complete value, with the
+// arrays and objects still open at that point closed. It returns nil when no
+// value is complete.
+func completePrefix(data []byte) []byte {
+	var (
+		open    []byte // '{' or '[' for each open container
+		wantKey []bool // per open container, whether the next string is a key
+		cut     = -1
+		closers []byte
+	)
+	mark := func(pos int) {
+		cut = pos
+		closers = closers[:0]
+		for i := len(open) - 1; i >= 0; i-- {
+			closers = append(closers, open[i]+2) // '{'+2 is '}', '['+2 is ']'
+		}
+	}
+
+	for i := 0; i < len(data); {
+		switch c := data[i]; c {
+		case ' ', '\t', '\n', '\r', ':':
+			i++
+		case '{', '[':
+			open = append(open, c)
+			wantKey = append(wantKey, c == '{')
+			i++
+			mark(i)
+		case '}', ']':
+			open, wantKey = open[:len(open)-1], wantKey[:len(wantKey)-1]
+			i++
+			mark(i)
+		case ',':
+			if len(open) > 0 && open[len(open)-1] == '{' {
+				wantKey[len(wantKey)-1] = true
+			}
+			i++
+		case '"':
+			end := stringEnd(data, i)
+			if end < 0 {
+				return finishPrefix(data, cut, closers)
+			}
+			i = end
+			if n := len(wantKey); n > 0 && wantKey[n-1] {
+				wantKey[n-1] = false
+			} else {
+				mark(i)
+			}
+		default:
+			// A number or literal is complete only if something follows it
+			end := i
+			for end < len(data) && isScalarByte(data[end]) {
+				end++
+			}
+			if end == len(data) {
+				return finishPrefix(data, cut, closers)
+			}
+			i = end
+			mark(i)
+		}
+	}
+	return finishPrefix(data, cut, closers)
+}
+
+func finishPrefix(data []byte, cut int, closers []byte) []byte {
+	if cut < 0 {
+		return nil
+	}
+	out := make([]byte, 0, cut+len(closers))
+	out = append(out, data[:cut]...)
+	return append(out, closers...)
+}
+
+// stringEnd returns the offset just past the closing quote of the string
+// starting at data[start], or -1 if it is not closed
+func stringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// isScalarByte reports whether c can be part of a number or literal
+func isScalarByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c == '-' || c == '+' || c == '.' || c == 'E'
+}
@@ -0,0 +1,65 @@
+package jsjson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestParseBestEffort(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // "" when nothing is recovered
+	}{
+		{"valid", `{"a": [1, 2]}`, `{"a": [1, 2]}`},
+		{"truncated in a key", `{"events": [{"id": 1}, {"id": 2}, {"id"`, `{"events": [{"id": 1}, {"id": 2}, {}]}`},
+		{"truncated after a colon", `{"a": 1, "b": `, `{"a": 1}`},
+		{"truncated in a string", `["done", "pend`, `["done"]`},
+		{"truncated number is dropped", `{"a": true, "n": 12`, `{"a": true}`},
+		{"escaped quote", `["a\"b", "c`, `["a\"b"]`},
+		{"nested", `{"a": {"b": [1, {"c": null}`, `{"a": {"b": [1, {"c": null}]}}`},
+		{"broken in the middle", `{"a": 1, "b": 2 "c": 3}`, `{"a": 1, "b": 2}`},
+		{"trailing data", `{"a": 1} x`, `{"a": 1}`},
+		{"top-level scalar", `42 43`, `42`},
+		{"nothing complete", `tru`, ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSON.ParseBestEffort([]byte(tt.input))
+			if tt.input == tt.want {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			} else if !errors.Is(err, JSON.ErrSyntax) {
+				t.Fatalf("Expected a syntax error, got %v", err)
+			}
+			if tt.want == "" {
+				if got.IsValid() {
+					t.Errorf("Expected no value, got %v", got.Raw())
+				}
+				return
+			}
+			if !got.IsValid() {
+				t.Fatalf("Expected a value, got %v", got.Error())
+			}
+			if !reflect.DeepEqual(got.Raw(), JSON.Parse(tt.want).Raw()) {
+				t.Errorf("Expected %s, got %v", tt.want, got.Raw())
+			}
+		})
+	}
+}
+
+func TestParseBestEffortPosition(t *testing.T) {
+	_, err := JSON.ParseBestEffort([]byte("[1,\n 2,\n 3"))
+	var serr *JSON.SyntaxError
+	if !errors.As(err, &serr) {
+		t.Fatalf("Expected a *SyntaxError, got %v", err)
+	}
+	if serr.Line != 3 || serr.Column != 3 {
+		t.Errorf("Expected line 3, column 3, got line %d, column %d", serr.Line, serr.Column)
+	}
+}
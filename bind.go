@@ -0,0 +1,802 @@
+package jsjson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// -------------------- Struct Binding --------------------
+//
+// Binding a decoded tree to a Go value used to mean encoding the tree and
+// decoding the bytes again. The binder walks the tree straight into the
+// destination instead, following a plan compiled once per Go type. Plans
+// follow encoding/json's rules (field tags, case-insensitive names, embedded
+// structs, Unmarshaler types); for anything they do not cover, and for every
+// type mismatch, the caller falls back to the round trip so results and
+// error messages stay exactly those of encoding/json.
+
+// errBindFallback tells the caller to redo the binding with the codec
+var errBindFallback = errors.New("jsjson: binding needs the codec")
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonNumberType      = reflect.TypeOf(json.Number(""))
+	jsonValueType       = reflect.TypeOf(JSONValue{})
+)
+
+// bindFunc stores a tree value into v, which is always addressable
+type bindFunc func(v reflect.Value, data interface{}) error
+
+// bindPlan is the compiled binding for one Go type. Plans reference each
+// other through pointers so recursive types can be compiled.
+type bindPlan struct {
+	bind bindFunc
+}
+
+var (
+	bindPlans   sync.Map // reflect.Type -> *bindPlan
+	bindPlansMu sync.Mutex
+)
+
+// bindValue stores data into v using the cached plan for v's type
+func bindValue(v reflect.Value, data interface{}) error {
+	return planFor(v.Type()).bind(v, data)
+}
+
+// planFor returns the plan for t, compiling it (and any types it refers to)
+// on first use
+func planFor(t reflect.Type) *bindPlan {
+	if p, ok := bindPlans.Load(t); ok {
+		return p.(*bindPlan)
+	}
+
+	bindPlansMu.Lock()
+	defer bindPlansMu.Unlock()
+	building := make(map[reflect.Type]*bindPlan)
+	p := compilePlan(t, building)
+	for bt, bp := range building {
+		bindPlans.Store(bt, bp)
+	}
+	return p
+}
+
+func compilePlan(t reflect.Type, building map[reflect.Type]*bindPlan) *bindPlan {
+	if p, ok := bindPlans.Load(t); ok {
+		return p.(*bindPlan)
+	}
+	if p, ok := building[t]; ok {
+		return p
+	}
+	p := &bindPlan{}
+	building[t] = p
+	p.bind = newBindFunc(t, building)
+	return p
+}
+
+func newBindFunc(t reflect.Type, building map[reflect.Type]*bindPlan) bindFunc {
+	if dec := decoderFor(t); dec != nil {
+		return decoderBinder(dec)
+	}
+	if t == jsonValueType {
+		return bindJSONValue
+	}
+	if t.Kind() != reflect.Pointer {
+		if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+			return bindUnmarshaler
+		}
+		if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+			return bindTextUnmarshaler
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return bindBool
+	case reflect.String:
+		if t == jsonNumberType {
+			return bindNumber
+		}
+		return bindString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return bindInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return bindUint
+	case reflect.Float32, reflect.Float64:
+		return bindFloat
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return bindInterface
+		}
+	case reflect.Pointer:
+		return pointerBinder(t, building)
+	case reflect.Slice:
+		return sliceBinder(t, building)
+	case reflect.Array:
+		return arrayBinder(t, building)
+	case reflect.Map:
+		return mapBinder(t, building)
+	case reflect.Struct:
+		return structBinder(t, building)
+	}
+	return bindUnsupported
+}
+
+func bindUnsupported(reflect.Value, interface{}) error {
+	return errBindFallback
+}
+
+// bindUnmarshaler hands the re-encoded subtree to UnmarshalJSON, including
+// null, as encoding/json does for non-pointer values
+func bindUnmarshaler(v reflect.Value, data interface{}) error {
+	buf := getBytesBuffer()
+	defer putBytesBuffer(buf)
+	if err := encodeInto(buf, data); err != nil {
+		return errBindFallback
+	}
+	if err := v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(*buf); err != nil {
+		return errBindFallback
+	}
+	return nil
+}
+
+func bindTextUnmarshaler(v reflect.Value, data interface{}) error {
+	s, ok := data.(string)
+	if !ok {
+		return errBindFallback
+	}
+	if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+		return errBindFallback
+	}
+	return nil
+}
+
+// -------------------- Scalars --------------------
+
+func bindBool(v reflect.Value, data interface{}) error {
+	switch d := data.(type) {
+	case nil:
+		return nil
+	case bool:
+		v.SetBool(d)
+		return nil
+	}
+	return errBindFallback
+}
+
+func bindString(v reflect.Value, data interface{}) error {
+	switch d := data.(type) {
+	case nil:
+		return nil
+	case string:
+		v.SetString(d)
+		return nil
+	}
+	return errBindFallback
+}
+
+// bindNumber fills a json.Number from a number literal kept by
+// ParseWithNumbers. float64 values take the codec path, which formats them.
+func bindNumber(v reflect.Value, data interface{}) error {
+	switch d := data.(type) {
+	case nil:
+		return nil
+	case json.Number:
+		v.SetString(string(d))
+		return nil
+	}
+	return errBindFallback
+}
+
+// exactFloat is the largest magnitude below which every integral float64
+// prints as the integer it holds
+const exactFloat = 1 << 53
+
+func bindInt(v reflect.Value, data interface{}) error {
+	var n int64
+	switch d := data.(type) {
+	case nil:
+		return nil
+	case float64:
+		if d != math.Trunc(d) {
+			return errBindFallback
+		}
+		if math.Abs(d) < exactFloat {
+			n = int64(d)
+		} else {
+			// encoding/json would see the shortest decimal form of d, whose
+			// trailing digits are zeros rather than the binary value's
+			var err error
+			if n, err = strconv.ParseInt(strconv.FormatFloat(d, 'f', -1, 64), 10, 64); err != nil {
+				return errBindFallback
+			}
+		}
+	case json.Number:
+		var err error
+		if n, err = strconv.ParseInt(string(d), 10, 64); err != nil {
+			return errBindFallback
+		}
+	case int:
+		n = int64(d)
+	case int64:
+		n = d
+	default:
+		return errBindFallback
+	}
+	if v.OverflowInt(n) {
+		return errBindFallback
+	}
+	v.SetInt(n)
+	return nil
+}
+
+func bindUint(v reflect.Value, data interface{}) error {
+	var n uint64
+	switch d := data.(type) {
+	case nil:
+		return nil
+	case float64:
+		if d < 0 || d != math.Trunc(d) {
+			return errBindFallback
+		}
+		if d < exactFloat {
+			n = uint64(d)
+		} else {
+			var err error
+			if n, err = strconv.ParseUint(strconv.FormatFloat(d, 'f', -1, 64), 10, 64); err != nil {
+				return errBindFallback
+			}
+		}
+	case json.Number:
+		var err error
+		if n, err = strconv.ParseUint(string(d), 10, 64); err != nil {
+			return errBindFallback
+		}
+	case int:
+		if d < 0 {
+			return errBindFallback
+		}
+		n = uint64(d)
+	case int64:
+		if d < 0 {
+			return errBindFallback
+		}
+		n = uint64(d)
+	default:
+		return errBindFallback
+	}
+	if v.OverflowUint(n) {
+		return errBindFallback
+	}
+	v.SetUint(n)
+	return nil
+}
+
+func bindFloat(v reflect.Value, data interface{}) error {
+	var f float64
+	switch d := data.(type) {
+	case nil:
+		return nil
+	case float64:
+		f = d
+		if v.Kind() == reflect.Float32 {
+			// Round from the decimal form, as encoding/json does
+			var err error
+			if f, err = strconv.ParseFloat(strconv.FormatFloat(d, 'g', -1, 64), 32); err != nil {
+				return errBindFallback
+			}
+		}
+	case json.Number:
+		var err error
+		if f, err = strconv.ParseFloat(string(d), v.Type().Bits()); err != nil {
+			return errBindFallback
+		}
+	case int:
+		f = float64(d)
+	case int64:
+		f = float64(d)
+	default:
+		return errBindFallback
+	}
+	if v.OverflowFloat(f) {
+		return errBindFallback
+	}
+	v.SetFloat(f)
+	return nil
+}
+
+// bindInterface stores a copy of the tree value, so the destination never
+// shares maps or slices with the source document. A non-nil pointer already
+// in the interface is decoded into by encoding/json, so that takes the codec
+// path.
+func bindInterface(v reflect.Value, data interface{}) error {
+	if data == nil {
+		v.SetZero()
+		return nil
+	}
+	if e := v.Elem(); e.Kind() == reflect.Pointer && !e.IsNil() {
+		return errBindFallback
+	}
+	v.Set(reflect.ValueOf(deepCopy(data)))
+	return nil
+}
+
+// bindJSONValue wraps the subtree without re-encoding it for UnmarshalJSON
+func bindJSONValue(v reflect.Value, data interface{}) error {
+	v.Set(reflect.ValueOf(JSONValue{data: data}))
+	return nil
+}
+
+// -------------------- Containers --------------------
+
+func pointerBinder(t reflect.Type, building map[reflect.Type]*bindPlan) bindFunc {
+	elem := compilePlan(t.Elem(), building)
+	return func(v reflect.Value, data interface{}) error {
+		if data == nil {
+			v.SetZero()
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return elem.bind(v.Elem(), data)
+	}
+}
+
+// sliceBinder decodes arrays element by element into the existing backing
+// array, and strings into []byte as base64, like encoding/json
+func sliceBinder(t reflect.Type, building map[reflect.Type]*bindPlan) bindFunc {
+	elem := compilePlan(t.Elem(), building)
+	isBytes := t.Elem().Kind() == reflect.Uint8
+	return func(v reflect.Value, data interface{}) error {
+		switch d := data.(type) {
+		case nil:
+			v.SetZero()
+			return nil
+		case string:
+			if !isBytes {
+				return errBindFallback
+			}
+			b := make([]byte, base64.StdEncoding.DecodedLen(len(d)))
+			n, err := base64.StdEncoding.Decode(b, []byte(d))
+			if err != nil {
+				return errBindFallback
+			}
+			v.SetBytes(b[:n])
+			return nil
+		case []interface{}:
+			if len(d) == 0 {
+				v.Set(reflect.MakeSlice(t, 0, 0))
+				return nil
+			}
+			if len(d) > v.Cap() {
+				grown := reflect.MakeSlice(t, len(d), len(d))
+				reflect.Copy(grown, v)
+				v.Set(grown)
+			} else {
+				v.SetLen(len(d))
+			}
+			for i, item := range d {
+				if err := elem.bind(v.Index(i), item); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return errBindFallback
+	}
+}
+
+// arrayBinder fills a fixed-size array, ignoring extra elements and zeroing
+// the ones missing from the input
+func arrayBinder(t reflect.Type, building map[reflect.Type]*bindPlan) bindFunc {
+	elem := compilePlan(t.Elem(), building)
+	return func(v reflect.Value, data interface{}) error {
+		switch d := data.(type) {
+		case nil:
+			return nil
+		case []interface{}:
+			for i := 0; i < v.Len(); i++ {
+				if i >= len(d) {
+					v.Index(i).SetZero()
+					continue
+				}
+				if err := elem.bind(v.Index(i), d[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return errBindFallback
+	}
+}
+
+// mapBinder merges an object into a map with string or integer keys. Key
+// types with UnmarshalText take the codec path.
+func mapBinder(t reflect.Type, building map[reflect.Type]*bindPlan) bindFunc {
+	kt := t.Key()
+	switch kt.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		return bindUnsupported
+	}
+	if reflect.PointerTo(kt).Implements(textUnmarshalerType) {
+		return bindUnsupported
+	}
+
+	elem := compilePlan(t.Elem(), building)
+	return func(v reflect.Value, data interface{}) error {
+		switch d := data.(type) {
+		case nil:
+			v.SetZero()
+			return nil
+		case map[string]interface{}:
+			if v.IsNil() {
+				v.Set(reflect.MakeMapWithSize(t, len(d)))
+			}
+			ev := reflect.New(t.Elem()).Elem()
+			kv := reflect.New(kt).Elem()
+			for key, item := range d {
+				ev.SetZero()
+				if err := elem.bind(ev, item); err != nil {
+					return err
+				}
+				switch kt.Kind() {
+				case reflect.String:
+					kv.SetString(key)
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+					n, err := strconv.ParseInt(key, 10, 64)
+					if err != nil || kv.OverflowInt(n) {
+						return errBindFallback
+					}
+					kv.SetInt(n)
+				default:
+					n, err := strconv.ParseUint(key, 10, 64)
+					if err != nil || kv.OverflowUint(n) {
+						return errBindFallback
+					}
+					kv.SetUint(n)
+				}
+				v.SetMapIndex(kv, ev)
+			}
+			return nil
+		}
+		return errBindFallback
+	}
+}
+
+// -------------------- Structs --------------------
+
+// bindField is one decodable struct field, possibly promoted from an
+// embedded struct
+type bindField struct {
+	name   string
+	index  []int
+	typ    reflect.Type // field type with one unnamed pointer removed
+	tagged bool
+	quoted bool // ",string" option on a scalar field
+	plan   *bindPlan
+}
+
+// structBinder matches object keys to fields by exact name first and then
+// case-insensitively. When the input holds both forms of a name, the exact
+// one wins, and among several case-insensitive matches the smallest key in
+// byte order does, so the result does not depend on map iteration order.
+// Unknown keys are ignored.
+func structBinder(t reflect.Type, building map[reflect.Type]*bindPlan) bindFunc {
+	fields := structFields(t)
+	exact := make(map[string]*bindField, len(fields))
+	folded := make(map[string]*bindField, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		f.plan = compilePlan(t.FieldByIndex(f.index).Type, building)
+		exact[f.name] = f
+		if key := foldName(f.name); folded[key] == nil {
+			folded[key] = f
+		}
+	}
+
+	return func(v reflect.Value, data interface{}) error {
+		switch d := data.(type) {
+		case nil:
+			return nil
+		case map[string]interface{}:
+			var foldedKeys map[*bindField]string
+			for key, item := range d {
+				if f := exact[key]; f != nil {
+					if err := bindStructField(v, f, item); err != nil {
+						return err
+					}
+					continue
+				}
+				f := folded[foldName(key)]
+				if f == nil {
+					continue
+				}
+				if _, ok := d[f.name]; ok {
+					continue
+				}
+				if prev, ok := foldedKeys[f]; !ok || key < prev {
+					if foldedKeys == nil {
+						foldedKeys = make(map[*bindField]string)
+					}
+					foldedKeys[f] = key
+				}
+			}
+			for f, key := range foldedKeys {
+				if err := bindStructField(v, f, d[key]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return errBindFallback
+	}
+}
+
+// bindStructField binds item to the field f of the struct v
+func bindStructField(v reflect.Value, f *bindField, item interface{}) error {
+	fv, err := fieldByIndex(v, f.index)
+	if err != nil {
+		return err
+	}
+	if f.quoted {
+		if item, err = unquoteField(item); err != nil {
+			return err
+		}
+	}
+	return f.plan.bind(fv, item)
+}
+
+// fieldByIndex returns the field at index, allocating nil embedded pointers
+// on the way
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					// Embedded pointer to an unexported struct type
+					return reflect.Value{}, errBindFallback
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// unquoteField decodes the JSON literal inside the string of a ",string"
+// field
+func unquoteField(item interface{}) (interface{}, error) {
+	s, ok := item.(string)
+	if !ok {
+		return nil, errBindFallback
+	}
+	inner, err := parseTree([]byte(s), &parseOptions{useNumber: true}, false)
+	if err != nil {
+		return nil, errBindFallback
+	}
+	switch inner.(type) {
+	case map[string]interface{}, []interface{}:
+		return nil, errBindFallback
+	}
+	return inner, nil
+}
+
+// structFields lists the fields of t that take part in decoding, applying
+// encoding/json's rules for tags and for fields promoted from embedded
+// structs: a shallower field hides deeper ones of the same name, and among
+// fields at the same depth a tagged one wins; otherwise all are dropped.
+// Untagged fields are named by the active namer, if any.
+func structFields(t reflect.Type) []bindField {
+	type queued struct {
+		typ   reflect.Type
+		index []int
+	}
+	var (
+		fields           []bindField
+		next             = []queued{{typ: t}}
+		count, nextCount map[reflect.Type]int
+		visited          = map[reflect.Type]bool{}
+		namer            = fieldNamer()
+	)
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, q := range current {
+			if visited[q.typ] {
+				continue
+			}
+			visited[q.typ] = true
+
+			for i := 0; i < q.typ.NumField(); i++ {
+				sf := q.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				if !isValidTag(name) {
+					name = ""
+				}
+				index := make([]int, len(q.index)+1)
+				copy(index, q.index)
+				index[len(q.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					f := bindField{name: name, index: index, typ: ft, tagged: name != ""}
+					if f.name == "" {
+						f.name = sf.Name
+						if namer != nil {
+							f.name = namer(sf.Name)
+						}
+					}
+					if hasTagOption(opts, "string") {
+						switch ft.Kind() {
+						case reflect.Bool, reflect.String,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64:
+							f.quoted = true
+						}
+					}
+					fields = append(fields, f)
+					if count[q.typ] > 1 {
+						// The same struct embedded twice at this depth: add a
+						// duplicate so the field is dropped below
+						fields = append(fields, f)
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, queued{typ: ft, index: index})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) < len(b.index)
+		}
+		if a.tagged != b.tagged {
+			return a.tagged
+		}
+		return indexLess(a.index, b.index)
+	})
+
+	out := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		group := fields[i:j]
+		if len(group) == 1 || len(group[0].index) != len(group[1].index) || group[0].tagged != group[1].tagged {
+			out = append(out, group[0])
+		}
+		i = j
+	}
+
+	sort.Slice(out, func(i, j int) bool { return indexLess(out[i].index, out[j].index) })
+	return out
+}
+
+func indexLess(a, b []int) bool {
+	for k, x := range a {
+		if k >= len(b) {
+			return false
+		}
+		if x != b[k] {
+			return x < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func hasTagOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidTag reports whether s can be used as a JSON field name in a tag
+func isValidTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+// foldName returns a key such that foldName(a) == foldName(b) exactly when
+// strings.EqualFold(a, b)
+func foldName(s string) string {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			return foldUnicode(s)
+		}
+		if 'A' <= c && c <= 'Z' {
+			return foldASCII(s)
+		}
+	}
+	return s
+}
+
+func foldASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// foldUnicode maps every rune to the smallest member of its case-folding
+// orbit
+func foldUnicode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		min := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+		b.WriteRune(min)
+	}
+	return b.String()
+}
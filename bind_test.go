@@ -0,0 +1,257 @@
+package jsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+type bindInner struct {
+	Code int     `json:"code"`
+	Note *string `json:"note"`
+}
+
+type BindEmbedded struct {
+	Shared string `json:"shared"`
+	Level  int
+}
+
+type bindText struct{ v string }
+
+func (t *bindText) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		return errors.New("empty text")
+	}
+	t.v = strings.ToUpper(string(b))
+	return nil
+}
+
+type bindRaw struct{ raw string }
+
+func (r *bindRaw) UnmarshalJSON(b []byte) error {
+	r.raw = string(b)
+	return nil
+}
+
+type bindNode struct {
+	Value int       `json:"value"`
+	Next  *bindNode `json:"next"`
+}
+
+type bindAll struct {
+	BindEmbedded
+	Name     string  `json:"name"`
+	Age      uint8   `json:"age"`
+	Score    float32 `json:"score"`
+	Ratio    float64
+	Tags     []string          `json:"tags"`
+	Grid     [2]int            `json:"grid"`
+	Counts   map[string]int    `json:"counts"`
+	ByID     map[int]string    `json:"by_id"`
+	Inner    bindInner         `json:"inner"`
+	InnerPtr *bindInner        `json:"inner_ptr"`
+	Any      interface{}       `json:"any"`
+	Blob     []byte            `json:"blob"`
+	When     time.Time         `json:"when"`
+	Text     bindText          `json:"text"`
+	Raw      bindRaw           `json:"raw"`
+	Quoted   int64             `json:"quoted,string"`
+	List     *bindNode         `json:"list"`
+	Nested   map[string][]bool `json:"nested"`
+	Skip     string            `json:"-"`
+	hidden   string
+}
+
+// bindConflict embeds two structs with an untagged Level field at the same
+// depth, so encoding/json drops it
+type bindConflict struct {
+	BindEmbedded
+	bindOther
+}
+
+type bindOther struct {
+	Level int
+}
+
+func TestToMatchesEncodingJSON(t *testing.T) {
+	full := `{
+		"shared": "s", "Level": 3, "name": "Ada", "age": 36, "score": 0.1,
+		"Ratio": 2.5, "tags": ["a", "b"], "grid": [1, 2, 3],
+		"counts": {"x": 1, "y": -2}, "by_id": {"7": "seven", "-1": "neg"},
+		"inner": {"code": 200, "note": "ok"}, "inner_ptr": {"code": 404},
+		"any": {"k": [1, "two", null, true]}, "blob": "aGVsbG8=",
+		"when": "2023-06-15T10:30:00Z", "text": "abc", "raw": {"z":[1,2]},
+		"quoted": "42", "list": {"value": 1, "next": {"value": 2}},
+		"nested": {"f": [true, false]}, "Skip": "no", "hidden": "no",
+		"unknown": {"deep": [1]}
+	}`
+
+	tests := []struct {
+		name  string
+		input string
+		dest  func() interface{}
+	}{
+		{"full", full, func() interface{} { return &bindAll{} }},
+		{"case-insensitive names", `{"NAME": "x", "ratio": 1.5, "LEVEL": 2}`, func() interface{} { return &bindAll{} }},
+		{"nulls keep or clear", `{"name": null, "tags": null, "inner_ptr": null, "any": null, "age": null, "grid": null}`,
+			func() interface{} {
+				return &bindAll{Name: "keep", Tags: []string{"x"}, InnerPtr: &bindInner{}, Any: 1.0, Age: 9, Grid: [2]int{5, 6}}
+			}},
+		{"merge into existing", `{"tags": ["n"], "counts": {"b": 2}, "inner_ptr": {"code": 1}, "grid": [7]}`,
+			func() interface{} {
+				return &bindAll{Tags: make([]string, 3, 8), Counts: map[string]int{"a": 1}, InnerPtr: &bindInner{Code: 5}, Grid: [2]int{5, 6}}
+			}},
+		{"empty array", `{"tags": []}`, func() interface{} { return &bindAll{} }},
+		{"large integer", `{"Level": 12345678901234567890000}`, func() interface{} { return &bindAll{} }},
+		{"embedded conflict", `{"shared": "x", "Level": 3}`, func() interface{} { return &bindConflict{} }},
+		{"top-level slice", `[{"code": 1}, {"code": 2, "note": "n"}]`, func() interface{} { return &[]bindInner{} }},
+		{"top-level map", `{"a": {"code": 1}}`, func() interface{} { return &map[string]*bindInner{} }},
+		{"top-level scalar", `12`, func() interface{} { return new(int16) }},
+
+		{"overflow", `{"age": 300}`, func() interface{} { return &bindAll{} }},
+		{"negative unsigned", `{"age": -1}`, func() interface{} { return &bindAll{} }},
+		{"fraction into int", `{"age": 1.5}`, func() interface{} { return &bindAll{} }},
+		{"float32 overflow", `{"score": 1e40}`, func() interface{} { return &bindAll{} }},
+		{"number into string", `{"name": 5}`, func() interface{} { return &bindAll{} }},
+		{"string into slice", `{"tags": "x"}`, func() interface{} { return &bindAll{} }},
+		{"object into array", `{"grid": {}}`, func() interface{} { return &bindAll{} }},
+		{"bad map key", `{"by_id": {"x": "y"}}`, func() interface{} { return &bindAll{} }},
+		{"bad base64", `{"blob": "***"}`, func() interface{} { return &bindAll{} }},
+		{"unquoted string option", `{"quoted": 5}`, func() interface{} { return &bindAll{} }},
+		{"text from number", `{"text": 5}`, func() interface{} { return &bindAll{} }},
+		{"text error", `{"text": ""}`, func() interface{} { return &bindAll{} }},
+		{"bad time", `{"when": "soon"}`, func() interface{} { return &bindAll{} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.dest()
+			wantErr := json.Unmarshal([]byte(tt.input), want)
+
+			for _, via := range []string{"To", "Parse"} {
+				got := tt.dest()
+				var err error
+				if via == "To" {
+					err = JSON.Parse(tt.input).To(got)
+				} else {
+					err = JSON.Parse(tt.input, got).Error()
+				}
+
+				// To re-encodes the tree on the fallback path, so number
+				// literals in its messages may be spelled differently
+				if (err == nil) != (wantErr == nil) || (via == "Parse" && err != nil && !strings.Contains(err.Error(), wantErr.Error())) {
+					t.Fatalf("%s: expected error %v, got %v", via, wantErr, err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: expected %+v, got %+v", via, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestToInterfaceHoldingPointer(t *testing.T) {
+	var inner bindInner
+	var dest interface{} = &inner
+	holder := struct{ V interface{} }{V: dest}
+	if err := JSON.Parse(`{"V": {"code": 7}}`).To(&holder); err != nil {
+		t.Fatalf("To failed: %v", err)
+	}
+	if inner.Code != 7 {
+		t.Errorf("Expected decoding into the existing pointer, got %+v", inner)
+	}
+}
+
+func TestToInterfaceFieldsDoNotShareTree(t *testing.T) {
+	src := JSON.Parse(`{"a": {"x": 1}, "b": [1, 2]}`)
+	var dest struct {
+		A interface{} `json:"a"`
+		B interface{} `json:"b"`
+	}
+	if err := src.To(&dest); err != nil {
+		t.Fatalf("To failed: %v", err)
+	}
+	dest.A.(map[string]interface{})["x"] = "changed"
+	dest.B.([]interface{})[0] = "changed"
+
+	if got, _ := JSON.Stringify(src); got != `{"a":{"x":1},"b":[1,2]}` {
+		t.Errorf("Mutating the destination changed the source: %s", got)
+	}
+}
+
+func TestToCaseFoldedKeysDeterministic(t *testing.T) {
+	src := JSON.Parse(`{"NaMe": "b", "NAME": "a", "nAME": "c"}`)
+	for i := 0; i < 50; i++ {
+		var dest struct {
+			Name string `json:"name"`
+		}
+		if err := src.To(&dest); err != nil {
+			t.Fatalf("To failed: %v", err)
+		}
+		if dest.Name != "a" {
+			t.Fatalf("Expected the smallest matching key to win, got %q", dest.Name)
+		}
+	}
+}
+
+func TestToWithNumbers(t *testing.T) {
+	var dest struct {
+		ID  uint64      `json:"id"`
+		Num json.Number `json:"num"`
+		F   float32     `json:"f"`
+	}
+	obj := JSON.Parse(`{"id": 18446744073709551615, "num": 1.50, "f": 0.1}`, JSON.ParseWithNumbers())
+	if err := obj.To(&dest); err != nil {
+		t.Fatalf("To failed: %v", err)
+	}
+	if dest.ID != 18446744073709551615 || dest.Num != "1.50" || dest.F != 0.1 {
+		t.Errorf("Unexpected result %+v", dest)
+	}
+}
+
+func TestToConcurrentPlans(t *testing.T) {
+	done := make(chan error)
+	for i := 0; i < 8; i++ {
+		go func() {
+			var dest bindAll
+			done <- JSON.Parse(`{"list": {"value": 1}, "inner": {"code": 2}}`).To(&dest)
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("To failed: %v", err)
+		}
+	}
+}
+
+func TestParseStructDestMatchesUnmarshal(t *testing.T) {
+	type dest struct {
+		ID int64 `json:"id"`
+	}
+	var got dest
+	obj := JSON.Parse(`{"id": 12345678901234567}`, &got)
+	if !obj.IsValid() {
+		t.Fatalf("Parse failed: %v", obj.Error())
+	}
+	if got.ID != 12345678901234567 {
+		t.Errorf("Expected 12345678901234567, got %d", got.ID)
+	}
+	if _, ok := obj.Get("id").Raw().(float64); !ok {
+		t.Errorf("Expected the tree to hold float64, got %T", obj.Get("id").Raw())
+	}
+
+	for _, input := range []string{`{"id": 1.0}`, `{"id": 1e2}`} {
+		var d dest
+		if obj := JSON.Parse(input, &d); obj.IsValid() {
+			t.Errorf("Parse(%s) into an int field should fail, got %+v", input, d)
+		}
+		if err := json.Unmarshal([]byte(input), &d); err == nil {
+			t.Errorf("encoding/json accepted %s", input)
+		}
+	}
+}
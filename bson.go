@@ -0,0 +1,603 @@
+package jsjson
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// -------------------- BSON --------------------
+//
+// BSON types without a JSON counterpart are mapped to values the typed
+// accessors understand:
+//
+//	ObjectId           24 hex digits               "65a1f0c2e4b0a1b2c3d4e5f6"
+//	UTC datetime       RFC 3339, UTC, milliseconds "2024-01-12T08:30:00.123Z" (Time)
+//	Decimal128         exact number literal        12.50 (Decimal)
+//	int64              number, exact beyond 2^53   (Int64)
+//	binary             standard base64             (Bytes)
+//	binary UUID        canonical UUID string       (UUID)
+//	regex, timestamp,  MongoDB Extended JSON       {"$regularExpression": {...}}
+//	min/max key
+//
+// ToBSON reads the Extended JSON wrappers $oid, $date, $numberDecimal,
+// $numberLong, $regularExpression, $timestamp, $minKey and $maxKey, so those
+// values can be written back with their BSON type.
+
+const (
+	bsonDouble     byte = 0x01
+	bsonString     byte = 0x02
+	bsonDocument   byte = 0x03
+	bsonArray      byte = 0x04
+	bsonBinary     byte = 0x05
+	bsonUndefined  byte = 0x06
+	bsonObjectID   byte = 0x07
+	bsonBool       byte = 0x08
+	bsonDateTime   byte = 0x09
+	bsonNull       byte = 0x0A
+	bsonRegex      byte = 0x0B
+	bsonJavaScript byte = 0x0D
+	bsonSymbol     byte = 0x0E
+	bsonInt32      byte = 0x10
+	bsonTimestamp  byte = 0x11
+	bsonInt64      byte = 0x12
+	bsonDecimal128 byte = 0x13
+	bsonMinKey     byte = 0xFF
+	bsonMaxKey     byte = 0x7F
+
+	// bsonMaxDepth bounds nesting when decoding untrusted input
+	bsonMaxDepth = 10000
+
+	// maxSafeInteger is the largest integer every float64 below it represents exactly
+	maxSafeInteger = 1 << 53
+)
+
+// ParseBSON decodes a BSON document, such as a raw MongoDB query result,
+// into a JSONValue that can be navigated with Get and the typed accessors
+func ParseBSON(doc []byte) JSONValue {
+	d := bsonDecoder{buf: doc}
+	v, err := d.document(0, false)
+	if err == nil && d.pos != len(d.buf) {
+		err = fmt.Errorf("%w: %d trailing bytes", ErrSyntax, len(d.buf)-d.pos)
+	}
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseBSON", Err: err}}
+	}
+	return JSONValue{data: v}
+}
+
+// ToBSON encodes an object as a BSON document with its keys in sorted order.
+// Integers become int32 or int64 as MongoDB drivers store them, other numbers
+// doubles; see ParseBSON for the Extended JSON wrappers that select other
+// BSON types.
+func (j JSONValue) ToBSON() ([]byte, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	data, err := normalize(j.data)
+	if err != nil {
+		return nil, &JSONError{Op: "ToBSON", Err: err}
+	}
+	obj, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, &JSONError{Op: "ToBSON", Err: fmt.Errorf("%w: a BSON document must be an object, got %s", ErrTypeMismatch, JSONValue{data: data}.Type())}
+	}
+	b, err := appendBSONDocument(nil, obj)
+	if err != nil {
+		return nil, &JSONError{Op: "ToBSON", Err: err}
+	}
+	return b, nil
+}
+
+// -------------------- Decoding --------------------
+
+type bsonDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *bsonDecoder) truncated() error {
+	return fmt.Errorf("%w: truncated BSON at offset %d", ErrSyntax, d.pos)
+}
+
+func (d *bsonDecoder) take(n int) ([]byte, error) {
+	if n < 0 || len(d.buf)-d.pos < n {
+		return nil, d.truncated()
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *bsonDecoder) int32() (int32, error) {
+	b, err := d.take(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.LittleEndian.Uint32(b)), nil
+}
+
+func (d *bsonDecoder) uint64() (uint64, error) {
+	b, err := d.take(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (d *bsonDecoder) cstring() (string, error) {
+	for i := d.pos; i < len(d.buf); i++ {
+		if d.buf[i] == 0 {
+			s := string(d.buf[d.pos:i])
+			d.pos = i + 1
+			return s, nil
+		}
+	}
+	return "", d.truncated()
+}
+
+func (d *bsonDecoder) string() (string, error) {
+	n, err := d.int32()
+	if err != nil {
+		return "", err
+	}
+	b, err := d.take(int(n))
+	if err != nil {
+		return "", err
+	}
+	if n < 1 || b[n-1] != 0 {
+		return "", fmt.Errorf("%w: invalid BSON string at offset %d", ErrSyntax, d.pos-int(n))
+	}
+	return string(b[:n-1]), nil
+}
+
+// document decodes an embedded document or, with asArray, an array
+func (d *bsonDecoder) document(depth int, asArray bool) (interface{}, error) {
+	if depth > bsonMaxDepth {
+		return nil, fmt.Errorf("%w: nesting exceeds %d levels", ErrLimitExceeded, bsonMaxDepth)
+	}
+	start := d.pos
+	n, err := d.int32()
+	if err != nil {
+		return nil, err
+	}
+	if n < 5 || int(n) > len(d.buf)-start {
+		return nil, fmt.Errorf("%w: invalid BSON document length %d at offset %d", ErrSyntax, n, start)
+	}
+	end := start + int(n)
+
+	obj := make(map[string]interface{})
+	var arr []interface{}
+	if asArray {
+		arr = make([]interface{}, 0)
+	}
+	for {
+		if d.pos >= end {
+			return nil, d.truncated()
+		}
+		typ := d.buf[d.pos]
+		d.pos++
+		if typ == 0 {
+			break
+		}
+		key, err := d.cstring()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.element(typ, depth)
+		if err != nil {
+			return nil, err
+		}
+		if asArray {
+			arr = append(arr, v)
+		} else {
+			obj[key] = v
+		}
+	}
+	if d.pos != end {
+		return nil, fmt.Errorf("%w: BSON document length mismatch at offset %d", ErrSyntax, start)
+	}
+	if asArray {
+		return arr, nil
+	}
+	return obj, nil
+}
+
+// element decodes the value of an element of type typ
+func (d *bsonDecoder) element(typ byte, depth int) (interface{}, error) {
+	switch typ {
+	case bsonDouble:
+		u, err := d.uint64()
+		if err != nil {
+			return nil, err
+		}
+		return floatValue(math.Float64frombits(u)), nil
+	case bsonString, bsonJavaScript, bsonSymbol:
+		return d.string()
+	case bsonDocument:
+		return d.document(depth+1, false)
+	case bsonArray:
+		return d.document(depth+1, true)
+	case bsonBinary:
+		n, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		sub, err := d.take(1)
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.take(int(n))
+		if err != nil {
+			return nil, err
+		}
+		if (sub[0] == 0x03 || sub[0] == 0x04) && len(b) == 16 {
+			return formatUUID([16]byte(b)), nil
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	case bsonUndefined, bsonNull:
+		return nil, nil
+	case bsonObjectID:
+		b, err := d.take(12)
+		if err != nil {
+			return nil, err
+		}
+		return hex.EncodeToString(b), nil
+	case bsonBool:
+		b, err := d.take(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case bsonDateTime:
+		u, err := d.uint64()
+		if err != nil {
+			return nil, err
+		}
+		return time.UnixMilli(int64(u)).UTC().Format(time.RFC3339Nano), nil
+	case bsonRegex:
+		pattern, err := d.cstring()
+		if err != nil {
+			return nil, err
+		}
+		options, err := d.cstring()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"$regularExpression": map[string]interface{}{"pattern": pattern, "options": options}}, nil
+	case bsonInt32:
+		n, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		return float64(n), nil
+	case bsonTimestamp:
+		u, err := d.uint64()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"$timestamp": map[string]interface{}{"t": float64(u >> 32), "i": float64(uint32(u))}}, nil
+	case bsonInt64:
+		u, err := d.uint64()
+		if err != nil {
+			return nil, err
+		}
+		if n := int64(u); n >= -maxSafeInteger && n <= maxSafeInteger {
+			return float64(n), nil
+		}
+		return json.Number(strconv.FormatInt(int64(u), 10)), nil
+	case bsonDecimal128:
+		lo, err := d.uint64()
+		if err != nil {
+			return nil, err
+		}
+		hi, err := d.uint64()
+		if err != nil {
+			return nil, err
+		}
+		return decimal128Value(hi, lo), nil
+	case bsonMinKey:
+		return map[string]interface{}{"$minKey": float64(1)}, nil
+	case bsonMaxKey:
+		return map[string]interface{}{"$maxKey": float64(1)}, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported BSON type 0x%02x at offset %d", ErrTypeMismatch, typ, d.pos-1)
+}
+
+// floatValue keeps NaN and infinities, which JSON cannot hold, as strings
+func floatValue(f float64) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return f
+}
+
+// decimal128Value decodes an IEEE 754-2008 decimal128 in binary integer
+// decimal encoding
+func decimal128Value(hi, lo uint64) interface{} {
+	negative := hi>>63 == 1
+	var exp uint64
+	coef := new(big.Int)
+	switch {
+	case (hi>>58)&0x1F == 0x1F:
+		return "NaN"
+	case (hi>>58)&0x1F == 0x1E:
+		if negative {
+			return "-Infinity"
+		}
+		return "Infinity"
+	case (hi>>61)&3 == 3:
+		// The coefficient of this form exceeds 34 digits and counts as zero
+		exp = (hi >> 47) & 0x3FFF
+	default:
+		exp = (hi >> 49) & 0x3FFF
+		coef.SetUint64(hi & (1<<49 - 1))
+		coef.Lsh(coef, 64)
+		coef.Or(coef, new(big.Int).SetUint64(lo))
+		if coef.Cmp(maxDecimal128Coefficient) > 0 {
+			coef.SetInt64(0)
+		}
+	}
+	if negative {
+		coef.Neg(coef)
+	}
+	return json.Number(Decimal{Coefficient: coef, Exponent: int32(exp) - decimal128Bias}.String())
+}
+
+const (
+	decimal128Bias   = 6176
+	decimal128MaxExp = 6111
+)
+
+// maxDecimal128Coefficient is 10^34 - 1
+var maxDecimal128Coefficient = new(big.Int).Sub(new(big.Int).Exp(big.NewInt(10), big.NewInt(34), nil), big.NewInt(1))
+
+// -------------------- Encoding --------------------
+
+func appendBSONDocument(b []byte, obj map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	start := len(b)
+	b = append(b, 0, 0, 0, 0)
+	for _, k := range keys {
+		var err error
+		if b, err = appendBSONElement(b, k, obj[k]); err != nil {
+			return nil, err
+		}
+	}
+	b = append(b, 0)
+	binary.LittleEndian.PutUint32(b[start:], uint32(len(b)-start))
+	return b, nil
+}
+
+func appendBSONArray(b []byte, items []interface{}) ([]byte, error) {
+	start := len(b)
+	b = append(b, 0, 0, 0, 0)
+	for i, item := range items {
+		var err error
+		if b, err = appendBSONElement(b, strconv.Itoa(i), item); err != nil {
+			return nil, err
+		}
+	}
+	b = append(b, 0)
+	binary.LittleEndian.PutUint32(b[start:], uint32(len(b)-start))
+	return b, nil
+}
+
+// appendBSONElement appends the element key: v
+func appendBSONElement(b []byte, key string, v interface{}) ([]byte, error) {
+	if strings.IndexByte(key, 0) >= 0 {
+		return nil, fmt.Errorf("%w: BSON keys cannot contain NUL: %q", ErrTypeMismatch, key)
+	}
+	header := func(typ byte) []byte {
+		b = append(b, typ)
+		b = append(b, key...)
+		return append(b, 0)
+	}
+
+	switch c := v.(type) {
+	case nil:
+		return header(bsonNull), nil
+	case bool:
+		b = header(bsonBool)
+		if c {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case string:
+		return appendBSONString(header(bsonString), c), nil
+	case float64:
+		if c == math.Trunc(c) && math.Abs(c) <= maxSafeInteger {
+			return appendBSONInt(header, int64(c)), nil
+		}
+		return binary.LittleEndian.AppendUint64(header(bsonDouble), math.Float64bits(c)), nil
+	case json.Number:
+		if n, err := strconv.ParseInt(string(c), 10, 64); err == nil {
+			return appendBSONInt(header, n), nil
+		}
+		f, err := strconv.ParseFloat(string(c), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: number %s does not fit a BSON double", ErrLimitExceeded, c)
+		}
+		return binary.LittleEndian.AppendUint64(header(bsonDouble), math.Float64bits(f)), nil
+	case []interface{}:
+		return appendBSONArray(header(bsonArray), c)
+	case map[string]interface{}:
+		if len(c) == 1 {
+			if typed, ok, err := appendBSONWrapper(b, key, c); ok || err != nil {
+				return typed, err
+			}
+		}
+		return appendBSONDocument(header(bsonDocument), c)
+	}
+	return nil, fmt.Errorf("%w: cannot encode %T as BSON", ErrTypeMismatch, v)
+}
+
+func appendBSONInt(header func(byte) []byte, n int64) []byte {
+	if n >= math.MinInt32 && n <= math.MaxInt32 {
+		return binary.LittleEndian.AppendUint32(header(bsonInt32), uint32(int32(n)))
+	}
+	return binary.LittleEndian.AppendUint64(header(bsonInt64), uint64(n))
+}
+
+func appendBSONString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)+1))
+	b = append(b, s...)
+	return append(b, 0)
+}
+
+// appendBSONWrapper appends an Extended JSON wrapper with its BSON type. ok
+// is false if obj is not a wrapper; a malformed wrapper is an error.
+func appendBSONWrapper(b []byte, key string, obj map[string]interface{}) (out []byte, ok bool, err error) {
+	header := func(typ byte) []byte {
+		b = append(b, typ)
+		b = append(b, key...)
+		return append(b, 0)
+	}
+	invalid := func(name string, v interface{}) ([]byte, bool, error) {
+		return nil, true, fmt.Errorf("%w: invalid %s value %v", ErrTypeMismatch, name, v)
+	}
+
+	for name, v := range obj {
+		switch name {
+		case "$oid":
+			s, _ := v.(string)
+			id, err := hex.DecodeString(s)
+			if err != nil || len(id) != 12 {
+				return invalid(name, v)
+			}
+			return append(header(bsonObjectID), id...), true, nil
+		case "$date":
+			var ms int64
+			switch d := v.(type) {
+			case string:
+				t, err := time.Parse(time.RFC3339Nano, d)
+				if err != nil {
+					return invalid(name, v)
+				}
+				ms = t.UnixMilli()
+			case map[string]interface{}:
+				n, ok := d["$numberLong"].(string)
+				if !ok {
+					return invalid(name, v)
+				}
+				if ms, err = strconv.ParseInt(n, 10, 64); err != nil {
+					return invalid(name, v)
+				}
+			default:
+				f, ok := numberValue(d)
+				if !ok || f != math.Trunc(f) {
+					return invalid(name, v)
+				}
+				ms = int64(f)
+			}
+			return binary.LittleEndian.AppendUint64(header(bsonDateTime), uint64(ms)), true, nil
+		case "$numberLong":
+			s, _ := v.(string)
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return invalid(name, v)
+			}
+			return binary.LittleEndian.AppendUint64(header(bsonInt64), uint64(n)), true, nil
+		case "$numberDecimal":
+			s, _ := v.(string)
+			hi, lo, err := decimal128Bits(s)
+			if err != nil {
+				return nil, true, err
+			}
+			b = binary.LittleEndian.AppendUint64(header(bsonDecimal128), lo)
+			return binary.LittleEndian.AppendUint64(b, hi), true, nil
+		case "$regularExpression":
+			re, _ := v.(map[string]interface{})
+			pattern, ok1 := re["pattern"].(string)
+			options, ok2 := re["options"].(string)
+			if !ok1 || !ok2 || strings.IndexByte(pattern, 0) >= 0 || strings.IndexByte(options, 0) >= 0 {
+				return invalid(name, v)
+			}
+			b = append(header(bsonRegex), pattern...)
+			b = append(append(b, 0), options...)
+			return append(b, 0), true, nil
+		case "$timestamp":
+			ts, _ := v.(map[string]interface{})
+			t, ok1 := numberValue(ts["t"])
+			i, ok2 := numberValue(ts["i"])
+			if !ok1 || !ok2 || t != math.Trunc(t) || i != math.Trunc(i) || t < 0 || t > math.MaxUint32 || i < 0 || i > math.MaxUint32 {
+				return invalid(name, v)
+			}
+			return binary.LittleEndian.AppendUint64(header(bsonTimestamp), uint64(t)<<32|uint64(i)), true, nil
+		case "$minKey":
+			return header(bsonMinKey), true, nil
+		case "$maxKey":
+			return header(bsonMaxKey), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// decimal128Bits encodes a decimal literal, or NaN and [-]Infinity, exactly
+// as a decimal128
+func decimal128Bits(s string) (hi, lo uint64, err error) {
+	switch s {
+	case "NaN":
+		return 0x1F << 58, 0, nil
+	case "Infinity", "+Infinity":
+		return 0x1E << 58, 0, nil
+	case "-Infinity":
+		return 1<<63 | 0x1E<<58, 0, nil
+	}
+
+	d, err := ParseDecimal(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	negative := d.Coefficient.Sign() < 0 || strings.HasPrefix(s, "-")
+	coef := new(big.Int).Abs(d.Coefficient)
+	exp := int64(d.Exponent)
+
+	// Trade trailing zeros for exponent range where that makes it fit
+	ten := big.NewInt(10)
+	for exp > decimal128MaxExp && coef.Sign() != 0 {
+		next := new(big.Int).Mul(coef, ten)
+		if next.Cmp(maxDecimal128Coefficient) > 0 {
+			break
+		}
+		coef, exp = next, exp-1
+	}
+	for exp < -decimal128Bias || coef.Cmp(maxDecimal128Coefficient) > 0 {
+		q, r := new(big.Int).QuoRem(coef, ten, new(big.Int))
+		if r.Sign() != 0 {
+			break
+		}
+		coef, exp = q, exp+1
+	}
+	if coef.Sign() == 0 {
+		exp = max(min(exp, decimal128MaxExp), -decimal128Bias)
+	}
+	if exp > decimal128MaxExp || exp < -decimal128Bias || coef.Cmp(maxDecimal128Coefficient) > 0 {
+		return 0, 0, fmt.Errorf("%w: %s does not fit a decimal128 exactly", ErrLimitExceeded, s)
+	}
+
+	lo = new(big.Int).And(coef, new(big.Int).SetUint64(math.MaxUint64)).Uint64()
+	hi = new(big.Int).Rsh(coef, 64).Uint64() | uint64(exp+decimal128Bias)<<49
+	if negative {
+		hi |= 1 << 63
+	}
+	return hi, lo, nil
+}
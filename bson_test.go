@@ -0,0 +1,136 @@
+package jsjson_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestToBSONBytes(t *testing.T) {
+	got, err := JSON.Parse(`{"b": true, "a": 1}`).ToBSON()
+	want := []byte("\x10\x00\x00\x00\x10a\x00\x01\x00\x00\x00\x08b\x00\x01\x00")
+	if err != nil || !bytes.Equal(got, want) {
+		t.Errorf("Expected %q, got %q (%v)", want, got, err)
+	}
+}
+
+func TestBSONRoundTrip(t *testing.T) {
+	const input = `{
+		"_id": {"$oid": "65a1f0c2e4b0a1b2c3d4e5f6"},
+		"when": {"$date": "2024-01-12T08:30:00.123Z"},
+		"price": {"$numberDecimal": "12.50"},
+		"tiny": {"$numberDecimal": "-1E-6176"},
+		"count": {"$numberLong": "7"},
+		"big": 1152921504606846976,
+		"ratio": 0.25,
+		"n": -3,
+		"name": "widget",
+		"tags": ["a", {"deep": [null, false]}],
+		"re": {"$regularExpression": {"pattern": "^w", "options": "i"}},
+		"ts": {"$timestamp": {"t": 1700000000, "i": 2}},
+		"lo": {"$minKey": 1},
+		"other": {"$unknown": 1}
+	}`
+	doc, err := JSON.Parse(input, JSON.ParseWithNumbers()).ToBSON()
+	if err != nil {
+		t.Fatalf("ToBSON failed: %v", err)
+	}
+
+	got := JSON.ParseBSON(doc)
+	if got.Error() != nil {
+		t.Fatalf("ParseBSON failed: %v", got.Error())
+	}
+	if id := got.Get("_id").StringOr(""); id != "65a1f0c2e4b0a1b2c3d4e5f6" {
+		t.Errorf("Unexpected ObjectId %q", id)
+	}
+	if when, err := got.Get("when").Time(); err != nil || !when.Equal(time.Date(2024, 1, 12, 8, 30, 0, 123e6, time.UTC)) {
+		t.Errorf("Unexpected date %v (%v)", when, err)
+	}
+	if d, err := got.Get("price").Decimal(); err != nil || d.String() != "12.50" {
+		t.Errorf("Unexpected decimal %v (%v)", d, err)
+	}
+	if d, _ := got.Get("tiny").Decimal(); d.Exponent != -6176 || d.Coefficient.Int64() != -1 {
+		t.Errorf("Unexpected decimal %v", d)
+	}
+	if n, err := got.Get("big").Int64(); err != nil || n != 1<<60 {
+		t.Errorf("Expected 2^60, got %d (%v)", n, err)
+	}
+	if got.Get("count").IntOr(0) != 7 || got.Get("n").IntOr(0) != -3 || got.Get("ratio").Float64Or(0) != 0.25 {
+		t.Errorf("Unexpected numbers %v", got.Raw())
+	}
+	if !got.Get("tags", 1, "deep", 0).IsNull() || got.Get("tags", 1, "deep", 1).BoolOr(true) {
+		t.Errorf("Unexpected nesting %v", got.Get("tags").Raw())
+	}
+	if got.Get("re", "$regularExpression", "options").StringOr("") != "i" ||
+		got.Get("ts", "$timestamp", "t").IntOr(0) != 1700000000 || !got.Has("lo", "$minKey") ||
+		got.Get("other", "$unknown").IntOr(0) != 1 {
+		t.Errorf("Unexpected Extended JSON values %v", got.Raw())
+	}
+
+	// Writing the decoded document again gives the same bytes except for
+	// the types that came back as plain strings
+	again, _ := got.ToBSON()
+	if len(again) == 0 || bytes.Equal(again, doc) {
+		t.Errorf("Expected ObjectId and date to come back as strings")
+	}
+}
+
+func TestParseBSONTypes(t *testing.T) {
+	// {"bin": binary(0x00, "hi"), "uuid": binary(0x04, 16 bytes), "f": NaN, "dec": 1.5 as decimal128}
+	doc := []byte{
+		0x52, 0, 0, 0,
+		0x05, 'b', 'i', 'n', 0, 2, 0, 0, 0, 0x00, 'h', 'i',
+		0x05, 'u', 'u', 'i', 'd', 0, 16, 0, 0, 0, 0x04,
+		0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00,
+		0x01, 'f', 0, 0, 0, 0, 0, 0, 0, 0xf8, 0x7f,
+		0x13, 'd', 'e', 'c', 0, 15, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x3e, 0x30,
+		0x0a, 'n', 0,
+		0x06, 'u', 0,
+		0,
+	}
+	got := JSON.ParseBSON(doc)
+	if got.Error() != nil {
+		t.Fatalf("ParseBSON failed: %v", got.Error())
+	}
+	if b, err := got.Get("bin").Bytes(); err != nil || string(b) != "hi" {
+		t.Errorf("Unexpected binary %q (%v)", b, err)
+	}
+	if u := got.Get("uuid").UUIDStringOr(""); u != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Errorf("Unexpected UUID %q", u)
+	}
+	if got.Get("f").StringOr("") != "NaN" || got.Get("dec").Float64Or(0) != 1.5 {
+		t.Errorf("Unexpected values %v", got.Raw())
+	}
+	if !got.Get("n").IsNull() || !got.Get("u").IsNull() {
+		t.Errorf("Expected null and undefined to be null")
+	}
+}
+
+func TestBSONErrors(t *testing.T) {
+	valid, _ := JSON.Parse(`{"a": "b"}`).ToBSON()
+	for name, doc := range map[string][]byte{
+		"empty":     nil,
+		"truncated": valid[:len(valid)-1],
+		"trailing":  append(append([]byte(nil), valid...), 0),
+		"length":    append([]byte{0xff, 0, 0, 0}, valid[4:]...),
+	} {
+		if err := JSON.ParseBSON(doc).Error(); !errors.Is(err, JSON.ErrSyntax) {
+			t.Errorf("%s: expected ErrSyntax, got %v", name, err)
+		}
+	}
+	if err := JSON.ParseBSON([]byte("\x08\x00\x00\x00\x0fa\x00\x00")).Error(); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for an unsupported type, got %v", err)
+	}
+
+	for _, input := range []string{`[1]`, `{"a\u0000": 1}`, `{"id": {"$oid": "xyz"}}`, `{"d": {"$date": "yesterday"}}`} {
+		if _, err := JSON.Parse(input).ToBSON(); !errors.Is(err, JSON.ErrTypeMismatch) {
+			t.Errorf("%s: expected ErrTypeMismatch, got %v", input, err)
+		}
+	}
+	if _, err := JSON.Parse(`{"d": {"$numberDecimal": "1.00000000000000000000000000000000001"}}`).ToBSON(); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded for an inexact decimal, got %v", err)
+	}
+}
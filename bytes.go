@@ -0,0 +1,58 @@
+package jsjson
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Bytes returns a base64-encoded string value as raw bytes. Both the standard
+// and URL-safe alphabets are accepted, with or without padding, which covers
+// encoding/json's []byte encoding as well as JWT-style segments. Values built
+// with Valid from a []byte are returned as a copy.
+func (j JSONValue) Bytes() ([]byte, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	switch v := j.data.(type) {
+	case string:
+		b, err := decodeBase64(v)
+		if err != nil {
+			return nil, &JSONError{Op: "Bytes", Err: fmt.Errorf("%w: invalid base64 string %q", ErrTypeMismatch, truncate(v, 32))}
+		}
+		return b, nil
+	case []byte:
+		return append([]byte(nil), v...), nil
+	default:
+		return nil, &JSONError{Op: "Bytes", Err: fmt.Errorf("%w: cannot convert %T to bytes", ErrTypeMismatch, v)}
+	}
+}
+
+// BytesOr returns the value as []byte or default if error/conversion fails
+func (j JSONValue) BytesOr(defaultValue []byte) []byte {
+	if b, err := j.Bytes(); err == nil {
+		return b
+	}
+	return defaultValue
+}
+
+// decodeBase64 decodes s with the alphabet and padding it uses
+func decodeBase64(s string) ([]byte, error) {
+	enc := base64.StdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.URLEncoding
+	}
+	if !strings.HasSuffix(s, "=") {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	return enc.Strict().DecodeString(s)
+}
+
+// truncate shortens s for use in error messages
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
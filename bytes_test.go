@@ -0,0 +1,71 @@
+package jsjson_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestBytes(t *testing.T) {
+	// 0xfb 0xff 0xbf encodes to "+/+/" in the standard alphabet
+	obj := JSON.Parse(`{
+		"std": "aGVsbG8=",
+		"raw": "aGVsbG8",
+		"url": "-_-_",
+		"stdSymbols": "+/+/",
+		"empty": "",
+		"invalid": "not base64!",
+		"number": 42
+	}`)
+
+	tests := []struct {
+		key     string
+		want    []byte
+		wantErr bool
+	}{
+		{"std", []byte("hello"), false},
+		{"raw", []byte("hello"), false},
+		{"url", []byte{0xfb, 0xff, 0xbf}, false},
+		{"stdSymbols", []byte{0xfb, 0xff, 0xbf}, false},
+		{"empty", []byte{}, false},
+		{"invalid", nil, true},
+		{"number", nil, true},
+		{"missing", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := obj.Get(tt.key).Bytes()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil || !bytes.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v (err: %v)", tt.want, got, err)
+			}
+		})
+	}
+
+	if _, err := obj.Get("invalid").Bytes(); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got %v", err)
+	}
+	if got := obj.Get("number").BytesOr([]byte("x")); string(got) != "x" {
+		t.Errorf("Expected default, got %q", got)
+	}
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	payload := []byte{0, 1, 2, 0xfe, 0xff}
+	s, err := JSON.Stringify(map[string]interface{}{"blob": payload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := JSON.Parse(s).Get("blob").Bytes()
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Errorf("Expected %v, got %v (err: %v)", payload, got, err)
+	}
+}
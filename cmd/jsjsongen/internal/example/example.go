@@ -0,0 +1,39 @@
+// Package example holds the types the jsjsongen tests generate binders for
+package example
+
+import "time"
+
+//go:generate go run github.com/ktbsomen/jsjson/cmd/jsjsongen -type User,Profile,Base
+
+type User struct {
+	Base
+	Name     string         `json:"name"`
+	Age      int            `json:"age"`
+	Score    float64        `json:"score"`
+	Active   bool           `json:"active"`
+	ID       int64          `json:"id,omitempty"`
+	Joined   time.Time      `json:"joined"`
+	Avatar   []byte         `json:"avatar"`
+	Tags     []string       `json:"tags"`
+	Profile  Profile        `json:"profile"`
+	Manager  *Profile       `json:"manager"`
+	Friends  []Profile      `json:"friends"`
+	Limits   map[string]int `json:"limits"`
+	Level    uint8          `json:"level"`
+	Status   Status         `json:"status"`
+	Nickname string
+	Internal string `json:"-"`
+	secret   string
+}
+
+type Base struct {
+	Kind string `json:"kind"`
+}
+
+type Profile struct {
+	Email string              `json:"email"`
+	Links map[string][]string `json:"links"`
+	Seen  *time.Time          `json:"seen"`
+}
+
+type Status string
@@ -0,0 +1,77 @@
+package example
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+const userJSON = `{
+	"kind": "person", "name": "Ada", "age": 36, "score": 9.5, "active": true,
+	"id": 9007199254740993, "joined": "2023-06-15T10:30:00Z", "avatar": "aGk=",
+	"tags": ["a", "b"], "profile": {"email": "ada@example.com", "links": {"web": ["x"]}},
+	"manager": {"email": "boss@example.com", "seen": "2024-01-02T00:00:00Z"},
+	"friends": [{"email": "f@example.com"}], "limits": {"daily": 5},
+	"level": 3, "status": "ok", "Nickname": "ada", "Internal": "no"
+}`
+
+func TestGeneratedMatchesTo(t *testing.T) {
+	jv := jsjson.Parse(userJSON, jsjson.ParseWithNumbers())
+
+	got, err := UserFromJSON(jv)
+	if err != nil {
+		t.Fatalf("UserFromJSON failed: %v", err)
+	}
+	var want User
+	if err := jv.To(&want); err != nil {
+		t.Fatalf("To failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestGeneratedNullAndMissing(t *testing.T) {
+	if u, err := UserFromJSON(jsjson.Parse(`null`)); err != nil || !reflect.DeepEqual(u, User{}) {
+		t.Errorf("Expected zero value for null, got %+v (err: %v)", u, err)
+	}
+	u, err := UserFromJSON(jsjson.Parse(`{"name": "Ada", "manager": null, "tags": null}`))
+	if err != nil || u.Name != "Ada" || u.Manager != nil || u.Tags != nil {
+		t.Errorf("Unexpected result %+v (err: %v)", u, err)
+	}
+}
+
+func TestGeneratedErrors(t *testing.T) {
+	if _, err := UserFromJSON(jsjson.Parse(`[1]`)); !errors.Is(err, jsjson.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for an array, got %v", err)
+	}
+	if _, err := UserFromJSON(jsjson.Parse(`{"friends": [{"links": 5}]}`)); err == nil {
+		t.Error("Expected error for an object in a string field")
+	}
+	if _, err := UserFromJSON(jsjson.Parse(`{"age": "old"}`)); !errors.Is(err, jsjson.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got %v", err)
+	}
+	bad := jsjson.Parse(`{`)
+	if _, err := UserFromJSON(bad); err != bad.Error() {
+		t.Errorf("Expected the parse error, got %v", err)
+	}
+}
+
+func BenchmarkUserFromJSON(b *testing.B) {
+	jv := jsjson.Parse(userJSON)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		UserFromJSON(jv)
+	}
+}
+
+func BenchmarkUserTo(b *testing.B) {
+	jv := jsjson.Parse(userJSON)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var u User
+		jv.To(&u)
+	}
+}
@@ -0,0 +1,257 @@
+// Code generated by jsjsongen -type User,Profile,Base; DO NOT EDIT.
+
+package example
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+// UserFromJSON binds jv, which must be an object or null, to a User
+func UserFromJSON(jv jsjson.JSONValue) (User, error) {
+	var v User
+	if err := jv.Error(); err != nil {
+		return v, err
+	}
+	if jv.IsNull() {
+		return v, nil
+	}
+	obj, ok := jv.Raw().(map[string]interface{})
+	if !ok {
+		return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("%w: expected object, got %s", jsjson.ErrTypeMismatch, jv.Type())}
+	}
+	var err error
+	if v.Base, err = BaseFromJSON(jv); err != nil {
+		return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "Base", err)}
+	}
+	if raw, ok := obj["name"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Name, err = f.String(); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "name", err)}
+		}
+	}
+	if raw, ok := obj["age"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Age, err = f.Int(); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "age", err)}
+		}
+	}
+	if raw, ok := obj["score"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Score, err = f.Float64(); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "score", err)}
+		}
+	}
+	if raw, ok := obj["active"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Active, err = f.Bool(); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "active", err)}
+		}
+	}
+	if raw, ok := obj["id"]; ok {
+		f := jsjson.Valid(raw)
+		if v.ID, err = f.Int64(); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "id", err)}
+		}
+	}
+	if raw, ok := obj["joined"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Joined, err = f.Time(); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "joined", err)}
+		}
+	}
+	if raw, ok := obj["avatar"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Avatar, err = f.Bytes(); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "avatar", err)}
+		}
+	}
+	if raw, ok := obj["tags"]; ok {
+		f := jsjson.Valid(raw)
+		if f.IsNull() {
+			v.Tags = nil
+		} else {
+			items1, ok := f.Raw().([]interface{})
+			if !ok {
+				_, err = f.Array()
+				return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "tags", err)}
+			}
+			v.Tags = make([]string, len(items1))
+			for i1, item1 := range items1 {
+				elem1 := jsjson.Valid(item1)
+				if v.Tags[i1], err = elem1.String(); err != nil {
+					return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "tags", err)}
+				}
+			}
+		}
+	}
+	if raw, ok := obj["profile"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Profile, err = ProfileFromJSON(f); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "profile", err)}
+		}
+	}
+	if raw, ok := obj["manager"]; ok {
+		f := jsjson.Valid(raw)
+		if f.IsNull() {
+			v.Manager = nil
+		} else {
+			v.Manager = new(Profile)
+			if *v.Manager, err = ProfileFromJSON(f); err != nil {
+				return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "manager", err)}
+			}
+		}
+	}
+	if raw, ok := obj["friends"]; ok {
+		f := jsjson.Valid(raw)
+		if f.IsNull() {
+			v.Friends = nil
+		} else {
+			items2, ok := f.Raw().([]interface{})
+			if !ok {
+				_, err = f.Array()
+				return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "friends", err)}
+			}
+			v.Friends = make([]Profile, len(items2))
+			for i2, item2 := range items2 {
+				elem2 := jsjson.Valid(item2)
+				if v.Friends[i2], err = ProfileFromJSON(elem2); err != nil {
+					return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "friends", err)}
+				}
+			}
+		}
+	}
+	if raw, ok := obj["limits"]; ok {
+		f := jsjson.Valid(raw)
+		if f.IsNull() {
+			v.Limits = nil
+		} else {
+			members3, ok := f.Raw().(map[string]interface{})
+			if !ok {
+				_, err = f.Object()
+				return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "limits", err)}
+			}
+			v.Limits = make(map[string]int, len(members3))
+			for k3, member3 := range members3 {
+				var val3 int
+				elem3 := jsjson.Valid(member3)
+				if val3, err = elem3.Int(); err != nil {
+					return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "limits", err)}
+				}
+				v.Limits[k3] = val3
+			}
+		}
+	}
+	if raw, ok := obj["level"]; ok {
+		f := jsjson.Valid(raw)
+		if err = f.To(&v.Level); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "level", err)}
+		}
+	}
+	if raw, ok := obj["status"]; ok {
+		f := jsjson.Valid(raw)
+		if err = f.To(&v.Status); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "status", err)}
+		}
+	}
+	if raw, ok := obj["Nickname"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Nickname, err = f.String(); err != nil {
+			return v, &jsjson.JSONError{Op: "UserFromJSON", Err: fmt.Errorf("field %q: %w", "Nickname", err)}
+		}
+	}
+	return v, nil
+}
+
+// ProfileFromJSON binds jv, which must be an object or null, to a Profile
+func ProfileFromJSON(jv jsjson.JSONValue) (Profile, error) {
+	var v Profile
+	if err := jv.Error(); err != nil {
+		return v, err
+	}
+	if jv.IsNull() {
+		return v, nil
+	}
+	obj, ok := jv.Raw().(map[string]interface{})
+	if !ok {
+		return v, &jsjson.JSONError{Op: "ProfileFromJSON", Err: fmt.Errorf("%w: expected object, got %s", jsjson.ErrTypeMismatch, jv.Type())}
+	}
+	var err error
+	if raw, ok := obj["email"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Email, err = f.String(); err != nil {
+			return v, &jsjson.JSONError{Op: "ProfileFromJSON", Err: fmt.Errorf("field %q: %w", "email", err)}
+		}
+	}
+	if raw, ok := obj["links"]; ok {
+		f := jsjson.Valid(raw)
+		if f.IsNull() {
+			v.Links = nil
+		} else {
+			members1, ok := f.Raw().(map[string]interface{})
+			if !ok {
+				_, err = f.Object()
+				return v, &jsjson.JSONError{Op: "ProfileFromJSON", Err: fmt.Errorf("field %q: %w", "links", err)}
+			}
+			v.Links = make(map[string][]string, len(members1))
+			for k1, member1 := range members1 {
+				var val1 []string
+				elem1 := jsjson.Valid(member1)
+				if elem1.IsNull() {
+					val1 = nil
+				} else {
+					items2, ok := elem1.Raw().([]interface{})
+					if !ok {
+						_, err = elem1.Array()
+						return v, &jsjson.JSONError{Op: "ProfileFromJSON", Err: fmt.Errorf("field %q: %w", "links", err)}
+					}
+					val1 = make([]string, len(items2))
+					for i2, item2 := range items2 {
+						elem2 := jsjson.Valid(item2)
+						if val1[i2], err = elem2.String(); err != nil {
+							return v, &jsjson.JSONError{Op: "ProfileFromJSON", Err: fmt.Errorf("field %q: %w", "links", err)}
+						}
+					}
+				}
+				v.Links[k1] = val1
+			}
+		}
+	}
+	if raw, ok := obj["seen"]; ok {
+		f := jsjson.Valid(raw)
+		if f.IsNull() {
+			v.Seen = nil
+		} else {
+			v.Seen = new(time.Time)
+			if *v.Seen, err = f.Time(); err != nil {
+				return v, &jsjson.JSONError{Op: "ProfileFromJSON", Err: fmt.Errorf("field %q: %w", "seen", err)}
+			}
+		}
+	}
+	return v, nil
+}
+
+// BaseFromJSON binds jv, which must be an object or null, to a Base
+func BaseFromJSON(jv jsjson.JSONValue) (Base, error) {
+	var v Base
+	if err := jv.Error(); err != nil {
+		return v, err
+	}
+	if jv.IsNull() {
+		return v, nil
+	}
+	obj, ok := jv.Raw().(map[string]interface{})
+	if !ok {
+		return v, &jsjson.JSONError{Op: "BaseFromJSON", Err: fmt.Errorf("%w: expected object, got %s", jsjson.ErrTypeMismatch, jv.Type())}
+	}
+	var err error
+	if raw, ok := obj["kind"]; ok {
+		f := jsjson.Valid(raw)
+		if v.Kind, err = f.String(); err != nil {
+			return v, &jsjson.JSONError{Op: "BaseFromJSON", Err: fmt.Errorf("field %q: %w", "kind", err)}
+		}
+	}
+	return v, nil
+}
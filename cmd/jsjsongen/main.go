@@ -0,0 +1,374 @@
+// Command jsjsongen generates reflection-free binders from jsjson values to
+// Go structs. For every named struct type it emits
+//
+//	func TypeFromJSON(jv jsjson.JSONValue) (Type, error)
+//
+// which looks each field up in the object and reads it with the typed
+// accessors (String, Int64, Time, ...) instead of going through reflection. Fields whose types are
+// also generated call each other's binders; other types fall back to To.
+//
+// Usage, typically from a go:generate directive in the package directory:
+//
+//	//go:generate go run github.com/ktbsomen/jsjson/cmd/jsjsongen -type User,Profile
+//
+// Keys are matched exactly, by the name in the json tag or the field name.
+// Missing keys and null leave the field at its zero value.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("jsjsongen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of struct type names; required")
+	output := flag.String("output", "", "output file name; default <dir>/<type>_jsjson.go")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: jsjsongen -type T[,T...] [-output file] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+
+	outName := *output
+	if outName == "" {
+		outName = filepath.Join(dir, strings.ToLower(types[0])+"_jsjson.go")
+	}
+
+	src, err := generate(dir, types, filepath.Base(outName))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(outName, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate parses the Go package in dir and returns the formatted source of
+// the binders for the named types. The file called skip, the previous output,
+// is not read.
+func generate(dir string, typeNames []string, skip string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != skip
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+
+	structs := make(map[string]*ast.StructType)
+	files := make(map[string]*ast.File)
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if st, ok := ts.Type.(*ast.StructType); ok && ts.TypeParams == nil {
+					structs[ts.Name.Name] = st
+					files[ts.Name.Name] = file
+				}
+			}
+		}
+	}
+
+	g := &generator{generated: make(map[string]bool), imports: map[string]bool{"fmt": true}}
+	for _, name := range typeNames {
+		if structs[name] == nil {
+			return nil, fmt.Errorf("struct type %s not found in %s", name, dir)
+		}
+		g.generated[name] = true
+	}
+	for _, name := range typeNames {
+		if err := g.binder(name, structs[name], files[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by jsjsongen -type %s; DO NOT EDIT.\n\n", strings.Join(typeNames, ","))
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", pkg.Name)
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	for _, path := range imports {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	fmt.Fprintf(&out, "\n\t\"github.com/ktbsomen/jsjson\"\n)\n")
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	buf       bytes.Buffer
+	generated map[string]bool // types getting a binder in this file
+	imports   map[string]bool
+
+	// per binder state
+	file     *ast.File
+	typeName string
+	usesErr  bool
+	usesObj  bool
+	vars     int
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// binder emits TypeFromJSON for one struct
+func (g *generator) binder(name string, st *ast.StructType, file *ast.File) error {
+	var body bytes.Buffer
+	saved := g.buf
+	g.buf = bytes.Buffer{}
+	g.file, g.typeName, g.usesErr, g.usesObj, g.vars = file, name, false, false, 0
+
+	for _, field := range st.Fields.List {
+		if err := g.field(field); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	body, g.buf = g.buf, saved
+
+	g.printf("\n// %sFromJSON binds jv, which must be an object or null, to a %s\n", name, name)
+	g.printf("func %sFromJSON(jv jsjson.JSONValue) (%s, error) {\n", name, name)
+	g.printf("var v %s\n", name)
+	g.printf("if err := jv.Error(); err != nil {\nreturn v, err\n}\n")
+	g.printf("if jv.IsNull() {\nreturn v, nil\n}\n")
+	obj := "_"
+	if g.usesObj {
+		obj = "obj"
+	}
+	g.printf("%s, ok := jv.Raw().(map[string]interface{})\nif !ok {\n", obj)
+	g.printf("return v, &jsjson.JSONError{Op: %q, Err: fmt.Errorf(\"%%w: expected object, got %%s\", jsjson.ErrTypeMismatch, jv.Type())}\n}\n", name+"FromJSON")
+	if g.usesErr {
+		g.printf("var err error\n")
+	}
+	g.buf.Write(body.Bytes())
+	g.printf("return v, nil\n}\n")
+	return nil
+}
+
+// field emits the code binding one struct field
+func (g *generator) field(field *ast.Field) error {
+	var tag string
+	if field.Tag != nil {
+		s, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return err
+		}
+		tag = reflect.StructTag(s).Get("json")
+	}
+	if tag == "-" {
+		return nil
+	}
+	key, _, _ := strings.Cut(tag, ",")
+
+	if len(field.Names) == 0 {
+		// Embedded struct: its fields are promoted into the same object
+		name := typeName(field.Type)
+		if name == "" || !ast.IsExported(name) {
+			return nil
+		}
+		if key == "" {
+			g.usesErr = true
+			if star, ok := field.Type.(*ast.StarExpr); ok {
+				g.printf("v.%s = new(%s)\n", name, g.typeString(star.X))
+				g.value("*v."+name, star.X, "jv", name)
+			} else {
+				g.value("v."+name, field.Type, "jv", name)
+			}
+			return nil
+		}
+		field.Names = []*ast.Ident{ast.NewIdent(name)}
+	}
+
+	for _, ident := range field.Names {
+		if !ident.IsExported() {
+			continue
+		}
+		k := key
+		if k == "" {
+			k = ident.Name
+		}
+		g.usesErr, g.usesObj = true, true
+		g.printf("if raw, ok := obj[%q]; ok {\n", k)
+		g.printf("f := jsjson.Valid(raw)\n")
+		g.value("v."+ident.Name, field.Type, "f", k)
+		g.printf("}\n")
+	}
+	return nil
+}
+
+// value emits code storing the JSONValue src into the addressable
+// expression dst of type typ. key names the field in error messages.
+func (g *generator) value(dst string, typ ast.Expr, src, key string) {
+	accessor := ""
+	switch t := typ.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			accessor = "String"
+		case "bool":
+			accessor = "Bool"
+		case "int":
+			accessor = "Int"
+		case "int64":
+			accessor = "Int64"
+		case "float64":
+			accessor = "Float64"
+		default:
+			if g.generated[t.Name] {
+				g.printf("if %s, err = %sFromJSON(%s); err != nil {\n%s}\n", dst, t.Name, src, g.fail(key))
+				return
+			}
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" && t.Sel.Name == "Time" {
+			accessor = "Time"
+		}
+	case *ast.StarExpr:
+		g.printf("if %s.IsNull() {\n%s = nil\n} else {\n", src, dst)
+		g.printf("%s = new(%s)\n", dst, g.typeString(t.X))
+		g.value("*"+dst, t.X, src, key)
+		g.printf("}\n")
+		return
+	case *ast.ArrayType:
+		if t.Len != nil {
+			break
+		}
+		if elem, ok := t.Elt.(*ast.Ident); ok && elem.Name == "byte" {
+			accessor = "Bytes"
+			break
+		}
+		n := g.nextVar()
+		g.printf("if %s.IsNull() {\n%s = nil\n} else {\n", src, dst)
+		g.printf("items%s, ok := %s.Raw().([]interface{})\nif !ok {\n_, err = %s.Array()\n%s}\n", n, src, src, g.fail(key))
+		g.printf("%s = make(%s, len(items%s))\n", dst, g.typeString(t), n)
+		g.printf("for i%s, item%s := range items%s {\n", n, n, n)
+		g.printf("elem%s := jsjson.Valid(item%s)\n", n, n)
+		g.value(fmt.Sprintf("%s[i%s]", dst, n), t.Elt, "elem"+n, key)
+		g.printf("}\n}\n")
+		return
+	case *ast.MapType:
+		if k, ok := t.Key.(*ast.Ident); !ok || k.Name != "string" {
+			break
+		}
+		n := g.nextVar()
+		g.printf("if %s.IsNull() {\n%s = nil\n} else {\n", src, dst)
+		g.printf("members%s, ok := %s.Raw().(map[string]interface{})\nif !ok {\n_, err = %s.Object()\n%s}\n", n, src, src, g.fail(key))
+		g.printf("%s = make(%s, len(members%s))\n", dst, g.typeString(t), n)
+		g.printf("for k%s, member%s := range members%s {\n", n, n, n)
+		g.printf("var val%s %s\n", n, g.typeString(t.Value))
+		g.printf("elem%s := jsjson.Valid(member%s)\n", n, n)
+		g.value("val"+n, t.Value, "elem"+n, key)
+		g.printf("%s[k%s] = val%s\n}\n}\n", dst, n, n)
+		return
+	}
+
+	if accessor != "" {
+		g.printf("if %s, err = %s.%s(); err != nil {\n%s}\n", dst, src, accessor, g.fail(key))
+		return
+	}
+	g.printf("if err = %s.To(&%s); err != nil {\n%s}\n", src, dst, g.fail(key))
+}
+
+// fail returns the statement reporting err for the field key
+func (g *generator) fail(key string) string {
+	return fmt.Sprintf("return v, &jsjson.JSONError{Op: %q, Err: fmt.Errorf(\"field %%q: %%w\", %q, err)}\n", g.typeName+"FromJSON", key)
+}
+
+func (g *generator) nextVar() string {
+	g.vars++
+	return strconv.Itoa(g.vars)
+}
+
+// typeName returns the name of an embedded field's type
+func typeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return typeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+// typeString formats a type expression for the generated file, importing
+// the packages it refers to
+func (g *generator) typeString(expr ast.Expr) string {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok {
+				g.imports[g.importPath(pkg.Name)] = true
+			}
+			return false
+		}
+		return true
+	})
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), expr); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+// importPath returns the path of the package the current file imports under
+// name
+func (g *generator) importPath(name string) string {
+	for _, spec := range g.file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			if spec.Name.Name == name {
+				return path
+			}
+		} else if path == name || strings.HasSuffix(path, "/"+name) {
+			return path
+		}
+	}
+	return name
+}
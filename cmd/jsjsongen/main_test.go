@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedExampleUpToDate(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	got, err := generate(dir, []string{"User", "Profile", "Base"}, "user_jsjson.go")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join(dir, "user_jsjson.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("internal/example/user_jsjson.go is stale; run go generate ./cmd/jsjsongen/internal/example")
+	}
+}
+
+func TestGenerateUnknownType(t *testing.T) {
+	_, err := generate(filepath.Join("internal", "example"), []string{"Missing"}, "")
+	if err == nil || !strings.Contains(err.Error(), "Missing") {
+		t.Errorf("Expected an error naming the missing type, got %v", err)
+	}
+}
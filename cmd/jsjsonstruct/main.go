@@ -0,0 +1,71 @@
+// Command jsjsonstruct prints Go struct definitions, with json tags, for a
+// sample JSON document read from a file or standard input:
+//
+//	curl -s https://api.example.com/users/1 | jsjsonstruct -type User -package api > user.go
+//
+// Types are inferred as described for jsjson.GenerateStruct; the output is
+// a starting point to edit, not a file to regenerate.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("jsjsonstruct: ")
+
+	typeName := flag.String("type", "", "name of the top-level type; required")
+	pkg := flag.String("package", "main", "package name of the output")
+	output := flag.String("output", "", "output file name; default standard output")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: jsjsonstruct -type T [-package p] [-output file] [sample.json]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeName == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	in := io.Reader(os.Stdin)
+	if flag.NArg() == 1 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	src, err := generate(in, *pkg, *typeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns a Go source file declaring typeName for the sample in r
+func generate(r io.Reader, pkg, typeName string) ([]byte, error) {
+	sample, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	decls, err := jsjson.GenerateStruct(jsjson.Parse(sample, jsjson.ParseWithNumbers()), typeName)
+	if err != nil {
+		return nil, err
+	}
+	return []byte("package " + pkg + "\n\n" + decls), nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	got, err := generate(strings.NewReader(`{"id": 9007199254740993, "name": "x"}`), "api", "User")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	want := "package api\n\ntype User struct {\n\tID   int64  `json:\"id\"`\n\tName string `json:\"name\"`\n}\n"
+	if string(got) != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestGenerateInvalidSample(t *testing.T) {
+	if _, err := generate(strings.NewReader(`{"id": `), "api", "User"); err == nil {
+		t.Error("Expected a parse error")
+	}
+}
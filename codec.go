@@ -0,0 +1,140 @@
+package jsjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Codec is the JSON backend used by Parse, ParseInto, To, Stringify and
+// ParseIntoSlice. Any type with encoding/json-compatible Marshal and
+// Unmarshal methods can be plugged in with SetCodec, for example
+// jsoniter.ConfigCompatibleWithStandardLibrary, or a small adapter around
+// github.com/goccy/go-json or github.com/bytedance/sonic.
+//
+// Canonical hashing (Pack, PatchLog) and ParseWithNumbers always use
+// encoding/json so their output does not depend on the backend.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// IndentCodec is implemented by codecs that can pretty-print natively.
+// StringifyPretty falls back to json.Indent for codecs without it.
+type IndentCodec interface {
+	MarshalIndent(v interface{}, prefix, indent string) ([]byte, error)
+}
+
+// StdCodec returns the default encoding/json backend
+func StdCodec() Codec {
+	return stdCodec{}
+}
+
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (stdCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (stdCodec) MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(v, prefix, indent)
+}
+
+// codecBox gives atomic.Value a single concrete type to store
+type codecBox struct{ Codec }
+
+var activeCodecBox atomic.Value
+
+// SetCodec replaces the JSON backend for the whole package; nil restores
+// the default, StdCodec(). Set it once during program initialization: values parsed by
+// different backends are interchangeable, but swapping while requests are
+// in flight makes behavior depend on timing.
+func SetCodec(c Codec) {
+	if c == nil {
+		c = stdCodec{}
+	}
+	activeCodecBox.Store(codecBox{c})
+}
+
+// activeCodec returns the codec set with SetCodec, or the default
+func activeCodec() Codec {
+	if box, ok := activeCodecBox.Load().(codecBox); ok {
+		return box.Codec
+	}
+	return stdCodec{}
+}
+
+// usingStdCodec reports whether the active codec is encoding/json, which
+// the fast paths that bypass the codec rely on
+func usingStdCodec() bool {
+	_, ok := activeCodec().(stdCodec)
+	return ok
+}
+
+// pooledEncoder is a json.Encoder writing to whichever buffer it is lent
+type pooledEncoder struct {
+	w   bytesWriter
+	enc *json.Encoder
+}
+
+var encoderPool = sync.Pool{
+	New: func() interface{} {
+		e := &pooledEncoder{}
+		e.enc = json.NewEncoder(&e.w)
+		return e
+	},
+}
+
+// encodeInto appends the encoding of v to buf. The standard backend encodes
+// straight into the pooled buffer; other codecs marshal and copy.
+func encodeInto(buf *[]byte, v interface{}) error {
+	if customEncoding() {
+		b, err := appendNamed(*buf, reflect.ValueOf(v))
+		if err != nil {
+			return err
+		}
+		*buf = b
+		return nil
+	}
+
+	c := activeCodec()
+	if _, ok := c.(stdCodec); !ok {
+		b, err := c.Marshal(v)
+		if err != nil {
+			return err
+		}
+		*buf = append(*buf, b...)
+		return nil
+	}
+
+	e := encoderPool.Get().(*pooledEncoder)
+	e.w.buf = buf
+	err := e.enc.Encode(v)
+	e.w.buf = nil
+	encoderPool.Put(e)
+	if err != nil {
+		return err
+	}
+	// Remove trailing newline that encoder adds
+	if n := len(*buf); n > 0 && (*buf)[n-1] == '\n' {
+		*buf = (*buf)[:n-1]
+	}
+	return nil
+}
+
+// marshalIndent pretty-prints v with the active codec
+func marshalIndent(v interface{}, indent string) ([]byte, error) {
+	c := activeCodec()
+	if ic, ok := c.(IndentCodec); ok && !customEncoding() {
+		return ic.MarshalIndent(v, "", indent)
+	}
+	b, err := marshalValue(v)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, b, "", indent); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
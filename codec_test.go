@@ -0,0 +1,74 @@
+package jsjson_test
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+// countingCodec wraps jsoniter and counts calls
+type countingCodec struct {
+	marshal, unmarshal atomic.Int32
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshal.Add(1)
+	return jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshal.Add(1)
+	return jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(data, v)
+}
+
+func TestSetCodec(t *testing.T) {
+	codec := &countingCodec{}
+	JSON.SetCodec(codec)
+	defer JSON.SetCodec(nil)
+
+	obj := JSON.Parse(`{"name":"Ada","tags":["a","b"]}`)
+	if name := obj.Get("name").StringOr(""); name != "Ada" {
+		t.Errorf("Expected Ada, got %q", name)
+	}
+	if codec.unmarshal.Load() != 1 {
+		t.Errorf("Expected Parse to use the codec, got %d unmarshal calls", codec.unmarshal.Load())
+	}
+
+	var dest struct {
+		Tags []string `json:"tags"`
+	}
+	if err := obj.To(&dest); err != nil || len(dest.Tags) != 2 {
+		t.Errorf("Unexpected To result %+v (err: %v)", dest, err)
+	}
+	if err := JSON.ParseInto(`{"tags":["c"]}`, &dest); err != nil || dest.Tags[0] != "c" {
+		t.Errorf("Unexpected ParseInto result %+v (err: %v)", dest, err)
+	}
+
+	s, err := JSON.Stringify(obj.Get("tags"))
+	if err != nil || s != `["a","b"]` {
+		t.Errorf("Expected [\"a\",\"b\"], got %s (err: %v)", s, err)
+	}
+	pretty, err := JSON.StringifyPretty(obj.Get("tags"), "  ")
+	if err != nil || !strings.Contains(pretty, "\n  \"a\"") {
+		t.Errorf("Unexpected pretty output %q (err: %v)", pretty, err)
+	}
+
+	if codec.marshal.Load() < 3 || codec.unmarshal.Load() < 3 {
+		t.Errorf("Expected To, ParseInto and Stringify to use the codec, got %d marshal / %d unmarshal calls",
+			codec.marshal.Load(), codec.unmarshal.Load())
+	}
+}
+
+func TestSetCodecNilRestoresDefault(t *testing.T) {
+	codec := &countingCodec{}
+	JSON.SetCodec(codec)
+	JSON.SetCodec(nil)
+
+	JSON.Parse(`{"a":1}`)
+	if codec.unmarshal.Load() != 0 {
+		t.Error("Expected the default codec after SetCodec(nil)")
+	}
+}
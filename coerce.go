@@ -0,0 +1,312 @@
+package jsjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// -------------------- Coercion --------------------
+
+// NumberOptions controls which formatted numeric strings Int, Int64 and
+// Float64 accept, for data from CSV exports and user input where numbers
+// arrive as "1,234.5", "1 234,5" or "$42". The zero value accepts only plain
+// numbers such as "1234.5".
+//
+//	// US style: "1,234.5", "$42", "-$1,000"
+//	jsjson.SetNumberOptions(jsjson.NumberOptions{GroupSeparators: ",", Currency: true})
+//	// European style: "1 234,5", "1.234,5 €"
+//	eu := jsjson.NumberOptions{GroupSeparators: ". \u00a0", DecimalSeparator: ',', Currency: true}
+//	f, err := row.Get("amount").Float64With(eu)
+//
+// When any formatting option is set, surrounding whitespace is ignored as
+// well.
+type NumberOptions struct {
+	// GroupSeparators lists the characters accepted between digit groups in
+	// the integer part, such as "," or " ", and dropped before conversion.
+	// The first group has one to three digits and every later one exactly
+	// three, so "1,5" is rejected rather than read as 15.
+	GroupSeparators string
+	// DecimalSeparator is the character before the fraction; zero means '.'.
+	// It takes precedence over a group separator that is the same character.
+	DecimalSeparator rune
+	// Currency accepts a currency symbol ($, €, £, ¥ and other Unicode Sc
+	// characters) before or after the number, on either side of its sign
+	Currency bool
+	// Exact makes Int and Int64 fail with ErrTypeMismatch for numbers with a
+	// fractional part instead of truncating them. IntExact and Int64Exact
+	// always behave this way.
+	Exact bool
+}
+
+// defaultNumberOptions holds the package-level options used by Int, Int64
+// and Float64
+var defaultNumberOptions atomic.Pointer[NumberOptions]
+
+// SetNumberOptions sets the package-level options used by Int, Int64,
+// Float64 and their Or variants. It is safe to call concurrently with
+// conversions.
+func SetNumberOptions(o NumberOptions) {
+	defaultNumberOptions.Store(&o)
+}
+
+// numberOptions returns the current package-level options
+func numberOptions() NumberOptions {
+	if o := defaultNumberOptions.Load(); o != nil {
+		return *o
+	}
+	return NumberOptions{}
+}
+
+// IntWith returns the value as int using the given options instead of the
+// package-level ones
+func (j JSONValue) IntWith(o NumberOptions) (int, error) {
+	return j.intWith("IntWith", o)
+}
+
+// IntExact returns the value as int, failing with ErrTypeMismatch instead of
+// truncating when it has a fractional part: 3.0 and 1e3 are integers, 3.99
+// is not. Like Int, it fails with ErrOverflow when the value does not fit.
+func (j JSONValue) IntExact() (int, error) {
+	o := numberOptions()
+	o.Exact = true
+	return j.intWith("IntExact", o)
+}
+
+// Int64Exact is IntExact for int64
+func (j JSONValue) Int64Exact() (int64, error) {
+	o := numberOptions()
+	o.Exact = true
+	return j.int64With("Int64Exact", o)
+}
+
+// Int64With returns the value as int64 using the given options instead of
+// the package-level ones
+func (j JSONValue) Int64With(o NumberOptions) (int64, error) {
+	return j.int64With("Int64With", o)
+}
+
+// Float64With returns the value as float64 using the given options instead
+// of the package-level ones
+func (j JSONValue) Float64With(o NumberOptions) (float64, error) {
+	return j.float64With("Float64With", o)
+}
+
+// normalize rewrites a formatted numeric string as a plain literal for
+// strconv. s is returned unchanged when the options are unset or it does not
+// match them, so conversion errors quote the original text.
+func (o NumberOptions) normalize(s string) string {
+	if o.GroupSeparators == "" && o.DecimalSeparator == 0 && !o.Currency {
+		return s
+	}
+	t := strings.TrimSpace(s)
+	if o.Currency {
+		t = trimCurrency(t)
+	}
+	sign := ""
+	if strings.HasPrefix(t, "-") || strings.HasPrefix(t, "+") {
+		sign, t = t[:1], t[1:]
+		if o.Currency {
+			t = trimCurrency(t)
+		}
+	}
+
+	decimal := o.DecimalSeparator
+	if decimal == 0 {
+		decimal = '.'
+	}
+	runes := []rune(t)
+	var b strings.Builder
+	b.WriteString(sign)
+
+	// The integer part is digits in groups: with separators, the first group
+	// has one to three digits and every later one exactly three
+	i, group, grouped := 0, 0, false
+	for ; i < len(runes); i++ {
+		r := runes[i]
+		if isDigitRune(r) {
+			b.WriteRune(r)
+			group++
+			continue
+		}
+		if r == decimal || !strings.ContainsRune(o.GroupSeparators, r) {
+			break
+		}
+		if group == 0 || group > 3 || (grouped && group != 3) ||
+			i+1 == len(runes) || !isDigitRune(runes[i+1]) {
+			return s
+		}
+		group, grouped = 0, true
+	}
+	if grouped && group != 3 {
+		return s
+	}
+	digits := i > 0
+
+	if i < len(runes) && runes[i] == decimal {
+		b.WriteByte('.')
+		for i++; i < len(runes) && isDigitRune(runes[i]); i++ {
+			b.WriteRune(runes[i])
+			digits = true
+		}
+	}
+	if !digits {
+		return s
+	}
+
+	// An exponent needs at least one digit, after an optional sign
+	if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+		b.WriteByte('e')
+		if i++; i < len(runes) && (runes[i] == '+' || runes[i] == '-') {
+			b.WriteRune(runes[i])
+			i++
+		}
+		start := i
+		for ; i < len(runes) && isDigitRune(runes[i]); i++ {
+			b.WriteRune(runes[i])
+		}
+		if i == start {
+			return s
+		}
+	}
+	if i != len(runes) {
+		return s
+	}
+	return b.String()
+}
+
+// trimCurrency removes currency symbols and the spaces next to them from
+// both ends of s
+func trimCurrency(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return unicode.Is(unicode.Sc, r) || unicode.IsSpace(r)
+	})
+}
+
+func isDigitRune(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// EmptyStringMode selects how String and StringOr treat empty strings
+type EmptyStringMode int
+
+const (
+	// EmptyOrDefault returns an empty string from String, while StringOr
+	// returns its default instead
+	EmptyOrDefault EmptyStringMode = iota
+	// EmptyAsValue treats an empty string as an ordinary value, so StringOr
+	// returns it too
+	EmptyAsValue
+	// EmptyAsMissing treats an empty string as absent: String fails with an
+	// error matching ErrKeyNotFound and StringOr returns its default
+	EmptyAsMissing
+)
+
+// StringOptions controls how String and StringOr convert values. The zero
+// value returns strings unchanged.
+type StringOptions struct {
+	// TrimSpace removes leading and trailing whitespace, before the check
+	// for an empty string, so "  " counts as empty
+	TrimSpace bool
+	// Empty selects how empty strings are treated. null converts to an
+	// empty string and is treated the same way.
+	Empty EmptyStringMode
+}
+
+// defaultStringOptions holds the package-level options used by String and
+// StringOr
+var defaultStringOptions atomic.Pointer[StringOptions]
+
+// SetStringOptions sets the package-level options used by String and
+// StringOr. It is safe to call concurrently with conversions.
+func SetStringOptions(o StringOptions) {
+	defaultStringOptions.Store(&o)
+}
+
+// stringOptions returns the current package-level options
+func stringOptions() StringOptions {
+	if o := defaultStringOptions.Load(); o != nil {
+		return *o
+	}
+	return StringOptions{}
+}
+
+// StringWith returns the value as string using the given options instead of
+// the package-level ones
+func (j JSONValue) StringWith(o StringOptions) (string, error) {
+	return j.stringWith("StringWith", o)
+}
+
+// apply applies the options to a converted string
+func (o StringOptions) apply(op string, s string) (string, error) {
+	if o.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+	if s == "" && o.Empty == EmptyAsMissing {
+		return "", &JSONError{Op: op, Err: fmt.Errorf("%w: empty string", ErrKeyNotFound)}
+	}
+	return s, nil
+}
+
+// BoolOptions controls which strings Bool converts, for config files and
+// form data that write booleans as "yes"/"no", "on"/"off" or "Y"/"N":
+//
+//	jsjson.SetBoolOptions(jsjson.BoolOptions{
+//		TrueStrings:  []string{"yes", "on", "y"},
+//		FalseStrings: []string{"no", "off", "n"},
+//	})
+//
+// Numbers are true when non-zero and null is false, whatever the options.
+type BoolOptions struct {
+	// TrueStrings and FalseStrings are matched case-insensitively, before
+	// the strings strconv.ParseBool accepts ("1", "t", "true", "0", "f",
+	// "false" and their upper-case forms). Set OnlyListed to accept only the
+	// listed strings.
+	TrueStrings  []string
+	FalseStrings []string
+	OnlyListed   bool
+}
+
+// defaultBoolOptions holds the package-level options used by Bool
+var defaultBoolOptions atomic.Pointer[BoolOptions]
+
+// SetBoolOptions sets the package-level options used by Bool and BoolOr.
+// It is safe to call concurrently with conversions.
+func SetBoolOptions(o BoolOptions) {
+	defaultBoolOptions.Store(&o)
+}
+
+// boolOptions returns the current package-level options
+func boolOptions() BoolOptions {
+	if o := defaultBoolOptions.Load(); o != nil {
+		return *o
+	}
+	return BoolOptions{}
+}
+
+// BoolWith returns the value as bool using the given options instead of the
+// package-level ones
+func (j JSONValue) BoolWith(o BoolOptions) (bool, error) {
+	return j.boolWith("BoolWith", o)
+}
+
+// parse converts s as the options allow and reports whether it could
+func (o BoolOptions) parse(s string) (value, ok bool) {
+	for _, t := range o.TrueStrings {
+		if strings.EqualFold(s, t) {
+			return true, true
+		}
+	}
+	for _, f := range o.FalseStrings {
+		if strings.EqualFold(s, f) {
+			return false, true
+		}
+	}
+	if o.OnlyListed {
+		return false, false
+	}
+	b, err := strconv.ParseBool(s)
+	return b, err == nil
+}
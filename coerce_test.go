@@ -0,0 +1,264 @@
+package jsjson_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestFloat64With(t *testing.T) {
+	us := JSON.NumberOptions{GroupSeparators: ",", Currency: true}
+	eu := JSON.NumberOptions{GroupSeparators: ". \u00a0", DecimalSeparator: ',', Currency: true}
+
+	tests := []struct {
+		name  string
+		input string
+		opts  JSON.NumberOptions
+		want  float64
+		fail  bool
+	}{
+		{"plain", "1234.5", JSON.NumberOptions{}, 1234.5, false},
+		{"grouped without options", "1,234.5", JSON.NumberOptions{}, 0, true},
+		{"us grouping", "1,234.5", us, 1234.5, false},
+		{"us currency", "$42", us, 42, false},
+		{"sign before currency", "-$1,000.25", us, -1000.25, false},
+		{"currency before sign", "$-7", us, -7, false},
+		{"surrounding space", "  12 ", us, 12, false},
+		{"eu space grouping", "1 234,5", eu, 1234.5, false},
+		{"eu dot grouping", "1.234.567,89 €", eu, 1234567.89, false},
+		{"eu nbsp grouping", "1\u00a0234", eu, 1234, false},
+		{"doubled separator", "1,,234", us, 0, true},
+		{"trailing separator", "1234,", us, 0, true},
+		{"separator in fraction", "1.2,34", us, 0, true},
+		{"letters", "12abc", us, 0, true},
+		{"currency not allowed", "$42", JSON.NumberOptions{GroupSeparators: ","}, 0, true},
+		{"short group", "1,5", us, 0, true},
+		{"two digit groups", "12,34", us, 0, true},
+		{"long first group", "1234,567", us, 0, true},
+		{"sign inside", "1-2", us, 0, true},
+		{"exponent", "1,234e-2", us, 12.34, false},
+		{"bare exponent", "1e", us, 0, true},
+		{"exponent without mantissa", "e5", us, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSON.Parse(`{"v":` + strconv.Quote(tt.input) + `}`).Get("v").Float64With(tt.opts)
+			if tt.fail {
+				if !errors.Is(err, JSON.ErrTypeMismatch) {
+					t.Errorf("Expected ErrTypeMismatch, got %v (err: %v)", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %v, got %v (err: %v)", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestSetNumberOptions(t *testing.T) {
+	defer JSON.SetNumberOptions(JSON.NumberOptions{})
+
+	obj := JSON.Parse(`{"count":"1,234","price":"$19.99","id":"9,007,199,254,740,993"}`)
+	if _, err := obj.Get("count").Int(); err == nil {
+		t.Fatal("Expected error before setting number options")
+	}
+
+	JSON.SetNumberOptions(JSON.NumberOptions{GroupSeparators: ",", Currency: true})
+	if got, err := obj.Get("count").Int(); err != nil || got != 1234 {
+		t.Errorf("Expected 1234, got %d (err: %v)", got, err)
+	}
+	if got := obj.Get("price").Float64Or(0); got != 19.99 {
+		t.Errorf("Expected 19.99, got %v", got)
+	}
+	if got, err := obj.Get("id").Int64(); err != nil || got != 9007199254740993 {
+		t.Errorf("Expected exact int64, got %d (err: %v)", got, err)
+	}
+	if _, err := obj.Get("price").Int(); err == nil {
+		t.Error("Expected a fraction to fail integer conversion")
+	}
+	if got, err := obj.Get("count").IntWith(JSON.NumberOptions{}); err == nil {
+		t.Errorf("Expected IntWith to ignore the package-level options, got %d", got)
+	}
+}
+
+func TestStringWith(t *testing.T) {
+	obj := JSON.Parse(`{"name":"  Ann  ","blank":"   ","empty":"","none":null,"n":42}`)
+	trimMissing := JSON.StringOptions{TrimSpace: true, Empty: JSON.EmptyAsMissing}
+
+	tests := []struct {
+		key     string
+		opts    JSON.StringOptions
+		want    string
+		missing bool
+	}{
+		{"name", JSON.StringOptions{}, "  Ann  ", false},
+		{"name", JSON.StringOptions{TrimSpace: true}, "Ann", false},
+		{"blank", JSON.StringOptions{TrimSpace: true}, "", false},
+		{"blank", trimMissing, "", true},
+		{"blank", JSON.StringOptions{Empty: JSON.EmptyAsMissing}, "   ", false},
+		{"empty", JSON.StringOptions{Empty: JSON.EmptyAsMissing}, "", true},
+		{"none", JSON.StringOptions{Empty: JSON.EmptyAsMissing}, "", true},
+		{"n", trimMissing, "42", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := obj.Get(tt.key).StringWith(tt.opts)
+			if tt.missing {
+				if !errors.Is(err, JSON.ErrKeyNotFound) {
+					t.Errorf("Expected ErrKeyNotFound, got %q (err: %v)", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %q, got %q (err: %v)", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestSetStringOptions(t *testing.T) {
+	defer JSON.SetStringOptions(JSON.StringOptions{})
+	obj := JSON.Parse(`{"name":" Ann ","empty":"","blank":" "}`)
+
+	// The default keeps the historical StringOr behavior
+	if got := obj.Get("empty").StringOr("n/a"); got != "n/a" {
+		t.Errorf("Expected default for empty string, got %q", got)
+	}
+	if got := obj.Get("blank").StringOr("n/a"); got != " " {
+		t.Errorf("Expected untrimmed value, got %q", got)
+	}
+
+	JSON.SetStringOptions(JSON.StringOptions{Empty: JSON.EmptyAsValue})
+	if got := obj.Get("empty").StringOr("n/a"); got != "" {
+		t.Errorf("Expected empty string as a value, got %q", got)
+	}
+
+	JSON.SetStringOptions(JSON.StringOptions{TrimSpace: true, Empty: JSON.EmptyAsMissing})
+	if got, err := obj.Get("name").String(); err != nil || got != "Ann" {
+		t.Errorf("Expected trimmed name, got %q (err: %v)", got, err)
+	}
+	if _, err := obj.Get("blank").String(); !errors.Is(err, JSON.ErrKeyNotFound) {
+		t.Errorf("Expected blank string to be missing, got %v", err)
+	}
+	if got := obj.Get("blank").StringOr("n/a"); got != "n/a" {
+		t.Errorf("Expected default for blank string, got %q", got)
+	}
+}
+
+func TestBoolWith(t *testing.T) {
+	yesNo := JSON.BoolOptions{TrueStrings: []string{"yes", "on"}, FalseStrings: []string{"no", "off"}}
+	only := yesNo
+	only.OnlyListed = true
+
+	tests := []struct {
+		name  string
+		input string
+		opts  JSON.BoolOptions
+		want  bool
+		fail  bool
+	}{
+		{"default true", `"true"`, JSON.BoolOptions{}, true, false},
+		{"default rejects yes", `"yes"`, JSON.BoolOptions{}, false, true},
+		{"listed true", `"yes"`, yesNo, true, false},
+		{"listed false", `"Off"`, yesNo, false, false},
+		{"parse bool still accepted", `"0"`, yesNo, false, false},
+		{"only listed", `"ON"`, only, true, false},
+		{"only listed rejects others", `"true"`, only, false, true},
+		{"unlisted", `"maybe"`, yesNo, false, true},
+		{"number", `2`, only, true, false},
+		{"null", `null`, only, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSON.Parse(tt.input).BoolWith(tt.opts)
+			if tt.fail {
+				if !errors.Is(err, JSON.ErrTypeMismatch) {
+					t.Errorf("Expected ErrTypeMismatch, got %v (err: %v)", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %v, got %v (err: %v)", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestSetBoolOptions(t *testing.T) {
+	defer JSON.SetBoolOptions(JSON.BoolOptions{})
+	obj := JSON.Parse(`{"debug":"on","cache":"no"}`)
+
+	if got := obj.Get("debug").BoolOr(false); got {
+		t.Fatal("Expected default before setting bool options")
+	}
+	JSON.SetBoolOptions(JSON.BoolOptions{TrueStrings: []string{"on"}, FalseStrings: []string{"no"}})
+	if got, err := obj.Get("debug").Bool(); err != nil || !got {
+		t.Errorf("Expected true, got %v (err: %v)", got, err)
+	}
+	if got := obj.Get("cache").BoolOr(true); got {
+		t.Error("Expected false for \"no\"")
+	}
+}
+
+func TestIntExact(t *testing.T) {
+	input := `{"whole":3.0,"exp":1e3,"frac":3.99,"neg":-2.5,"huge":1e20,"str":"42","strfrac":"4.2","id":9007199254740993,"big":9223372036854775808}`
+	floats := JSON.Parse(input)
+	numbers := JSON.Parse(input, JSON.ParseWithNumbers())
+
+	tests := []struct {
+		key  string
+		want int64
+		fail bool
+	}{
+		{"whole", 3, false},
+		{"exp", 1000, false},
+		{"frac", 0, true},
+		{"neg", 0, true},
+		{"huge", 0, true},
+		{"str", 42, false},
+		{"strfrac", 0, true},
+		{"big", 0, true},
+	}
+	for _, tt := range tests {
+		for mode, obj := range map[string]JSON.JSONValue{"float": floats, "number": numbers} {
+			t.Run(tt.key+"/"+mode, func(t *testing.T) {
+				got, err := obj.Get(tt.key).Int64Exact()
+				if tt.fail {
+					if !errors.Is(err, JSON.ErrTypeMismatch) {
+						t.Errorf("Expected ErrTypeMismatch, got %d (err: %v)", got, err)
+					}
+					return
+				}
+				if err != nil || got != tt.want {
+					t.Errorf("Expected %d, got %d (err: %v)", tt.want, got, err)
+				}
+			})
+		}
+	}
+
+	if id, err := numbers.Get("id").Int64Exact(); err != nil || id != 9007199254740993 {
+		t.Errorf("Expected exact id, got %d (err: %v)", id, err)
+	}
+	if _, err := floats.Get("frac").IntExact(); err == nil || !strings.Contains(err.Error(), "3.99 has a fractional part") {
+		t.Errorf("Expected fractional part error, got %v", err)
+	}
+	if got := floats.Get("frac").IntOr(-1); got != 3 {
+		t.Errorf("Expected Int to keep truncating, got %d", got)
+	}
+
+	defer JSON.SetNumberOptions(JSON.NumberOptions{})
+	JSON.SetNumberOptions(JSON.NumberOptions{Exact: true})
+	if got := floats.Get("frac").IntOr(-1); got != -1 {
+		t.Errorf("Expected strict Int to reject 3.99, got %d", got)
+	}
+	if got, err := floats.Get("whole").Int(); err != nil || got != 3 {
+		t.Errorf("Expected 3, got %d (err: %v)", got, err)
+	}
+	if _, err := JSON.Parse(`" 42 "`).Int(); err == nil {
+		t.Error("Expected Exact alone not to trim strings")
+	}
+}
@@ -0,0 +1,136 @@
+package jsjson
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// -------------------- Colored Output --------------------
+
+// Theme holds the ANSI SGR parameters used to color each kind of token,
+// such as "1;34" for bold blue. An empty field leaves that kind uncolored.
+type Theme struct {
+	Key         string
+	String      string
+	Number      string
+	Bool        string
+	Null        string
+	Punctuation string
+}
+
+var (
+	// DefaultTheme colors output like jq: blue keys, green strings and gray
+	// nulls on the terminal's default color
+	DefaultTheme = Theme{Key: "34;1", String: "0;32", Number: "0;39", Bool: "0;39", Null: "1;30", Punctuation: "1;39"}
+	// BrightTheme gives every kind of token its own color, for dark terminals
+	BrightTheme = Theme{Key: "1;94", String: "92", Number: "96", Bool: "93", Null: "95", Punctuation: "37"}
+	// NoColorTheme writes plain indented JSON, for output that is not a
+	// terminal or when NO_COLOR is set
+	NoColorTheme = Theme{}
+)
+
+// ColorOption configures PrettyColor
+type ColorOption func(*colorOptions)
+
+type colorOptions struct {
+	theme  Theme
+	indent string
+}
+
+// WithTheme sets the colors PrettyColor uses; the default is DefaultTheme
+func WithTheme(t Theme) ColorOption {
+	return func(o *colorOptions) { o.theme = t }
+}
+
+// WithColorIndent sets the indentation PrettyColor uses; the default is two
+// spaces
+func WithColorIndent(indent string) ColorOption {
+	return func(o *colorOptions) { o.indent = indent }
+}
+
+// PrettyColor writes the value as indented JSON with ANSI colors for a
+// terminal, followed by a newline, for CLI tools and debug sessions:
+//
+//	doc.PrettyColor(os.Stdout)
+//	doc.PrettyColor(os.Stdout, jsjson.WithTheme(jsjson.BrightTheme))
+//
+// The text is exactly that of StringifyPretty with the same indent, keys in
+// sorted order, with color codes around each token. Whether the output is a
+// terminal is for the caller to decide; pass NoColorTheme otherwise.
+func (j JSONValue) PrettyColor(w io.Writer, opts ...ColorOption) error {
+	if j.err != nil {
+		return j.err
+	}
+	o := colorOptions{theme: DefaultTheme, indent: "  "}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	text, err := marshalIndent(j.data, o.indent)
+	if err != nil {
+		return &JSONError{Op: "PrettyColor", Err: err}
+	}
+	out := colorize(make([]byte, 0, len(text)*2), text, &o.theme)
+	if _, err := w.Write(append(out, '\n')); err != nil {
+		return &JSONError{Op: "PrettyColor", Err: err}
+	}
+	return nil
+}
+
+// colorize appends the valid JSON text src to dst with each token wrapped
+// in the theme's color
+func colorize(dst, src []byte, t *Theme) []byte {
+	for i := 0; i < len(src); {
+		c := src[i]
+		start := i
+		var color string
+		switch {
+		case c == '"':
+			i++
+			for i < len(src) && src[i] != '"' {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+			color = t.String
+			rest := bytes.TrimLeft(src[i:], " \t\r\n")
+			if len(rest) > 0 && rest[0] == ':' {
+				color = t.Key
+			}
+		case c == '{' || c == '}' || c == '[' || c == ']' || c == ':' || c == ',':
+			i++
+			color = t.Punctuation
+		case c == 't' || c == 'f':
+			for i < len(src) && src[i] >= 'a' && src[i] <= 'z' {
+				i++
+			}
+			color = t.Bool
+		case c == 'n':
+			i += len("null")
+			color = t.Null
+		case c == '-' || (c >= '0' && c <= '9'):
+			for i < len(src) && strings.IndexByte("+-.eE0123456789", src[i]) >= 0 {
+				i++
+			}
+			color = t.Number
+		default:
+			i++
+		}
+		if i > len(src) {
+			i = len(src)
+		}
+		if color == "" {
+			dst = append(dst, src[start:i]...)
+			continue
+		}
+		dst = append(dst, "\x1b["...)
+		dst = append(dst, color...)
+		dst = append(dst, 'm')
+		dst = append(dst, src[start:i]...)
+		dst = append(dst, "\x1b[0m"...)
+	}
+	return dst
+}
@@ -0,0 +1,62 @@
+package jsjson_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestPrettyColor(t *testing.T) {
+	theme := JSON.Theme{Key: "K", String: "S", Number: "N", Bool: "B", Null: "U", Punctuation: "P"}
+	tests := []struct {
+		name  string
+		input string
+		opts  []JSON.ColorOption
+		want  string
+	}{
+		{
+			name:  "every token",
+			input: `{"b": [1.5e3, -2, true, false, null], "a": "x:\"y\""}`,
+			opts:  []JSON.ColorOption{JSON.WithTheme(theme)},
+			want: "\x1b[Pm{\x1b[0m\n" +
+				"  \x1b[Km\"a\"\x1b[0m\x1b[Pm:\x1b[0m \x1b[Sm\"x:\\\"y\\\"\"\x1b[0m\x1b[Pm,\x1b[0m\n" +
+				"  \x1b[Km\"b\"\x1b[0m\x1b[Pm:\x1b[0m \x1b[Pm[\x1b[0m\n" +
+				"    \x1b[Nm1500\x1b[0m\x1b[Pm,\x1b[0m\n" +
+				"    \x1b[Nm-2\x1b[0m\x1b[Pm,\x1b[0m\n" +
+				"    \x1b[Bmtrue\x1b[0m\x1b[Pm,\x1b[0m\n" +
+				"    \x1b[Bmfalse\x1b[0m\x1b[Pm,\x1b[0m\n" +
+				"    \x1b[Umnull\x1b[0m\n" +
+				"  \x1b[Pm]\x1b[0m\n" +
+				"\x1b[Pm}\x1b[0m\n",
+		},
+		{
+			name:  "no color",
+			input: `{"a": [1, "s"]}`,
+			opts:  []JSON.ColorOption{JSON.WithTheme(JSON.NoColorTheme), JSON.WithColorIndent("\t")},
+			want:  "{\n\t\"a\": [\n\t\t1,\n\t\t\"s\"\n\t]\n}\n",
+		},
+		{
+			name:  "default theme",
+			input: `{"k": null}`,
+			want:  "\x1b[1;39m{\x1b[0m\n  \x1b[34;1m\"k\"\x1b[0m\x1b[1;39m:\x1b[0m \x1b[1;30mnull\x1b[0m\n\x1b[1;39m}\x1b[0m\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := JSON.Parse(tt.input).PrettyColor(&buf, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+
+	if err := JSON.Invalid(errors.New("bad")).PrettyColor(&bytes.Buffer{}); err == nil {
+		t.Error("Expected the value's error")
+	}
+}
@@ -0,0 +1,204 @@
+package jsjson
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+)
+
+// -------------------- Compression --------------------
+//
+// Compressed input is recognized by its magic bytes rather than a file
+// name: no JSON text can start with a gzip or zstd header, so the check is
+// unambiguous.
+
+// Compression describes a compression format for ParseReader, ParseFile and
+// WriteFile. gzip is built in; others are added with RegisterCompression.
+type Compression struct {
+	// Name identifies the format in errors, e.g. "zstd"
+	Name string
+	// Ext is the file extension that makes WriteFile compress, e.g. ".zst"
+	Ext string
+	// Magic are the bytes every compressed stream starts with
+	Magic []byte
+	// NewReader returns a reader decompressing r
+	NewReader func(r io.Reader) (io.ReadCloser, error)
+	// NewWriter returns a writer compressing to w; Close must flush it
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+var (
+	compressionsMu sync.RWMutex
+	compressions   = []Compression{{
+		Name:  "gzip",
+		Ext:   ".gz",
+		Magic: []byte{0x1f, 0x8b},
+		NewReader: func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+		NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriter(w), nil
+		},
+	}}
+)
+
+// zstdMagic is recognized even before a zstd Compression is registered, to
+// point at the package that provides it
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// compressedExts lists extensions of common compression formats, with the
+// package providing each one where there is one, so WriteFile refuses to
+// write plain JSON under a name that claims compression
+var compressedExts = map[string]string{
+	".gz":  "compress/gzip",
+	".zst": "github.com/ktbsomen/jsjson/zstd",
+	".bz2": "",
+	".xz":  "",
+	".br":  "",
+	".lz4": "",
+}
+
+// RegisterCompression adds a compression format, replacing any registered
+// format with the same name. The github.com/ktbsomen/jsjson/zstd package
+// registers zstd when imported:
+//
+//	import _ "github.com/ktbsomen/jsjson/zstd"
+//
+// Register formats during program initialization.
+func RegisterCompression(c Compression) {
+	compressionsMu.Lock()
+	defer compressionsMu.Unlock()
+	for i := range compressions {
+		if compressions[i].Name == c.Name {
+			compressions[i] = c
+			return
+		}
+	}
+	compressions = append(compressions, c)
+}
+
+// findCompression returns the registered format matching fn, if any
+func findCompression(match func(Compression) bool) (Compression, bool) {
+	compressionsMu.RLock()
+	defer compressionsMu.RUnlock()
+	for _, c := range compressions {
+		if match(c) {
+			return c, true
+		}
+	}
+	return Compression{}, false
+}
+
+// ParseReader reads r to the end and parses it. Input compressed with gzip,
+// or a registered format such as zstd, is decompressed on the fly. opts
+// apply as in Parse, and WithMaxBytes limits the decompressed size, so a
+// small compressed input cannot expand without bound.
+func ParseReader(r io.Reader, opts ...ParseOption) JSONValue {
+	jv, err := parseStream(r, opts)
+	if err != nil {
+		return JSONValue{err: withOp("ParseReader", err)}
+	}
+	return jv
+}
+
+// parseStream reads, decompresses and parses r. Errors are returned
+// unwrapped so callers can report them under their own name.
+func parseStream(r io.Reader, opts []ParseOption) (JSONValue, error) {
+	o := newParseOptions(opts)
+	b, err := readDecompressed(r, o.maxBytes)
+	if err != nil {
+		return JSONValue{}, err
+	}
+	if o.maxBytes > 0 && int64(len(b)) > o.maxBytes {
+		return JSONValue{}, fmt.Errorf("%w: input is larger than %d bytes", ErrLimitExceeded, o.maxBytes)
+	}
+	// The buffer is ours alone, so the tree can borrow it
+	jv := ParseNoCopy(b, opts...)
+	return jv, jv.err
+}
+
+// readDecompressed reads all of r, decompressing it if it starts with the
+// magic bytes of a registered format. With limit set it stops one byte past
+// limit bytes of output.
+func readDecompressed(r io.Reader, limit int64) ([]byte, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(8)
+
+	var src io.Reader = br
+	if c, ok := findCompression(func(c Compression) bool { return bytes.HasPrefix(head, c.Magic) }); ok {
+		zr, err := c.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrSyntax, c.Name, err)
+		}
+		defer zr.Close()
+		src = zr
+	} else if bytes.HasPrefix(head, zstdMagic) {
+		return nil, fmt.Errorf("%w: zstd-compressed input; import github.com/ktbsomen/jsjson/zstd to read it", ErrSyntax)
+	}
+	if limit > 0 {
+		src = io.LimitReader(src, limit+1)
+	}
+	return io.ReadAll(src)
+}
+
+// CompressTo writes the value as compact JSON compressed with gzip at level
+// (gzip.BestSpeed through gzip.BestCompression, or gzip.DefaultCompression),
+// which ParseReader and ParseFile read back.
+func (j JSONValue) CompressTo(w io.Writer, level int) error {
+	if j.err != nil {
+		return j.err
+	}
+	buf := getBytesBuffer()
+	defer putBytesBuffer(buf)
+	if err := encodeInto(buf, j.data); err != nil {
+		return &JSONError{Op: "CompressTo", Err: err}
+	}
+
+	zw, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return &JSONError{Op: "CompressTo", Err: err}
+	}
+	if _, err := zw.Write(*buf); err != nil {
+		return &JSONError{Op: "CompressTo", Err: err}
+	}
+	if err := zw.Close(); err != nil {
+		return &JSONError{Op: "CompressTo", Err: err}
+	}
+	return nil
+}
+
+// compressForPath compresses data with the registered format whose
+// extension path has, and returns it unchanged if there is none. A known
+// compression extension without a registered format is an error.
+func compressForPath(path string, data []byte) ([]byte, error) {
+	ext := filepath.Ext(path)
+	c, ok := findCompression(func(c Compression) bool { return c.Ext != "" && c.Ext == ext })
+	if !ok {
+		pkg, known := compressedExts[ext]
+		switch {
+		case !known:
+			return data, nil
+		case pkg != "":
+			return nil, fmt.Errorf("no compression registered for %s files; import %s", ext, pkg)
+		default:
+			return nil, fmt.Errorf("no compression registered for %s files; add one with RegisterCompression", ext)
+		}
+	}
+	var out bytes.Buffer
+	zw, err := c.NewWriter(&out)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		zw.Close()
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
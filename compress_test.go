@@ -0,0 +1,131 @@
+package jsjson_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func gzipBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(s))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseReader(t *testing.T) {
+	const doc = `{"level": "info", "msg": "started"}`
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"plain", []byte(doc)},
+		{"gzip", gzipBytes(t, doc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jv := JSON.ParseReader(bytes.NewReader(tt.input))
+			if msg, _ := jv.Get("msg").String(); msg != "started" {
+				t.Errorf("Expected started, got %v (%v)", jv.Raw(), jv.Error())
+			}
+		})
+	}
+}
+
+func TestParseReaderErrors(t *testing.T) {
+	// A megabyte of whitespace compresses to almost nothing
+	bomb := gzipBytes(t, strings.Repeat(" ", 1<<20)+"1")
+
+	tests := []struct {
+		name    string
+		input   []byte
+		opts    []JSON.ParseOption
+		wantErr error
+	}{
+		{"decompressed size limit", bomb, []JSON.ParseOption{JSON.WithMaxBytes(1 << 10)}, JSON.ErrLimitExceeded},
+		{"corrupt gzip", []byte{0x1f, 0x8b, 0, 0}, nil, JSON.ErrSyntax},
+		{"zstd not registered", []byte{0x28, 0xb5, 0x2f, 0xfd, 0, 0}, nil, JSON.ErrSyntax},
+		{"empty", nil, nil, JSON.ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JSON.ParseReader(bytes.NewReader(tt.input), tt.opts...).Error()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if jv := JSON.ParseReader(bytes.NewReader(bomb)); jv.Error() != nil {
+		t.Errorf("Expected the bomb to parse without a limit, got %v", jv.Error())
+	}
+}
+
+func TestCompressTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := JSON.Parse(`{"b": [1, 2], "a": true}`).CompressTo(&buf, gzip.BestCompression); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	out.ReadFrom(zr)
+	if got := out.String(); got != `{"a":true,"b":[1,2]}` {
+		t.Errorf("Unexpected output %s", got)
+	}
+
+	if err := JSON.Parse(`{"a": 1}`).CompressTo(&buf, 42); err == nil {
+		t.Error("Expected an error for an invalid level")
+	}
+}
+
+func TestWriteFileUnregisteredCompression(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"state.json.zst", "state.json.xz"} {
+		path := filepath.Join(dir, name)
+		err := JSON.Parse(`{"a": 1}`).WriteFile(path, 0o644)
+		if err == nil {
+			t.Errorf("%s: expected an error without a registered format", name)
+		}
+		if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+			t.Errorf("%s: expected no file to be written", name)
+		}
+	}
+	err := JSON.Parse(`{}`).WriteFile(filepath.Join(dir, "state.json.zst"), 0o644)
+	if err == nil || !strings.Contains(err.Error(), "github.com/ktbsomen/jsjson/zstd") {
+		t.Errorf("Expected the error to name the zstd package, got %v", err)
+	}
+}
+
+func TestCompressedFiles(t *testing.T) {
+	dir := t.TempDir()
+	jv := JSON.Parse(`{"events": [{"id": 1}, {"id": 2}]}`)
+
+	for _, name := range []string{"log.json.gz", "log.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := jv.WriteFile(path, 0o644); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			raw, _ := os.ReadFile(path)
+			if compressed := raw[0] != '{'; compressed != (filepath.Ext(name) != ".json") {
+				t.Errorf("Unexpected encoding %q", raw[:4])
+			}
+			got := JSON.ParseFile(path)
+			if id, _ := got.Get("events", 1, "id").Int(); id != 2 {
+				t.Errorf("Expected id 2, got %v (%v)", got.Raw(), got.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package jsjson
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// -------------------- Config Loading --------------------
+
+// Loader builds a configuration document from layers, each deep-merged over
+// the ones added before it:
+//
+//	cfg := new(jsjson.Loader).
+//		AddValue(defaults).
+//		AddFile("base.json").
+//		AddFile("override.json").
+//		AddEnv("APP_").
+//		Load()
+//	port := cfg.Get("server", "port").IntOr(8080)
+//
+// Objects are merged key by key; any other value, arrays included, replaces
+// the earlier one. The zero Loader is empty and ready to use. Layers are
+// read when Load is called, so a Loader can be loaded again to pick up
+// changed files and environment.
+type Loader struct {
+	layers []configLayer
+}
+
+type configLayer struct {
+	kind     int
+	value    JSONValue // layerValue
+	file     string    // layerFile
+	optional bool
+	env      string // layerEnv prefix
+}
+
+const (
+	layerValue = iota
+	layerFile
+	layerEnv
+)
+
+// AddValue adds a layer holding jv, typically the built-in defaults
+func (l *Loader) AddValue(jv JSONValue) *Loader {
+	l.layers = append(l.layers, configLayer{kind: layerValue, value: jv})
+	return l
+}
+
+// AddFile adds a layer read from a JSON file. Load fails if the file is
+// missing; see AddOptionalFile.
+func (l *Loader) AddFile(path string) *Loader {
+	l.layers = append(l.layers, configLayer{kind: layerFile, file: path})
+	return l
+}
+
+// AddOptionalFile is like AddFile, but a missing file adds nothing
+func (l *Loader) AddOptionalFile(path string) *Loader {
+	l.layers = append(l.layers, configLayer{kind: layerFile, file: path, optional: true})
+	return l
+}
+
+// AddEnv adds a layer from the environment variables starting with prefix.
+// The rest of each name is a path, matched case-insensitively against the
+// keys of the layers below: with {"server": {"max_conns": 10}} loaded,
+// APP_SERVER_MAX_CONNS=50 sets server.max_conns. Segments that match no
+// existing key start new objects, one per underscore. Values replacing a
+// string stay strings; other values are parsed as JSON when they are valid
+// JSON and kept as strings when not.
+func (l *Loader) AddEnv(prefix string) *Loader {
+	l.layers = append(l.layers, configLayer{kind: layerEnv, env: prefix})
+	return l
+}
+
+// Load reads every layer and returns the merged document, an object. Each
+// file and value layer must hold an object. The first layer that fails to
+// load makes the result an error naming it.
+func (l *Loader) Load() JSONValue {
+	merged := make(map[string]interface{})
+	for _, layer := range l.layers {
+		var (
+			src interface{}
+			err error
+			// name describes the layer in errors
+			name string
+		)
+		switch layer.kind {
+		case layerValue:
+			name = "value"
+			src, err = normalize(layer.value)
+		case layerFile:
+			name = layer.file
+			src, err = loadConfigFile(layer.file, layer.optional)
+		case layerEnv:
+			name = layer.env + "*"
+			src = envLayer(merged, layer.env)
+		}
+		if err == nil && src != nil {
+			if _, ok := src.(map[string]interface{}); !ok {
+				err = fmt.Errorf("%w: expected an object, got %s", ErrTypeMismatch, JSONValue{data: src}.Type())
+			}
+		}
+		if err != nil {
+			return JSONValue{err: &JSONError{Op: "Load", Err: fmt.Errorf("%s: %w", name, err)}}
+		}
+		if src != nil {
+			mergeTrees(merged, src)
+		}
+	}
+	return JSONValue{data: merged}
+}
+
+// loadConfigFile reads a file layer, returning nil for a missing optional
+// file
+func loadConfigFile(path string, optional bool) (interface{}, error) {
+	b, err := os.ReadFile(path)
+	if optional && os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	jv := Parse(b)
+	if jv.err != nil {
+		return nil, jv.err
+	}
+	return jv.data, nil
+}
+
+// envLayer builds the object set by the environment variables with prefix,
+// resolving their names against the keys already in base
+func envLayer(base map[string]interface{}, prefix string) map[string]interface{} {
+	environ := os.Environ()
+	sort.Strings(environ)
+
+	layer := make(map[string]interface{})
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+		path, existing := envPath(base, strings.Split(strings.ToLower(name[len(prefix):]), "_"))
+		obj := layer
+		for _, k := range path[:len(path)-1] {
+			child, ok := obj[k].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				obj[k] = child
+			}
+			obj = child
+		}
+		obj[path[len(path)-1]] = envValue(value, existing)
+	}
+	return layer
+}
+
+// envPath maps underscore-separated name segments to a key path, preferring
+// the longest run of segments that names an existing key at each level. It
+// also returns the value at that path in base, if there is one.
+func envPath(base map[string]interface{}, segments []string) ([]string, interface{}) {
+	var (
+		path    []string
+		current interface{} = base
+	)
+	for len(segments) > 0 {
+		obj, _ := current.(map[string]interface{})
+		n, key := 1, segments[0]
+		for i := len(segments); i > 0; i-- {
+			if k, ok := matchEnvKey(obj, strings.Join(segments[:i], "_")); ok {
+				n, key = i, k
+				break
+			}
+		}
+		path = append(path, key)
+		current = obj[key]
+		segments = segments[n:]
+	}
+	return path, current
+}
+
+// matchEnvKey finds the key of obj that name refers to, ignoring case and
+// treating - and . as _
+func matchEnvKey(obj map[string]interface{}, name string) (string, bool) {
+	if _, ok := obj[name]; ok {
+		return name, true
+	}
+	for k := range obj {
+		if strings.EqualFold(strings.NewReplacer("-", "_", ".", "_").Replace(k), name) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// envValue converts an environment value for the value it replaces
+func envValue(value string, existing interface{}) interface{} {
+	if _, ok := existing.(string); ok {
+		return value
+	}
+	if jv := Parse(value); jv.err == nil {
+		return jv.data
+	}
+	return value
+}
@@ -0,0 +1,101 @@
+package jsjson_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoader(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"base.json":     `{"debug": false, "server": {"host": "localhost", "port": 8080, "max_conns": 10}, "tags": ["a", "b"]}`,
+		"override.json": `{"server": {"host": "0.0.0.0"}, "tags": ["c"]}`,
+	})
+	t.Setenv("APP_DEBUG", "true")
+	t.Setenv("APP_SERVER_MAX_CONNS", "50")
+	t.Setenv("APP_SERVER_HOST", "example.com")
+	t.Setenv("APP_LOG_LEVEL", "info")
+	t.Setenv("OTHER_DEBUG", "ignored")
+
+	cfg := new(JSON.Loader).
+		AddValue(JSON.Parse(`{"timeout": 30, "server": {"port": 80}}`)).
+		AddFile(filepath.Join(dir, "base.json")).
+		AddFile(filepath.Join(dir, "override.json")).
+		AddOptionalFile(filepath.Join(dir, "local.json")).
+		AddEnv("APP_").
+		Load()
+	if err := cfg.Error(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := JSON.Parse(`{
+		"debug": true,
+		"timeout": 30,
+		"server": {"host": "example.com", "port": 8080, "max_conns": 50},
+		"tags": ["c"],
+		"log": {"level": "info"}
+	}`)
+	if !reflect.DeepEqual(cfg.Raw(), want.Raw()) {
+		s, _ := JSON.Stringify(cfg)
+		t.Errorf("Unexpected config %s", s)
+	}
+}
+
+func TestLoaderEnvStrings(t *testing.T) {
+	t.Setenv("APP_VERSION", "1.10")
+	t.Setenv("APP_LIMIT", "1.10")
+	cfg := new(JSON.Loader).AddValue(JSON.Parse(`{"version": "1.0"}`)).AddEnv("APP_").Load()
+
+	if v, err := cfg.Get("version").String(); err != nil || v != "1.10" {
+		t.Errorf("Expected the string 1.10, got %v (%v)", cfg.Get("version").Raw(), err)
+	}
+	if v, err := cfg.Get("limit").Float64(); err != nil || v != 1.1 {
+		t.Errorf("Expected the number 1.1, got %v (%v)", cfg.Get("limit").Raw(), err)
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"bad.json":   `{"server": `,
+		"array.json": `[1, 2]`,
+	})
+	tests := []struct {
+		name    string
+		loader  *JSON.Loader
+		wantErr error
+	}{
+		{"missing file", new(JSON.Loader).AddFile(filepath.Join(dir, "missing.json")), os.ErrNotExist},
+		{"malformed file", new(JSON.Loader).AddFile(filepath.Join(dir, "bad.json")), nil},
+		{"not an object", new(JSON.Loader).AddFile(filepath.Join(dir, "array.json")), JSON.ErrTypeMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.loader.Load().Error()
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(err.Error(), dir) {
+				t.Errorf("Expected the file name in %v", err)
+			}
+		})
+	}
+}
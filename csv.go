@@ -0,0 +1,281 @@
+package jsjson
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// -------------------- CSV --------------------
+
+// CSVOptions controls ToCSV and ParseCSV
+type CSVOptions struct {
+	// Columns names the columns by dotted path (address.city). For ToCSV it
+	// selects and orders them; when empty, the columns are every path found
+	// in the rows: keys of one object in sorted order, new paths appended as
+	// later rows introduce them. For ParseCSV it replaces the header row, and
+	// is required with NoHeader.
+	Columns []string
+	// Comma is the field delimiter; zero means ','
+	Comma rune
+	// NoHeader means there is no header row: ToCSV omits it and ParseCSV
+	// reads the first row as data
+	NoHeader bool
+	// InferTypes makes ParseCSV type each column from its cells: numbers if
+	// every non-empty cell is a JSON number, booleans if every one is true or
+	// false in any case, and strings otherwise. Empty cells of a number or
+	// boolean column become null.
+	InferTypes bool
+}
+
+// ToCSV writes an array of objects as CSV, one row per object. Nested
+// objects are flattened into dotted columns, so {"address": {"city": "Oslo"}}
+// fills the address.city column. Strings are written as they are, numbers
+// and booleans as their JSON text, arrays as JSON text, and null or missing
+// values as empty cells.
+func (j JSONValue) ToCSV(w io.Writer, opts CSVOptions) error {
+	if j.err != nil {
+		return j.err
+	}
+
+	rows, columns, err := flattenRows(j.data)
+	if err != nil {
+		return &JSONError{Op: "ToCSV", Err: err}
+	}
+	if len(opts.Columns) > 0 {
+		columns = opts.Columns
+	}
+
+	records := make([][]string, 0, len(rows)+1)
+	if !opts.NoHeader {
+		records = append(records, columns)
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			if record[i], err = csvCell(row[col]); err != nil {
+				return &JSONError{Op: "ToCSV", Err: err}
+			}
+		}
+		records = append(records, record)
+	}
+
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return &JSONError{Op: "ToCSV", Err: err}
+	}
+	return nil
+}
+
+// flattenRows flattens an array of objects into rows keyed by dotted column
+// name. columns lists every column found: keys of one object in sorted
+// order, new columns appended as later rows introduce them.
+func flattenRows(data interface{}) (rows []map[string]interface{}, columns []string, err error) {
+	data, err = normalize(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, ok := data.([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: expected an array of objects, got %s", ErrTypeMismatch, JSONValue{data: data}.Type())
+	}
+
+	rows = make([]map[string]interface{}, len(items))
+	seen := make(map[string]bool)
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: row %d is %s, not an object", ErrTypeMismatch, i, JSONValue{data: item}.Type())
+		}
+		rows[i] = make(map[string]interface{})
+		flattenObject(rows[i], "", obj, &columns, seen)
+	}
+	return rows, columns, nil
+}
+
+// flattenObject stores the leaves of obj in row under dotted column names,
+// recording columns not seen before in order. Empty objects are leaves.
+func flattenObject(row map[string]interface{}, prefix string, obj map[string]interface{}, columns *[]string, seen map[string]bool) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		col := k
+		if prefix != "" {
+			col = prefix + "." + k
+		}
+		if nested, ok := obj[k].(map[string]interface{}); ok && len(nested) > 0 {
+			flattenObject(row, col, nested, columns, seen)
+			continue
+		}
+		row[col] = obj[k]
+		if !seen[col] {
+			seen[col] = true
+			*columns = append(*columns, col)
+		}
+	}
+}
+
+// csvCell formats a leaf value for a CSV cell
+func csvCell(v interface{}) (string, error) {
+	switch c := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return c, nil
+	case float64:
+		return strconv.FormatFloat(c, 'f', -1, 64), nil
+	default:
+		var b []byte
+		if err := encodeInto(&b, c); err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
+
+// ParseCSV reads CSV into an array of objects, one per row, so spreadsheets
+// can enter the same pipeline as JSON payloads. The header row names the
+// keys, and dotted names nest like ToCSV writes them: an address.city column
+// fills {"address": {"city": ...}}. Cells are strings unless opts.InferTypes
+// is set. Rows must all have as many cells as there are columns.
+func ParseCSV(r io.Reader, opts CSVOptions) JSONValue {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	records, err := cr.ReadAll()
+	if err != nil {
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			err = fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		return JSONValue{err: &JSONError{Op: "ParseCSV", Err: err}}
+	}
+
+	columns := opts.Columns
+	if !opts.NoHeader && len(records) > 0 {
+		if len(columns) == 0 {
+			columns = records[0]
+		} else if len(columns) != len(records[0]) {
+			return JSONValue{err: &JSONError{Op: "ParseCSV", Err: fmt.Errorf("%d columns given for %d header cells", len(columns), len(records[0]))}}
+		}
+		records = records[1:]
+	}
+	if opts.NoHeader && len(columns) == 0 {
+		return JSONValue{err: &JSONError{Op: "ParseCSV", Err: fmt.Errorf("NoHeader requires Columns")}}
+	}
+	if len(records) > 0 && len(records[0]) != len(columns) {
+		return JSONValue{err: &JSONError{Op: "ParseCSV", Err: fmt.Errorf("%w: %d cells for %d columns", ErrSyntax, len(records[0]), len(columns))}}
+	}
+
+	paths, err := csvPaths(columns)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseCSV", Err: err}}
+	}
+	kinds := make([]string, len(columns))
+	for i := range columns {
+		kinds[i] = "string"
+		if opts.InferTypes {
+			kinds[i] = csvColumnKind(records, i)
+		}
+	}
+
+	rows := make([]interface{}, len(records))
+	for n, record := range records {
+		row := make(map[string]interface{})
+		for i, cell := range record {
+			obj := row
+			path := paths[i]
+			for _, k := range path[:len(path)-1] {
+				child, ok := obj[k].(map[string]interface{})
+				if !ok {
+					child = make(map[string]interface{})
+					obj[k] = child
+				}
+				obj = child
+			}
+			obj[path[len(path)-1]] = csvValue(cell, kinds[i])
+		}
+		rows[n] = row
+	}
+	return JSONValue{data: rows}
+}
+
+// csvPaths splits the column names into key paths, rejecting a column that
+// would have to be both a value and an object
+func csvPaths(columns []string) ([][]string, error) {
+	names := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		if names[col] {
+			return nil, fmt.Errorf("duplicate column %q", col)
+		}
+		names[col] = true
+	}
+	paths := make([][]string, len(columns))
+	for i, col := range columns {
+		paths[i] = strings.Split(col, ".")
+		for j := 1; j < len(paths[i]); j++ {
+			if prefix := strings.Join(paths[i][:j], "."); names[prefix] {
+				return nil, fmt.Errorf("column %q conflicts with column %q", col, prefix)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// csvColumnKind infers the type of column i from its non-empty cells
+func csvColumnKind(records [][]string, i int) string {
+	numbers, bools, cells := true, true, 0
+	for _, record := range records {
+		cell := record[i]
+		if cell == "" {
+			continue
+		}
+		cells++
+		if numbers && !isJSONNumber(cell) {
+			numbers = false
+		}
+		if bools && !strings.EqualFold(cell, "true") && !strings.EqualFold(cell, "false") {
+			bools = false
+		}
+	}
+	switch {
+	case cells == 0:
+		return "string"
+	case numbers:
+		return "number"
+	case bools:
+		return "boolean"
+	}
+	return "string"
+}
+
+// csvValue converts a cell to the tree value for its column kind
+func csvValue(cell, kind string) interface{} {
+	if cell == "" && kind != "string" {
+		return nil
+	}
+	switch kind {
+	case "number":
+		if f, err := strconv.ParseFloat(cell, 64); err == nil {
+			return f
+		}
+		// Out of float64 range: keep the literal
+		return json.Number(cell)
+	case "boolean":
+		return strings.EqualFold(cell, "true")
+	}
+	return cell
+}
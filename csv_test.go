@@ -0,0 +1,135 @@
+package jsjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+const csvUsers = `[
+	{"name": "Ada", "age": 36, "address": {"city": "London", "zip": "N1"}, "tags": ["math", "code"]},
+	{"name": "Linus, Jr.", "active": true, "address": {"city": "Helsinki"}, "score": 1e21},
+	{"name": "Grace", "age": null, "meta": {}}
+]`
+
+func TestToCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		opts JSON.CSVOptions
+		want string
+	}{
+		{"derived columns", JSON.CSVOptions{},
+			"address.city,address.zip,age,name,tags,active,score,meta\n" +
+				"London,N1,36,Ada,\"[\"\"math\"\",\"\"code\"\"]\",,,\n" +
+				"Helsinki,,,\"Linus, Jr.\",,true,1000000000000000000000,\n" +
+				",,,Grace,,,,{}\n"},
+		{"explicit columns", JSON.CSVOptions{Columns: []string{"name", "address.city", "missing"}},
+			"name,address.city,missing\nAda,London,\n\"Linus, Jr.\",Helsinki,\nGrace,,\n"},
+		{"delimiter without header", JSON.CSVOptions{Columns: []string{"name", "age"}, Comma: ';', NoHeader: true},
+			"Ada;36\nLinus, Jr.;\nGrace;\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := JSON.Parse(csvUsers).ToCSV(&sb, tt.opts); err != nil {
+				t.Fatalf("ToCSV failed: %v", err)
+			}
+			if sb.String() != tt.want {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.want, sb.String())
+			}
+		})
+	}
+}
+
+func TestToCSVErrors(t *testing.T) {
+	var sb strings.Builder
+	if err := JSON.Parse(`{"a": 1}`).ToCSV(&sb, JSON.CSVOptions{}); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for an object, got %v", err)
+	}
+	if err := JSON.Parse(`[{"a": 1}, 2]`).ToCSV(&sb, JSON.CSVOptions{}); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for a scalar row, got %v", err)
+	}
+	if sb.Len() != 0 {
+		t.Errorf("Expected nothing written on error, got %q", sb.String())
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	const input = "name,age,active,zip,address.city,address.country\n" +
+		"Ada,36,true,00123,London,UK\n" +
+		"\"Linus, Jr.\",,FALSE,,Helsinki,\n"
+
+	tests := []struct {
+		name string
+		in   string
+		opts JSON.CSVOptions
+		want string
+	}{
+		{"strings", input, JSON.CSVOptions{},
+			`[{"name":"Ada","age":"36","active":"true","zip":"00123","address":{"city":"London","country":"UK"}},` +
+				`{"name":"Linus, Jr.","age":"","active":"FALSE","zip":"","address":{"city":"Helsinki","country":""}}]`},
+		{"inferred types", input, JSON.CSVOptions{InferTypes: true},
+			`[{"name":"Ada","age":36,"active":true,"zip":"00123","address":{"city":"London","country":"UK"}},` +
+				`{"name":"Linus, Jr.","age":null,"active":false,"zip":"","address":{"city":"Helsinki","country":""}}]`},
+		{"explicit columns", "a;b\n1;2\n", JSON.CSVOptions{Columns: []string{"x", "y.z"}, Comma: ';', InferTypes: true},
+			`[{"x":1,"y":{"z":2}}]`},
+		{"no header", "1,2e400\n", JSON.CSVOptions{Columns: []string{"x", "big"}, NoHeader: true, InferTypes: true},
+			`[{"x":1,"big":2e400}]`},
+		{"header only", "a,b\n", JSON.CSVOptions{}, `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := JSON.ParseCSV(strings.NewReader(tt.in), tt.opts)
+			if got.Error() != nil {
+				t.Fatalf("ParseCSV failed: %v", got.Error())
+			}
+			gotStr, _ := JSON.Stringify(got)
+			wantStr, _ := JSON.Stringify(JSON.Parse(tt.want, JSON.ParseWithNumbers()))
+			if gotStr != wantStr {
+				t.Errorf("Expected %s, got %s", wantStr, gotStr)
+			}
+		})
+	}
+}
+
+func TestParseCSVRoundTrip(t *testing.T) {
+	var sb strings.Builder
+	if err := JSON.Parse(csvUsers).ToCSV(&sb, JSON.CSVOptions{Columns: []string{"name", "age", "address.city"}}); err != nil {
+		t.Fatalf("ToCSV failed: %v", err)
+	}
+	back := JSON.ParseCSV(strings.NewReader(sb.String()), JSON.CSVOptions{InferTypes: true})
+	if back.Get(0, "age").IntOr(0) != 36 || back.Get(1, "address", "city").StringOr("") != "Helsinki" || !back.Get(2, "age").IsNull() {
+		t.Errorf("Unexpected round trip %v", back.Raw())
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		opts   JSON.CSVOptions
+		syntax bool
+	}{
+		{"ragged rows", "a,b\n1\n", JSON.CSVOptions{}, true},
+		{"bad quoting", "a\n\"x\"y\n", JSON.CSVOptions{}, true},
+		{"column count", "a,b\n1,2\n", JSON.CSVOptions{Columns: []string{"x"}}, false},
+		{"no header without columns", "1,2\n", JSON.CSVOptions{NoHeader: true}, false},
+		{"duplicate column", "a,a\n1,2\n", JSON.CSVOptions{}, false},
+		{"conflicting columns", "a,a.b\n1,2\n", JSON.CSVOptions{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JSON.ParseCSV(strings.NewReader(tt.in), tt.opts).Error()
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if errors.Is(err, JSON.ErrSyntax) != tt.syntax {
+				t.Errorf("Unexpected error %v", err)
+			}
+		})
+	}
+}
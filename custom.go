@@ -0,0 +1,168 @@
+package jsjson
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// -------------------- Custom Type Codecs --------------------
+
+// typeRegistry holds the registered encoders and decoders. It is replaced
+// as a whole on every registration, so readers never lock.
+type typeRegistry struct {
+	encoders map[reflect.Type]func(reflect.Value) (interface{}, error)
+	decoders map[reflect.Type]func(interface{}) (reflect.Value, error)
+}
+
+var (
+	registryBox atomic.Value // *typeRegistry
+	registryMu  sync.Mutex
+)
+
+// RegisterEncoder makes Stringify, StringifyPretty and Parse of Go values
+// encode every value of type T as whatever fn returns, so domain types such
+// as money amounts, IDs or enums serialize the same way everywhere:
+//
+//	jsjson.RegisterEncoder(func(m Money) (interface{}, error) {
+//	    return m.String(), nil // "12.50 EUR"
+//	})
+//
+// The match is on the exact type T, wherever it appears: at the top level,
+// in struct fields, or inside slices, maps and interfaces. fn's result is
+// encoded in turn, so it must not return a T. A registered encoder takes
+// precedence over MarshalJSON, and a nil fn removes the registration.
+// Register encoders during program initialization.
+func RegisterEncoder[T any](fn func(T) (interface{}, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	updateRegistry(func(r *typeRegistry) {
+		if fn == nil {
+			delete(r.encoders, t)
+			return
+		}
+		r.encoders[t] = func(v reflect.Value) (interface{}, error) {
+			return fn(v.Interface().(T))
+		}
+	})
+}
+
+// RegisterDecoder makes To, ParseInto, As and GetAs build every value of
+// type T with fn, which receives the JSON value being decoded:
+//
+//	jsjson.RegisterDecoder(func(jv jsjson.JSONValue) (Money, error) {
+//	    s, err := jv.String()
+//	    if err != nil {
+//	        return Money{}, err
+//	    }
+//	    return ParseMoney(s)
+//	})
+//
+// As with encoding/json, null leaves a T unchanged and sets a *T to nil, so
+// fn never sees null. An error from fn is returned by the call that was
+// decoding. A registered decoder takes precedence over UnmarshalJSON, and a
+// nil fn removes the registration. Register decoders during program
+// initialization.
+func RegisterDecoder[T any](fn func(JSONValue) (T, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	updateRegistry(func(r *typeRegistry) {
+		if fn == nil {
+			delete(r.decoders, t)
+			return
+		}
+		r.decoders[t] = func(data interface{}) (reflect.Value, error) {
+			out, err := fn(JSONValue{data: data})
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(&out).Elem(), nil
+		}
+	})
+}
+
+// updateRegistry applies fn to a copy of the registry and publishes it
+func updateRegistry(fn func(*typeRegistry)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	next := &typeRegistry{
+		encoders: make(map[reflect.Type]func(reflect.Value) (interface{}, error)),
+		decoders: make(map[reflect.Type]func(interface{}) (reflect.Value, error)),
+	}
+	if cur := loadRegistry(); cur != nil {
+		for t, enc := range cur.encoders {
+			next.encoders[t] = enc
+		}
+		for t, dec := range cur.decoders {
+			next.decoders[t] = dec
+		}
+	}
+	fn(next)
+	registryBox.Store(next)
+	// Binding plans embed the decoders
+	resetTypeCaches()
+}
+
+func loadRegistry() *typeRegistry {
+	r, _ := registryBox.Load().(*typeRegistry)
+	return r
+}
+
+// encoderFor returns the registered encoder for t, or nil
+func encoderFor(t reflect.Type) func(reflect.Value) (interface{}, error) {
+	if r := loadRegistry(); r != nil {
+		return r.encoders[t]
+	}
+	return nil
+}
+
+// decoderFor returns the registered decoder for t, or nil
+func decoderFor(t reflect.Type) func(interface{}) (reflect.Value, error) {
+	if r := loadRegistry(); r != nil {
+		return r.decoders[t]
+	}
+	return nil
+}
+
+// customEncoding reports whether encoding must go through appendNamed
+// because field names or registered encoders change the output
+func customEncoding() bool {
+	if fieldNamer() != nil {
+		return true
+	}
+	r := loadRegistry()
+	return r != nil && len(r.encoders) > 0
+}
+
+// customDecoding reports whether decoding must go through the binder
+// because field names or registered decoders change the result
+func customDecoding() bool {
+	if fieldNamer() != nil {
+		return true
+	}
+	r := loadRegistry()
+	return r != nil && len(r.decoders) > 0
+}
+
+// decoderBinder binds with a registered decoder. Its errors are final:
+// they are not a reason to fall back to the codec.
+func decoderBinder(dec func(interface{}) (reflect.Value, error)) bindFunc {
+	return func(v reflect.Value, data interface{}) error {
+		if data == nil {
+			return nil
+		}
+		out, err := dec(data)
+		if err != nil {
+			return &decoderError{err: err}
+		}
+		v.Set(out)
+		return nil
+	}
+}
+
+// decoderError carries an error returned by a registered decoder
+type decoderError struct {
+	err error
+}
+
+func (e *decoderError) Error() string { return e.err.Error() }
+func (e *decoderError) Unwrap() error { return e.err }
@@ -0,0 +1,127 @@
+package jsjson_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+// customMoney is stored in cents and written as "12.50"
+type customMoney int64
+
+type customLevel int
+
+type customOrder struct {
+	ID     string                 `json:"id"`
+	Total  customMoney            `json:"total"`
+	Items  []customMoney          `json:"items"`
+	Tip    *customMoney           `json:"tip"`
+	ByName map[string]customLevel `json:"by_name"`
+	Level  customLevel            `json:"level"`
+	Note   interface{}            `json:"note"`
+}
+
+var levelNames = []string{"low", "high"}
+
+func registerCustomTypes(t *testing.T) {
+	JSON.RegisterEncoder(func(m customMoney) (interface{}, error) {
+		return fmt.Sprintf("%d.%02d", m/100, m%100), nil
+	})
+	JSON.RegisterDecoder(func(jv JSON.JSONValue) (customMoney, error) {
+		s, err := jv.String()
+		if err != nil {
+			return 0, err
+		}
+		var whole, cents int64
+		if _, err := fmt.Sscanf(s, "%d.%02d", &whole, &cents); err != nil {
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+		return customMoney(whole*100 + cents), nil
+	})
+	JSON.RegisterEncoder(func(l customLevel) (interface{}, error) {
+		if int(l) >= len(levelNames) {
+			return nil, fmt.Errorf("unknown level %d", int(l))
+		}
+		return levelNames[l], nil
+	})
+	JSON.RegisterDecoder(func(jv JSON.JSONValue) (customLevel, error) {
+		for i, name := range levelNames {
+			if jv.StringOr("") == name {
+				return customLevel(i), nil
+			}
+		}
+		return 0, errors.New("unknown level")
+	})
+	t.Cleanup(func() {
+		JSON.RegisterEncoder[customMoney](nil)
+		JSON.RegisterDecoder[customMoney](nil)
+		JSON.RegisterEncoder[customLevel](nil)
+		JSON.RegisterDecoder[customLevel](nil)
+	})
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	registerCustomTypes(t)
+
+	tip := customMoney(5)
+	order := customOrder{
+		ID: "o1", Total: 1250, Items: []customMoney{1000, 250}, Tip: &tip,
+		ByName: map[string]customLevel{"a": 1}, Note: customMoney(1),
+	}
+	want := `{"id":"o1","total":"12.50","items":["10.00","2.50"],"tip":"0.05","by_name":{"a":"high"},"level":"low","note":"0.01"}`
+	if got, err := JSON.Stringify(order); err != nil || got != want {
+		t.Errorf("Stringify:\nexpected %s\ngot      %s (%v)", want, got, err)
+	}
+	if got, _ := JSON.Stringify(customMoney(7)); got != `"0.07"` {
+		t.Errorf("Expected a top-level encoding, got %s", got)
+	}
+	if got := JSON.Parse(order).Get("total").StringOr(""); got != "12.50" {
+		t.Errorf("Parse did not use the encoder, got %q", got)
+	}
+
+	_, err := JSON.Stringify(customOrder{Level: 9})
+	if err == nil || !strings.Contains(err.Error(), "unknown level 9") {
+		t.Errorf("Expected the encoder's error, got %v", err)
+	}
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	registerCustomTypes(t)
+	input := `{"id":"o1","total":"12.50","items":["10.00","2.50"],"tip":null,"by_name":{"a":"high"},"level":"high"}`
+
+	check := func(via string, got customOrder, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s failed: %v", via, err)
+		}
+		if got.Total != 1250 || len(got.Items) != 2 || got.Items[1] != 250 || got.Tip != nil ||
+			got.ByName["a"] != 1 || got.Level != 1 {
+			t.Errorf("%s: unexpected result %+v", via, got)
+		}
+	}
+
+	var viaTo customOrder
+	check("To", viaTo, JSON.Parse(input).To(&viaTo))
+	var viaInto customOrder
+	check("ParseInto", viaInto, JSON.ParseInto(input, &viaInto))
+	viaAs, err := JSON.As[customOrder](JSON.Parse(input))
+	check("As", viaAs, err)
+
+	if m, err := JSON.GetAs[customMoney](JSON.Parse(input), "items", 0); err != nil || m != 1000 {
+		t.Errorf("GetAs: expected 1000, got %v (%v)", m, err)
+	}
+
+	err = JSON.ParseInto(`{"total": "lots"}`, &customOrder{})
+	if err == nil || !strings.Contains(err.Error(), `invalid amount "lots"`) {
+		t.Errorf("Expected the decoder's error, got %v", err)
+	}
+
+	// Unregistered again, the types use their underlying encoding
+	JSON.RegisterEncoder[customMoney](nil)
+	if got, _ := JSON.Stringify(customMoney(7)); got != "7" {
+		t.Errorf("Expected the default encoding, got %s", got)
+	}
+}
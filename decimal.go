@@ -0,0 +1,200 @@
+package jsjson
+
+import (
+	"cmp"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact base-10 number equal to Coefficient × 10^Exponent.
+// Trailing zeros of the original literal are kept, so 2.50 is {250, -2}.
+type Decimal struct {
+	Coefficient *big.Int
+	Exponent    int32
+}
+
+// maxDecimalPadding bounds how many zeros String pads before switching to
+// exponent notation
+const maxDecimalPadding = 32
+
+// maxDecimalExponent bounds the exponent of parsed decimals so that
+// 10^|Exponent| has at most maxBigIntBits bits, guarding Rat against
+// exponent bombs as BigInt does
+const maxDecimalExponent = maxBigIntBits * 3 / 10
+
+// ParseDecimal parses a JSON number literal (or a plain decimal string) into
+// an exact Decimal
+func ParseDecimal(s string) (Decimal, error) {
+	lit := s
+	exp := int64(0)
+	if i := strings.IndexAny(lit, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(lit[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("%w: invalid exponent in %q", ErrTypeMismatch, s)
+		}
+		exp = e
+		lit = lit[:i]
+	}
+	if i := strings.IndexByte(lit, '.'); i >= 0 {
+		frac := lit[i+1:]
+		if frac == "" {
+			return Decimal{}, fmt.Errorf("%w: invalid decimal %q", ErrTypeMismatch, s)
+		}
+		exp -= int64(len(frac))
+		lit = lit[:i] + frac
+	}
+	if exp < -maxDecimalExponent || exp > maxDecimalExponent {
+		return Decimal{}, fmt.Errorf("%w: exponent of %q exceeds %d", ErrLimitExceeded, s, maxDecimalExponent)
+	}
+
+	digits := strings.TrimPrefix(strings.TrimPrefix(lit, "-"), "+")
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, fmt.Errorf("%w: invalid decimal %q", ErrTypeMismatch, s)
+	}
+	coef, ok := new(big.Int).SetString(lit, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: invalid decimal %q", ErrSyntax, s)
+	}
+	return Decimal{Coefficient: coef, Exponent: int32(exp)}, nil
+}
+
+// String returns the decimal in plain notation (e.g. "0.10", "1200"),
+// falling back to exponent notation for very large or small exponents
+func (d Decimal) String() string {
+	if d.Coefficient == nil {
+		return "0"
+	}
+	digits := d.Coefficient.String()
+	sign := ""
+	if digits[0] == '-' {
+		sign, digits = "-", digits[1:]
+	}
+
+	switch {
+	case d.Exponent == 0:
+		return sign + digits
+	case d.Exponent > 0:
+		if d.Exponent > maxDecimalPadding {
+			return fmt.Sprintf("%s%se%d", sign, digits, d.Exponent)
+		}
+		return sign + digits + strings.Repeat("0", int(d.Exponent))
+	default:
+		scale := int(-d.Exponent)
+		if scale > len(digits)+maxDecimalPadding {
+			return fmt.Sprintf("%s%se%d", sign, digits, d.Exponent)
+		}
+		if scale >= len(digits) {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		point := len(digits) - scale
+		return sign + digits[:point] + "." + digits[point:]
+	}
+}
+
+// MarshalJSON writes the decimal as a JSON number literal
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Rat returns the decimal as an exact rational number. Its size grows with
+// the exponent, which ParseDecimal bounds.
+func (d Decimal) Rat() *big.Rat {
+	r := new(big.Rat)
+	if d.Coefficient == nil {
+		return r
+	}
+	r.SetInt(d.Coefficient)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs32(d.Exponent))), nil)
+	if d.Exponent >= 0 {
+		return r.Mul(r, new(big.Rat).SetInt(scale))
+	}
+	return r.Quo(r, new(big.Rat).SetInt(scale))
+}
+
+// Float64 returns the nearest float64 to the decimal
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.scientific(), 64)
+	return f
+}
+
+// Cmp compares two decimals by value and returns -1, 0 or +1. Decimals of
+// different magnitude are told apart by their exponents, so the work done
+// depends on the number of digits, not on the exponents.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := d.coefficient(), other.coefficient()
+	if a.Sign() != b.Sign() || a.Sign() == 0 {
+		return cmp.Compare(int64(a.Sign()), int64(b.Sign()))
+	}
+	// The position of the leading digit decides unless it is the same
+	digitsA := int64(len(new(big.Int).Abs(a).String()))
+	digitsB := int64(len(new(big.Int).Abs(b).String()))
+	if c := cmp.Compare(digitsA+int64(d.Exponent), digitsB+int64(other.Exponent)); c != 0 {
+		return c * a.Sign()
+	}
+	// Then the exponents differ by at most the number of digits
+	shift := int64(d.Exponent) - int64(other.Exponent)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(absInt64(shift)), nil)
+	if shift > 0 {
+		a = new(big.Int).Mul(a, scale)
+	} else {
+		b = new(big.Int).Mul(b, scale)
+	}
+	return a.Cmp(b)
+}
+
+// coefficient returns the coefficient, treating nil as zero
+func (d Decimal) coefficient() *big.Int {
+	if d.Coefficient == nil {
+		return new(big.Int)
+	}
+	return d.Coefficient
+}
+
+// scientific returns the decimal as "<coefficient>e<exponent>"
+func (d Decimal) scientific() string {
+	if d.Coefficient == nil {
+		return "0"
+	}
+	return d.Coefficient.String() + "e" + strconv.Itoa(int(d.Exponent))
+}
+
+func abs32(v int32) int64 {
+	return absInt64(int64(v))
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Decimal returns the value as an exact decimal. With ParseWithNumbers the
+// original literal is used digit for digit; in the default mode the shortest
+// representation of the float64 is used, which recovers literals such as 0.1
+// exactly. Numeric strings such as "19.99" are accepted as well.
+func (j JSONValue) Decimal() (Decimal, error) {
+	if j.err != nil {
+		return Decimal{}, j.err
+	}
+
+	lit, err := numberLiteral("Decimal", j.data)
+	if err != nil {
+		return Decimal{}, err
+	}
+	d, err := ParseDecimal(lit)
+	if err != nil {
+		return Decimal{}, &JSONError{Op: "Decimal", Err: err}
+	}
+	return d, nil
+}
+
+// DecimalOr returns the value as Decimal or default if error/conversion fails
+func (j JSONValue) DecimalOr(defaultValue Decimal) Decimal {
+	if d, err := j.Decimal(); err == nil {
+		return d
+	}
+	return defaultValue
+}
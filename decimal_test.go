@@ -0,0 +1,144 @@
+package jsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input   string
+		coef    string
+		exp     int32
+		str     string
+		wantErr bool
+	}{
+		{"0.1", "1", -1, "0.1", false},
+		{"2.50", "250", -2, "2.50", false},
+		{"-12.340e-3", "-12340", -6, "-0.012340", false},
+		{"1e3", "1", 3, "1000", false},
+		{"1200", "1200", 0, "1200", false},
+		{"0", "0", 0, "0", false},
+		{"-0.005", "-5", -3, "-0.005", false},
+		{"1e100", "1", 100, "1e100", false},
+		{"5E-2", "5", -2, "0.05", false},
+		{"abc", "", 0, "", true},
+		{"1.", "", 0, "", true},
+		{"1e", "", 0, "", true},
+		{"1e99999999999", "", 0, "", true},
+		{"--1", "", 0, "", true},
+		{"-+1", "", 0, "", true},
+		{"1e2147483647", "", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := JSON.ParseDecimal(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", d)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if d.Coefficient.String() != tt.coef || d.Exponent != tt.exp {
+				t.Errorf("Expected %se%d, got %se%d", tt.coef, tt.exp, d.Coefficient, d.Exponent)
+			}
+			if d.String() != tt.str {
+				t.Errorf("Expected String() %s, got %s", tt.str, d.String())
+			}
+		})
+	}
+}
+
+func TestDecimalAccessor(t *testing.T) {
+	input := `{"price":0.1,"total":1234567890123456789.99,"str":"19.99","name":"x"}`
+
+	for _, mode := range []struct {
+		name string
+		obj  JSON.JSONValue
+	}{
+		{"float mode", JSON.Parse(input)},
+		{"number mode", JSON.Parse(input, JSON.ParseWithNumbers())},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			price, err := mode.obj.Get("price").Decimal()
+			if err != nil || price.String() != "0.1" {
+				t.Errorf("Expected exactly 0.1, got %v (err: %v)", price, err)
+			}
+			if d := mode.obj.Get("str").DecimalOr(JSON.Decimal{}); d.String() != "19.99" {
+				t.Errorf("Expected 19.99 from string, got %v", d)
+			}
+			if _, err := mode.obj.Get("name").Decimal(); err == nil {
+				t.Error("Expected error for non-numeric string")
+			}
+		})
+	}
+
+	// Only the literal-preserving mode keeps all digits of large values
+	total, _ := JSON.Parse(input, JSON.ParseWithNumbers()).Get("total").Decimal()
+	if total.String() != "1234567890123456789.99" {
+		t.Errorf("Expected exact total, got %s", total)
+	}
+}
+
+func TestDecimalArithmeticHelpers(t *testing.T) {
+	a, _ := JSON.ParseDecimal("0.10")
+	b, _ := JSON.ParseDecimal("1e-1")
+	if a.Cmp(b) != 0 {
+		t.Errorf("Expected 0.10 == 1e-1")
+	}
+	c, _ := JSON.ParseDecimal("0.3")
+	if a.Cmp(c) != -1 || c.Cmp(a) != 1 {
+		t.Error("Expected 0.10 < 0.3")
+	}
+	if a.Float64() != 0.1 {
+		t.Errorf("Expected 0.1, got %v", a.Float64())
+	}
+	if r := c.Rat(); r.String() != "3/10" {
+		t.Errorf("Expected 3/10, got %s", r)
+	}
+
+	out, err := json.Marshal(map[string]JSON.Decimal{"amount": a})
+	if err != nil || string(out) != `{"amount":0.10}` {
+		t.Errorf("Expected number literal, got %s (err: %v)", out, err)
+	}
+}
+
+func TestDecimalLimits(t *testing.T) {
+	if _, err := JSON.ParseDecimal("-+1"); !errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Expected ErrSyntax for -+1, got %v", err)
+	}
+	if _, err := JSON.ParseDecimal("1e2147483647"); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded for an exponent bomb, got %v", err)
+	}
+
+	dec := func(coef int64, exp int32) JSON.Decimal {
+		return JSON.Decimal{Coefficient: big.NewInt(coef), Exponent: exp}
+	}
+	tests := []struct {
+		a, b JSON.Decimal
+		want int
+	}{
+		{dec(1, math.MaxInt32), dec(1, math.MinInt32), 1},
+		{dec(-1, math.MaxInt32), dec(1, math.MinInt32), -1},
+		{dec(-1, math.MaxInt32), dec(-1, math.MaxInt32-1), -1},
+		{dec(120, -1), dec(12, 0), 0},
+		{dec(999, 0), dec(1, 3), -1},
+		{dec(-999, 0), dec(-1, 3), 1},
+		{dec(0, math.MaxInt32), JSON.Decimal{}, 0},
+		{dec(5, -2), JSON.Decimal{}, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Cmp(tt.b); got != tt.want {
+			t.Errorf("%v.Cmp(%v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
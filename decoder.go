@@ -0,0 +1,202 @@
+package jsjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Decoder reads a stream of JSON values (for example NDJSON or concatenated
+// documents) and returns each one as a JSONValue. It honors the same
+// ParseOption limits as Parse, applied to every value in the stream.
+type Decoder struct {
+	r    *limitReader
+	dec  *json.Decoder
+	opts parseOptions
+}
+
+// NewDecoder returns a Decoder reading from r
+func NewDecoder(r io.Reader, opts ...ParseOption) *Decoder {
+	lr := &limitReader{r: textReader(r)}
+	return &Decoder{
+		r:    lr,
+		dec:  json.NewDecoder(lr),
+		opts: newParseOptions(opts),
+	}
+}
+
+// More reports whether there is another value in the stream
+func (d *Decoder) More() bool {
+	return d.dec.More()
+}
+
+// Decode reads the next value from the stream. It returns io.EOF when the
+// stream is exhausted.
+func (d *Decoder) Decode() (JSONValue, error) {
+	if d.opts.maxBytes > 0 {
+		// Allow one byte past the limit so a value ending exactly at the
+		// limit can still be terminated
+		d.r.limit = d.dec.InputOffset() + d.opts.maxBytes + 1
+	}
+
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return JSONValue{err: &JSONError{Op: "Decode", Err: err}}, io.EOF
+		}
+		if errors.Is(err, errReadLimit) {
+			err = fmt.Errorf("%w: value exceeds %d bytes", ErrLimitExceeded, d.opts.maxBytes)
+		}
+		err = &JSONError{Op: "Decode", Err: err}
+		return JSONValue{err: err}, err
+	}
+
+	if err := checkLimits(raw, &d.opts); err != nil {
+		err = &JSONError{Op: "Decode", Err: err}
+		return JSONValue{err: err}, err
+	}
+	raw, err := normalizeText(raw, &d.opts)
+	if err != nil {
+		err = &JSONError{Op: "Decode", Err: err}
+		return JSONValue{err: err}, err
+	}
+
+	opts := d.opts
+	result, err := decodeTree(raw, &opts)
+	if err != nil {
+		err = &JSONError{Op: "Decode", Err: err}
+		return JSONValue{err: err}, err
+	}
+	return JSONValue{data: result, raw: newRawRef(raw, &opts), arena: opts.arena.handle()}, nil
+}
+
+// DecodeEach streams typed records from r and calls fn for each one. The
+// input may be NDJSON (or any sequence of concatenated values) or a single
+// top-level array, which is detected from the first byte. Each record is
+// decoded directly into a new T without building an intermediate tree, and
+// ParseOption limits apply per record. Decoding stops at the first error;
+// an error returned by fn is passed through unchanged.
+func DecodeEach[T any](r io.Reader, fn func(T) error, opts ...ParseOption) error {
+	o := newParseOptions(opts)
+	br := bufio.NewReader(textReader(r))
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return &JSONError{Op: "DecodeEach", Err: err}
+	}
+
+	lr := &limitReader{r: br}
+	dec := json.NewDecoder(lr)
+	if o.useNumber {
+		dec.UseNumber()
+	}
+
+	inArray := first == '['
+	if inArray {
+		// Consume the opening bracket; elements are then read one by one
+		if _, err := dec.Token(); err != nil {
+			return &JSONError{Op: "DecodeEach", Err: err}
+		}
+	}
+
+	for index := 0; ; index++ {
+		if inArray && !dec.More() {
+			break
+		}
+		if o.maxBytes > 0 {
+			lr.limit = dec.InputOffset() + o.maxBytes + 1
+		}
+
+		record, err := decodeRecord[T](dec, &o)
+		if err == io.EOF && !inArray {
+			return nil
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			if errors.Is(err, errReadLimit) {
+				err = fmt.Errorf("%w: record exceeds %d bytes", ErrLimitExceeded, o.maxBytes)
+			}
+			return &JSONError{Op: "DecodeEach", Err: fmt.Errorf("record %d: %w", index, err)}
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	lr.limit = 0
+	if _, err := dec.Token(); err != nil {
+		return &JSONError{Op: "DecodeEach", Err: err}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return &JSONError{Op: "DecodeEach", Err: fmt.Errorf("%w: unexpected data after top-level array", ErrSyntax)}
+	}
+	return nil
+}
+
+// decodeRecord reads the next value into a new T. Token limits need the raw
+// bytes, so the value is only buffered when they are configured.
+func decodeRecord[T any](dec *json.Decoder, o *parseOptions) (T, error) {
+	var record T
+	if !o.hasTokenLimits() {
+		err := dec.Decode(&record)
+		return record, err
+	}
+
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return record, err
+	}
+	if err := checkLimits(raw, o); err != nil {
+		return record, err
+	}
+	err := json.Unmarshal(raw, &record)
+	return record, err
+}
+
+// peekNonSpace skips leading whitespace and returns the next byte without
+// consuming it
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return c, br.UnreadByte()
+	}
+}
+
+// errReadLimit is returned by limitReader once the configured limit is reached
+var errReadLimit = errors.New("read limit reached")
+
+// limitReader stops reading at an absolute offset, which the Decoder moves
+// forward before each value
+type limitReader struct {
+	r     io.Reader
+	n     int64 // bytes read so far
+	limit int64 // absolute offset to stop at, 0 means unlimited
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.limit > 0 {
+		remaining := l.limit - l.n
+		if remaining <= 0 {
+			return 0, errReadLimit
+		}
+		if int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	return n, err
+}
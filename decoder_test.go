@@ -0,0 +1,147 @@
+package jsjson_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestDecoder(t *testing.T) {
+	stream := `{"id":1}
+{"id":2}
+{"id":3}`
+
+	dec := JSON.NewDecoder(strings.NewReader(stream))
+	var ids []int
+	for {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids = append(ids, v.Get("id").IntOr(0))
+	}
+
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Errorf("Expected ids [1 2 3], got: %v", ids)
+	}
+}
+
+func TestDecoderLimits(t *testing.T) {
+	t.Run("max bytes per value", func(t *testing.T) {
+		stream := `{"id":1} {"id":2,"name":"` + strings.Repeat("x", 1000) + `"}`
+		dec := JSON.NewDecoder(strings.NewReader(stream), JSON.WithMaxBytes(64))
+
+		if _, err := dec.Decode(); err != nil {
+			t.Fatalf("First value should decode, got: %v", err)
+		}
+		if _, err := dec.Decode(); !errors.Is(err, JSON.ErrLimitExceeded) {
+			t.Errorf("Expected ErrLimitExceeded for second value, got: %v", err)
+		}
+	})
+
+	t.Run("value exactly at limit", func(t *testing.T) {
+		dec := JSON.NewDecoder(strings.NewReader(`12345 678`), JSON.WithMaxBytes(5))
+		v, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if v.IntOr(0) != 12345 {
+			t.Errorf("Expected 12345, got: %v", v.Raw())
+		}
+		v, err = dec.Decode()
+		if err != nil || v.IntOr(0) != 678 {
+			t.Errorf("Expected 678, got: %v (err: %v)", v.Raw(), err)
+		}
+	})
+
+	t.Run("token limits", func(t *testing.T) {
+		dec := JSON.NewDecoder(strings.NewReader(`[1,2] [1,2,3]`), JSON.WithMaxArrayElements(2))
+		if _, err := dec.Decode(); err != nil {
+			t.Fatalf("First value should decode, got: %v", err)
+		}
+		if _, err := dec.Decode(); !errors.Is(err, JSON.ErrLimitExceeded) {
+			t.Errorf("Expected ErrLimitExceeded, got: %v", err)
+		}
+	})
+}
+
+type eachRecord struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDecodeEach(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{"ndjson", "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n", []int{1, 2, 3}, false},
+		{"array", ` [{"id":1}, {"id":2}] `, []int{1, 2}, false},
+		{"empty array", `[]`, nil, false},
+		{"empty input", "  \n", nil, false},
+		{"truncated array", `[{"id":1}, {"id":2}`, []int{1, 2}, true},
+		{"trailing data", `[{"id":1}] {"id":2}`, []int{1}, true},
+		{"syntax error", "{\"id\":1}\n{\"id\":", []int{1}, true},
+		{"type error", `[{"id":"x"}]`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			err := JSON.DecodeEach(strings.NewReader(tt.input), func(r eachRecord) error {
+				got = append(got, r.ID)
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error: %v, got %v", tt.wantErr, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeEachStopsOnCallbackError(t *testing.T) {
+	errStop := errors.New("stop")
+	count := 0
+	err := JSON.DecodeEach(strings.NewReader(`[{"id":1},{"id":2},{"id":3}]`), func(r eachRecord) error {
+		count++
+		if r.ID == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || count != 2 {
+		t.Errorf("Expected callback error after 2 records, got %v after %d", err, count)
+	}
+}
+
+func TestDecodeEachLimits(t *testing.T) {
+	input := `[{"id":1,"name":"a"},{"id":2,"name":"` + strings.Repeat("x", 100) + `"}]`
+	handle := func(eachRecord) error { return nil }
+
+	if err := JSON.DecodeEach(strings.NewReader(input), handle, JSON.WithMaxBytes(64)); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded for max bytes, got %v", err)
+	}
+	if err := JSON.DecodeEach(strings.NewReader(input), handle, JSON.WithMaxStringLen(10)); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded for string length, got %v", err)
+	}
+	// The limits apply per record, not to the whole array
+	if err := JSON.DecodeEach(strings.NewReader(input), handle, JSON.WithMaxBytes(120)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
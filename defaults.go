@@ -0,0 +1,142 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// -------------------- Schema Defaults --------------------
+
+// ApplyDefaults returns a copy of the value with a JSON Schema's defaults
+// filled in and compatible scalars coerced to the declared types, so config
+// code can read values directly instead of through GetOr:
+//
+//	schema := Parse(`{"properties": {
+//	    "port":  {"type": "integer", "default": 8080},
+//	    "debug": {"type": "boolean", "default": false}
+//	}}`)
+//	cfg := Parse(`{"port": "9090"}`).ApplyDefaults(schema)
+//	// {"port": 9090, "debug": false}
+//
+// A missing object member takes the "default" of its schema in
+// "properties"; members without a default stay missing. Objects follow
+// "properties" and "additionalProperties", arrays follow "items". A value
+// whose type differs from the schema's "type" is converted when the
+// conversion is lossless: numeric and boolean strings become numbers and
+// booleans, and numbers and booleans become strings. Values that cannot be
+// converted are left as they are for Validate to report.
+func (j JSONValue) ApplyDefaults(schema JSONValue) JSONValue {
+	if j.err != nil {
+		return j
+	}
+	if schema.err != nil {
+		return JSONValue{err: &JSONError{Op: "ApplyDefaults", Err: schema.err}}
+	}
+	s, ok := schema.data.(map[string]interface{})
+	if !ok {
+		return JSONValue{err: &JSONError{Op: "ApplyDefaults", Err: fmt.Errorf("%w: schema must be an object, got %T", ErrTypeMismatch, schema.data)}}
+	}
+	return JSONValue{data: applySchemaDefaults(deepCopy(j.data), s)}
+}
+
+// applySchemaDefaults applies s to v in place, returning the new value
+func applySchemaDefaults(v interface{}, s map[string]interface{}) interface{} {
+	v = coerceToSchema(v, s["type"])
+
+	switch c := v.(type) {
+	case map[string]interface{}:
+		props, _ := s["properties"].(map[string]interface{})
+		for key, p := range props {
+			ps, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if val, ok := c[key]; ok {
+				c[key] = applySchemaDefaults(val, ps)
+			} else if def, ok := ps["default"]; ok {
+				c[key] = applySchemaDefaults(deepCopy(def), ps)
+			}
+		}
+		if extra, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			for key, val := range c {
+				if _, declared := props[key]; !declared {
+					c[key] = applySchemaDefaults(val, extra)
+				}
+			}
+		}
+	case []interface{}:
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			for i, val := range c {
+				c[i] = applySchemaDefaults(val, items)
+			}
+		}
+	}
+	return v
+}
+
+// coerceToSchema converts v to the first type in typ, a schema "type"
+// keyword, that it can be converted to without loss. v is returned
+// unchanged if it already has one of the types or none of them fit.
+func coerceToSchema(v interface{}, typ interface{}) interface{} {
+	var types []string
+	switch t := typ.(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	if len(types) == 0 {
+		return v
+	}
+
+	actual := Valid(v).Type()
+	for _, t := range types {
+		if t == actual || (t == "integer" && actual == "number" && isIntegral(v)) {
+			return v
+		}
+	}
+
+	for _, t := range types {
+		if c, ok := coerceScalar(v, t); ok {
+			return c
+		}
+	}
+	return v
+}
+
+func coerceScalar(v interface{}, typ string) (interface{}, bool) {
+	switch typ {
+	case "number", "integer":
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) || (typ == "integer" && f != math.Trunc(f)) {
+			return nil, false
+		}
+		return f, true
+	case "boolean":
+		if s, ok := v.(string); ok {
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b, true
+			}
+		}
+	case "string":
+		switch c := v.(type) {
+		case float64:
+			return strconv.FormatFloat(c, 'f', -1, 64), true
+		case json.Number:
+			return string(c), true
+		case bool:
+			return strconv.FormatBool(c), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,91 @@
+package jsjson_test
+
+import (
+	"errors"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+const defaultsSchema = `{
+	"type": "object",
+	"properties": {
+		"port":    {"type": "integer", "default": 8080},
+		"debug":   {"type": "boolean", "default": false},
+		"ratio":   {"type": "number"},
+		"version": {"type": "string"},
+		"id":      {"type": ["integer", "string"]},
+		"server":  {
+			"type": "object",
+			"default": {},
+			"properties": {"host": {"type": "string", "default": "localhost"}}
+		},
+		"workers": {
+			"type": "array",
+			"items": {"type": "object", "properties": {"weight": {"type": "number", "default": 1}}}
+		},
+		"limits": {"type": "object", "additionalProperties": {"type": "integer"}}
+	}
+}`
+
+func TestApplyDefaults(t *testing.T) {
+	schema := JSON.Parse(defaultsSchema)
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"fills missing", `{}`,
+			`{"port": 8080, "debug": false, "server": {"host": "localhost"}}`},
+		{"keeps present", `{"port": 9000, "debug": true, "server": {"host": "example.com"}}`,
+			`{"port": 9000, "debug": true, "server": {"host": "example.com"}}`},
+		{"null is present", `{"port": null}`,
+			`{"port": null, "debug": false, "server": {"host": "localhost"}}`},
+		{"coerces strings", `{"port": "9090", "debug": "true", "ratio": "0.5"}`,
+			`{"port": 9090, "debug": true, "ratio": 0.5, "server": {"host": "localhost"}}`},
+		{"coerces to string", `{"version": 2, "server": {"host": true}}`,
+			`{"version": "2", "port": 8080, "debug": false, "server": {"host": "true"}}`},
+		{"leaves incompatible values", `{"port": "8080.5", "debug": "maybe", "ratio": [1]}`,
+			`{"port": "8080.5", "debug": "maybe", "ratio": [1], "server": {"host": "localhost"}}`},
+		{"type lists", `{"id": true}`,
+			`{"id": "true", "port": 8080, "debug": false, "server": {"host": "localhost"}}`},
+		{"array items", `{"workers": [{}, {"weight": "3"}]}`,
+			`{"workers": [{"weight": 1}, {"weight": 3}], "port": 8080, "debug": false, "server": {"host": "localhost"}}`},
+		{"additional properties", `{"limits": {"cpu": "4", "mem": 512}}`,
+			`{"limits": {"cpu": 4, "mem": 512}, "port": 8080, "debug": false, "server": {"host": "localhost"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := JSON.Parse(tt.input)
+			before, _ := JSON.Stringify(input)
+
+			got := input.ApplyDefaults(schema)
+			if got.Error() != nil {
+				t.Fatalf("ApplyDefaults failed: %v", got.Error())
+			}
+			gotStr, _ := JSON.Stringify(got)
+			wantStr, _ := JSON.Stringify(JSON.Parse(tt.want))
+			if gotStr != wantStr {
+				t.Errorf("Expected %s, got %s", wantStr, gotStr)
+			}
+			if after, _ := JSON.Stringify(input); after != before {
+				t.Errorf("Receiver was modified: %s", after)
+			}
+		})
+	}
+}
+
+func TestApplyDefaultsErrors(t *testing.T) {
+	if err := JSON.Parse(`{}`).ApplyDefaults(JSON.Parse(`[]`)).Error(); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for a non-object schema, got %v", err)
+	}
+	if err := JSON.Parse(`{}`).ApplyDefaults(JSON.Parse(`{bad`)).Error(); err == nil {
+		t.Error("Expected the schema's parse error")
+	}
+	invalid := JSON.Parse(`{bad`)
+	if err := invalid.ApplyDefaults(JSON.Parse(defaultsSchema)).Error(); err != invalid.Error() {
+		t.Errorf("Expected the receiver's error, got %v", err)
+	}
+}
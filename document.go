@@ -0,0 +1,552 @@
+package jsjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// -------------------- Documents --------------------
+
+// Document is a mutable JSON document. JSONValue is a read view that never
+// changes; a Document is edited with Set, Delete and Merge and hands out
+// JSONValue snapshots of its current state:
+//
+//	doc := jsjson.NewDocument(jsjson.Parse(body))
+//	before := doc.Value()
+//	doc.Set("admin", "user", "role")
+//	doc.Delete("user", "password")
+//	after := doc.Value() // before is unchanged
+//
+// Edits are copy-on-write: the objects and arrays on the path to a change are
+// copied and everything else is shared, so snapshots and the JSONValue the
+// document was created from are never modified, and taking a snapshot costs
+// nothing. A Document is not safe for concurrent use.
+//
+// Edits are recorded as JSON Patch operations, which Changes and DirtyPaths
+// report, until Checkpoint starts a new record.
+type Document struct {
+	root    interface{}
+	err     error
+	version uint64 // counts edits, for Tx.Commit to detect conflicts
+	changes Patch  // edits since the last checkpoint
+}
+
+// NewDocument returns a Document holding jv. jv itself is not modified by
+// later edits. If jv holds an error, edits fail with it.
+func NewDocument(jv JSONValue) *Document {
+	return &Document{root: jv.data, err: jv.err}
+}
+
+// Value returns a snapshot of the document. Later edits do not affect it.
+func (d *Document) Value() JSONValue {
+	if d.err != nil {
+		return JSONValue{err: d.err}
+	}
+	return JSONValue{data: d.root}
+}
+
+// Get returns the value at keys in the current state, as JSONValue.Get does
+func (d *Document) Get(keys ...interface{}) JSONValue {
+	return d.Value().Get(keys...)
+}
+
+// Set stores value at keys, which are object keys and array indices as for
+// Get; with no keys it replaces the whole document. Missing objects on the
+// way are created for string keys, and an index equal to an array's length
+// appends to it. value may be anything Parse accepts as a Go value,
+// including a JSONValue.
+func (d *Document) Set(value interface{}, keys ...interface{}) error {
+	if d.err != nil {
+		return d.err
+	}
+	v, err := normalize(value)
+	if err != nil {
+		return &JSONError{Op: "Set", Err: err}
+	}
+	root, err := setIn("Set", d.root, keys, 0, v)
+	if err != nil {
+		return err
+	}
+	d.update(root, setOperation(d.root, root, keys))
+	return nil
+}
+
+// Delete removes the object member or array element at keys. Later array
+// elements move down by one.
+func (d *Document) Delete(keys ...interface{}) error {
+	if d.err != nil {
+		return d.err
+	}
+	if len(keys) == 0 {
+		return &JSONError{Op: "Delete", Err: fmt.Errorf("cannot delete the document root")}
+	}
+	root, err := deleteIn("Delete", d.root, keys, 0)
+	if err != nil {
+		return err
+	}
+	_, path := pointerTo(d.root, keys)
+	d.update(root, PatchOperation{Op: "remove", Path: path})
+	return nil
+}
+
+// Merge deep-merges value into the value at keys, as Loader merges layers:
+// objects are merged key by key and anything else replaces the current
+// value. A missing value at keys is created as for Set.
+func (d *Document) Merge(value interface{}, keys ...interface{}) error {
+	if d.err != nil {
+		return d.err
+	}
+	v, err := normalize(value)
+	if err != nil {
+		return &JSONError{Op: "Merge", Err: err}
+	}
+	// A failed Get leaves nothing to merge into; setIn reports the paths
+	// that cannot be written
+	current := d.Get(keys...).data
+	root, err := setIn("Merge", d.root, keys, 0, mergeCopy(current, v))
+	if err != nil {
+		return err
+	}
+	d.update(root, setOperation(d.root, root, keys))
+	return nil
+}
+
+// Changes returns the edits made since the last Checkpoint, or since the
+// document was created, as a JSON Patch that turns the state at that point
+// into the current one, for sending minimal PATCH requests or writing audit
+// logs. Set is recorded as "add" or "replace" at the first level it created,
+// Merge as the merged result, and Delete as "remove".
+func (d *Document) Changes() Patch {
+	if len(d.changes) == 0 {
+		return nil
+	}
+	out := make(Patch, len(d.changes))
+	for i, op := range d.changes {
+		op.Value = deepCopy(op.Value)
+		out[i] = op
+	}
+	return out
+}
+
+// DirtyPaths returns the JSON Pointers of the paths Changes touches, each
+// once, in the order they were first edited
+func (d *Document) DirtyPaths() []string {
+	var paths []string
+	seen := make(map[string]bool, len(d.changes))
+	for _, op := range d.changes {
+		if !seen[op.Path] {
+			seen[op.Path] = true
+			paths = append(paths, op.Path)
+		}
+	}
+	return paths
+}
+
+// Checkpoint returns the current state and the changes leading to it, as
+// Value and Changes do, and starts a new record of changes
+func (d *Document) Checkpoint() (JSONValue, Patch) {
+	changes := d.Changes()
+	d.changes = nil
+	return d.Value(), changes
+}
+
+// update makes root the current state, reached by op
+func (d *Document) update(root interface{}, op PatchOperation) {
+	d.root = root
+	d.version++
+	d.changes = append(d.changes, op)
+}
+
+// clone returns a copy of d whose edits do not affect d
+func (d *Document) clone() Document {
+	c := *d
+	c.changes = c.changes[:len(c.changes):len(c.changes)]
+	return c
+}
+
+// Tx is a set of edits to a Document that take effect together, so a
+// multi-field update cannot leave the document half changed:
+//
+//	tx := doc.Begin()
+//	tx.Set(order.Total, "order", "total")
+//	tx.Delete("order", "draft")
+//	if err := tx.Commit(); err != nil {
+//	    return err // doc is unchanged
+//	}
+//
+// Edits are made to a private copy, which Get reads; the document sees none
+// of them until Commit. If an edit fails, Commit discards them all and
+// returns that error. A Tx is finished by Commit or Rollback, after which
+// its methods fail.
+type Tx struct {
+	doc      *Document
+	work     Document
+	version  uint64 // doc.version at Begin
+	recorded int    // len(doc.changes) at Begin
+	err      error  // first failed edit
+	done     bool
+}
+
+// Begin starts a transaction on d. Copying the document is free, as edits
+// are copy-on-write.
+func (d *Document) Begin() *Tx {
+	return &Tx{doc: d, work: d.clone(), version: d.version, recorded: len(d.changes)}
+}
+
+// Get returns the value at keys as the transaction sees it
+func (t *Tx) Get(keys ...interface{}) JSONValue {
+	return t.work.Get(keys...)
+}
+
+// Set stores value at keys, as Document.Set does, when the transaction is
+// committed
+func (t *Tx) Set(value interface{}, keys ...interface{}) error {
+	if err := t.check("Set"); err != nil {
+		return err
+	}
+	return t.record(t.work.Set(value, keys...))
+}
+
+// Delete removes the value at keys, as Document.Delete does, when the
+// transaction is committed
+func (t *Tx) Delete(keys ...interface{}) error {
+	if err := t.check("Delete"); err != nil {
+		return err
+	}
+	return t.record(t.work.Delete(keys...))
+}
+
+// Merge deep-merges value into the value at keys, as Document.Merge does,
+// when the transaction is committed
+func (t *Tx) Merge(value interface{}, keys ...interface{}) error {
+	if err := t.check("Merge"); err != nil {
+		return err
+	}
+	return t.record(t.work.Merge(value, keys...))
+}
+
+// Commit applies the transaction's edits to the document. It fails, leaving
+// the document unchanged, if an edit failed or if the document was edited
+// directly since Begin.
+func (t *Tx) Commit() error {
+	if err := t.check("Commit"); err != nil {
+		return err
+	}
+	t.done = true
+	if t.err != nil {
+		return t.err
+	}
+	if t.doc.version != t.version {
+		return &JSONError{Op: "Commit", Err: fmt.Errorf("document was modified after Begin")}
+	}
+	// The document may have been checkpointed since Begin
+	t.doc.root = t.work.root
+	t.doc.version++
+	t.doc.changes = append(t.doc.changes, t.work.changes[t.recorded:]...)
+	return nil
+}
+
+// Rollback discards the transaction's edits. It does nothing on a finished
+// transaction, so it can be deferred.
+func (t *Tx) Rollback() {
+	t.done = true
+}
+
+// check reports an edit of a finished transaction
+func (t *Tx) check(op string) error {
+	if t.done {
+		return &JSONError{Op: op, Err: fmt.Errorf("transaction already committed or rolled back")}
+	}
+	return nil
+}
+
+// record keeps the first error of the transaction's edits and returns err
+func (t *Tx) record(err error) error {
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	return err
+}
+
+// SyncDocument is a Document that is safe for concurrent use, for state
+// shared between goroutines such as feature flags or session data. Reads
+// take a read lock only long enough to pick up the current tree; the
+// JSONValues they return are snapshots that later writes do not affect.
+type SyncDocument struct {
+	mu  sync.RWMutex
+	doc Document
+}
+
+// NewSyncDocument returns a SyncDocument holding jv
+func NewSyncDocument(jv JSONValue) *SyncDocument {
+	return &SyncDocument{doc: *NewDocument(jv)}
+}
+
+// Snapshot returns the current state of the document
+func (s *SyncDocument) Snapshot() JSONValue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.doc.Value()
+}
+
+// Get returns the value at keys in the current state
+func (s *SyncDocument) Get(keys ...interface{}) JSONValue {
+	return s.Snapshot().Get(keys...)
+}
+
+// Set stores value at keys, as Document.Set does
+func (s *SyncDocument) Set(value interface{}, keys ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.doc.Set(value, keys...)
+}
+
+// Delete removes the value at keys, as Document.Delete does
+func (s *SyncDocument) Delete(keys ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.doc.Delete(keys...)
+}
+
+// Merge deep-merges value into the value at keys, as Document.Merge does
+func (s *SyncDocument) Merge(value interface{}, keys ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.doc.Merge(value, keys...)
+}
+
+// Changes returns the edits since the last Checkpoint, as Document.Changes
+// does
+func (s *SyncDocument) Changes() Patch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.doc.Changes()
+}
+
+// DirtyPaths returns the paths edited since the last Checkpoint, as
+// Document.DirtyPaths does
+func (s *SyncDocument) DirtyPaths() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.doc.DirtyPaths()
+}
+
+// Checkpoint returns the current state and the changes leading to it and
+// starts a new record of changes, as one step
+func (s *SyncDocument) Checkpoint() (JSONValue, Patch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.doc.Checkpoint()
+}
+
+// Update calls fn with the document while holding the write lock, for
+// read-modify-write changes such as incrementing a counter. If fn returns an
+// error, its edits are discarded and the error is returned.
+func (s *SyncDocument) Update(fn func(d *Document) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	work := s.doc.clone()
+	if err := fn(&work); err != nil {
+		return err
+	}
+	s.doc = work
+	return nil
+}
+
+// setIn returns node with value stored at keys[i:], copying the containers
+// it changes
+func setIn(op string, node interface{}, keys []interface{}, i int, value interface{}) (interface{}, error) {
+	if i == len(keys) {
+		return value, nil
+	}
+	key := keys[i]
+	if node == nil {
+		if _, ok := key.(string); !ok {
+			return nil, accessError(op, keys, i, fmt.Errorf("%w: cannot create array element %v", ErrIndexOutOfRange, key)).err
+		}
+		node = map[string]interface{}{}
+	}
+
+	switch c := node.(type) {
+	case map[string]interface{}:
+		k, ok := key.(string)
+		if !ok {
+			return nil, accessError(op, keys, i, fmt.Errorf("%w: key must be string for object access, got %T", ErrTypeMismatch, key)).err
+		}
+		child, err := setIn(op, c[k], keys, i+1, value)
+		if err != nil {
+			return nil, err
+		}
+		out := cloneObject(c, 1)
+		out[k] = child
+		return out, nil
+
+	case []interface{}:
+		idx, err := convertToIndex(key)
+		if err != nil {
+			return nil, accessError(op, keys, i, fmt.Errorf("%w: invalid array index %v: %v", ErrTypeMismatch, key, err)).err
+		}
+		if idx < 0 || idx > len(c) {
+			return nil, accessError(op, keys, i, fmt.Errorf("%w: index %d (length: %d)", ErrIndexOutOfRange, idx, len(c))).err
+		}
+		var current interface{}
+		if idx < len(c) {
+			current = c[idx]
+		}
+		child, err := setIn(op, current, keys, i+1, value)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(c), len(c)+1)
+		copy(out, c)
+		if idx == len(c) {
+			out = append(out, child)
+		} else {
+			out[idx] = child
+		}
+		return out, nil
+
+	default:
+		return nil, accessError(op, keys, i, fmt.Errorf("%w: cannot access key %v on type %T", ErrTypeMismatch, key, node)).err
+	}
+}
+
+// deleteIn returns node without the value at keys[i:], copying the
+// containers it changes
+func deleteIn(op string, node interface{}, keys []interface{}, i int) (interface{}, error) {
+	key := keys[i]
+	last := i == len(keys)-1
+
+	switch c := node.(type) {
+	case map[string]interface{}:
+		k, ok := key.(string)
+		if !ok {
+			return nil, accessError(op, keys, i, fmt.Errorf("%w: key must be string for object access, got %T", ErrTypeMismatch, key)).err
+		}
+		child, exists := c[k]
+		if !exists {
+			return nil, accessError(op, keys, i, ErrKeyNotFound).err
+		}
+		out := cloneObject(c, 0)
+		if last {
+			delete(out, k)
+			return out, nil
+		}
+		updated, err := deleteIn(op, child, keys, i+1)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = updated
+		return out, nil
+
+	case []interface{}:
+		idx, err := convertToIndex(key)
+		if err != nil {
+			return nil, accessError(op, keys, i, fmt.Errorf("%w: invalid array index %v: %v", ErrTypeMismatch, key, err)).err
+		}
+		if idx < 0 || idx >= len(c) {
+			return nil, accessError(op, keys, i, fmt.Errorf("%w: index %d (length: %d)", ErrIndexOutOfRange, idx, len(c))).err
+		}
+		if last {
+			out := make([]interface{}, 0, len(c)-1)
+			out = append(out, c[:idx]...)
+			return append(out, c[idx+1:]...), nil
+		}
+		updated, err := deleteIn(op, c[idx], keys, i+1)
+		if err != nil {
+			return nil, err
+		}
+		out := append([]interface{}(nil), c...)
+		out[idx] = updated
+		return out, nil
+
+	case nil:
+		return nil, accessError(op, keys, i, fmt.Errorf("%w: cannot access key %v on %w", ErrTypeMismatch, key, ErrNilValue)).err
+
+	default:
+		return nil, accessError(op, keys, i, fmt.Errorf("%w: cannot access key %v on type %T", ErrTypeMismatch, key, node)).err
+	}
+}
+
+// mergeCopy is mergeTrees without modifying dst: the objects it changes are
+// copied
+func mergeCopy(dst, src interface{}) interface{} {
+	d, ok := dst.(map[string]interface{})
+	s, ok2 := src.(map[string]interface{})
+	if !ok || !ok2 {
+		return src
+	}
+	out := cloneObject(d, len(s))
+	for k, v := range s {
+		out[k] = mergeCopy(d[k], v)
+	}
+	return out
+}
+
+// cloneObject returns a shallow copy of m with room for extra more members
+func cloneObject(m map[string]interface{}, extra int) map[string]interface{} {
+	out := make(map[string]interface{}, len(m)+extra)
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// setOperation returns the patch operation that turns old into root, which
+// Set or Merge made by storing a value at keys. Objects that Set created on
+// the way become part of the value.
+func setOperation(old, root interface{}, keys []interface{}) PatchOperation {
+	node := old
+	for i, key := range keys {
+		var exists bool
+		switch c := node.(type) {
+		case map[string]interface{}:
+			node, exists = c[key.(string)]
+		case []interface{}:
+			idx, _ := convertToIndex(key)
+			if exists = idx < len(c); exists {
+				node = c[idx]
+			}
+		case nil:
+			// A null parent was replaced by an object
+			return replaceOperation(root, keys[:i])
+		}
+		if !exists {
+			value, path := pointerTo(root, keys[:i+1])
+			return PatchOperation{Op: "add", Path: path, Value: value}
+		}
+	}
+	return replaceOperation(root, keys)
+}
+
+func replaceOperation(root interface{}, keys []interface{}) PatchOperation {
+	value, path := pointerTo(root, keys)
+	return PatchOperation{Op: "replace", Path: path, Value: value}
+}
+
+// pointerTo returns the value at keys in root, which must exist up to the
+// last key, and its JSON Pointer (RFC 6901)
+func pointerTo(root interface{}, keys []interface{}) (interface{}, string) {
+	var b strings.Builder
+	node := root
+	for _, key := range keys {
+		b.WriteByte('/')
+		switch c := node.(type) {
+		case map[string]interface{}:
+			k := key.(string)
+			b.WriteString(strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1"))
+			node = c[k]
+		case []interface{}:
+			idx, _ := convertToIndex(key)
+			b.WriteString(strconv.Itoa(idx))
+			if idx < len(c) {
+				node = c[idx]
+			} else {
+				node = nil
+			}
+		}
+	}
+	return node, b.String()
+}
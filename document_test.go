@@ -0,0 +1,304 @@
+package jsjson_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestDocument(t *testing.T) {
+	const base = `{"user":{"name":"Ann","tags":["a","b"]},"n":1}`
+
+	tests := []struct {
+		name    string
+		edit    func(d *JSON.Document) error
+		want    string
+		wantErr error
+	}{
+		{"set member", func(d *JSON.Document) error { return d.Set("Bob", "user", "name") }, `{"user":{"name":"Bob","tags":["a","b"]},"n":1}`, nil},
+		{"set creates objects", func(d *JSON.Document) error { return d.Set(true, "flags", "beta") }, `{"user":{"name":"Ann","tags":["a","b"]},"n":1,"flags":{"beta":true}}`, nil},
+		{"set array element", func(d *JSON.Document) error { return d.Set("x", "user", "tags", 1) }, `{"user":{"name":"Ann","tags":["a","x"]},"n":1}`, nil},
+		{"set appends", func(d *JSON.Document) error { return d.Set("c", "user", "tags", 2) }, `{"user":{"name":"Ann","tags":["a","b","c"]},"n":1}`, nil},
+		{"set root", func(d *JSON.Document) error { return d.Set([]int{1, 2}) }, `[1,2]`, nil},
+		{"set JSONValue", func(d *JSON.Document) error { return d.Set(JSON.Parse(`{"k":[1]}`), "n") }, `{"user":{"name":"Ann","tags":["a","b"]},"n":{"k":[1]}}`, nil},
+		{"set out of range", func(d *JSON.Document) error { return d.Set("c", "user", "tags", 5) }, ``, JSON.ErrIndexOutOfRange},
+		{"set on scalar", func(d *JSON.Document) error { return d.Set(1, "n", "x") }, ``, JSON.ErrTypeMismatch},
+		{"delete member", func(d *JSON.Document) error { return d.Delete("user", "name") }, `{"user":{"tags":["a","b"]},"n":1}`, nil},
+		{"delete element", func(d *JSON.Document) error { return d.Delete("user", "tags", 0) }, `{"user":{"name":"Ann","tags":["b"]},"n":1}`, nil},
+		{"delete missing", func(d *JSON.Document) error { return d.Delete("user", "age") }, ``, JSON.ErrKeyNotFound},
+		{"delete root", func(d *JSON.Document) error { return d.Delete() }, ``, nil},
+		{"merge object", func(d *JSON.Document) error {
+			return d.Merge(map[string]interface{}{"user": map[string]interface{}{"age": 30}, "n": 2})
+		}, `{"user":{"name":"Ann","tags":["a","b"],"age":30},"n":2}`, nil},
+		{"merge replaces arrays", func(d *JSON.Document) error { return d.Merge([]string{"z"}, "user", "tags") }, `{"user":{"name":"Ann","tags":["z"]},"n":1}`, nil},
+		{"merge into missing", func(d *JSON.Document) error { return d.Merge(map[string]interface{}{"a": 1}, "extra") }, `{"user":{"name":"Ann","tags":["a","b"]},"n":1,"extra":{"a":1}}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := JSON.Parse(base)
+			doc := JSON.NewDocument(original)
+			before := doc.Value()
+			err := tt.edit(doc)
+
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("expected error, got document %v", doc.Value().Raw())
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				got, _ := JSON.Stringify(doc.Value())
+				want, _ := JSON.Stringify(JSON.Parse(tt.want))
+				if got != want {
+					t.Errorf("expected %s, got %s", want, got)
+				}
+			}
+
+			// Neither the parsed value nor an earlier snapshot may change
+			wantBase, _ := JSON.Stringify(JSON.Parse(base))
+			for name, jv := range map[string]JSON.JSONValue{"original": original, "snapshot": before} {
+				if got, _ := JSON.Stringify(jv); got != wantBase {
+					t.Errorf("%s changed to %s", name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDocumentErrors(t *testing.T) {
+	doc := JSON.NewDocument(JSON.Parse(`{bad`))
+	if err := doc.Set(1, "a"); !errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Set on a failed parse: expected ErrSyntax, got %v", err)
+	}
+	if doc.Value().IsValid() {
+		t.Error("Value of a failed parse should hold the error")
+	}
+
+	doc = JSON.NewDocument(JSON.Parse(`{"a":{"b":1}}`))
+	err := doc.Set(2, "a", "b", "c")
+	var jerr *JSON.JSONError
+	if !errors.As(err, &jerr) || jerr.Op != "Set" {
+		t.Fatalf("expected a Set JSONError, got %v", err)
+	}
+	if got := jerr.Path(); len(got) != 3 || got[2] != "c" {
+		t.Errorf("expected path [a b c], got %v", got)
+	}
+	if got := doc.Get("a", "b").IntOr(0); got != 1 {
+		t.Errorf("failed Set changed the document: a.b = %d", got)
+	}
+}
+
+func TestSyncDocument(t *testing.T) {
+	doc := JSON.NewSyncDocument(JSON.Parse(`{"flags":{},"hits":0}`))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := doc.Set(i%2 == 0, "flags", "f"+strconv.Itoa(i)); err != nil {
+				t.Error(err)
+			}
+			err := doc.Update(func(d *JSON.Document) error {
+				return d.Set(d.Get("hits").IntOr(0)+1, "hits")
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			snap := doc.Snapshot()
+			if _, err := JSON.Stringify(snap); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := doc.Get("hits").IntOr(0); got != 20 {
+		t.Errorf("expected 20 hits, got %d", got)
+	}
+	if flags, err := doc.Get("flags").Object(); err != nil || len(flags) != 20 {
+		t.Errorf("expected 20 flags, got %d (%v)", len(flags), err)
+	}
+
+	before := doc.Snapshot()
+	err := doc.Update(func(d *JSON.Document) error {
+		d.Set(0, "hits")
+		return errors.New("abort")
+	})
+	if err == nil || doc.Get("hits").IntOr(0) != 20 {
+		t.Errorf("failed Update should leave the document unchanged, got err %v, hits %d", err, doc.Get("hits").IntOr(0))
+	}
+	if err := doc.Delete("flags"); err != nil {
+		t.Fatal(err)
+	}
+	if !before.Get("flags").IsValid() {
+		t.Error("Delete changed an earlier snapshot")
+	}
+}
+
+func TestDocumentTx(t *testing.T) {
+	const base = `{"order":{"total":10,"draft":true,"items":[1]}}`
+	stringify := func(d *JSON.Document) string {
+		s, _ := JSON.Stringify(d.Value())
+		return s
+	}
+
+	t.Run("commit applies all edits", func(t *testing.T) {
+		doc := JSON.NewDocument(JSON.Parse(base))
+		tx := doc.Begin()
+		tx.Set(25, "order", "total")
+		tx.Delete("order", "draft")
+		tx.Set(2, "order", "items", 1)
+		if got := doc.Get("order", "total").IntOr(0); got != 10 {
+			t.Errorf("edit visible before Commit: total = %d", got)
+		}
+		if got := tx.Get("order", "total").IntOr(0); got != 25 {
+			t.Errorf("Tx.Get should see its own edits: total = %d", got)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		want, _ := JSON.Stringify(JSON.Parse(`{"order":{"total":25,"items":[1,2]}}`))
+		if got := stringify(doc); got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("rollback discards edits", func(t *testing.T) {
+		doc := JSON.NewDocument(JSON.Parse(base))
+		want := stringify(doc)
+		tx := doc.Begin()
+		tx.Set(25, "order", "total")
+		tx.Rollback()
+		if got := stringify(doc); got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+		if err := tx.Commit(); err == nil {
+			t.Error("Commit after Rollback should fail")
+		}
+		if err := tx.Set(1, "x"); err == nil {
+			t.Error("Set after Rollback should fail")
+		}
+	})
+
+	t.Run("failed edit aborts commit", func(t *testing.T) {
+		doc := JSON.NewDocument(JSON.Parse(base))
+		want := stringify(doc)
+		tx := doc.Begin()
+		tx.Set(25, "order", "total")
+		if err := tx.Delete("order", "missing"); !errors.Is(err, JSON.ErrKeyNotFound) {
+			t.Fatalf("expected ErrKeyNotFound, got %v", err)
+		}
+		tx.Set(false, "order", "paid")
+		if err := tx.Commit(); !errors.Is(err, JSON.ErrKeyNotFound) {
+			t.Errorf("Commit should return the failed edit's error, got %v", err)
+		}
+		if got := stringify(doc); got != want {
+			t.Errorf("document changed: %s", got)
+		}
+	})
+
+	t.Run("conflicting edit", func(t *testing.T) {
+		doc := JSON.NewDocument(JSON.Parse(base))
+		tx := doc.Begin()
+		tx.Set(25, "order", "total")
+		doc.Set("direct", "note")
+		if err := tx.Commit(); err == nil {
+			t.Fatal("Commit after a direct edit should fail")
+		}
+		if got := doc.Get("order", "total").IntOr(0); got != 10 {
+			t.Errorf("conflicting Commit changed the document: total = %d", got)
+		}
+	})
+}
+
+func TestDocumentChanges(t *testing.T) {
+	const base = `{"user":{"name":"Ann","tags":["a"],"bio":null},"a/b":1}`
+
+	t.Run("patch replays edits", func(t *testing.T) {
+		original := JSON.Parse(base)
+		doc := JSON.NewDocument(original)
+		doc.Set("Bob", "user", "name")
+		doc.Set(true, "flags", "beta", "on")
+		doc.Set("b", "user", "tags", 1)
+		doc.Set("hi", "user", "bio", "text")
+		doc.Delete("a/b")
+		doc.Merge(map[string]interface{}{"age": 30}, "user")
+		doc.Set("Cy", "user", "name")
+
+		wantPaths := []string{"/user/name", "/flags", "/user/tags/1", "/user/bio", "/a~1b", "/user"}
+		if got := doc.DirtyPaths(); strings.Join(got, " ") != strings.Join(wantPaths, " ") {
+			t.Errorf("DirtyPaths() = %v, want %v", got, wantPaths)
+		}
+
+		changes := doc.Changes()
+		if len(changes) != 7 || changes[1].Op != "add" || changes[3].Op != "replace" || changes[4].Op != "remove" {
+			t.Errorf("unexpected changes %+v", changes)
+		}
+		replayed := original.ApplyPatch(changes)
+		if !replayed.IsValid() {
+			t.Fatalf("replaying changes failed: %v", replayed.Error())
+		}
+		got, _ := JSON.Stringify(replayed)
+		want, _ := JSON.Stringify(doc.Value())
+		if got != want {
+			t.Errorf("replayed %s, document is %s", got, want)
+		}
+	})
+
+	t.Run("checkpoint starts a new record", func(t *testing.T) {
+		doc := JSON.NewDocument(JSON.Parse(base))
+		doc.Set(1, "x")
+		snap, changes := doc.Checkpoint()
+		if len(changes) != 1 || changes[0].Path != "/x" {
+			t.Errorf("unexpected changes %+v", changes)
+		}
+		if snap.Get("x").IntOr(0) != 1 {
+			t.Error("Checkpoint should return the current state")
+		}
+		if doc.Changes() != nil || doc.DirtyPaths() != nil {
+			t.Error("no changes expected after Checkpoint")
+		}
+		doc.Delete("x")
+		if got := doc.DirtyPaths(); len(got) != 1 || got[0] != "/x" {
+			t.Errorf("DirtyPaths() = %v", got)
+		}
+	})
+
+	t.Run("changes are copies", func(t *testing.T) {
+		doc := JSON.NewDocument(JSON.Parse(`{}`))
+		doc.Set(map[string]interface{}{"k": 1}, "m")
+		doc.Changes()[0].Value.(map[string]interface{})["k"] = 2
+		if got := doc.Get("m", "k").IntOr(0); got != 1 {
+			t.Errorf("editing Changes changed the document: k = %d", got)
+		}
+	})
+
+	t.Run("transactions", func(t *testing.T) {
+		doc := JSON.NewDocument(JSON.Parse(base))
+		doc.Set(1, "before")
+		discarded := doc.Begin()
+		discarded.Set(2, "discarded")
+		tx := doc.Begin()
+		tx.Set(3, "committed")
+		discarded.Rollback()
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if got := doc.DirtyPaths(); strings.Join(got, " ") != "/before /committed" {
+			t.Errorf("DirtyPaths() = %v", got)
+		}
+	})
+}
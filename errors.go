@@ -0,0 +1,264 @@
+package jsjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors wrapped by JSONError, for use with errors.Is
+var (
+	// ErrKeyNotFound is returned when an object key does not exist
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrIndexOutOfRange is returned when an array index is out of bounds
+	ErrIndexOutOfRange = errors.New("index out of range")
+	// ErrTypeMismatch is returned when a value has the wrong JSON type for
+	// the requested access or conversion
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrNilValue is returned along with ErrTypeMismatch when a null value is
+	// accessed as an object or array, e.g. Get("user", "name") with a null user
+	ErrNilValue = errors.New("nil value")
+	// ErrOverflow is returned along with ErrTypeMismatch when a number does
+	// not fit the requested integer type, e.g. Int32() on 3000000000
+	ErrOverflow = errors.New("integer overflow")
+	// ErrSyntax is returned for malformed input; the error is a *SyntaxError
+	// giving its position
+	ErrSyntax = errors.New("syntax error")
+	// ErrLimitExceeded is returned when input exceeds a configured limit
+	// (see WithMaxBytes, WithMaxStringLen, WithMaxArrayElements)
+	ErrLimitExceeded = errors.New("limit exceeded")
+	// ErrValidation is matched by a *ValidationError reporting failed
+	// validation rules
+	ErrValidation = errors.New("validation failed")
+	// ErrUnsupportedMediaType is returned by DecodeRequest when the request
+	// body is not declared as JSON
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+// Violation is one failed validation rule
+type Violation struct {
+	// Path locates the value, e.g. users[0].email
+	Path string
+	// Rule is the rule that failed, e.g. required or min=1
+	Rule    string
+	Message string
+}
+
+// Error implements the error interface
+func (v *Violation) Error() string {
+	return v.Path + ": " + v.Message
+}
+
+// ValidationError lists every violation found while validating a value.
+// It matches ErrValidation, and errors.As can extract any of its
+// *Violation values.
+type ValidationError struct {
+	Violations []*Violation
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString(ErrValidation.Error())
+	for i, v := range e.Violations {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(v.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns ErrValidation followed by the individual violations
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Violations)+1)
+	errs = append(errs, ErrValidation)
+	for _, v := range e.Violations {
+		errs = append(errs, v)
+	}
+	return errs
+}
+
+// SyntaxError reports malformed JSON text with the position of the problem,
+// so a mistake in a hand-edited file can be found directly:
+//
+//	syntax error at line 3, column 11: invalid character '}' looking for beginning of value, near "\"port\": }"
+//
+// It matches ErrSyntax, and errors.As also finds the *json.SyntaxError it
+// wraps when the error came from encoding/json.
+type SyntaxError struct {
+	Msg string
+	// Offset is the byte offset of the problem in the input
+	Offset int64
+	// Line and Column are 1-based; Column counts characters, not bytes
+	Line   int
+	Column int
+	// Excerpt is the text around the problem, from its line only
+	Excerpt string
+
+	err error // the error reported by the decoder, if any
+}
+
+// Error implements the error interface
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v at line %d, column %d: %s, near %q", ErrSyntax, e.Line, e.Column, e.Msg, e.Excerpt)
+}
+
+// Unwrap returns ErrSyntax and the decoder's own error, if any
+func (e *SyntaxError) Unwrap() []error {
+	if e.err != nil {
+		return []error{ErrSyntax, e.err}
+	}
+	return []error{ErrSyntax}
+}
+
+// excerptRadius is the number of characters an excerpt shows on each side of
+// the problem
+const excerptRadius = 20
+
+// newSyntaxError locates offset in data
+func newSyntaxError(data []byte, offset int, msg string, cause error) *SyntaxError {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	lineEnd := len(data)
+	if i := bytes.IndexByte(data[offset:], '\n'); i >= 0 {
+		lineEnd = offset + i
+	}
+	before := []rune(string(data[lineStart:offset]))
+	after := []rune(strings.TrimRight(string(data[offset:lineEnd]), "\r"))
+
+	excerpt := ""
+	if len(before) > excerptRadius {
+		excerpt = "..." + string(before[len(before)-excerptRadius:])
+	} else {
+		excerpt = string(before)
+	}
+	if len(after) > excerptRadius {
+		excerpt += string(after[:excerptRadius]) + "..."
+	} else {
+		excerpt += string(after)
+	}
+
+	return &SyntaxError{
+		Msg:     msg,
+		Offset:  int64(offset),
+		Line:    bytes.Count(data[:lineStart], []byte{'\n'}) + 1,
+		Column:  len(before) + 1,
+		Excerpt: excerpt,
+		err:     cause,
+	}
+}
+
+// locateSyntaxError adds the position to a *json.SyntaxError from decoding
+// data, and returns any other error unchanged
+func locateSyntaxError(data []byte, err error) error {
+	var jerr *json.SyntaxError
+	if !errors.As(err, &jerr) {
+		return err
+	}
+	// Offset counts the bytes read, including the offending one
+	offset := int(jerr.Offset)
+	if offset > 0 && offset <= len(data) && jerr.Error() != "unexpected end of JSON input" {
+		offset--
+	}
+	return newSyntaxError(data, offset, jerr.Error(), jerr)
+}
+
+// notKind reports that v is not the kind of value an operation needs, such
+// as "an array". A null value also matches ErrNilValue.
+func notKind(kind string, v interface{}) error {
+	if v == nil {
+		return fmt.Errorf("%w: value is not %s, got %w", ErrTypeMismatch, kind, ErrNilValue)
+	}
+	return fmt.Errorf("%w: value is not %s, got %T", ErrTypeMismatch, kind, v)
+}
+
+// withOp reports err, typically returned by a nested call, as failing in op
+func withOp(op string, err error) *JSONError {
+	if jerr, ok := err.(*JSONError); ok {
+		return &JSONError{Op: op, Err: jerr.Err, path: jerr.path}
+	}
+	return &JSONError{Op: op, Err: err}
+}
+
+// unwrapOp strips a *JSONError from err, leaving the error it wraps
+func unwrapOp(err error) error {
+	if jerr, ok := err.(*JSONError); ok {
+		return jerr.Err
+	}
+	return err
+}
+
+// Machine-readable error codes reported by ErrorToHTTP
+const (
+	CodeSyntaxError          = "syntax_error"
+	CodeTypeMismatch         = "type_mismatch"
+	CodeMissingKey           = "missing_key"
+	CodeLimitExceeded        = "limit_exceeded"
+	CodeValidation           = "validation_failed"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeInternal             = "internal_error"
+)
+
+// HTTPError describes how a library error should be reported to an HTTP client
+type HTTPError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e HTTPError) Error() string {
+	return e.Message
+}
+
+// ErrorToHTTP maps an error returned by this package to a suggested HTTP
+// status code and a machine-readable error code, so handlers can translate
+// parse and binding failures uniformly:
+//
+//	syntax errors            -> 400 syntax_error
+//	limit exceeded           -> 413 limit_exceeded
+//	unsupported media type   -> 415 unsupported_media_type
+//	type mismatch            -> 422 type_mismatch
+//	missing key / index      -> 422 missing_key
+//	failed validation        -> 422 validation_failed
+//	anything else            -> 500 internal_error
+//
+// A nil error maps to the zero HTTPError.
+func ErrorToHTTP(err error) HTTPError {
+	if err == nil {
+		return HTTPError{}
+	}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.Is(err, ErrLimitExceeded):
+		return HTTPError{Status: http.StatusRequestEntityTooLarge, Code: CodeLimitExceeded, Message: err.Error()}
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return HTTPError{Status: http.StatusUnsupportedMediaType, Code: CodeUnsupportedMediaType, Message: err.Error()}
+	case errors.As(err, &syntaxErr), errors.Is(err, ErrSyntax), errors.Is(err, io.ErrUnexpectedEOF):
+		return HTTPError{Status: http.StatusBadRequest, Code: CodeSyntaxError, Message: err.Error()}
+	case errors.As(err, &typeErr), errors.Is(err, ErrTypeMismatch):
+		return HTTPError{Status: http.StatusUnprocessableEntity, Code: CodeTypeMismatch, Message: err.Error()}
+	case errors.Is(err, ErrKeyNotFound), errors.Is(err, ErrIndexOutOfRange):
+		return HTTPError{Status: http.StatusUnprocessableEntity, Code: CodeMissingKey, Message: err.Error()}
+	case errors.Is(err, ErrValidation):
+		return HTTPError{Status: http.StatusUnprocessableEntity, Code: CodeValidation, Message: err.Error()}
+	default:
+		return HTTPError{Status: http.StatusInternalServerError, Code: CodeInternal, Message: err.Error()}
+	}
+}
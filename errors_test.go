@@ -0,0 +1,232 @@
+package jsjson_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	obj := JSON.Parse(`{"name":"John","tags":["a"],"age":"thirty","manager":null}`)
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"missing key", obj.Get("email").Error(), JSON.ErrKeyNotFound},
+		{"index out of range", obj.Get("tags", 5).Error(), JSON.ErrIndexOutOfRange},
+		{"key on string", obj.Get("name", "first").Error(), JSON.ErrTypeMismatch},
+		{"int from string", errOf(obj.Get("age").Int()), JSON.ErrTypeMismatch},
+		{"array from object", errOf(obj.Array()), JSON.ErrTypeMismatch},
+		{"key on null", obj.Get("manager", "name").Error(), JSON.ErrNilValue},
+		{"key on null is a mismatch", obj.Get("manager", "name").Error(), JSON.ErrTypeMismatch},
+		{"compiled path on null", JSON.CompilePath("manager", "name").Get(obj).Error(), JSON.ErrNilValue},
+		{"object from null", errOf(obj.Get("manager").Object()), JSON.ErrNilValue},
+		{"empty input", JSON.Parse("").Error(), JSON.ErrSyntax},
+		{"int overflow", errOf(JSON.Parse(`1e300`).Int64()), JSON.ErrOverflow},
+		{"int overflow is a mismatch", errOf(JSON.Parse(`1e300`).Int64()), JSON.ErrTypeMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.target) {
+				t.Errorf("Expected errors.Is(%v, %v)", tt.err, tt.target)
+			}
+		})
+	}
+}
+
+// errOf returns the error from a (value, error) pair
+func errOf[T any](_ T, err error) error {
+	return err
+}
+
+func TestErrorToHTTP(t *testing.T) {
+	obj := JSON.Parse(`{"name":"John"}`)
+	var dest struct {
+		Name int `json:"name"`
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"syntax", JSON.Parse(`{"name":`).Error(), http.StatusBadRequest, JSON.CodeSyntaxError},
+		{"invalid character", JSON.Parse(`{name}`).Error(), http.StatusBadRequest, JSON.CodeSyntaxError},
+		{"limit", JSON.Parse(`[1,2,3]`, JSON.WithMaxArrayElements(2)).Error(), http.StatusRequestEntityTooLarge, JSON.CodeLimitExceeded},
+		{"binding type mismatch", JSON.ParseInto(`{"name":"John"}`, &dest), http.StatusUnprocessableEntity, JSON.CodeTypeMismatch},
+		{"accessor type mismatch", errOf(obj.Get("name").Float64()), http.StatusUnprocessableEntity, JSON.CodeTypeMismatch},
+		{"missing key", obj.Get("email").Error(), http.StatusUnprocessableEntity, JSON.CodeMissingKey},
+		{"wrapped by caller", fmt.Errorf("decoding body: %w", obj.Get("email").Error()), http.StatusUnprocessableEntity, JSON.CodeMissingKey},
+		{"media type", fmt.Errorf("%w: text/plain", JSON.ErrUnsupportedMediaType), http.StatusUnsupportedMediaType, JSON.CodeUnsupportedMediaType},
+		{"unknown error", errors.New("boom"), http.StatusInternalServerError, JSON.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := JSON.ErrorToHTTP(tt.err)
+			if got.Status != tt.wantStatus || got.Code != tt.wantCode {
+				t.Errorf("Expected %d %s, got %d %s (%v)", tt.wantStatus, tt.wantCode, got.Status, got.Code, tt.err)
+			}
+			if got.Message == "" {
+				t.Error("Expected a message")
+			}
+		})
+	}
+
+	if got := JSON.ErrorToHTTP(nil); got != (JSON.HTTPError{}) {
+		t.Errorf("Expected zero value for nil error, got %+v", got)
+	}
+}
+
+func TestJSONErrorPath(t *testing.T) {
+	obj := JSON.Parse(`{"users": [{"name": "Ann", "preferences": {"privacy": {"public": true}}}], "n": null}`)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantPath []interface{}
+		wantMsg  string
+	}{
+		{
+			name:     "missing key deep in the path",
+			err:      obj.Get("users", 0, "preferences", "privacy", "publik").Error(),
+			wantPath: []interface{}{"users", 0, "preferences", "privacy", "publik"},
+			wantMsg:  "jsonjs.Get: users[0].preferences.privacy.publik: key not found",
+		},
+		{
+			name:     "stops at the failing key",
+			err:      obj.Get("users", 0, "prefs", "privacy").Error(),
+			wantPath: []interface{}{"users", 0, "prefs"},
+			wantMsg:  "jsonjs.Get: users[0].prefs: key not found",
+		},
+		{
+			name:     "index out of range",
+			err:      obj.Get("users", 3, "name").Error(),
+			wantPath: []interface{}{"users", 3},
+			wantMsg:  "jsonjs.Get: users[3]: index out of range: index 3 (length: 1)",
+		},
+		{
+			name:     "null value",
+			err:      obj.Get("n", "x").Error(),
+			wantPath: []interface{}{"n", "x"},
+			wantMsg:  "jsonjs.Get: n.x: type mismatch: cannot access key x on nil value",
+		},
+		{
+			name:     "compiled path",
+			err:      JSON.CompilePath("users", 0, "age").Get(obj).Error(),
+			wantPath: []interface{}{"users", 0, "age"},
+			wantMsg:  "jsonjs.Get: users[0].age: key not found",
+		},
+		{
+			name:    "conversion errors have no path",
+			err:     errOf(obj.Get("users", 0, "name").Int()),
+			wantMsg: `jsonjs.Int: type mismatch: cannot convert string "Ann" to int`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jerr *JSON.JSONError
+			if !errors.As(tt.err, &jerr) {
+				t.Fatalf("Expected a *JSONError, got %v", tt.err)
+			}
+			if !reflect.DeepEqual(jerr.Path(), tt.wantPath) {
+				t.Errorf("Expected path %v, got %v", tt.wantPath, jerr.Path())
+			}
+			if tt.err.Error() != tt.wantMsg {
+				t.Errorf("Expected %q, got %q", tt.wantMsg, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestSyntaxErrorPosition(t *testing.T) {
+	config := "{\n  \"name\": \"api\",\n  \"port\": ,\n  \"debug\": true\n}"
+
+	tests := []struct {
+		name        string
+		err         error
+		wantLine    int
+		wantColumn  int
+		wantExcerpt string
+	}{
+		{
+			name:        "parser",
+			err:         JSON.Parse(config).Error(),
+			wantLine:    3,
+			wantColumn:  11,
+			wantExcerpt: `  "port": ,`,
+		},
+		{
+			name:        "struct destination",
+			err:         JSON.ParseInto(config, &struct{ Port int }{}),
+			wantLine:    3,
+			wantColumn:  11,
+			wantExcerpt: `  "port": ,`,
+		},
+		{
+			name:        "end of input",
+			err:         JSON.Parse("[1,\n 2").Error(),
+			wantLine:    2,
+			wantColumn:  3,
+			wantExcerpt: " 2",
+		},
+		{
+			name:        "long line",
+			err:         JSON.Parse(`{"description": "a long value on one line", "count": 12x}`).Error(),
+			wantLine:    1,
+			wantColumn:  56,
+			wantExcerpt: `...e line", "count": 12x}`,
+		},
+		{
+			name:        "columns count characters",
+			err:         JSON.Parse(`["héllo" x]`).Error(),
+			wantLine:    1,
+			wantColumn:  10,
+			wantExcerpt: `["héllo" x]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, JSON.ErrSyntax) {
+				t.Errorf("Expected errors.Is(err, ErrSyntax), got %v", tt.err)
+			}
+			var serr *JSON.SyntaxError
+			if !errors.As(tt.err, &serr) {
+				t.Fatalf("Expected a *SyntaxError, got %v", tt.err)
+			}
+			if serr.Line != tt.wantLine || serr.Column != tt.wantColumn {
+				t.Errorf("Expected line %d, column %d, got line %d, column %d", tt.wantLine, tt.wantColumn, serr.Line, serr.Column)
+			}
+			if serr.Excerpt != tt.wantExcerpt {
+				t.Errorf("Expected excerpt %q, got %q", tt.wantExcerpt, serr.Excerpt)
+			}
+		})
+	}
+}
+
+func TestResult(t *testing.T) {
+	obj := JSON.Parse(`{"user": {"name": "Ann", "address": null}}`)
+
+	v, err := obj.Get("user", "name").Result()
+	if err != nil || v != "Ann" {
+		t.Errorf("Expected (Ann, nil), got (%v, %v)", v, err)
+	}
+	v, err = obj.Get("user", "address").Result()
+	if err != nil || v != nil {
+		t.Errorf("Expected (nil, nil) for null, got (%v, %v)", v, err)
+	}
+	v, err = obj.Get("user", "email").Result()
+	if !errors.Is(err, JSON.ErrKeyNotFound) || v != nil {
+		t.Errorf("Expected (nil, ErrKeyNotFound), got (%v, %v)", v, err)
+	}
+}
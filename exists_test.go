@@ -0,0 +1,73 @@
+package jsjson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestExistsAndIsMissing(t *testing.T) {
+	obj := JSON.Parse(`{"name": "Ann", "nickname": null, "tags": ["a"]}`)
+
+	tests := []struct {
+		name        string
+		value       JSON.JSONValue
+		wantExists  bool
+		wantMissing bool
+	}{
+		{"present", obj.Get("name"), true, false},
+		{"present null", obj.Get("nickname"), true, false},
+		{"missing key", obj.Get("email"), false, true},
+		{"index past the end", obj.Get("tags", 1), false, true},
+		{"key below null", obj.Get("nickname", "first"), false, true},
+		{"key on a string", obj.Get("name", "first"), false, false},
+		{"syntax error", JSON.Parse(`{`), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.value.Exists(); got != tt.wantExists {
+				t.Errorf("Exists() = %v, want %v", got, tt.wantExists)
+			}
+			if got := tt.value.IsMissing(); got != tt.wantMissing {
+				t.Errorf("IsMissing() = %v, want %v", got, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestGetOrMissing(t *testing.T) {
+	patch := JSON.Parse(`{"nickname": null, "age": 31, "name": "Ann"}`)
+
+	tests := []struct {
+		name    string
+		keys    []interface{}
+		want    interface{}
+		wantErr error
+	}{
+		{"missing key takes the default", []interface{}{"email"}, "ann@example.com", nil},
+		{"present null stays null", []interface{}{"nickname"}, nil, nil},
+		{"present value", []interface{}{"age"}, float64(31), nil},
+		{"wrong type is an error", []interface{}{"name", "first"}, nil, JSON.ErrTypeMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := patch.GetOrMissing("ann@example.com", tt.keys...)
+			if tt.wantErr != nil {
+				if !errors.Is(got.Error(), tt.wantErr) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, got.Error())
+				}
+				return
+			}
+			if got.Error() != nil {
+				t.Fatalf("Unexpected error: %v", got.Error())
+			}
+			if !reflect.DeepEqual(got.Raw(), tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got.Raw())
+			}
+		})
+	}
+}
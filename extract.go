@@ -0,0 +1,87 @@
+package jsjson
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// -------------------- Batch Extraction --------------------
+
+// optionalTarget marks a destination Extract may leave unset
+type optionalTarget struct {
+	dest interface{}
+}
+
+// Optional marks an Extract destination whose path may be missing; the
+// destination keeps its value when it is
+func Optional(dest interface{}) interface{} {
+	return optionalTarget{dest: dest}
+}
+
+// Extract reads many fields at once and reports every failure together, so
+// a request handler can show all problems instead of the first:
+//
+//	var name string
+//	var age int
+//	var tags []string
+//	nickname := "anonymous"
+//	err := jsjson.Extract(body, map[string]interface{}{
+//	    "user.name":     &name,
+//	    "user.age":      &age,
+//	    "user.tags":     &tags,
+//	    "user.nickname": jsjson.Optional(&nickname),
+//	})
+//
+// Keys are dotted paths whose numeric segments index arrays, and values are
+// pointers converted as GetAs converts to their element type. A missing
+// path fails with the "required" rule unless its destination is wrapped in
+// Optional, and a value that does not convert fails with the "type" rule.
+// The error matches ErrValidation and lists one *Violation per failed path,
+// sorted by path; destinations of failed paths are left unchanged.
+func Extract(j JSONValue, targets map[string]interface{}) error {
+	if j.err != nil {
+		return j.err
+	}
+
+	var violations []*Violation
+	for path, dest := range targets {
+		optional := false
+		if o, ok := dest.(optionalTarget); ok {
+			dest, optional = o.dest, true
+		}
+		rv := reflect.ValueOf(dest)
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return &JSONError{Op: "Extract", Err: fmt.Errorf("destination for %q must be a non-nil pointer, got %T", path, dest)}
+		}
+
+		var keys []interface{}
+		if path != "" {
+			for _, k := range strings.Split(path, ".") {
+				keys = append(keys, k)
+			}
+		}
+		v := j.Get(keys...)
+		if v.IsMissing() {
+			if !optional {
+				violations = append(violations, &Violation{Path: path, Rule: "required", Message: "is required"})
+			}
+			continue
+		}
+
+		// Convert into a fresh value so a failure leaves dest as it was
+		tmp := reflect.New(rv.Type().Elem())
+		if err := convertInto(v, tmp.Interface()); err != nil {
+			violations = append(violations, &Violation{Path: path, Rule: "type", Message: unwrapOp(err).Error()})
+			continue
+		}
+		rv.Elem().Set(tmp.Elem())
+	}
+
+	if len(violations) > 0 {
+		sort.Slice(violations, func(a, b int) bool { return violations[a].Path < violations[b].Path })
+		return &JSONError{Op: "Extract", Err: &ValidationError{Violations: violations}}
+	}
+	return nil
+}
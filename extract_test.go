@@ -0,0 +1,78 @@
+package jsjson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestExtract(t *testing.T) {
+	body := JSON.Parse(`{"user": {"name": "Ann", "age": "thirty", "tags": ["a", "b"], "roles": [{"id": 7}]}}`)
+
+	var (
+		name     string
+		age      = -1
+		tags     []string
+		email    string
+		roleID   int
+		nickname = "anonymous"
+	)
+	err := JSON.Extract(body, map[string]interface{}{
+		"user.name":       &name,
+		"user.age":        &age,
+		"user.tags":       &tags,
+		"user.email":      &email,
+		"user.roles.0.id": &roleID,
+		"user.nickname":   JSON.Optional(&nickname),
+	})
+
+	if !errors.Is(err, JSON.ErrValidation) {
+		t.Fatalf("Expected ErrValidation, got %v", err)
+	}
+	var verr *JSON.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected a *ValidationError, got %v", err)
+	}
+	var got []string
+	for _, v := range verr.Violations {
+		got = append(got, v.Path+" "+v.Rule)
+	}
+	if want := []string{"user.age type", "user.email required"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected violations %v, got %v", want, got)
+	}
+
+	if name != "Ann" || roleID != 7 || !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("Expected fields to be set, got name=%q roleID=%d tags=%v", name, roleID, tags)
+	}
+	if age != -1 {
+		t.Errorf("Expected a failed field to be left unchanged, got %d", age)
+	}
+	if nickname != "anonymous" {
+		t.Errorf("Expected a missing optional field to be left unchanged, got %q", nickname)
+	}
+}
+
+func TestExtractSuccess(t *testing.T) {
+	var port int
+	var host string
+	err := JSON.Extract(JSON.Parse(`{"server": {"host": "localhost", "port": 8080}}`), map[string]interface{}{
+		"server.host": &host,
+		"server.port": &port,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if host != "localhost" || port != 8080 {
+		t.Errorf("Expected localhost:8080, got %s:%d", host, port)
+	}
+}
+
+func TestExtractInvalidDestination(t *testing.T) {
+	var n int
+	err := JSON.Extract(JSON.Parse(`{"n": 1}`), map[string]interface{}{"n": n})
+	if err == nil || errors.Is(err, JSON.ErrValidation) {
+		t.Errorf("Expected a destination error, got %v", err)
+	}
+}
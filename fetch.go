@@ -0,0 +1,202 @@
+package jsjson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// -------------------- Remote Documents --------------------
+
+// Fetcher keeps a local copy of a remote JSON document, such as feature
+// flags or configuration, by polling its URL with conditional requests:
+//
+//	f := &jsjson.Fetcher{URL: "https://config.example.com/flags.json", Interval: 30 * time.Second}
+//	changes := f.Changes()
+//	go f.Run(ctx)
+//	for doc := range changes { // ends when Run returns
+//		apply(doc)
+//	}
+//
+// Requests carry If-None-Match and If-Modified-Since from the last response,
+// so an unchanged document costs a 304 and no parsing. Set the exported
+// fields before the first Fetch or Run; the methods are safe for concurrent
+// use.
+type Fetcher struct {
+	URL string
+	// Client sends the requests; nil means http.DefaultClient
+	Client *http.Client
+	// Interval is the time between polls in Run; zero means one minute
+	Interval time.Duration
+	// Options apply when parsing each new version, as in GetJSON
+	Options []ParseOption
+
+	mu           sync.Mutex
+	current      JSONValue
+	fetched      bool
+	lastErr      error
+	etag         string
+	lastModified string
+	changes      chan JSONValue
+}
+
+// Fetch requests the document once and reports whether it changed. The
+// first successful fetch is always a change; after that, a 304 response or
+// a document equal in value to the current one is not.
+func (f *Fetcher) Fetch(ctx context.Context) (changed bool, err error) {
+	defer func() {
+		f.mu.Lock()
+		f.lastErr = err
+		f.mu.Unlock()
+	}()
+
+	f.mu.Lock()
+	etag, lastModified := f.etag, f.lastModified
+	f.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.URL, nil)
+	if err != nil {
+		return false, &JSONError{Op: "Fetch", Err: err}
+	}
+	req.Header.Set("Accept", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, &JSONError{Op: "Fetch", Err: err}
+	}
+	defer drainBody(resp.Body)
+
+	if resp.StatusCode == http.StatusNotModified {
+		return false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return false, &JSONError{Op: "Fetch", Err: &StatusError{Method: http.MethodGet, URL: f.URL, StatusCode: resp.StatusCode, Status: resp.Status, Body: snippet}}
+	}
+
+	dec := NewDecoder(resp.Body, f.Options...)
+	jv, err := dec.Decode()
+	if err == io.EOF {
+		err = fmt.Errorf("%w: empty body", ErrSyntax)
+	} else if err == nil {
+		if _, err = dec.Decode(); err == io.EOF {
+			err = nil
+		} else {
+			err = fmt.Errorf("%w: data after the JSON value", ErrSyntax)
+		}
+	}
+	if err != nil {
+		var jerr *JSONError
+		if errors.As(err, &jerr) {
+			err = jerr.Err
+		}
+		return false, &JSONError{Op: "Fetch", Err: err}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.etag = resp.Header.Get("ETag")
+	f.lastModified = resp.Header.Get("Last-Modified")
+	if f.fetched && jsonEqual(jv.data, f.current.data) {
+		return false, nil
+	}
+	f.current, f.fetched = jv, true
+	f.notify(jv)
+	return true, nil
+}
+
+// Run fetches the document now and then every Interval until ctx is done,
+// and returns ctx's error. Failed fetches keep the current document; Err
+// reports the most recent failure. When Run returns it closes the Changes
+// channel, so a range over it ends.
+func (f *Fetcher) Run(ctx context.Context) error {
+	interval := f.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer f.closeChanges()
+	for {
+		f.Fetch(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Latest returns the most recently fetched document. Before the first
+// successful fetch it holds the last fetch error, or an error saying nothing
+// has been fetched.
+func (f *Fetcher) Latest() JSONValue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fetched {
+		return f.current
+	}
+	if f.lastErr != nil {
+		return JSONValue{err: f.lastErr}
+	}
+	return JSONValue{err: &JSONError{Op: "Latest", Err: errors.New("no document fetched yet")}}
+}
+
+// Err returns the error of the most recent fetch, or nil if it succeeded
+func (f *Fetcher) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastErr
+}
+
+// Changes returns a channel receiving each new version of the document. It
+// holds at most one pending version: a slow receiver gets the newest one,
+// not a backlog, and fetching never waits for it. The channel is closed
+// when Run returns; after that Changes returns a new channel.
+func (f *Fetcher) Changes() <-chan JSONValue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.changesLocked()
+}
+
+func (f *Fetcher) changesLocked() chan JSONValue {
+	if f.changes == nil {
+		f.changes = make(chan JSONValue, 1)
+	}
+	return f.changes
+}
+
+// closeChanges closes the Changes channel, keeping a pending version
+// readable, and lets the next Changes or notify create a new one
+func (f *Fetcher) closeChanges() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.changes != nil {
+		close(f.changes)
+		f.changes = nil
+	}
+}
+
+// notify replaces any pending version with jv; f.mu must be held
+func (f *Fetcher) notify(jv JSONValue) {
+	ch := f.changesLocked()
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- jv
+}
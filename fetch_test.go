@@ -0,0 +1,150 @@
+package jsjson_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestFetcher(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		body     = `{"beta": false}`
+		etag     = `"v1"`
+		requests int
+		full     int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	set := func(b, e string) {
+		mu.Lock()
+		body, etag = b, e
+		mu.Unlock()
+	}
+
+	f := &JSON.Fetcher{URL: srv.URL}
+	ctx := context.Background()
+	if err := f.Latest().Error(); err == nil {
+		t.Error("Expected an error before the first fetch")
+	}
+
+	steps := []struct {
+		name        string
+		body, etag  string
+		wantChanged bool
+		wantBeta    bool
+	}{
+		{"first fetch", `{"beta": false}`, `"v1"`, true, false},
+		{"not modified", `{"beta": false}`, `"v1"`, false, false},
+		{"same value, new etag", `{ "beta": false }`, `"v2"`, false, false},
+		{"changed", `{"beta": true}`, `"v3"`, true, true},
+	}
+	for _, s := range steps {
+		set(s.body, s.etag)
+		changed, err := f.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", s.name, err)
+		}
+		if changed != s.wantChanged {
+			t.Errorf("%s: expected changed=%v", s.name, s.wantChanged)
+		}
+		if beta := f.Latest().Get("beta").BoolOr(!s.wantBeta); beta != s.wantBeta {
+			t.Errorf("%s: expected beta=%v", s.name, s.wantBeta)
+		}
+	}
+	if requests != 4 || full != 3 {
+		t.Errorf("Expected 4 requests with 3 full responses, got %d and %d", requests, full)
+	}
+
+	// Two changes were made; only the newest is pending
+	select {
+	case jv := <-f.Changes():
+		if !jv.Get("beta").BoolOr(false) {
+			t.Errorf("Expected the newest version, got %v", jv.Raw())
+		}
+	default:
+		t.Error("Expected a pending change")
+	}
+	select {
+	case jv := <-f.Changes():
+		t.Errorf("Expected no further change, got %v", jv.Raw())
+	default:
+	}
+}
+
+func TestFetcherRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"n": 1}`))
+	}))
+	defer srv.Close()
+
+	f := &JSON.Fetcher{URL: srv.URL, Interval: 5 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- f.Run(ctx) }()
+
+	select {
+	case jv := <-f.Changes():
+		if n, _ := jv.Get("n").Int(); n != 1 {
+			t.Errorf("Expected n=1, got %v", jv.Raw())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a change")
+	}
+	changes := f.Changes()
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	// The channel is closed once Run returns, so a range over it ends
+	for range changes {
+	}
+}
+
+func TestFetcherErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	f := &JSON.Fetcher{URL: srv.URL}
+	_, err := f.Fetch(context.Background())
+	var statusErr *JSON.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a 503 StatusError, got %v", err)
+	}
+	if f.Err() != err || f.Latest().Error() != err {
+		t.Errorf("Expected Err and Latest to report %v", err)
+	}
+
+	for _, body := range []string{`{"a":1}{"b":2}`, `{} garbage`} {
+		trailing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+		f := &JSON.Fetcher{URL: trailing.URL}
+		if _, err := f.Fetch(context.Background()); !errors.Is(err, JSON.ErrSyntax) {
+			t.Errorf("Expected ErrSyntax for trailing data in %s, got %v", body, err)
+		}
+		if f.Latest().IsValid() {
+			t.Errorf("Expected no document from %s", body)
+		}
+		trailing.Close()
+	}
+}
@@ -0,0 +1,189 @@
+package jsjson
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// -------------------- Files --------------------
+
+// ParseFile reads and parses a JSON file. Files compressed with gzip or
+// zstd, such as .json.gz and .json.zst archives, are decompressed on the
+// fly. opts apply as in ParseReader. Parse errors name the file.
+func ParseFile(path string, opts ...ParseOption) JSONValue {
+	f, err := os.Open(path)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseFile", Err: err}}
+	}
+	defer f.Close()
+
+	jv, err := parseStream(f, opts)
+	if err != nil {
+		return JSONValue{err: withOp("ParseFile", fmt.Errorf("%s: %w", path, unwrapOp(err)))}
+	}
+	return jv
+}
+
+// ParseDir parses the files in dir whose names match pattern, in lexical
+// order, and deep-merges them into one object, conf.d style:
+//
+//	cfg := jsjson.ParseDir("/etc/app/conf.d", "*.json")
+//
+// Later files win: objects are merged key by key, and any other value
+// replaces the one from earlier files. pattern uses filepath.Match syntax;
+// directories and files not matching it are skipped. Every file must hold an
+// object, and opts apply to each file as in Parse. A directory with no
+// matching files gives an empty object.
+func ParseDir(dir, pattern string, opts ...ParseOption) JSONValue {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseDir", Err: err}}
+	}
+
+	merged := make(map[string]interface{})
+	for _, entry := range entries {
+		ok, err := filepath.Match(pattern, entry.Name())
+		if err != nil {
+			return JSONValue{err: &JSONError{Op: "ParseDir", Err: err}}
+		}
+		if !ok || entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		jv := ParseFile(path, opts...)
+		if jv.err != nil {
+			return jv
+		}
+		if _, ok := jv.data.(map[string]interface{}); !ok {
+			return JSONValue{err: &JSONError{Op: "ParseDir", Err: fmt.Errorf("%s: %w: expected an object, got %s", path, ErrTypeMismatch, jv.Type())}}
+		}
+		mergeTrees(merged, jv.data)
+	}
+	return JSONValue{data: merged}
+}
+
+// WriteOption configures Stringify, StringifyPretty and WriteFile
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	indent    string
+	ascii     bool
+	nonFinite NonFiniteMode
+}
+
+// WithIndent pretty-prints the output, indenting each level with indent
+func WithIndent(indent string) WriteOption {
+	return func(o *writeOptions) { o.indent = indent }
+}
+
+// WithASCII escapes every non-ASCII character in the output as \uXXXX,
+// using a surrogate pair above U+FFFF, for consumers that only accept ASCII.
+// By default such characters are written as UTF-8.
+func WithASCII() WriteOption {
+	return func(o *writeOptions) { o.ascii = true }
+}
+
+// encodeWith encodes v as the options describe
+func encodeWith(v interface{}, o *writeOptions) ([]byte, error) {
+	v = o.replaceNonFinite(v)
+	var b []byte
+	var err error
+	if o.indent != "" {
+		b, err = marshalIndent(v, o.indent)
+	} else {
+		err = encodeInto(&b, v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if o.ascii {
+		b = escapeNonASCII(b)
+	}
+	return b, nil
+}
+
+// WriteFile writes the value to path as JSON followed by a newline, with
+// the permissions perm, compressed with gzip or zstd if path ends in .gz or
+// .zst:
+//
+//	err := cfg.WriteFile("config.json", 0o644, jsjson.WithIndent("  "))
+//
+// The file is replaced atomically: the document is written and synced to a
+// temporary file in the same directory, which is then renamed over path, so
+// readers see either the old file or the complete new one, never a partial
+// write. Nothing is written if the value cannot be encoded.
+func (j JSONValue) WriteFile(path string, perm os.FileMode, opts ...WriteOption) error {
+	if j.err != nil {
+		return j.err
+	}
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	b, err := encodeWith(j.data, &o)
+	if err != nil {
+		return &JSONError{Op: "WriteFile", Err: err}
+	}
+	b = append(b, '\n')
+	if b, err = compressForPath(path, b); err != nil {
+		return &JSONError{Op: "WriteFile", Err: err}
+	}
+
+	if err := writeFileAtomic(path, b, perm); err != nil {
+		return &JSONError{Op: "WriteFile", Err: err}
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path, renames it
+// into place and syncs the directory so the rename survives a crash
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+	if err = f.Chmod(perm); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir flushes a directory entry change such as a rename to disk. Windows
+// cannot open directories for syncing and needs no separate step.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
@@ -0,0 +1,110 @@
+package jsjson_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestParseFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"ok.json":  `{"name": "John", "tags": [1, 2, 3]}`,
+		"bad.json": `{"name": }`,
+	})
+
+	jv := JSON.ParseFile(filepath.Join(dir, "ok.json"))
+	if name, _ := jv.Get("name").String(); name != "John" {
+		t.Errorf("Expected John, got %v (%v)", jv.Raw(), jv.Error())
+	}
+
+	if err := JSON.ParseFile(filepath.Join(dir, "ok.json"), JSON.WithMaxArrayElements(2)).Error(); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got %v", err)
+	}
+	if err := JSON.ParseFile(filepath.Join(dir, "missing.json")).Error(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist, got %v", err)
+	}
+	err := JSON.ParseFile(filepath.Join(dir, "bad.json")).Error()
+	if err == nil || !strings.Contains(err.Error(), "bad.json") {
+		t.Errorf("Expected an error naming bad.json, got %v", err)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	jv := JSON.Parse(`{"b": [1, 2], "a": "x"}`)
+
+	tests := []struct {
+		name string
+		opts []JSON.WriteOption
+		want string
+	}{
+		{"compact", nil, "{\"a\":\"x\",\"b\":[1,2]}\n"},
+		{"indented", []JSON.WriteOption{JSON.WithIndent("  ")}, "{\n  \"a\": \"x\",\n  \"b\": [\n    1,\n    2\n  ]\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := jv.WriteFile(path, 0o600, tt.opts...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("Expected %q, got %q (%v)", tt.want, got, err)
+			}
+			if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+				t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+			}
+		})
+	}
+
+	if err := JSON.Parse(`{`).WriteFile(path, 0o600); err == nil {
+		t.Error("Expected the value's error")
+	}
+	if err := JSON.Parse(`1`).WriteFile(filepath.Join(dir, "missing", "x.json"), 0o600); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only config.json to remain, got %v", entries)
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"10-base.json":   `{"server": {"host": "localhost", "port": 8080}, "features": ["a"]}`,
+		"20-prod.json":   `{"server": {"host": "prod.example.com"}, "features": ["b"]}`,
+		"05-early.json":  `{"server": {"port": 1}, "debug": true}`,
+		"99-notes.txt":   `not json`,
+		"30-extra.json~": `{"debug": false}`,
+	})
+	if err := os.Mkdir(filepath.Join(dir, "40-dir.json"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := JSON.ParseDir(dir, "*.json")
+	want := JSON.Parse(`{"server": {"host": "prod.example.com", "port": 8080}, "features": ["b"], "debug": true}`)
+	if err := got.Error(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got.Raw(), want.Raw()) {
+		s, _ := JSON.Stringify(got)
+		t.Errorf("Unexpected result %s", s)
+	}
+
+	if got := JSON.ParseDir(dir, "*.yaml"); got.Error() != nil || got.Type() != "object" {
+		t.Errorf("Expected an empty object, got %v (%v)", got.Raw(), got.Error())
+	}
+	if err := JSON.ParseDir(filepath.Join(dir, "missing"), "*.json").Error(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist, got %v", err)
+	}
+
+	bad := writeFiles(t, map[string]string{"a.json": `{}`, "b.json": `[1]`})
+	if err := JSON.ParseDir(bad, "*.json").Error(); !errors.Is(err, JSON.ErrTypeMismatch) || !strings.Contains(err.Error(), "b.json") {
+		t.Errorf("Expected ErrTypeMismatch naming b.json, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package jsjson
+
+import "strings"
+
+// -------------------- Case-Insensitive Access --------------------
+
+// GetFold is like Get but matches object keys case-insensitively when there
+// is no exact match, so "UserId", "userId" and "userid" all find the same
+// member. This helps when several APIs disagree on the casing of the same
+// fields. If more than one key matches, the lexically smallest wins, which
+// keeps results stable regardless of map order.
+func (j JSONValue) GetFold(keys ...interface{}) JSONValue {
+	return j.get("GetFold", true, keys)
+}
+
+// GetFoldOr returns the value at the given keys, matched as by GetFold, or
+// the default value if not found/error
+func (j JSONValue) GetFoldOr(defaultValue interface{}, keys ...interface{}) interface{} {
+	result := j.GetFold(keys...)
+	if result.err != nil {
+		return defaultValue
+	}
+	return result.data
+}
+
+// foldLookup finds the member of obj whose key equals key under Unicode
+// case folding
+func foldLookup(obj map[string]interface{}, key string) (string, interface{}, bool) {
+	var (
+		actual string
+		value  interface{}
+		found  bool
+	)
+	for k, v := range obj {
+		if strings.EqualFold(k, key) && (!found || k < actual) {
+			actual, value, found = k, v, true
+		}
+	}
+	return actual, value, found
+}
@@ -0,0 +1,57 @@
+package jsjson_test
+
+import (
+	"errors"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestGetFold(t *testing.T) {
+	obj := JSON.Parse(`{"UserId": 1, "profile": {"DisplayName": "Ada", "TAGS": ["x"]}, "name": "exact", "NAME": "upper"}`)
+
+	tests := []struct {
+		name string
+		keys []interface{}
+		want interface{}
+	}{
+		{"exact", []interface{}{"UserId"}, 1.0},
+		{"lower", []interface{}{"userid"}, 1.0},
+		{"camel", []interface{}{"userId"}, 1.0},
+		{"nested", []interface{}{"PROFILE", "displayname"}, "Ada"},
+		{"through array", []interface{}{"Profile", "tags", 0}, "x"},
+		{"exact match wins", []interface{}{"NAME"}, "upper"},
+		{"smallest fold wins", []interface{}{"Name"}, "upper"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := obj.GetFold(tt.keys...)
+			if got.Error() != nil || got.Raw() != tt.want {
+				t.Errorf("Expected %v, got %v (%v)", tt.want, got.Raw(), got.Error())
+			}
+		})
+	}
+
+	missing := obj.GetFold("user_id")
+	if !errors.Is(missing.Error(), JSON.ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", missing.Error())
+	}
+	var jsonErr *JSON.JSONError
+	if !errors.As(missing.Error(), &jsonErr) || jsonErr.Op != "GetFold" {
+		t.Errorf("Expected a GetFold error, got %v", missing.Error())
+	}
+	if obj.Get("userid").IsValid() {
+		t.Error("Get must stay case-sensitive")
+	}
+	if got := obj.GetFoldOr("none", "PROFILE", "missing"); got != "none" {
+		t.Errorf("Expected default, got %v", got)
+	}
+}
+
+func TestGetFoldRawBytes(t *testing.T) {
+	obj := JSON.Parse(`{"Data": {"Items": [1, 2]}}`, JSON.ParseWithRaw())
+	raw, err := obj.GetFold("data", "items").RawBytes()
+	if err != nil || string(raw) != "[1, 2]" {
+		t.Errorf("Expected the original bytes, got %q (%v)", raw, err)
+	}
+}
@@ -0,0 +1,98 @@
+package jsjson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// -------------------- Fuzzing --------------------
+
+// CheckRoundTrip checks the invariants jsjson keeps for the input data and
+// returns an error describing the first one broken. Input that is not valid
+// JSON passes as long as Parse rejects it without panicking. For valid JSON:
+//
+//   - ParseNoCopy builds the same tree as Parse
+//   - Stringify succeeds, and parsing its output gives an equal value
+//   - Stringify of that value gives the same text again
+//
+// It is meant for fuzz targets that exercise jsjson alongside the caller's
+// own code:
+//
+//	f.Fuzz(func(t *testing.T, data []byte) {
+//		if err := jsjson.CheckRoundTrip(data); err != nil {
+//			t.Fatal(err)
+//		}
+//		handle(jsjson.Parse(data))
+//	})
+func CheckRoundTrip(data []byte) error {
+	first := Parse(data)
+	if first.err != nil {
+		return nil
+	}
+	if noCopy := ParseNoCopy(append([]byte(nil), data...)); noCopy.err != nil || !jsonEqual(noCopy.data, first.data) {
+		return fmt.Errorf("ParseNoCopy differs from Parse for %q", data)
+	}
+
+	text, err := Stringify(first)
+	if err != nil {
+		return fmt.Errorf("Stringify failed for %q: %v", data, err)
+	}
+	second := Parse(text)
+	if second.err != nil {
+		return fmt.Errorf("Stringify output %q of %q does not parse: %v", text, data, second.err)
+	}
+	if !jsonEqual(first.data, second.data) {
+		return fmt.Errorf("round trip of %q through %q changed the value", data, text)
+	}
+	if again, err := Stringify(second); err != nil || again != text {
+		return fmt.Errorf("Stringify of %q is not stable: %q, then %q", data, text, again)
+	}
+	return nil
+}
+
+// FuzzParse is a go-fuzz style entry point: it panics if CheckRoundTrip
+// fails, and returns 1 for valid JSON and 0 otherwise, so the fuzzer
+// favors inputs that parse
+func FuzzParse(data []byte) int {
+	if err := CheckRoundTrip(data); err != nil {
+		panic(err)
+	}
+	if Parse(data).err != nil {
+		return 0
+	}
+	return 1
+}
+
+// FuzzCorpus returns seed inputs for fuzzing code that parses JSON: valid
+// documents covering every value type, escapes, unusual numbers and Unicode,
+// deep nesting, and near misses such as truncated or trailing data. Each
+// call returns a new slice the caller may modify.
+//
+//	for _, seed := range jsjson.FuzzCorpus() {
+//		f.Add(seed)
+//	}
+func FuzzCorpus() [][]byte {
+	seeds := []string{
+		// Scalars and numbers
+		`null`, `true`, `false`, `0`, `-0`, `1`, `-1`, `3.25`, `1e3`, `-2.5E-3`, `1E+2`,
+		`9007199254740993`, `12345678901234567890123`, `1.7976931348623157e308`, `5e-324`, `0.1`,
+		// Strings and escapes
+		`""`, `"plain"`, `"\"\\\/\b\f\n\r\t"`, `"\u0000\u001f\u007f"`, `"é日"`,
+		`"😀"`, `"lone \ud800"`, `"bad pair \ud800A"`, `"<script>&amp;</script>"`,
+		"\"raw \xe2\x82\xac and \xff\"", "\"  \"",
+		// Containers
+		`[]`, `{}`, `[[]]`, `{"":{}}`, `[1,"two",[3],{"four":4},null,true]`,
+		`{"a":{"b":[null,true,{"c":"d"}]}}`, `{"dup":1,"dup":2}`, " \t\r\n{ \"a\" : [ 1 , 2 ] } \n",
+		strings.Repeat("[", 100) + strings.Repeat("]", 100),
+		strings.Repeat(`{"a":`, 50) + `1` + strings.Repeat("}", 50),
+		// Near misses
+		``, ` `, `{`, `[1,]`, `{"a":1,}`, `{"a" 1}`, `{'a':1}`, `[1 2]`, `01`, `1.`, `.5`, `+1`,
+		`-`, `1e`, `NaN`, `Infinity`, `tru`, `nul`, `"unterminated`, `"bad \x escape"`,
+		"\"control \x01 char\"", `{} {}`, `[] x`, "\xef\xbb\xbf{}", `/* comment */ {}`,
+	}
+	out := make([][]byte, len(seeds))
+	for i, s := range seeds {
+		out[i] = []byte(s)
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package jsjson_test
+
+import (
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestFuzzCorpus(t *testing.T) {
+	valid := 0
+	for _, seed := range JSON.FuzzCorpus() {
+		if err := JSON.CheckRoundTrip(seed); err != nil {
+			t.Error(err)
+		}
+		valid += JSON.FuzzParse(seed)
+	}
+	if corpus := JSON.FuzzCorpus(); valid == 0 || valid == len(corpus) {
+		t.Errorf("Expected both valid and invalid seeds, got %d valid of %d", valid, len(corpus))
+	}
+}
+
+// FuzzRoundTrip checks the CheckRoundTrip invariants
+func FuzzRoundTrip(f *testing.F) {
+	for _, seed := range JSON.FuzzCorpus() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := JSON.CheckRoundTrip(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,308 @@
+package jsjson
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// -------------------- Random Documents --------------------
+
+// maxGenerateDepth bounds recursion through $ref: below it, optional
+// properties are left out and arrays get their minimum length, and four
+// times as deep generation fails
+const maxGenerateDepth = 8
+
+// Generate returns a random document conforming to a JSON Schema, for
+// property-based tests and load-test payloads. The same schema and seed
+// always give the same document. To generate documents shaped like a
+// sample, pass the sample's inferred schema:
+//
+//	user := jsjson.Generate(sample.InferSchema(), 42)
+//
+// The supported keywords are type, enum, const, properties, required,
+// items, minItems, maxItems, minLength, maxLength, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, multipleOf (for integers), format
+// (date-time, date, email, uuid and uri), oneOf, anyOf and local $ref to
+// $defs or definitions. Required properties are always present and other
+// properties present at random. Other keywords, such as pattern, are
+// ignored.
+func Generate(schema JSONValue, seed int64) JSONValue {
+	if schema.err != nil {
+		return JSONValue{err: &JSONError{Op: "Generate", Err: schema.err}}
+	}
+	s, ok := schema.data.(map[string]interface{})
+	if !ok {
+		return JSONValue{err: &JSONError{Op: "Generate", Err: fmt.Errorf("%w: schema must be an object, got %T", ErrTypeMismatch, schema.data)}}
+	}
+	g := &generator{root: s, rnd: rand.New(rand.NewSource(seed))}
+	v, err := g.value(s, 0)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "Generate", Err: err}}
+	}
+	return JSONValue{data: v}
+}
+
+type generator struct {
+	root map[string]interface{}
+	rnd  *rand.Rand
+}
+
+// value generates a value for the schema s
+func (g *generator) value(s map[string]interface{}, depth int) (interface{}, error) {
+	if depth > 4*maxGenerateDepth {
+		return nil, fmt.Errorf("schema recursion deeper than %d levels; a required property may refer to its own schema", 4*maxGenerateDepth)
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		target, err := g.resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		return g.value(target, depth+1)
+	}
+	if c, ok := s["const"]; ok {
+		return deepCopy(c), nil
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		return deepCopy(enum[g.rnd.Intn(len(enum))]), nil
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if list, ok := s[key].([]interface{}); ok && len(list) > 0 {
+			sub, ok := list[g.rnd.Intn(len(list))].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s entries must be schemas", key)
+			}
+			return g.value(sub, depth)
+		}
+	}
+
+	switch g.pickType(s) {
+	case "object":
+		return g.object(s, depth)
+	case "array":
+		return g.array(s, depth)
+	case "string":
+		return g.string(s), nil
+	case "integer":
+		return float64(g.integer(s)), nil
+	case "number":
+		return g.number(s), nil
+	case "boolean":
+		return g.rnd.Intn(2) == 1, nil
+	default:
+		return nil, nil
+	}
+}
+
+// pickType chooses one of the schema's types, or infers one from its
+// keywords when it has none
+func (g *generator) pickType(s map[string]interface{}) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		if len(t) > 0 {
+			name, _ := t[g.rnd.Intn(len(t))].(string)
+			return name
+		}
+	}
+	switch {
+	case s["properties"] != nil:
+		return "object"
+	case s["items"] != nil:
+		return "array"
+	case s["format"] != nil || s["minLength"] != nil || s["maxLength"] != nil:
+		return "string"
+	case s["minimum"] != nil || s["maximum"] != nil:
+		return "number"
+	}
+	return []string{"string", "integer", "boolean"}[g.rnd.Intn(3)]
+}
+
+// resolve looks up a local reference such as #/$defs/user
+func (g *generator) resolve(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref %q: only local references are supported", ref)
+	}
+	tokens, err := parsePointer(ref[1:])
+	if err != nil {
+		return nil, fmt.Errorf("$ref %q: %w", ref, err)
+	}
+	target, err := pointerGet(g.root, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("$ref %q: %w", ref, err)
+	}
+	s, ok := target.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("$ref %q does not point to a schema", ref)
+	}
+	return s, nil
+}
+
+func (g *generator) object(s map[string]interface{}, depth int) (interface{}, error) {
+	props, _ := s["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	if list, ok := s["required"].([]interface{}); ok {
+		for _, k := range list {
+			if name, ok := k.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	// Sorted keys keep the random sequence, and so the output, stable
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		if !required[k] && (depth >= maxGenerateDepth || g.rnd.Intn(2) == 0) {
+			continue
+		}
+		ps, ok := props[k].(map[string]interface{})
+		if !ok {
+			ps = map[string]interface{}{}
+		}
+		v, err := g.value(ps, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (g *generator) array(s map[string]interface{}, depth int) (interface{}, error) {
+	lo, hi := intBounds(s, "minItems", "maxItems", 0, 3)
+	n := lo
+	if depth < maxGenerateDepth {
+		n += g.rnd.Intn(hi - lo + 1)
+	}
+	items, ok := s["items"].(map[string]interface{})
+	if !ok {
+		items = map[string]interface{}{}
+	}
+	out := make([]interface{}, n)
+	for i := range out {
+		v, err := g.value(items, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (g *generator) string(s map[string]interface{}) string {
+	base := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	switch s["format"] {
+	case "date-time":
+		return base.Add(time.Duration(g.rnd.Int63n(int64(30 * 365 * 24 * time.Hour)))).Truncate(time.Second).Format(time.RFC3339)
+	case "date":
+		return base.AddDate(0, 0, g.rnd.Intn(30*365)).Format("2006-01-02")
+	case "email":
+		return g.word(3, 8) + "@example.com"
+	case "uuid":
+		var u [16]byte
+		g.rnd.Read(u[:])
+		u[6] = u[6]&0x0f | 0x40
+		u[8] = u[8]&0x3f | 0x80
+		return formatUUID(u)
+	case "uri":
+		return "https://example.com/" + g.word(3, 8)
+	}
+	lo, hi := intBounds(s, "minLength", "maxLength", 3, 10)
+	return g.word(lo, hi)
+}
+
+// word returns lowercase letters, between lo and hi of them
+func (g *generator) word(lo, hi int) string {
+	b := make([]byte, lo+g.rnd.Intn(hi-lo+1))
+	for i := range b {
+		b[i] = 'a' + byte(g.rnd.Intn(26))
+	}
+	return string(b)
+}
+
+func (g *generator) integer(s map[string]interface{}) int64 {
+	lo, hi := numberBounds(s, 0, 1000)
+	min, max := int64(math.Ceil(lo)), int64(math.Floor(hi))
+	if ex, ok := numberValue(s["exclusiveMinimum"]); ok && float64(min) <= ex {
+		min = int64(math.Floor(ex)) + 1
+	}
+	if ex, ok := numberValue(s["exclusiveMaximum"]); ok && float64(max) >= ex {
+		max = int64(math.Ceil(ex)) - 1
+	}
+	if max < min {
+		max = min
+	}
+	if m, ok := numberValue(s["multipleOf"]); ok && m >= 1 && m == math.Trunc(m) {
+		step := int64(m)
+		first := int64(math.Ceil(float64(min)/m)) * step
+		if first <= max {
+			return first + g.rnd.Int63n((max-first)/step+1)*step
+		}
+	}
+	return min + g.rnd.Int63n(max-min+1)
+}
+
+func (g *generator) number(s map[string]interface{}) float64 {
+	lo, hi := numberBounds(s, 0, 1000)
+	if ex, ok := numberValue(s["exclusiveMinimum"]); ok && lo <= ex {
+		lo = ex + 0.01
+	}
+	if ex, ok := numberValue(s["exclusiveMaximum"]); ok && hi >= ex {
+		hi = ex - 0.01
+	}
+	if hi < lo {
+		hi = lo
+	}
+	// Two decimals read well in fixtures; clamping keeps the rounding in range
+	v := math.Round((lo+g.rnd.Float64()*(hi-lo))*100) / 100
+	return math.Min(math.Max(v, lo), hi)
+}
+
+// intBounds reads a pair of length keywords, using lo and hi when absent
+func intBounds(s map[string]interface{}, minKey, maxKey string, lo, hi int) (int, int) {
+	min, hasMin := numberValue(s[minKey])
+	max, hasMax := numberValue(s[maxKey])
+	switch {
+	case hasMin && hasMax:
+		lo, hi = int(min), int(max)
+	case hasMin:
+		lo, hi = int(min), int(min)+hi-lo
+	case hasMax:
+		hi = int(max)
+		if lo > hi {
+			lo = hi
+		}
+	}
+	if lo < 0 {
+		lo = 0
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// numberBounds reads minimum and maximum, using lo and hi when absent
+func numberBounds(s map[string]interface{}, lo, hi float64) (float64, float64) {
+	min, hasMin := numberValue(s["minimum"])
+	max, hasMax := numberValue(s["maximum"])
+	switch {
+	case hasMin && hasMax:
+		return min, max
+	case hasMin:
+		return min, min + (hi - lo)
+	case hasMax:
+		return max - (hi - lo), max
+	}
+	return lo, hi
+}
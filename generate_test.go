@@ -0,0 +1,121 @@
+package jsjson_test
+
+import (
+	"errors"
+	"net/mail"
+	"reflect"
+	"testing"
+	"time"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestGenerateFromSchema(t *testing.T) {
+	schema := JSON.Parse(`{
+		"type": "object",
+		"required": ["id", "email", "status", "tags", "score", "created", "owner"],
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"email": {"type": "string", "format": "email"},
+			"status": {"enum": ["active", "banned"]},
+			"kind": {"const": "user"},
+			"tags": {"type": "array", "items": {"type": "string", "minLength": 2, "maxLength": 4}, "minItems": 1, "maxItems": 3},
+			"score": {"type": "integer", "minimum": 10, "exclusiveMaximum": 20, "multipleOf": 5},
+			"ratio": {"type": "number", "minimum": 0, "maximum": 1},
+			"created": {"type": "string", "format": "date-time"},
+			"owner": {"$ref": "#/$defs/person"}
+		},
+		"$defs": {
+			"person": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "boss": {"$ref": "#/$defs/person"}}}
+		}
+	}`)
+
+	for seed := int64(0); seed < 50; seed++ {
+		doc := JSON.Generate(schema, seed)
+		if err := doc.Error(); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if _, err := doc.Get("id").UUID(); err != nil {
+			t.Errorf("seed %d: id %v", seed, err)
+		}
+		if _, err := mail.ParseAddress(doc.Get("email").StringOr("")); err != nil {
+			t.Errorf("seed %d: email %v", seed, err)
+		}
+		if s := doc.Get("status").StringOr(""); s != "active" && s != "banned" {
+			t.Errorf("seed %d: status %q", seed, s)
+		}
+		if doc.Has("kind") && doc.Get("kind").StringOr("") != "user" {
+			t.Errorf("seed %d: kind %v", seed, doc.Get("kind").Raw())
+		}
+		tags, _ := doc.Get("tags").Array()
+		if len(tags) < 1 || len(tags) > 3 {
+			t.Errorf("seed %d: %d tags", seed, len(tags))
+		}
+		for _, tag := range tags {
+			if s := tag.StringOr(""); len(s) < 2 || len(s) > 4 {
+				t.Errorf("seed %d: tag %q", seed, s)
+			}
+		}
+		if score := doc.Get("score").IntOr(-1); score != 10 && score != 15 {
+			t.Errorf("seed %d: score %d", seed, score)
+		}
+		if doc.Has("ratio") {
+			if r := doc.Get("ratio").Float64Or(-1); r < 0 || r > 1 {
+				t.Errorf("seed %d: ratio %v", seed, r)
+			}
+		}
+		if _, err := time.Parse(time.RFC3339, doc.Get("created").StringOr("")); err != nil {
+			t.Errorf("seed %d: created %v", seed, err)
+		}
+		if !doc.Get("owner", "name").IsValid() {
+			t.Errorf("seed %d: owner without name", seed)
+		}
+	}
+
+	if a, b := JSON.Generate(schema, 7), JSON.Generate(schema, 7); !reflect.DeepEqual(a.Raw(), b.Raw()) {
+		t.Error("Expected the same document for the same seed")
+	}
+}
+
+func TestGenerateFromSample(t *testing.T) {
+	sample := JSON.Parse(`{"id": 1, "name": "Ann", "active": true, "roles": [{"name": "admin"}]}`)
+	doc := JSON.Generate(sample.InferSchema(), 1)
+	if _, err := doc.Get("id").Int(); err != nil {
+		t.Errorf("id: %v", err)
+	}
+	if _, err := doc.Get("name").String(); err != nil {
+		t.Errorf("name: %v", err)
+	}
+	if _, err := doc.Get("active").Bool(); err != nil {
+		t.Errorf("active: %v", err)
+	}
+	roles, err := doc.Get("roles").Array()
+	if err != nil {
+		t.Fatalf("roles: %v", err)
+	}
+	for _, r := range roles {
+		if _, err := r.Get("name").String(); err != nil {
+			t.Errorf("role name: %v", err)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema JSON.JSONValue
+	}{
+		{"not an object", JSON.Parse(`[1]`)},
+		{"remote ref", JSON.Parse(`{"$ref": "https://example.com/schema.json"}`)},
+		{"missing ref", JSON.Parse(`{"$ref": "#/$defs/nope"}`)},
+		{"required self reference", JSON.Parse(`{"$defs": {"n": {"required": ["next"], "properties": {"next": {"$ref": "#/$defs/n"}}}}, "$ref": "#/$defs/n"}`)},
+		{"invalid schema", JSON.Invalid(errors.New("bad"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := JSON.Generate(tt.schema, 1).Error(); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
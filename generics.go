@@ -0,0 +1,136 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// GetAs returns the value at the given path converted to T. Types with a
+// dedicated accessor (string, the int, uint and float types, bool,
+// time.Time, []byte, json.Number, *big.Int, *big.Float, Decimal,
+// map[string]string, map[string]float64, JSONValue, []JSONValue and
+// map[string]JSONValue) use it, with the same coercion rules; any other type
+// is decoded as with To.
+func GetAs[T any](j JSONValue, keys ...interface{}) (T, error) {
+	var result T
+	if err := convertInto(j.Get(keys...), &result); err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// GetOrAs returns the value at the given path converted to T, or
+// defaultValue if the path is missing or the conversion fails
+func GetOrAs[T any](j JSONValue, defaultValue T, keys ...interface{}) T {
+	if v, err := GetAs[T](j, keys...); err == nil {
+		return v
+	}
+	return defaultValue
+}
+
+// As converts the value to T and returns it, e.g.
+//
+//	user, err := jsjson.As[User](jv.Get("data"))
+//
+// Unlike GetAs it does not coerce: the value is decoded as with To into a new
+// T, and a value of the wrong JSON type, such as a string for an int, fails
+// with ErrTypeMismatch.
+func As[T any](j JSONValue) (T, error) {
+	var result T
+	if err := j.To(&result); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			err = &JSONError{Op: "As", Err: fmt.Errorf("%w: %w", ErrTypeMismatch, typeErr)}
+		}
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// convertInto stores j in the value dest points to, using the accessor for
+// its type when there is one, as GetAs describes
+func convertInto(j JSONValue, dest interface{}) error {
+	var err error
+	switch p := dest.(type) {
+	case *string:
+		*p, err = j.String()
+	case *int:
+		*p, err = j.Int()
+	case *int64:
+		*p, err = j.Int64()
+	case *int32:
+		*p, err = j.Int32()
+	case *int16:
+		*p, err = j.Int16()
+	case *int8:
+		*p, err = j.Int8()
+	case *uint:
+		*p, err = j.Uint()
+	case *uint64:
+		*p, err = j.Uint64()
+	case *uint32:
+		*p, err = j.Uint32()
+	case *uint16:
+		*p, err = j.Uint16()
+	case *uint8:
+		*p, err = j.Uint8()
+	case *float64:
+		*p, err = j.Float64()
+	case *float32:
+		*p, err = j.Float32()
+	case *bool:
+		*p, err = j.Bool()
+	case *time.Time:
+		*p, err = j.Time()
+	case *[]byte:
+		*p, err = j.Bytes()
+	case *json.Number:
+		*p, err = j.Number()
+	case **big.Int:
+		*p, err = j.BigInt()
+	case **big.Float:
+		*p, err = j.BigFloat()
+	case *Decimal:
+		*p, err = j.Decimal()
+	case *map[string]string:
+		*p, err = j.StringMap()
+	case *map[string]float64:
+		*p, err = j.Float64Map()
+	case *JSONValue:
+		*p, err = j, j.err
+	case *[]JSONValue:
+		*p, err = j.Array()
+	case *map[string]JSONValue:
+		*p, err = j.Object()
+	default:
+		err = j.To(dest)
+	}
+	return err
+}
+
+// ParseTyped parses JSON data straight into a new value of type T, the
+// generic form of ParseInto that needs no pre-declared destination:
+//
+//	cfg, err := jsjson.ParseTyped[Config](data)
+func ParseTyped[T any](data interface{}, opts ...ParseOption) (T, error) {
+	var result T
+	if err := ParseInto(data, &result, opts...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// MustParseTyped is like ParseTyped but panics on error
+func MustParseTyped[T any](data interface{}, opts ...ParseOption) T {
+	result, err := ParseTyped[T](data, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+package jsjson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+type genericUser struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+func TestGetAs(t *testing.T) {
+	obj := JSON.Parse(`{
+		"user": {"name": "Ada", "tags": ["admin"], "age": "36", "created": "2023-06-15T10:30:00Z"},
+		"labels": {"env": "prod"},
+		"scores": [1, 2, 3]
+	}`)
+
+	if name, err := JSON.GetAs[string](obj, "user", "name"); err != nil || name != "Ada" {
+		t.Errorf("Expected Ada, got %q (err: %v)", name, err)
+	}
+	// Accessor coercion rules apply, so numeric strings convert to int
+	if age, err := JSON.GetAs[int](obj, "user", "age"); err != nil || age != 36 {
+		t.Errorf("Expected 36, got %d (err: %v)", age, err)
+	}
+	if created, err := JSON.GetAs[time.Time](obj, "user", "created"); err != nil || created.Year() != 2023 {
+		t.Errorf("Expected 2023 timestamp, got %v (err: %v)", created, err)
+	}
+	if labels, err := JSON.GetAs[map[string]string](obj, "labels"); err != nil || labels["env"] != "prod" {
+		t.Errorf("Expected labels, got %v (err: %v)", labels, err)
+	}
+	if scores, err := JSON.GetAs[[]int](obj, "scores"); err != nil || !reflect.DeepEqual(scores, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v (err: %v)", scores, err)
+	}
+
+	user, err := JSON.GetAs[genericUser](obj, "user")
+	if err != nil || user.Name != "Ada" || !reflect.DeepEqual(user.Tags, []string{"admin"}) {
+		t.Errorf("Unexpected user %+v (err: %v)", user, err)
+	}
+	if p, err := JSON.GetAs[*genericUser](obj, "user"); err != nil || p == nil || p.Name != "Ada" {
+		t.Errorf("Unexpected user pointer %+v (err: %v)", p, err)
+	}
+
+	if _, err := JSON.GetAs[string](obj, "user", "missing"); !errors.Is(err, JSON.ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+	if n, err := JSON.GetAs[int](obj, "user", "name"); err == nil {
+		t.Errorf("Expected error, got %d", n)
+	}
+}
+
+func TestGetOrAs(t *testing.T) {
+	obj := JSON.Parse(`{"retries": 3, "mode": "fast"}`)
+
+	if got := JSON.GetOrAs(obj, 1, "retries"); got != 3 {
+		t.Errorf("Expected 3, got %d", got)
+	}
+	if got := JSON.GetOrAs(obj, 1, "missing"); got != 1 {
+		t.Errorf("Expected default 1, got %d", got)
+	}
+	if got := JSON.GetOrAs(obj, 5, "mode"); got != 5 {
+		t.Errorf("Expected default on conversion failure, got %d", got)
+	}
+	if got := JSON.GetOrAs(obj, []string{"x"}, "missing"); !reflect.DeepEqual(got, []string{"x"}) {
+		t.Errorf("Expected default slice, got %v", got)
+	}
+}
+
+func TestAs(t *testing.T) {
+	data := JSON.Parse(`{"data": {"name": "Ada", "tags": ["admin", "ops"]}, "count": 2}`)
+
+	user, err := JSON.As[genericUser](data.Get("data"))
+	if err != nil || user.Name != "Ada" || len(user.Tags) != 2 {
+		t.Errorf("Unexpected user %+v (err: %v)", user, err)
+	}
+	if n, err := JSON.As[int](data.Get("count")); err != nil || n != 2 {
+		t.Errorf("Expected 2, got %d (err: %v)", n, err)
+	}
+	if m, err := JSON.As[map[string]interface{}](data.Get("data")); err != nil || m["name"] != "Ada" {
+		t.Errorf("Unexpected map %v (err: %v)", m, err)
+	}
+
+	// Errors on the receiver propagate
+	if _, err := JSON.As[genericUser](data.Get("missing")); !errors.Is(err, JSON.ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+	if _, err := JSON.As[genericUser](data.Get("count")); err == nil {
+		t.Error("Expected error converting a number to a struct")
+	}
+
+	// As decodes strictly, unlike GetAs
+	quoted := JSON.Parse(`{"n": "42", "user": {"name": 7}}`)
+	if n, err := JSON.As[int](quoted.Get("n")); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for a string as int, got %d (err: %v)", n, err)
+	}
+	if _, err := JSON.As[genericUser](quoted.Get("user")); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for a number as a string field, got %v", err)
+	}
+	if n, err := JSON.GetAs[int](quoted, "n"); err != nil || n != 42 {
+		t.Errorf("Expected GetAs to coerce to 42, got %d (err: %v)", n, err)
+	}
+}
+
+func TestParseTyped(t *testing.T) {
+	user, err := JSON.ParseTyped[genericUser](`{"name": "Ada", "tags": ["admin"]}`)
+	if err != nil || user.Name != "Ada" || len(user.Tags) != 1 {
+		t.Errorf("Unexpected user %+v (err: %v)", user, err)
+	}
+
+	ids, err := JSON.ParseTyped[[]int64]([]byte(`[1, 2, 3]`))
+	if err != nil || !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v (err: %v)", ids, err)
+	}
+
+	if _, err := JSON.ParseTyped[genericUser](`{"name": `); err == nil {
+		t.Error("Expected syntax error")
+	}
+	if _, err := JSON.ParseTyped[genericUser](`{"name": "Ada"}`, JSON.WithMaxBytes(4)); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustParseTyped to panic")
+		}
+	}()
+	JSON.MustParseTyped[genericUser](`not json`)
+}
@@ -0,0 +1,316 @@
+package jsjson
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// -------------------- HTTP --------------------
+
+// DefaultMaxRequestBytes caps request bodies read by DecodeRequest when no
+// WithMaxBytes option is given
+const DefaultMaxRequestBytes = 1 << 20
+
+// maxDrainBytes bounds how much of an unread body is discarded before it is
+// closed. Draining a short remainder lets the server reuse the connection;
+// a longer one is cheaper to drop with the connection.
+const maxDrainBytes = 256 << 10
+
+// DecodeRequest reads and parses a JSON request body:
+//
+//	jv, err := jsjson.DecodeRequest(r, jsjson.WithMaxBytes(64<<10))
+//	if err != nil {
+//		e := jsjson.ErrorToHTTP(err)
+//		http.Error(w, e.Message, e.Status)
+//		return
+//	}
+//
+// The Content-Type must be application/json or a +json type such as
+// application/merge-patch+json, with no charset other than UTF-8; anything
+// else, including a missing header, fails with ErrUnsupportedMediaType. The
+// body is limited to the WithMaxBytes size, or DefaultMaxRequestBytes, and a
+// larger one fails with ErrLimitExceeded before it is parsed. An empty body
+// is an ErrSyntax error. The body is always drained and closed.
+//
+// The returned JSONValue carries the same error, so it can also be chained
+// like the result of Parse.
+func DecodeRequest(r *http.Request, opts ...ParseOption) (JSONValue, error) {
+	fail := func(err error) (JSONValue, error) {
+		jerr := &JSONError{Op: "DecodeRequest", Err: err}
+		return JSONValue{err: jerr}, jerr
+	}
+	if r.Body != nil {
+		defer drainBody(r.Body)
+	}
+
+	if err := checkJSONContentType(r.Header.Get("Content-Type")); err != nil {
+		return fail(err)
+	}
+
+	limit := newParseOptions(opts).maxBytes
+	if limit <= 0 {
+		limit = DefaultMaxRequestBytes
+	}
+	if r.ContentLength > limit {
+		return fail(fmt.Errorf("%w: body is %d bytes, max %d", ErrLimitExceeded, r.ContentLength, limit))
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return fail(fmt.Errorf("%w: empty body", ErrSyntax))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			err = fmt.Errorf("%w: body is larger than %d bytes", ErrLimitExceeded, maxErr.Limit)
+		}
+		return fail(err)
+	}
+	if int64(len(body)) > limit {
+		return fail(fmt.Errorf("%w: body is larger than %d bytes", ErrLimitExceeded, limit))
+	}
+	if len(body) == 0 {
+		return fail(fmt.Errorf("%w: empty body", ErrSyntax))
+	}
+
+	// The body buffer is ours alone, so the tree can borrow it
+	jv := ParseNoCopy(body, opts...)
+	if jv.err != nil {
+		var jerr *JSONError
+		if errors.As(jv.err, &jerr) {
+			return fail(jerr.Err)
+		}
+		return fail(jv.err)
+	}
+	return jv, nil
+}
+
+// checkJSONContentType accepts application/json and +json media types
+// without a non-UTF-8 charset
+func checkJSONContentType(contentType string) error {
+	if contentType == "" {
+		return fmt.Errorf("%w: missing Content-Type, expected application/json", ErrUnsupportedMediaType)
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedMediaType, err)
+	}
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return fmt.Errorf("%w: %s, expected application/json", ErrUnsupportedMediaType, mediaType)
+	}
+	if charset, ok := params["charset"]; ok && !strings.EqualFold(charset, "utf-8") {
+		return fmt.Errorf("%w: charset %s, expected utf-8", ErrUnsupportedMediaType, charset)
+	}
+	return nil
+}
+
+// drainBody discards up to maxDrainBytes of what is left of body and
+// closes it
+func drainBody(body io.ReadCloser) {
+	io.CopyN(io.Discard, body, maxDrainBytes)
+	body.Close()
+}
+
+// WriteJSON encodes v and writes it as the response with the given status:
+//
+//	jsjson.WriteJSON(w, http.StatusCreated, user)
+//
+// v is encoded like Stringify, into a pooled buffer, before anything is
+// written, so an encoding error (or a JSONValue holding one) is returned with
+// the response still untouched and the caller free to send an error instead.
+// Content-Type defaults to application/json; charset=utf-8 but is kept if
+// the handler already set one, and Content-Length and
+// X-Content-Type-Options: nosniff are set. Statuses that cannot have a body,
+// such as 204 and 304, are written without one.
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) error {
+	if jv, ok := v.(JSONValue); ok {
+		if jv.err != nil {
+			return jv.err
+		}
+		v = jv.data
+	}
+
+	h := w.Header()
+	if !bodyAllowed(status) {
+		w.WriteHeader(status)
+		return nil
+	}
+
+	buf := getBytesBuffer()
+	defer putBytesBuffer(buf)
+	if err := encodeInto(buf, v); err != nil {
+		return &JSONError{Op: "WriteJSON", Err: err}
+	}
+
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Content-Length", strconv.Itoa(len(*buf)))
+	w.WriteHeader(status)
+	if _, err := w.Write(*buf); err != nil {
+		return &JSONError{Op: "WriteJSON", Err: err}
+	}
+	return nil
+}
+
+// bodyAllowed reports whether a response with status may have a body
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// maxErrorBody bounds how much of an error response StatusError keeps
+const maxErrorBody = 64 << 10
+
+// StatusError reports a response from GetJSON or PostJSON with a status
+// outside 2xx. Use errors.As to inspect it.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	// Status is the status line text, e.g. "404 Not Found"
+	Status string
+	// Body holds up to the first 64 KiB of the response body
+	Body []byte
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return e.Method + " " + e.URL + ": " + e.Status
+}
+
+// GetJSON sends a GET request and decodes the JSON response:
+//
+//	user, err := jsjson.GetJSON(ctx, client, "https://api.example.com/users/7")
+//
+// A nil client means http.DefaultClient. The body is decoded as it streams
+// in, with opts applied as in NewDecoder, so WithMaxBytes bounds it. Data
+// after the JSON value fails with ErrSyntax, as in Parse. A status outside
+// 2xx fails with a *StatusError; a 204 or empty body decodes as null. The
+// response body is always drained and closed.
+func GetJSON(ctx context.Context, client *http.Client, url string, opts ...ParseOption) (JSONValue, error) {
+	return doJSON(ctx, "GetJSON", client, http.MethodGet, url, nil, opts)
+}
+
+// PostJSON encodes body like Stringify, POSTs it as application/json and
+// decodes the JSON response as GetJSON does. A JSONValue body is sent as its
+// data.
+func PostJSON(ctx context.Context, client *http.Client, url string, body interface{}, opts ...ParseOption) (JSONValue, error) {
+	if jv, ok := body.(JSONValue); ok {
+		if jv.err != nil {
+			return jv, jv.err
+		}
+		body = jv.data
+	}
+	var b []byte
+	if err := encodeInto(&b, body); err != nil {
+		err = &JSONError{Op: "PostJSON", Err: err}
+		return JSONValue{err: err}, err
+	}
+	return doJSON(ctx, "PostJSON", client, http.MethodPost, url, b, opts)
+}
+
+func doJSON(ctx context.Context, op string, client *http.Client, method, url string, body []byte, opts []ParseOption) (JSONValue, error) {
+	fail := func(err error) (JSONValue, error) {
+		var jerr *JSONError
+		if errors.As(err, &jerr) {
+			err = jerr.Err
+		}
+		jerr = &JSONError{Op: op, Err: err}
+		return JSONValue{err: jerr}, jerr
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fail(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fail(err)
+	}
+	defer drainBody(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fail(&StatusError{Method: method, URL: url, StatusCode: resp.StatusCode, Status: resp.Status, Body: snippet})
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return JSONValue{}, nil
+	}
+
+	dec := NewDecoder(resp.Body, opts...)
+	jv, err := dec.Decode()
+	if err == io.EOF {
+		return JSONValue{}, nil
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		return fail(fmt.Errorf("%w: data after the JSON value", ErrSyntax))
+	}
+	return jv, nil
+}
+
+// bodyKey is the context key under which BodyParser stores the body
+type bodyKey struct{}
+
+// BodyParser is middleware that parses each JSON request body once and
+// stores it in the request context for FromContext:
+//
+//	mux.Handle("/users", jsjson.BodyParser(http.HandlerFunc(createUser)))
+//
+//	func createUser(w http.ResponseWriter, r *http.Request) {
+//		body, _ := jsjson.FromContext(r)
+//		name, err := body.Get("name").String()
+//		// ...
+//	}
+//
+// Bodies are read with DecodeRequest and opts, so the same Content-Type and
+// size checks apply. A request that fails them is answered with the
+// ErrorToHTTP status and an HTTPError JSON body, and next is not called.
+// Requests without a body, such as most GETs, are passed on untouched.
+// next sees an empty r.Body once it has been parsed.
+func BodyParser(next http.Handler, opts ...ParseOption) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		jv, err := DecodeRequest(r, opts...)
+		if err != nil {
+			e := ErrorToHTTP(err)
+			WriteJSON(w, e.Status, e)
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), bodyKey{}, jv))
+		r.Body = http.NoBody
+		next.ServeHTTP(w, r)
+	})
+}
+
+// FromContext returns the body BodyParser parsed for r. ok is false if
+// there was none, in which case the value is null.
+func FromContext(r *http.Request) (jv JSONValue, ok bool) {
+	jv, ok = r.Context().Value(bodyKey{}).(JSONValue)
+	return jv, ok
+}
@@ -0,0 +1,336 @@
+package jsjson_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+// trackedBody records whether it was read to the end and closed
+type trackedBody struct {
+	io.Reader
+	drained, closed bool
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		b.drained = true
+	}
+	return n, err
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDecodeRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []JSON.ParseOption
+		wantErr     error
+	}{
+		{"json", "application/json", `{"name":"John"}`, nil, nil},
+		{"charset", "application/json; charset=UTF-8", `{"name":"John"}`, nil, nil},
+		{"suffix type", "application/merge-patch+json", `{"name":"John"}`, nil, nil},
+		{"missing content type", "", `{"name":"John"}`, nil, JSON.ErrUnsupportedMediaType},
+		{"form", "application/x-www-form-urlencoded", `name=John`, nil, JSON.ErrUnsupportedMediaType},
+		{"latin1", "application/json; charset=iso-8859-1", `{"name":"John"}`, nil, JSON.ErrUnsupportedMediaType},
+		{"empty", "application/json", ``, nil, JSON.ErrSyntax},
+		{"too large", "application/json", `{"name":"John"}`, []JSON.ParseOption{JSON.WithMaxBytes(8)}, JSON.ErrLimitExceeded},
+		{"token limit", "application/json", `[1,2,3]`, []JSON.ParseOption{JSON.WithMaxArrayElements(2)}, JSON.ErrLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			body := &trackedBody{Reader: r.Body}
+			r.Body = body
+
+			jv, err := JSON.DecodeRequest(r, tt.opts...)
+			if !body.closed {
+				t.Error("Expected the body to be closed")
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+				if jv.Error() != err {
+					t.Errorf("Expected the value to carry the error, got %v", jv.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !body.drained {
+				t.Error("Expected the body to be drained")
+			}
+			if name, _ := jv.Get("name").String(); name != "John" {
+				t.Errorf("Expected John, got %q", name)
+			}
+		})
+	}
+}
+
+func TestDecodeRequestDefaultLimit(t *testing.T) {
+	large := `"` + strings.Repeat("x", JSON.DefaultMaxRequestBytes) + `"`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(large))
+	r.Header.Set("Content-Type", "application/json")
+	r.ContentLength = -1
+
+	_, err := JSON.DecodeRequest(r)
+	if !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got %v", err)
+	}
+	if got := JSON.ErrorToHTTP(err).Status; got != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413, got %d", got)
+	}
+}
+
+func TestDecodeRequestMaxBytesReader(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.ContentLength = -1
+	r.Body = http.MaxBytesReader(w, r.Body, 4)
+
+	_, err := JSON.DecodeRequest(r)
+	if !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got %v", err)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := JSON.WriteJSON(w, http.StatusCreated, map[string]interface{}{"id": 7, "tags": []string{"a"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected 201, got %d", w.Code)
+	}
+	if got, want := w.Body.String(), `{"id":7,"tags":["a"]}`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	wantHeaders := map[string]string{
+		"Content-Type":           "application/json; charset=utf-8",
+		"Content-Length":         "21",
+		"X-Content-Type-Options": "nosniff",
+	}
+	for k, want := range wantHeaders {
+		if got := w.Header().Get(k); got != want {
+			t.Errorf("Expected %s %q, got %q", k, want, got)
+		}
+	}
+
+	t.Run("JSONValue", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := JSON.WriteJSON(w, http.StatusOK, JSON.Parse(`{"ok": true}`)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := w.Body.String(); got != `{"ok":true}` {
+			t.Errorf("Expected {\"ok\":true}, got %s", got)
+		}
+	})
+
+	t.Run("keeps content type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		w.Header().Set("Content-Type", "application/problem+json")
+		if err := JSON.WriteJSON(w, http.StatusBadRequest, map[string]string{"title": "Bad"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+			t.Errorf("Expected application/problem+json, got %q", got)
+		}
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := JSON.WriteJSON(w, http.StatusNoContent, map[string]string{"ignored": "yes"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+			t.Errorf("Expected an empty 204, got %d %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("encoding error", func(t *testing.T) {
+		for _, v := range []interface{}{make(chan int), JSON.Parse(`{`)} {
+			w := httptest.NewRecorder()
+			if err := JSON.WriteJSON(w, http.StatusOK, v); err == nil {
+				t.Errorf("Expected an error for %T", v)
+			}
+			if w.Code != http.StatusOK || w.Body.Len() != 0 || len(w.Header()) != 0 {
+				t.Errorf("Expected an untouched response, got %d %v %q", w.Code, w.Header(), w.Body.String())
+			}
+		}
+	})
+}
+
+func TestGetJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			if r.Method != http.MethodGet || r.Header.Get("Accept") != "application/json" {
+				t.Errorf("Unexpected request %s Accept=%q", r.Method, r.Header.Get("Accept"))
+			}
+			w.Write([]byte(`{"id": 7, "name": "John"}`))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "no such user"}`))
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/large":
+			w.Write([]byte(`["` + strings.Repeat("x", 100) + `"]`))
+		case "/broken":
+			w.Write([]byte(`{"id":`))
+		case "/trailing":
+			w.Write([]byte(r.URL.Query().Get("body")))
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	jv, err := JSON.GetJSON(ctx, nil, srv.URL+"/user")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id, _ := jv.Get("id").Int(); id != 7 {
+		t.Errorf("Expected id 7, got %d", id)
+	}
+
+	_, err = JSON.GetJSON(ctx, srv.Client(), srv.URL+"/missing")
+	var statusErr *JSON.StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("Expected a StatusError, got %v", err)
+	}
+	if statusErr.StatusCode != http.StatusNotFound || statusErr.Method != http.MethodGet {
+		t.Errorf("Unexpected StatusError %+v", statusErr)
+	}
+	if msg, _ := JSON.Parse(statusErr.Body).Get("error").String(); msg != "no such user" {
+		t.Errorf("Expected the error body, got %q", statusErr.Body)
+	}
+
+	jv, err = JSON.GetJSON(ctx, nil, srv.URL+"/empty")
+	if err != nil || !jv.IsNull() {
+		t.Errorf("Expected null for 204, got %v %v", jv, err)
+	}
+
+	if _, err = JSON.GetJSON(ctx, nil, srv.URL+"/large", JSON.WithMaxBytes(50)); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got %v", err)
+	}
+	if _, err = JSON.GetJSON(ctx, nil, srv.URL+"/broken"); err == nil {
+		t.Error("Expected an error for a truncated body")
+	}
+	for _, body := range []string{`{"a":1}{"b":2}`, `{} garbage`} {
+		if _, err = JSON.GetJSON(ctx, nil, srv.URL+"/trailing?body="+url.QueryEscape(body)); !errors.Is(err, JSON.ErrSyntax) {
+			t.Errorf("Expected ErrSyntax for trailing data in %s, got %v", body, err)
+		}
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err = JSON.GetJSON(canceled, nil, srv.URL+"/user"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestPostJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request %s Content-Type=%q", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"received": ` + string(body) + `}`))
+	}))
+	defer srv.Close()
+
+	type user struct {
+		Name string `json:"name"`
+	}
+	jv, err := JSON.PostJSON(context.Background(), nil, srv.URL, user{Name: "John"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name, _ := jv.Get("received").Get("name").String(); name != "John" {
+		t.Errorf("Expected the echoed body, got %v", jv)
+	}
+
+	jv, err = JSON.PostJSON(context.Background(), nil, srv.URL, JSON.Parse(`[1, 2]`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n, _ := jv.Get("received", 1).Int(); n != 2 {
+		t.Errorf("Expected the echoed array, got %v", jv)
+	}
+
+	if _, err := JSON.PostJSON(context.Background(), nil, srv.URL, make(chan int)); err == nil {
+		t.Error("Expected an encoding error")
+	}
+}
+
+func TestBodyParser(t *testing.T) {
+	var (
+		called bool
+		got    JSON.JSONValue
+		ok     bool
+	)
+	handler := JSON.BodyParser(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		got, ok = JSON.FromContext(r)
+		w.WriteHeader(http.StatusOK)
+	}), JSON.WithMaxBytes(32))
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantCalled bool
+		wantBody   bool
+		wantStatus int
+		wantCode   string
+	}{
+		{"json body", http.MethodPost, `{"name":"John"}`, true, true, http.StatusOK, ""},
+		{"no body", http.MethodGet, ``, true, false, http.StatusOK, ""},
+		{"too large", http.MethodPost, `{"name":"` + strings.Repeat("x", 40) + `"}`, false, false, http.StatusRequestEntityTooLarge, JSON.CodeLimitExceeded},
+		{"malformed", http.MethodPost, `{"name":`, false, false, http.StatusBadRequest, JSON.CodeSyntaxError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called, got, ok = false, JSON.JSONValue{}, false
+			r := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if called != tt.wantCalled || ok != tt.wantBody || w.Code != tt.wantStatus {
+				t.Fatalf("Expected called=%v body=%v %d, got called=%v body=%v %d", tt.wantCalled, tt.wantBody, tt.wantStatus, called, ok, w.Code)
+			}
+			if tt.wantBody {
+				if name, _ := got.Get("name").String(); name != "John" {
+					t.Errorf("Expected John, got %v", got)
+				}
+			}
+			if tt.wantCode != "" {
+				if code, _ := JSON.Parse(w.Body.String()).Get("code").String(); code != tt.wantCode {
+					t.Errorf("Expected code %s, got %s", tt.wantCode, w.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package jsjson
+
+const (
+	// maxInternLen is the longest string that is interned; longer strings
+	// are rarely repeated verbatim
+	maxInternLen = 64
+
+	// maxInternEntries bounds the table so unique strings cannot grow it
+	// without limit; once full, only existing entries are reused
+	maxInternEntries = 1 << 14
+)
+
+// interner deduplicates short strings (object keys, enum values) while
+// parsing, so repeated occurrences share one allocation
+type interner struct {
+	table map[string]string
+}
+
+// WithInterning deduplicates object keys and short string values during
+// parsing. Large arrays of objects repeat the same keys and enum-like values
+// thousands of times; with interning each distinct string is allocated once.
+// A Decoder shares one table across all values in its stream. Parse switches
+// to the built-in parser in this mode, regardless of SetCodec.
+func WithInterning() ParseOption {
+	return parseOptionFunc(func(o *parseOptions) {
+		o.interner = &interner{table: make(map[string]string)}
+	})
+}
+
+// intern returns the shared copy of b, adding it if there is room
+func (in *interner) intern(b []byte) string {
+	// The string(b) conversion in a map index does not allocate
+	if s, ok := in.table[string(b)]; ok {
+		return s
+	}
+	s := string(b)
+	if len(in.table) < maxInternEntries {
+		in.table[s] = s
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+package jsjson_test
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestInterningMatchesParse(t *testing.T) {
+	for _, input := range parserCorpus {
+		t.Run(input, func(t *testing.T) {
+			want := JSON.Parse(input)
+			got := JSON.Parse(input, JSON.WithInterning())
+			if got.Error() != nil || !reflect.DeepEqual(got.Raw(), want.Raw()) {
+				t.Errorf("Expected %#v, got %#v (err: %v)", want.Raw(), got.Raw(), got.Error())
+			}
+		})
+	}
+}
+
+func TestInterningSharesStrings(t *testing.T) {
+	doc := JSON.Parse(`[{"status":"active"},{"status":"active"},{"status":"escaped"},{"status":"escaped"}]`, JSON.WithInterning())
+
+	a := doc.Get(0, "status").StringOr("")
+	b := doc.Get(1, "status").StringOr("")
+	if unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Error("Expected repeated values to share memory")
+	}
+	c := doc.Get(2, "status").StringOr("")
+	d := doc.Get(3, "status").StringOr("")
+	if c != "escaped" || unsafe.StringData(c) != unsafe.StringData(d) {
+		t.Errorf("Expected escaped and plain forms to share memory, got %q and %q", c, d)
+	}
+
+	// Interned strings are copies, so they do not borrow a ParseNoCopy input
+	buf := []byte(`{"k":"v"}`)
+	v := JSON.ParseNoCopy(buf, JSON.WithInterning()).Get("k").StringOr("")
+	if unsafe.StringData(v) == &buf[6] {
+		t.Error("Expected interned strings not to borrow the input")
+	}
+}
+
+func TestInterningAcrossDecoderStream(t *testing.T) {
+	var stream strings.Builder
+	for i := 0; i < 3; i++ {
+		fmt.Fprintf(&stream, "{\"level\":\"info\",\"n\":%d}\n", i)
+	}
+
+	dec := JSON.NewDecoder(strings.NewReader(stream.String()), JSON.WithInterning(), JSON.WithArena())
+	var levels []string
+	for {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		levels = append(levels, v.Get("level").StringOr(""))
+		// Interned strings stay valid after their document is released
+		v.Release()
+	}
+	if len(levels) != 3 || unsafe.StringData(levels[0]) != unsafe.StringData(levels[2]) || levels[2] != "info" {
+		t.Errorf("Expected one shared \"info\" across the stream, got %q", levels)
+	}
+}
+
+func TestInterningReducesAllocations(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < 200; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(`{"type":"click","region":"eu-west","device":"mobile"}`)
+	}
+	b.WriteString("]")
+	input := b.String()
+
+	plain := testing.AllocsPerRun(10, func() { JSON.Parse(input) })
+	interned := testing.AllocsPerRun(10, func() { JSON.Parse(input, JSON.WithInterning()) })
+	if interned >= plain {
+		t.Errorf("Expected interning to reduce allocations: %.0f vs %.0f", interned, plain)
+	}
+}
@@ -0,0 +1,86 @@
+package jsjsontest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+// update has a package-qualified name so it cannot collide with an -update
+// flag defined by the test package
+var update = flag.Bool("jsjsontest.update", false, "rewrite jsjsontest golden files with the actual values")
+
+// GoldenDir is the directory holding golden files, relative to the package
+// under test
+var GoldenDir = "testdata"
+
+// Golden compares got with the golden file GoldenDir/name.golden.json, or,
+// when the tests run with -jsjsontest.update, writes got to that file
+// instead:
+//
+//	jsjsontest.Golden(t, "users/list", resp)
+//
+//	go test ./... -jsjsontest.update   # record new snapshots
+//	git diff testdata/                 # review them
+//
+// A boolean -update flag defined by the test package is honored as well, so
+// packages with their own golden files can refresh everything with one
+// flag. Golden files are written canonically: indented, with keys in sorted
+// order and a final newline, so snapshots diff cleanly in review. The
+// comparison works as in AssertJSONEqual, so reformatting a golden file by
+// hand does not break it.
+func Golden(t testing.TB, name string, got interface{}) bool {
+	t.Helper()
+	path := filepath.Join(GoldenDir, filepath.FromSlash(name)+".golden.json")
+	g := jsjson.Parse(got)
+	if err := g.Error(); err != nil {
+		t.Errorf("Golden %s: invalid actual value: %v", name, err)
+		return false
+	}
+
+	if updating() {
+		text, err := jsjson.StringifyPretty(g, "  ")
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0o755)
+		}
+		if err == nil {
+			err = os.WriteFile(path, []byte(text+"\n"), 0o644)
+		}
+		if err != nil {
+			t.Errorf("Golden %s: %v", name, err)
+			return false
+		}
+		t.Logf("Golden %s: updated %s", name, path)
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Errorf("Golden %s: %s does not exist; run the test with -jsjsontest.update to create it", name, path)
+		return false
+	}
+	if err != nil {
+		t.Errorf("Golden %s: %v", name, err)
+		return false
+	}
+	return assertJSON(t, "Golden "+name, want, g, false)
+}
+
+// updating reports whether golden files should be rewritten: with
+// -jsjsontest.update, or with a boolean -update flag the test package
+// defines
+func updating() bool {
+	if *update {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			on, _ := g.Get().(bool)
+			return on
+		}
+	}
+	return false
+}
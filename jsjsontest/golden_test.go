@@ -0,0 +1,73 @@
+package jsjsontest_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ktbsomen/jsjson/jsjsontest"
+)
+
+// The test package's own -update flag must not collide with jsjsontest's
+var _ = flag.Bool("update", false, "rewrite this package's golden files")
+
+func TestGolden(t *testing.T) {
+	defer func(dir string) { jsjsontest.GoldenDir = dir }(jsjsontest.GoldenDir)
+	jsjsontest.GoldenDir = t.TempDir()
+	setFlag := func(name, v string) {
+		if err := flag.Set(name, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		r := &recorder{}
+		if jsjsontest.Golden(r, "users/list", `{"b": 1, "a": [true]}`) || len(r.errors) != 1 || !strings.Contains(r.errors[0], "-jsjsontest.update") {
+			t.Errorf("Unexpected errors %q", r.errors)
+		}
+	})
+
+	t.Run("update writes canonical file", func(t *testing.T) {
+		setFlag("jsjsontest.update", "true")
+		defer setFlag("jsjsontest.update", "false")
+		r := &recorder{}
+		if !jsjsontest.Golden(r, "users/list", `{"b": 1, "a": [true]}`) || len(r.errors) != 0 {
+			t.Fatalf("Unexpected errors %q", r.errors)
+		}
+		b, err := os.ReadFile(filepath.Join(jsjsontest.GoldenDir, "users", "list.golden.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "{\n  \"a\": [\n    true\n  ],\n  \"b\": 1\n}\n"; string(b) != want {
+			t.Errorf("Expected %q, got %q", want, b)
+		}
+	})
+
+	t.Run("matching value", func(t *testing.T) {
+		r := &recorder{}
+		if !jsjsontest.Golden(r, "users/list", `{"a":[true],"b":1.0}`) || len(r.errors) != 0 {
+			t.Errorf("Unexpected errors %q", r.errors)
+		}
+	})
+
+	t.Run("changed value", func(t *testing.T) {
+		r := &recorder{}
+		if jsjsontest.Golden(r, "users/list", `{"a": [false], "b": 1}`) || len(r.errors) != 1 || !strings.Contains(r.errors[0], "a[0]: expected true, got false") {
+			t.Errorf("Unexpected errors %q", r.errors)
+		}
+	})
+
+	t.Run("test package update flag", func(t *testing.T) {
+		setFlag("update", "true")
+		defer setFlag("update", "false")
+		r := &recorder{}
+		if !jsjsontest.Golden(r, "users/other", `{"c": 2}`) || len(r.errors) != 0 {
+			t.Fatalf("Unexpected errors %q", r.errors)
+		}
+		if _, err := os.Stat(filepath.Join(jsjsontest.GoldenDir, "users", "other.golden.json")); err != nil {
+			t.Errorf("Expected the golden file to be written: %v", err)
+		}
+	})
+}
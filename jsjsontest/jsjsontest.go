@@ -0,0 +1,161 @@
+// Package jsjsontest provides test helpers for code that produces JSON.
+// Comparisons ignore key order and whitespace and compare numbers by value,
+// and failures name the path of every difference:
+//
+//	func TestHandler(t *testing.T) {
+//		got := jsjson.Parse(rec.Body.Bytes())
+//		jsjsontest.AssertJSONEqual(t, `{"id": 7, "tags": ["a", "b"]}`, got)
+//		jsjsontest.AssertJSONSubset(t, `{"user": {"name": "Ann"}}`, got)
+//	}
+//
+// Golden compares values with snapshot files that go test -update rewrites,
+// and Response builds API response envelopes for handler and client tests.
+//
+// Expected and actual values may be JSON text (string or []byte), a
+// jsjson.JSONValue, or any Go value that jsjson.Parse accepts.
+package jsjsontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+// maxReported bounds the differences listed in one failure
+const maxReported = 20
+
+// AssertJSONEqual reports a test error unless want and got hold the same
+// JSON value, and returns whether they do
+func AssertJSONEqual(t testing.TB, want, got interface{}) bool {
+	t.Helper()
+	return assertJSON(t, "AssertJSONEqual", want, got, false)
+}
+
+// AssertJSONSubset reports a test error unless every member of the objects
+// in want is present in got with a matching value, and returns whether it
+// is. Extra members of got are ignored at any depth. Arrays must have the
+// same length, with elements matched in order by the same rule, and all
+// other values must be equal.
+func AssertJSONSubset(t testing.TB, want, got interface{}) bool {
+	t.Helper()
+	return assertJSON(t, "AssertJSONSubset", want, got, true)
+}
+
+func assertJSON(t testing.TB, name string, want, got interface{}, subset bool) bool {
+	t.Helper()
+	w, g := jsjson.Parse(want), jsjson.Parse(got)
+	if err := w.Error(); err != nil {
+		t.Errorf("%s: invalid expected value: %v", name, err)
+		return false
+	}
+	if err := g.Error(); err != nil {
+		t.Errorf("%s: invalid actual value: %v", name, err)
+		return false
+	}
+
+	var diffs []string
+	compare(&diffs, nil, w.Raw(), g.Raw(), subset)
+	if len(diffs) == 0 {
+		return true
+	}
+	if len(diffs) > maxReported {
+		diffs = append(diffs[:maxReported], fmt.Sprintf("... and %d more", len(diffs)-maxReported))
+	}
+	wantText, _ := jsjson.StringifyPretty(w, "  ")
+	gotText, _ := jsjson.StringifyPretty(g, "  ")
+	t.Errorf("%s: values differ:\n\t%s\nexpected:\n%s\nactual:\n%s", name, strings.Join(diffs, "\n\t"), wantText, gotText)
+	return false
+}
+
+// compare appends a description of each difference between want and got
+func compare(diffs *[]string, path []interface{}, want, got interface{}, subset bool) {
+	if fw, ok := number(want); ok {
+		if fg, ok := number(got); !ok || fw != fg {
+			*diffs = append(*diffs, fmt.Sprintf("%s: expected %s, got %s", pathString(path), text(want), text(got)))
+		}
+		return
+	}
+
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, k := range sortedKeys(w) {
+			if gv, ok := g[k]; ok {
+				compare(diffs, append(path, k), w[k], gv, subset)
+			} else {
+				*diffs = append(*diffs, fmt.Sprintf("%s: missing, expected %s", pathString(append(path, k)), text(w[k])))
+			}
+		}
+		if !subset {
+			for _, k := range sortedKeys(g) {
+				if _, ok := w[k]; !ok {
+					*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", pathString(append(path, k)), text(g[k])))
+				}
+			}
+		}
+		return
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			break
+		}
+		if len(w) != len(g) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: expected %d elements, got %d", pathString(path), len(w), len(g)))
+			return
+		}
+		for i := range w {
+			compare(diffs, append(path, i), w[i], g[i], subset)
+		}
+		return
+	default:
+		if reflect.DeepEqual(want, got) {
+			return
+		}
+	}
+	*diffs = append(*diffs, fmt.Sprintf("%s: expected %s, got %s", pathString(path), text(want), text(got)))
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pathString formats a path as jsjson.Path does, with the root as "(root)"
+func pathString(path []interface{}) string {
+	if len(path) == 0 {
+		return "(root)"
+	}
+	return jsjson.CompilePath(path...).String()
+}
+
+// text returns v as compact JSON for messages
+func text(v interface{}) string {
+	s, err := jsjson.Stringify(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return s
+}
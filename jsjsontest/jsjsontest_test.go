@@ -0,0 +1,95 @@
+package jsjsontest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ktbsomen/jsjson"
+	"github.com/ktbsomen/jsjson/jsjsontest"
+)
+
+// recorder captures the failures reported to it
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Logf(format string, args ...interface{}) {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertJSONEqual(t *testing.T) {
+	tests := []struct {
+		name      string
+		want, got interface{}
+		diffs     []string
+	}{
+		{"key order and whitespace", `{"a": 1, "b": [1, 2]}`, `{"b":[1,2],"a":1}`, nil},
+		{"numbers by value", `{"n": 1.0, "m": 1e2}`, map[string]interface{}{"n": 1, "m": 100}, nil},
+		{"big numbers", `{"n": 1}`, jsjson.Parse(`{"n": 1}`, jsjson.ParseWithNumbers()), nil},
+		{"changed value", `{"user": {"name": "Ann", "age": 30}}`, `{"user": {"name": "Bob", "age": 30}}`,
+			[]string{`user.name: expected "Ann", got "Bob"`}},
+		{"missing and unexpected keys", `{"a": 1}`, `{"b": 2}`,
+			[]string{`a: missing, expected 1`, `b: unexpected 2`}},
+		{"array element", `{"items": [{"id": 1}, {"id": 2}]}`, `{"items": [{"id": 1}, {"id": 3}]}`,
+			[]string{`items[1].id: expected 2, got 3`}},
+		{"array length", `[1, 2]`, `[1]`, []string{`(root): expected 2 elements, got 1`}},
+		{"type change", `{"a": "1"}`, `{"a": 1}`, []string{`a: expected "1", got 1`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			ok := jsjsontest.AssertJSONEqual(r, tt.want, tt.got)
+			if ok != (len(tt.diffs) == 0) || ok != (len(r.errors) == 0) {
+				t.Fatalf("Unexpected result %v with errors %q", ok, r.errors)
+			}
+			for _, d := range tt.diffs {
+				if !strings.Contains(r.errors[0], d) {
+					t.Errorf("Expected %q in %s", d, r.errors[0])
+				}
+			}
+		})
+	}
+}
+
+func TestAssertJSONSubset(t *testing.T) {
+	got := `{"id": 7, "user": {"name": "Ann", "email": "a@example.com"}, "tags": [{"k": "x", "v": 1}]}`
+	tests := []struct {
+		name  string
+		want  string
+		diffs []string
+	}{
+		{"extra members ignored", `{"user": {"name": "Ann"}, "tags": [{"k": "x"}]}`, nil},
+		{"different value", `{"user": {"name": "Bob"}}`, []string{`user.name: expected "Bob", got "Ann"`}},
+		{"missing member", `{"user": {"phone": "555"}}`, []string{`user.phone: missing, expected "555"`}},
+		{"array length", `{"tags": []}`, []string{`tags: expected 0 elements, got 1`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			ok := jsjsontest.AssertJSONSubset(r, tt.want, got)
+			if ok != (len(tt.diffs) == 0) || ok != (len(r.errors) == 0) {
+				t.Fatalf("Unexpected result %v with errors %q", ok, r.errors)
+			}
+			for _, d := range tt.diffs {
+				if !strings.Contains(r.errors[0], d) {
+					t.Errorf("Expected %q in %s", d, r.errors[0])
+				}
+			}
+		})
+	}
+}
+
+func TestAssertInvalidInput(t *testing.T) {
+	r := &recorder{}
+	if jsjsontest.AssertJSONEqual(r, `{`, `{}`) || len(r.errors) != 1 || !strings.Contains(r.errors[0], "invalid expected value") {
+		t.Errorf("Unexpected errors %q", r.errors)
+	}
+}
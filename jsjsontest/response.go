@@ -0,0 +1,120 @@
+package jsjsontest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+// ResponseBuilder builds API response envelopes for tests, in the shape
+//
+//	{"success": true, "data": ..., "message": "...", "meta": {"page": 1, "total_pages": 5}}
+//
+// Start one with Response and chain the setters:
+//
+//	resp := jsjsontest.Response().Status(200).Data(users).Paginated(1, 5).Build()
+//
+// The builder is also an http.Handler, so it can stand in for a server in
+// client tests: httptest.NewServer(jsjsontest.Response().Data(users)).
+type ResponseBuilder struct {
+	status    int
+	success   *bool
+	data      interface{}
+	message   string
+	paginated bool
+	page      int
+	pages     int
+	header    http.Header
+}
+
+// Response returns a builder for a 200 response with null data
+func Response() *ResponseBuilder {
+	return &ResponseBuilder{status: http.StatusOK, header: make(http.Header)}
+}
+
+// Status sets the HTTP status. Unless Success is called, success is true
+// for statuses below 400 and false otherwise.
+func (b *ResponseBuilder) Status(code int) *ResponseBuilder {
+	b.status = code
+	return b
+}
+
+// Success sets the success member regardless of the status
+func (b *ResponseBuilder) Success(ok bool) *ResponseBuilder {
+	b.success = &ok
+	return b
+}
+
+// Data sets the data member. v is converted as by jsjson.Parse, so a
+// string or []byte is JSON text.
+func (b *ResponseBuilder) Data(v interface{}) *ResponseBuilder {
+	b.data = v
+	return b
+}
+
+// Message sets the message member
+func (b *ResponseBuilder) Message(msg string) *ResponseBuilder {
+	b.message = msg
+	return b
+}
+
+// Paginated adds the meta member with the page number and page count
+func (b *ResponseBuilder) Paginated(page, totalPages int) *ResponseBuilder {
+	b.paginated, b.page, b.pages = true, page, totalPages
+	return b
+}
+
+// Header adds a header to the HTTP responses made by ServeHTTP and
+// HTTPResponse
+func (b *ResponseBuilder) Header(key, value string) *ResponseBuilder {
+	b.header.Add(key, value)
+	return b
+}
+
+// Build returns the envelope. If the data cannot be converted, the result
+// holds the error.
+func (b *ResponseBuilder) Build() jsjson.JSONValue {
+	var data interface{}
+	if b.data != nil {
+		jv := jsjson.Parse(b.data)
+		if jv.Error() != nil {
+			return jv
+		}
+		data = jv.Raw()
+	}
+	success := b.status < 400
+	if b.success != nil {
+		success = *b.success
+	}
+	envelope := map[string]interface{}{
+		"success": success,
+		"data":    data,
+		"message": b.message,
+	}
+	if b.paginated {
+		envelope["meta"] = map[string]interface{}{
+			"page":        float64(b.page),
+			"total_pages": float64(b.pages),
+		}
+	}
+	return jsjson.Valid(envelope)
+}
+
+// ServeHTTP writes the envelope with the builder's status and headers
+func (b *ResponseBuilder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for k, v := range b.header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+	if err := jsjson.WriteJSON(w, b.status, b.Build()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HTTPResponse returns the envelope as an *http.Response, as a mocked
+// http.RoundTripper would return it
+func (b *ResponseBuilder) HTTPResponse() *http.Response {
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Result()
+}
@@ -0,0 +1,74 @@
+package jsjsontest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ktbsomen/jsjson"
+	"github.com/ktbsomen/jsjson/jsjsontest"
+)
+
+func TestResponseBuild(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *jsjsontest.ResponseBuilder
+		want string
+	}{
+		{
+			name: "defaults",
+			resp: jsjsontest.Response(),
+			want: `{"success": true, "data": null, "message": ""}`,
+		},
+		{
+			name: "paginated data",
+			resp: jsjsontest.Response().Status(200).Data(`{"users": [{"name": "Ann"}]}`).Paginated(1, 5).Message("ok"),
+			want: `{"success": true, "data": {"users": [{"name": "Ann"}]}, "message": "ok", "meta": {"page": 1, "total_pages": 5}}`,
+		},
+		{
+			name: "error status",
+			resp: jsjsontest.Response().Status(404).Message("user not found"),
+			want: `{"success": false, "data": null, "message": "user not found"}`,
+		},
+		{
+			name: "explicit success and Go data",
+			resp: jsjsontest.Response().Status(500).Success(true).Data(map[string]int{"n": 1}),
+			want: `{"success": true, "data": {"n": 1}, "message": ""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.resp.Build()
+			if err := got.Error(); err != nil {
+				t.Fatal(err)
+			}
+			jsjsontest.AssertJSONEqual(t, tt.want, got)
+		})
+	}
+
+	if err := jsjsontest.Response().Data(`{`).Build().Error(); err == nil {
+		t.Error("Expected invalid data to give an error")
+	}
+}
+
+func TestResponseHTTP(t *testing.T) {
+	resp := jsjsontest.Response().Status(http.StatusCreated).Data(`{"id": 7}`).Header("X-Request-Id", "abc")
+
+	srv := httptest.NewServer(resp)
+	defer srv.Close()
+	got, err := jsjson.GetJSON(context.Background(), srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsjsontest.AssertJSONEqual(t, `{"success": true, "data": {"id": 7}, "message": ""}`, got)
+
+	hr := resp.HTTPResponse()
+	defer hr.Body.Close()
+	if hr.StatusCode != http.StatusCreated || hr.Header.Get("X-Request-Id") != "abc" || hr.Header.Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("Unexpected response %d %v", hr.StatusCode, hr.Header)
+	}
+	body := jsjson.ParseReader(hr.Body)
+	jsjsontest.AssertJSONSubset(t, `{"data": {"id": 7}}`, body)
+}
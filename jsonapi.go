@@ -0,0 +1,212 @@
+package jsjson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// -------------------- JSON:API --------------------
+
+// ResolveJSONAPI denormalizes a JSON:API (jsonapi.org) document. Each
+// resource in the primary data becomes a plain object holding its id, type
+// and attributes, with every relationship replaced by the related resource,
+// itself resolved the same way:
+//
+//	{"data": {"type": "articles", "id": "1",
+//	          "attributes": {"title": "Hi"},
+//	          "relationships": {"author": {"data": {"type": "people", "id": "9"}}}},
+//	 "included": [{"type": "people", "id": "9", "attributes": {"name": "Ann"}}]}
+//
+// resolves to
+//
+//	{"id": "1", "type": "articles", "title": "Hi",
+//	 "author": {"id": "9", "type": "people", "name": "Ann"}}
+//
+// The result is an object for a single resource, an array for a collection
+// and null for null data. To-one relationships become an object or null and
+// to-many relationships an array. A related resource found in neither
+// included nor the primary data, and a resource already being resolved
+// higher up the same chain, appear as just their id and type, so cyclic
+// references terminate. Relationships without data (links only) are left
+// out, as are links and meta. Resources identified by lid are matched by it.
+//
+// A document with errors instead of data fails with the first error's status,
+// title and detail.
+func ResolveJSONAPI(doc JSONValue) JSONValue {
+	if doc.err != nil {
+		return doc
+	}
+	fail := func(err error) JSONValue {
+		return JSONValue{err: &JSONError{Op: "ResolveJSONAPI", Err: err}}
+	}
+
+	root, err := normalize(doc.data)
+	if err != nil {
+		return fail(err)
+	}
+	top, ok := root.(map[string]interface{})
+	if !ok {
+		return fail(fmt.Errorf("%w: expected a JSON:API document object, got %s", ErrTypeMismatch, JSONValue{data: root}.Type()))
+	}
+	data, hasData := top["data"]
+	if !hasData {
+		if errs, ok := top["errors"].([]interface{}); ok && len(errs) > 0 {
+			return fail(jsonAPIError(errs))
+		}
+		return fail(fmt.Errorf("%w: document has no data", ErrKeyNotFound))
+	}
+
+	r := &jsonAPIResolver{resources: make(map[string]map[string]interface{}), visiting: make(map[string]bool)}
+	included, _ := top["included"].([]interface{})
+	for _, list := range [][]interface{}{included, jsonAPIList(data)} {
+		for _, item := range list {
+			res, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if key, ok := jsonAPIKey(res); ok {
+				r.resources[key] = res
+			}
+		}
+	}
+
+	switch d := data.(type) {
+	case nil:
+		return JSONValue{data: nil}
+	case []interface{}:
+		out := make([]interface{}, len(d))
+		for i, item := range d {
+			if out[i], err = r.resolve(item); err != nil {
+				return fail(fmt.Errorf("data[%d]: %w", i, err))
+			}
+		}
+		return JSONValue{data: out}
+	default:
+		out, err := r.resolve(d)
+		if err != nil {
+			return fail(fmt.Errorf("data: %w", err))
+		}
+		return JSONValue{data: out}
+	}
+}
+
+// jsonAPIResolver resolves resources by type and id
+type jsonAPIResolver struct {
+	resources map[string]map[string]interface{}
+	// visiting holds the resources being resolved on the current chain
+	visiting map[string]bool
+}
+
+func (r *jsonAPIResolver) resolve(item interface{}) (interface{}, error) {
+	res, ok := item.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: resource is %s, not an object", ErrTypeMismatch, JSONValue{data: item}.Type())
+	}
+	key, ok := jsonAPIKey(res)
+	if !ok {
+		return nil, fmt.Errorf("%w: resource has no type and id", ErrTypeMismatch)
+	}
+	if full, ok := r.resources[key]; ok {
+		res = full
+	}
+
+	out := jsonAPIIdentifier(res)
+	if r.visiting[key] {
+		return out, nil
+	}
+	r.visiting[key] = true
+	defer delete(r.visiting, key)
+
+	if attrs, ok := res["attributes"].(map[string]interface{}); ok {
+		for k, v := range attrs {
+			out[k] = deepCopy(v)
+		}
+	}
+	rels, _ := res["relationships"].(map[string]interface{})
+	for name, rel := range rels {
+		relObj, ok := rel.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		linkage, ok := relObj["data"]
+		if !ok {
+			continue
+		}
+		switch l := linkage.(type) {
+		case nil:
+			out[name] = nil
+		case []interface{}:
+			related := make([]interface{}, len(l))
+			for i, id := range l {
+				v, err := r.resolve(id)
+				if err != nil {
+					return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+				}
+				related[i] = v
+			}
+			out[name] = related
+		default:
+			v, err := r.resolve(l)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			out[name] = v
+		}
+	}
+	return out, nil
+}
+
+// jsonAPIKey identifies a resource by its type and its id, or lid
+func jsonAPIKey(res map[string]interface{}) (string, bool) {
+	typ, ok := res["type"].(string)
+	if !ok {
+		return "", false
+	}
+	if id, ok := res["id"].(string); ok {
+		return typ + "\x00" + id, true
+	}
+	if lid, ok := res["lid"].(string); ok {
+		return typ + "\x00lid\x00" + lid, true
+	}
+	return "", false
+}
+
+// jsonAPIIdentifier returns a new object holding the id (or lid) and type
+// of res
+func jsonAPIIdentifier(res map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{"type": res["type"]}
+	if id, ok := res["id"]; ok {
+		out["id"] = id
+	} else {
+		out["lid"] = res["lid"]
+	}
+	return out
+}
+
+// jsonAPIError describes the first entry of a JSON:API errors array
+func jsonAPIError(errs []interface{}) error {
+	var parts []string
+	if e, ok := errs[0].(map[string]interface{}); ok {
+		for _, k := range []string{"status", "title", "detail"} {
+			if s, ok := e[k].(string); ok && s != "" {
+				parts = append(parts, s)
+			}
+		}
+	}
+	msg := "error document"
+	if len(parts) > 0 {
+		msg += ": " + strings.Join(parts, ": ")
+	}
+	if len(errs) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(errs)-1)
+	}
+	return errors.New(msg)
+}
+
+func jsonAPIList(v interface{}) []interface{} {
+	if list, ok := v.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{v}
+}
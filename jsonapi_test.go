@@ -0,0 +1,99 @@
+package jsjson_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestResolveJSONAPI(t *testing.T) {
+	doc := JSON.Parse(`{
+		"data": [{
+			"type": "articles", "id": "1",
+			"attributes": {"title": "JSON:API paints my bikeshed!"},
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "9"}},
+				"comments": {"data": [{"type": "comments", "id": "5"}, {"type": "comments", "id": "12"}]},
+				"editor": {"data": null},
+				"tags": {"links": {"related": "/articles/1/tags"}}
+			}
+		}],
+		"included": [
+			{"type": "people", "id": "9", "attributes": {"name": "Dan"},
+			 "relationships": {"articles": {"data": [{"type": "articles", "id": "1"}]}}},
+			{"type": "comments", "id": "5", "attributes": {"body": "First!"},
+			 "relationships": {"author": {"data": {"type": "people", "id": "2"}}}},
+			{"type": "comments", "id": "12", "attributes": {"body": "I like XML better"},
+			 "relationships": {"author": {"data": {"type": "people", "id": "9"}}}}
+		]
+	}`)
+
+	got := JSON.ResolveJSONAPI(doc)
+	if err := got.Error(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := JSON.Parse(`[{
+		"type": "articles", "id": "1",
+		"title": "JSON:API paints my bikeshed!",
+		"author": {"type": "people", "id": "9", "name": "Dan",
+		           "articles": [{"type": "articles", "id": "1"}]},
+		"comments": [
+			{"type": "comments", "id": "5", "body": "First!",
+			 "author": {"type": "people", "id": "2"}},
+			{"type": "comments", "id": "12", "body": "I like XML better",
+			 "author": {"type": "people", "id": "9", "name": "Dan",
+			            "articles": [{"type": "articles", "id": "1"}]}}
+		],
+		"editor": null
+	}]`)
+	if !reflect.DeepEqual(got.Raw(), want.Raw()) {
+		s, _ := JSON.Stringify(got)
+		t.Errorf("Unexpected result %s", s)
+	}
+}
+
+func TestResolveJSONAPISingle(t *testing.T) {
+	got := JSON.ResolveJSONAPI(JSON.Parse(`{"data": {"type": "people", "lid": "tmp-1", "attributes": {"name": "Ann"}}}`))
+	if name, _ := got.Get("name").String(); name != "Ann" {
+		t.Errorf("Expected Ann, got %v", got)
+	}
+	if lid, _ := got.Get("lid").String(); lid != "tmp-1" {
+		t.Errorf("Expected lid tmp-1, got %v", got)
+	}
+
+	if got := JSON.ResolveJSONAPI(JSON.Parse(`{"data": null}`)); got.Error() != nil || !got.IsNull() {
+		t.Errorf("Expected null, got %v", got)
+	}
+}
+
+func TestResolveJSONAPIErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr error
+		wantMsg string
+	}{
+		{"error document", `{"errors": [{"status": "404", "title": "Not Found", "detail": "no article 7"}]}`, nil, "404: Not Found: no article 7"},
+		{"no data", `{"meta": {}}`, JSON.ErrKeyNotFound, ""},
+		{"not an object", `[1]`, JSON.ErrTypeMismatch, ""},
+		{"resource without type", `{"data": {"id": "1"}}`, JSON.ErrTypeMismatch, ""},
+		{"bad linkage", `{"data": {"type": "a", "id": "1", "relationships": {"b": {"data": "x"}}}}`, JSON.ErrTypeMismatch, "b:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JSON.ResolveJSONAPI(JSON.Parse(tt.doc)).Error()
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Expected %q in %v", tt.wantMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package jsjson
+
+import "fmt"
+
+// checkLimits validates raw JSON input against the configured limits before
+// it is decoded, so oversized payloads are rejected without allocating a tree
+func checkLimits(data []byte, o *parseOptions) error {
+	if o.maxBytes > 0 && int64(len(data)) > o.maxBytes {
+		return fmt.Errorf("%w: input is %d bytes, max %d", ErrLimitExceeded, len(data), o.maxBytes)
+	}
+	if !o.hasTokenLimits() {
+		return nil
+	}
+
+	// stack holds -1 for an open object and the element count for an open array
+	var stack []int
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '"':
+			start := i
+			for i++; i < len(data); i++ {
+				if data[i] == '\\' {
+					i++
+					continue
+				}
+				if data[i] == '"' {
+					break
+				}
+			}
+			if o.maxStringLen > 0 && i-start-1 > o.maxStringLen {
+				return fmt.Errorf("%w: string at offset %d is longer than %d bytes", ErrLimitExceeded, start, o.maxStringLen)
+			}
+		case ',':
+			if n := len(stack); n > 0 && stack[n-1] >= 0 {
+				stack[n-1]++
+				if o.maxArrayElements > 0 && stack[n-1] > o.maxArrayElements {
+					return fmt.Errorf("%w: array at offset %d has more than %d elements", ErrLimitExceeded, i, o.maxArrayElements)
+				}
+			}
+			continue
+		case ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		case ' ', '\t', '\n', '\r', ':':
+			continue
+		}
+
+		// Any other byte starts (or continues) a value; the first value
+		// inside an array makes its element count one
+		if n := len(stack); n > 0 && stack[n-1] == 0 {
+			stack[n-1] = 1
+		}
+		switch c {
+		case '[':
+			stack = append(stack, 0)
+		case '{':
+			stack = append(stack, -1)
+		}
+	}
+	return nil
+}
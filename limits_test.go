@@ -0,0 +1,82 @@
+package jsjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    []interface{}
+		wantErr bool
+	}{
+		{"within max bytes", `{"a":1}`, []interface{}{JSON.WithMaxBytes(7)}, false},
+		{"over max bytes", `{"a":1}`, []interface{}{JSON.WithMaxBytes(6)}, true},
+		{"string within limit", `{"key":"abcd"}`, []interface{}{JSON.WithMaxStringLen(4)}, false},
+		{"string over limit", `{"key":"abcde"}`, []interface{}{JSON.WithMaxStringLen(4)}, true},
+		{"key over limit", `{"longkey":1}`, []interface{}{JSON.WithMaxStringLen(4)}, true},
+		{"escaped quote in string", `["ab\"c"]`, []interface{}{JSON.WithMaxStringLen(5)}, false},
+		{"array within limit", `[1,2,3]`, []interface{}{JSON.WithMaxArrayElements(3)}, false},
+		{"array over limit", `[1,2,3,4]`, []interface{}{JSON.WithMaxArrayElements(3)}, true},
+		{"nested array over limit", `{"a":[[1,2],[1,2,3,4]]}`, []interface{}{JSON.WithMaxArrayElements(3)}, true},
+		{"commas in objects ignored", `[{"a":1,"b":2,"c":3,"d":4}]`, []interface{}{JSON.WithMaxArrayElements(1)}, false},
+		{"commas in strings ignored", `["a,b,c,d"]`, []interface{}{JSON.WithMaxArrayElements(1)}, false},
+		{"empty array", `[]`, []interface{}{JSON.WithMaxArrayElements(1)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := JSON.Parse(tt.input, tt.opts...)
+			if tt.wantErr {
+				if result.IsValid() {
+					t.Fatal("Expected error but got none")
+				}
+				if !errors.Is(result.Error(), JSON.ErrLimitExceeded) {
+					t.Errorf("Expected ErrLimitExceeded, got: %v", result.Error())
+				}
+			}
+			if !tt.wantErr && !result.IsValid() {
+				t.Errorf("Expected no error but got: %v", result.Error())
+			}
+		})
+	}
+}
+
+func TestParseLimitsWithDestination(t *testing.T) {
+	var dest struct {
+		Tags []string `json:"tags"`
+	}
+
+	result := JSON.Parse(`{"tags":["a","b","c"]}`, &dest, JSON.WithMaxArrayElements(2))
+	if result.IsValid() {
+		t.Error("Expected limit error with struct destination")
+	}
+	if len(dest.Tags) != 0 {
+		t.Errorf("Destination should not be populated on limit error, got: %v", dest.Tags)
+	}
+
+	result = JSON.Parse(`{"tags":["a","b"]}`, &dest, JSON.WithMaxArrayElements(2))
+	if !result.IsValid() {
+		t.Fatalf("Expected no error, got: %v", result.Error())
+	}
+	if len(dest.Tags) != 2 {
+		t.Errorf("Expected 2 tags, got: %v", dest.Tags)
+	}
+}
+
+func TestParseIntoLimits(t *testing.T) {
+	var dest map[string]interface{}
+	err := JSON.ParseInto(`{"name":"`+strings.Repeat("x", 100)+`"}`, &dest, JSON.WithMaxStringLen(10))
+	if !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got: %v", err)
+	}
+
+	if err := JSON.ParseInto(`{"name":"short"}`, &dest, JSON.WithMaxStringLen(10)); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
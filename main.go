@@ -2,6 +2,7 @@ package jsjson
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -12,18 +13,41 @@ import (
 type JSONValue struct {
 	data interface{}
 	err  error
+	raw   *rawRef      // original text, retained with ParseWithRaw
+	arena *arenaHandle // set on roots parsed with WithArena
 }
 
 // Error types for better error handling
 type JSONError struct {
 	Op  string
 	Err error
+
+	path []interface{} // keys up to the failing one, for access errors
 }
 
 func (e *JSONError) Error() string {
+	if len(e.path) > 0 {
+		return fmt.Sprintf("jsonjs.%s: %s: %v", e.Op, formatPath(e.path), e.Err)
+	}
 	return fmt.Sprintf("jsonjs.%s: %v", e.Op, e.Err)
 }
 
+// Path returns the keys (strings) and indices (ints) of a failed access, up
+// to and including the one that failed, so Get("users", 0, "prefs",
+// "privacy") failing on a missing prefs key reports ["users", 0, "prefs"].
+// It is nil for errors that are not about an access path.
+func (e *JSONError) Path() []interface{} {
+	if e.path == nil {
+		return nil
+	}
+	return append([]interface{}(nil), e.path...)
+}
+
+// Unwrap returns the underlying error so errors.Is and errors.As can inspect it
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
 var (
 	// Object pool for JSONValue instances to reduce GC pressure
 	jsonValuePool = sync.Pool{
@@ -67,12 +91,15 @@ func putBytesBuffer(b *[]byte) {
 // -------------------- Core JSON API --------------------
 
 // Parse creates a JSONValue from various input types with optional struct destination
-// Usage: Parse(data) or Parse(data, &structDest)
+// and ParseOption values
+// Usage: Parse(data), Parse(data, &structDest) or Parse(data, &structDest, opts...)
 func Parse(v interface{}, dest ...interface{}) JSONValue {
 	if v == nil {
 		return JSONValue{err: &JSONError{Op: "Parse", Err: fmt.Errorf("input is nil")}}
 	}
 
+	dest, opts := splitArgs(dest)
+
 	// Check if destination struct is provided
 	var structDest interface{}
 	if len(dest) > 0 {
@@ -93,12 +120,12 @@ func Parse(v interface{}, dest ...interface{}) JSONValue {
 	switch val := v.(type) {
 	case string:
 		if val == "" {
-			return JSONValue{err: &JSONError{Op: "Parse", Err: fmt.Errorf("empty string")}}
+			return JSONValue{err: &JSONError{Op: "Parse", Err: fmt.Errorf("%w: empty string", ErrSyntax)}}
 		}
 		jsonBytes = []byte(val)
 	case []byte:
 		if len(val) == 0 {
-			return JSONValue{err: &JSONError{Op: "Parse", Err: fmt.Errorf("empty byte slice")}}
+			return JSONValue{err: &JSONError{Op: "Parse", Err: fmt.Errorf("%w: empty byte slice", ErrSyntax)}}
 		}
 		jsonBytes = val
 	case JSONValue:
@@ -112,38 +139,75 @@ func Parse(v interface{}, dest ...interface{}) JSONValue {
 	default:
 		// For other types, try to marshal then unmarshal
 		var marshalErr error
-		jsonBytes, marshalErr = json.Marshal(val)
+		jsonBytes, marshalErr = marshalValue(val)
 		if marshalErr != nil {
 			return JSONValue{err: &JSONError{Op: "Parse", Err: marshalErr}}
 		}
 	}
 
-	// If struct destination is provided, unmarshal directly into it
+	if jsonBytes, err = prepareText(jsonBytes, &opts); err != nil {
+		return JSONValue{err: &JSONError{Op: "Parse", Err: err}}
+	}
+	if err = checkLimits(jsonBytes, &opts); err != nil {
+		return JSONValue{err: &JSONError{Op: "Parse", Err: err}}
+	}
+	if _, borrowed := v.([]byte); borrowed && opts.keepRaw {
+		// The caller may reuse its buffer, so retain a private copy
+		jsonBytes = append([]byte(nil), jsonBytes...)
+	}
+
+	// If struct destination is provided, unmarshal directly into it. With the
+	// standard codec the tree is parsed once and bound to the destination;
+	// arena strings must not escape into it, so WithArena decodes twice.
+	// Numbers are bound from their literals, so integer fields above 2^53
+	// stay exact and fractions are rejected as encoding/json does; types
+	// holding interface{} values, which would receive those literals, are
+	// decoded by the codec.
+	if structDest != nil && usingStdCodec() && !opts.useArena {
+		if destElem := reflect.ValueOf(structDest).Elem(); destElem.CanSet() && !holdsInterface(destElem.Type(), map[reflect.Type]bool{}) {
+			treeOpts := opts
+			treeOpts.useNumber = true
+			if tree, err := decodeTree(jsonBytes, &treeOpts); err == nil && bindValue(destElem, tree) == nil {
+				result, ok := tree, true
+				if !opts.useNumber {
+					result, ok = floatTree(tree)
+				}
+				if ok {
+					return JSONValue{data: result, raw: newRawRef(jsonBytes, &opts)}
+				}
+			}
+		}
+	}
 	if structDest != nil {
-		err = json.Unmarshal(jsonBytes, structDest)
+		_, err = unmarshalValue(jsonBytes, structDest)
 		if err != nil {
 			return JSONValue{err: &JSONError{Op: "Parse", Err: err}}
 		}
 		// Also parse into generic interface{} for JSONValue functionality
-		err = json.Unmarshal(jsonBytes, &result)
+		result, err = decodeTree(jsonBytes, &opts)
 		if err != nil {
 			return JSONValue{err: &JSONError{Op: "Parse", Err: err}}
 		}
-		return JSONValue{data: result}
+		return JSONValue{data: result, raw: newRawRef(jsonBytes, &opts), arena: opts.arena.handle()}
 	}
 
 	// Standard parsing into interface{}
-	err = json.Unmarshal(jsonBytes, &result)
+	result, err = decodeTree(jsonBytes, &opts)
 	if err != nil {
 		return JSONValue{err: &JSONError{Op: "Parse", Err: err}}
 	}
 
-	return JSONValue{data: result}
+	return JSONValue{data: result, raw: newRawRef(jsonBytes, &opts), arena: opts.arena.handle()}
 }
 
 // ParseInto directly parses JSON data into a struct with better performance
 // This is more efficient than Parse + To for struct unmarshaling
-func ParseInto(data interface{}, dest interface{}) error {
+//
+// Fields tagged with jsjson rules, e.g. `jsjson:"required,min=1,email"`,
+// are validated once decoding succeeds; every failing field is reported in
+// a single *ValidationError. With DisallowUnknownFields, keys that match no
+// field are reported there as well.
+func ParseInto(data interface{}, dest interface{}, opts ...ParseOption) error {
 	if dest == nil {
 		return &JSONError{Op: "ParseInto", Err: fmt.Errorf("destination cannot be nil")}
 	}
@@ -153,40 +217,200 @@ func ParseInto(data interface{}, dest interface{}) error {
 		return &JSONError{Op: "ParseInto", Err: fmt.Errorf("destination must be a pointer, got %T", dest)}
 	}
 
-	var jsonBytes []byte
-	var err error
-
-	switch val := data.(type) {
-	case string:
-		if val == "" {
-			return &JSONError{Op: "ParseInto", Err: fmt.Errorf("empty string")}
-		}
-		jsonBytes = []byte(val)
-	case []byte:
-		if len(val) == 0 {
-			return &JSONError{Op: "ParseInto", Err: fmt.Errorf("empty byte slice")}
-		}
-		jsonBytes = val
-	case JSONValue:
+	if val, ok := data.(JSONValue); ok {
 		if val.err != nil {
 			return &JSONError{Op: "ParseInto", Err: val.err}
 		}
-		return val.To(dest)
-	default:
-		jsonBytes, err = json.Marshal(val)
-		if err != nil {
+		if err := val.To(dest); err != nil {
+			return err
+		}
+		o := newParseOptions(opts)
+		if err := checkStructTags(dest, val.data, nil, &o); err != nil {
 			return &JSONError{Op: "ParseInto", Err: err}
 		}
+		return nil
 	}
 
-	err = json.Unmarshal(jsonBytes, dest)
+	jsonBytes, err := inputBytes("ParseInto", data)
 	if err != nil {
+		return err
+	}
+
+	o := newParseOptions(opts)
+	if jsonBytes, err = prepareText(jsonBytes, &o); err != nil {
+		return &JSONError{Op: "ParseInto", Err: err}
+	}
+	if err = checkLimits(jsonBytes, &o); err != nil {
+		return &JSONError{Op: "ParseInto", Err: err}
+	}
+
+	tree, err := unmarshalValue(jsonBytes, dest)
+	if err != nil {
+		return &JSONError{Op: "ParseInto", Err: err}
+	}
+	if err = checkStructTags(dest, tree, jsonBytes, &o); err != nil {
 		return &JSONError{Op: "ParseInto", Err: err}
 	}
 
 	return nil
 }
 
+// ParseIntoAll parses JSON data once and stores it in each of several
+// destinations, for payloads read as more than one type:
+//
+//	var summary Summary
+//	var details Details
+//	var fields map[string]interface{}
+//	err := jsjson.ParseIntoAll(body, &summary, &details, &fields, jsjson.WithMaxBytes(1<<20))
+//
+// With the standard codec the input is tokenized once and every destination
+// is filled from the same tree; a destination the tree cannot be bound to
+// directly is decoded from the text on its own. Destinations share no maps
+// or slices. Options may appear among the destinations, as in Parse.
+// Decoding and struct tag validation follow ParseInto, and the first
+// destination that fails is named in the error.
+func ParseIntoAll(data interface{}, args ...interface{}) error {
+	dests, o := splitArgs(args)
+	if len(dests) == 0 {
+		return &JSONError{Op: "ParseIntoAll", Err: fmt.Errorf("no destination given")}
+	}
+	for i, dest := range dests {
+		if dest == nil || reflect.TypeOf(dest).Kind() != reflect.Ptr {
+			return &JSONError{Op: "ParseIntoAll", Err: fmt.Errorf("destination %d must be a non-nil pointer, got %T", i, dest)}
+		}
+	}
+
+	var (
+		jsonBytes []byte
+		tree      interface{}
+		shared    bool // tree holds the whole document, ready to bind
+		toFloat   bool // tree has json.Numbers the caller did not ask for
+	)
+	if val, ok := data.(JSONValue); ok {
+		if val.err != nil {
+			return &JSONError{Op: "ParseIntoAll", Err: val.err}
+		}
+		tree, shared = val.data, true
+	} else {
+		var err error
+		if jsonBytes, err = inputBytes("ParseIntoAll", data); err != nil {
+			return err
+		}
+		if jsonBytes, err = prepareText(jsonBytes, &o); err != nil {
+			return &JSONError{Op: "ParseIntoAll", Err: err}
+		}
+		if err = checkLimits(jsonBytes, &o); err != nil {
+			return &JSONError{Op: "ParseIntoAll", Err: err}
+		}
+		if usingStdCodec() {
+			// Numbers are kept as literals so integer fields above 2^53 stay
+			// exact, as encoding/json reads them; arena strings must not
+			// escape into the destinations
+			treeOpts := o
+			treeOpts.useArena = false
+			treeOpts.useNumber = true
+			if tree, err = decodeTree(jsonBytes, &treeOpts); err != nil {
+				return &JSONError{Op: "ParseIntoAll", Err: err}
+			}
+			shared, toFloat = true, !o.useNumber
+		}
+	}
+
+	for i, dest := range dests {
+		var err error
+		if shared {
+			err = bindDest(dest, tree, toFloat, jsonBytes, &o)
+		} else {
+			err = decodeDest(dest, jsonBytes, &o)
+		}
+		if err != nil {
+			return &JSONError{Op: "ParseIntoAll", Err: fmt.Errorf("destination %d (%T): %w", i, dest, unwrapOp(err))}
+		}
+	}
+	return nil
+}
+
+// bindDest fills a ParseIntoAll destination from the shared tree. A
+// destination that can hold interface{} values gets its own copy, so
+// destinations share no maps or slices. data is nil when the input was a
+// JSONValue; otherwise it is decoded from data if binding fails.
+func bindDest(dest, tree interface{}, toFloat bool, data []byte, o *parseOptions) error {
+	if holdsInterface(reflect.TypeOf(dest).Elem(), map[reflect.Type]bool{}) {
+		if !toFloat {
+			tree = deepCopy(tree)
+		} else if own, ok := floatTree(tree); ok {
+			tree = own
+		} else {
+			return decodeDest(dest, data, o)
+		}
+	}
+	if data == nil {
+		if err := (JSONValue{data: tree}).To(dest); err != nil {
+			return err
+		}
+		return checkStructTags(dest, tree, nil, o)
+	}
+	if elem := reflect.ValueOf(dest).Elem(); !elem.CanSet() || bindValue(elem, tree) != nil {
+		return decodeDest(dest, data, o)
+	}
+	return checkStructTags(dest, tree, data, o)
+}
+
+// decodeDest fills a ParseIntoAll destination from the text, as ParseInto
+// does
+func decodeDest(dest interface{}, data []byte, o *parseOptions) error {
+	tree, err := unmarshalValue(data, dest)
+	if err != nil {
+		return err
+	}
+	return checkStructTags(dest, tree, data, o)
+}
+
+// holdsInterface reports whether a value of type t can contain an
+// interface{}, which binding fills with parts of the tree itself
+func holdsInterface(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return holdsInterface(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if holdsInterface(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inputBytes returns the JSON text for a string, []byte or any other value
+// passed as Parse input, marshaling the latter
+func inputBytes(op string, data interface{}) ([]byte, error) {
+	switch val := data.(type) {
+	case string:
+		if val == "" {
+			return nil, &JSONError{Op: op, Err: fmt.Errorf("%w: empty string", ErrSyntax)}
+		}
+		return []byte(val), nil
+	case []byte:
+		if len(val) == 0 {
+			return nil, &JSONError{Op: op, Err: fmt.Errorf("%w: empty byte slice", ErrSyntax)}
+		}
+		return val, nil
+	default:
+		b, err := marshalValue(val)
+		if err != nil {
+			return nil, &JSONError{Op: op, Err: err}
+		}
+		return b, nil
+	}
+}
+
 // MustParse is like Parse but panics on error
 func MustParse(v interface{}, dest ...interface{}) JSONValue {
 	result := Parse(v, dest...)
@@ -197,14 +421,15 @@ func MustParse(v interface{}, dest ...interface{}) JSONValue {
 }
 
 // MustParseInto is like ParseInto but panics on error
-func MustParseInto(data interface{}, dest interface{}) {
-	if err := ParseInto(data, dest); err != nil {
+func MustParseInto(data interface{}, dest interface{}, opts ...ParseOption) {
+	if err := ParseInto(data, dest, opts...); err != nil {
 		panic(err)
 	}
 }
 
-// Stringify converts a value to JSON string
-func Stringify(v interface{}) (string, error) {
+// Stringify converts a value to JSON string. opts can indent the output or
+// escape non-ASCII characters, as in WriteFile.
+func Stringify(v interface{}, opts ...WriteOption) (string, error) {
 	if v == nil {
 		return "null", nil
 	}
@@ -217,6 +442,18 @@ func Stringify(v interface{}) (string, error) {
 		v = jv.data
 	}
 
+	if len(opts) > 0 {
+		var o writeOptions
+		for _, opt := range opts {
+			opt(&o)
+		}
+		b, err := encodeWith(v, &o)
+		if err != nil {
+			return "", &JSONError{Op: "Stringify", Err: err}
+		}
+		return string(b), nil
+	}
+
 	// Use buffer pool for better performance
 	buffer := getBytesBuffer()
 	defer putBytesBuffer(buffer)
@@ -226,23 +463,16 @@ func Stringify(v interface{}) (string, error) {
 		*buffer = make([]byte, 0, 1024)
 	}
 
-	encoder := json.NewEncoder(&bytesWriter{buffer})
-	err := encoder.Encode(v)
-	if err != nil {
+	if err := encodeInto(buffer, v); err != nil {
 		return "", &JSONError{Op: "Stringify", Err: err}
 	}
 
-	// Remove trailing newline that encoder adds
-	result := *buffer
-	if len(result) > 0 && result[len(result)-1] == '\n' {
-		result = result[:len(result)-1]
-	}
-
-	return string(result), nil
+	return string(*buffer), nil
 }
 
-// StringifyPretty converts a value to pretty-printed JSON string
-func StringifyPretty(v interface{}, indent string) (string, error) {
+// StringifyPretty converts a value to pretty-printed JSON string. indent
+// takes precedence over a WithIndent option.
+func StringifyPretty(v interface{}, indent string, opts ...WriteOption) (string, error) {
 	if v == nil {
 		return "null", nil
 	}
@@ -254,10 +484,17 @@ func StringifyPretty(v interface{}, indent string) (string, error) {
 		v = jv.data
 	}
 
-	bytes, err := json.MarshalIndent(v, "", indent)
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	bytes, err := marshalIndent(o.replaceNonFinite(v), indent)
 	if err != nil {
 		return "", &JSONError{Op: "StringifyPretty", Err: err}
 	}
+	if o.ascii {
+		bytes = escapeNonASCII(bytes)
+	}
 	return string(bytes), nil
 }
 
@@ -275,6 +512,12 @@ func (j JSONValue) Error() error {
 
 // Get allows nested access with error propagation
 func (j JSONValue) Get(keys ...interface{}) JSONValue {
+	return j.get("Get", false, keys)
+}
+
+// get walks keys from j. With fold, object keys without an exact match are
+// matched case-insensitively.
+func (j JSONValue) get(op string, fold bool, keys []interface{}) JSONValue {
 	if j.err != nil {
 		return j // Propagate existing error
 	}
@@ -284,57 +527,58 @@ func (j JSONValue) Get(keys ...interface{}) JSONValue {
 	}
 
 	current := j.data
+	var folded []interface{} // keys with folded matches replaced, for raw lookups
 	for i, key := range keys {
 		if current == nil {
-			return JSONValue{err: &JSONError{
-				Op:  "Get",
-				Err: fmt.Errorf("cannot access key %v on nil value at position %d", key, i),
-			}}
+			return accessError(op, keys, i, fmt.Errorf("%w: cannot access key %v on %w", ErrTypeMismatch, key, ErrNilValue))
 		}
 
 		switch c := current.(type) {
 		case map[string]interface{}:
 			keyStr, ok := key.(string)
 			if !ok {
-				return JSONValue{err: &JSONError{
-					Op:  "Get",
-					Err: fmt.Errorf("key must be string for object access, got %T at position %d", key, i),
-				}}
+				return accessError(op, keys, i, fmt.Errorf("%w: key must be string for object access, got %T", ErrTypeMismatch, key))
 			}
 			var exists bool
 			current, exists = c[keyStr]
+			if !exists && fold {
+				var actual string
+				if actual, current, exists = foldLookup(c, keyStr); exists && j.raw != nil {
+					if folded == nil {
+						folded = append([]interface{}(nil), keys...)
+					}
+					folded[i] = actual
+				}
+			}
 			if !exists {
-				return JSONValue{err: &JSONError{
-					Op:  "Get",
-					Err: fmt.Errorf("key %q not found at position %d", keyStr, i),
-				}}
+				return accessError(op, keys, i, ErrKeyNotFound)
 			}
 
 		case []interface{}:
 			idx, err := convertToIndex(key)
 			if err != nil {
-				return JSONValue{err: &JSONError{
-					Op:  "Get",
-					Err: fmt.Errorf("invalid array index %v at position %d: %v", key, i, err),
-				}}
+				return accessError(op, keys, i, fmt.Errorf("%w: invalid array index %v: %v", ErrTypeMismatch, key, err))
 			}
 			if idx < 0 || idx >= len(c) {
-				return JSONValue{err: &JSONError{
-					Op:  "Get",
-					Err: fmt.Errorf("array index %d out of bounds (length: %d) at position %d", idx, len(c), i),
-				}}
+				return accessError(op, keys, i, fmt.Errorf("%w: index %d (length: %d)", ErrIndexOutOfRange, idx, len(c)))
 			}
 			current = c[idx]
 
 		default:
-			return JSONValue{err: &JSONError{
-				Op:  "Get",
-				Err: fmt.Errorf("cannot access key %v on type %T at position %d", key, current, i),
-			}}
+			return accessError(op, keys, i, fmt.Errorf("%w: cannot access key %v on type %T", ErrTypeMismatch, key, current))
 		}
 	}
 
-	return JSONValue{data: current}
+	if folded != nil {
+		keys = folded
+	}
+	return JSONValue{data: current, raw: j.raw.child(keys...)}
+}
+
+// accessError reports a failed access to keys[i], recording the keys up to
+// it as the error's path
+func accessError(op string, keys []interface{}, i int, err error) JSONValue {
+	return JSONValue{err: &JSONError{Op: op, Err: err, path: append([]interface{}(nil), keys[:i+1]...)}}
 }
 
 // GetOr returns the value at the given keys or the default value if not found/error
@@ -346,59 +590,91 @@ func (j JSONValue) GetOr(defaultValue interface{}, keys ...interface{}) interfac
 	return result.data
 }
 
+// GetOrMissing returns the value at the given keys, or defaultValue only if
+// they are missing: a present null stays null and other errors, such as a
+// key on a string, are returned rather than hidden. This is what PATCH
+// handlers need, where an explicit null clears a field and an absent one
+// keeps it:
+//
+//	nickname := patch.GetOrMissing(current.Nickname, "nickname")
+//
+// See IsMissing for what counts as missing.
+func (j JSONValue) GetOrMissing(defaultValue interface{}, keys ...interface{}) JSONValue {
+	result := j.Get(keys...)
+	if result.IsMissing() {
+		return Valid(defaultValue)
+	}
+	return result
+}
+
 // Has checks if a key path exists
 func (j JSONValue) Has(keys ...interface{}) bool {
 	return j.Get(keys...).IsValid()
 }
 
+// Exists reports whether the value was found. A present null exists; use
+// IsNull to tell it apart.
+func (j JSONValue) Exists() bool {
+	return j.err == nil
+}
+
+// IsMissing reports whether the value is absent from the document: an
+// object key that does not exist, an index past the end of an array, or a
+// key below a null value. Errors where a value is present but has the wrong
+// type, such as a key on a string, are not missing.
+func (j JSONValue) IsMissing() bool {
+	return errors.Is(j.err, ErrKeyNotFound) || errors.Is(j.err, ErrIndexOutOfRange) || errors.Is(j.err, ErrNilValue)
+}
+
 // -------------------- Type Conversion Methods --------------------
 
-// String returns the value as string with error handling
+// String returns the value as string with error handling. The package-level
+// StringOptions apply (see SetStringOptions).
 func (j JSONValue) String() (string, error) {
+	return j.stringWith("String", stringOptions())
+}
+
+func (j JSONValue) stringWith(op string, o StringOptions) (string, error) {
 	if j.err != nil {
 		return "", j.err
 	}
 
+	var s string
 	switch v := j.data.(type) {
 	case string:
-		return v, nil
+		s = v
+	case json.Number:
+		s = v.String()
 	case nil:
-		return "", nil
 	default:
-		return fmt.Sprintf("%v", v), nil
+		s = fmt.Sprintf("%v", v)
 	}
+	return o.apply(op, s)
 }
 
-// StringOr returns the value as string or default if error/not string
+// StringOr returns the value as string or default if error/not string. An
+// empty string also gives the default, unless the package-level StringOptions
+// set Empty to EmptyAsValue.
 func (j JSONValue) StringOr(defaultVal string) string {
-	s, err := j.String()
-	if err != nil || s == "" {
+	o := stringOptions()
+	s, err := j.stringWith("StringOr", o)
+	if err != nil || (s == "" && o.Empty != EmptyAsValue) {
 		return defaultVal
 	}
 	return s
 }
 
-// Int returns the value as int
+// Int returns the value as int, truncating fractions unless the
+// package-level NumberOptions set Exact (see IntExact). Values outside the
+// int range fail with ErrOverflow. Numeric strings are converted, formatted
+// as the NumberOptions allow (see SetNumberOptions).
 func (j JSONValue) Int() (int, error) {
-	if j.err != nil {
-		return 0, j.err
-	}
+	return j.intWith("Int", numberOptions())
+}
 
-	switch v := j.data.(type) {
-	case float64:
-		return int(v), nil
-	case int:
-		return v, nil
-	case string:
-		if i, err := strconv.Atoi(v); err == nil {
-			return i, nil
-		}
-		return 0, &JSONError{Op: "Int", Err: fmt.Errorf("cannot convert string %q to int", v)}
-	case nil:
-		return 0, nil
-	default:
-		return 0, &JSONError{Op: "Int", Err: fmt.Errorf("cannot convert %T to int", v)}
-	}
+func (j JSONValue) intWith(op string, o NumberOptions) (int, error) {
+	i, err := j.signedInt(op, "int", strconv.IntSize, o)
+	return int(i), err
 }
 
 // IntOr returns the value as int or default if error/conversion fails
@@ -409,8 +685,33 @@ func (j JSONValue) IntOr(defaultValue int) int {
 	return defaultValue
 }
 
-// Float64 returns the value as float64
+// Int64 returns the value as int64. Numbers parsed with ParseWithNumbers are
+// converted exactly, so IDs above 2^53 keep their precision. Values outside
+// the int64 range fail with ErrOverflow.
+func (j JSONValue) Int64() (int64, error) {
+	return j.int64With("Int64", numberOptions())
+}
+
+func (j JSONValue) int64With(op string, o NumberOptions) (int64, error) {
+	i, err := j.signedInt(op, "int64", 64, o)
+	return i, err
+}
+
+// Int64Or returns the value as int64 or default if error/conversion fails
+func (j JSONValue) Int64Or(defaultValue int64) int64 {
+	if i, err := j.Int64(); err == nil {
+		return i
+	}
+	return defaultValue
+}
+
+// Float64 returns the value as float64. Numeric strings are converted,
+// formatted as the package-level NumberOptions allow (see SetNumberOptions).
 func (j JSONValue) Float64() (float64, error) {
+	return j.float64With("Float64", numberOptions())
+}
+
+func (j JSONValue) float64With(op string, o NumberOptions) (float64, error) {
 	if j.err != nil {
 		return 0, j.err
 	}
@@ -420,15 +721,20 @@ func (j JSONValue) Float64() (float64, error) {
 		return v, nil
 	case int:
 		return float64(v), nil
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f, nil
+		}
+		return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert number %q to float64", ErrTypeMismatch, v)}
 	case string:
-		if f, err := strconv.ParseFloat(v, 64); err == nil {
+		if f, err := strconv.ParseFloat(o.normalize(v), 64); err == nil {
 			return f, nil
 		}
-		return 0, &JSONError{Op: "Float64", Err: fmt.Errorf("cannot convert string %q to float64", v)}
+		return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert string %q to float64", ErrTypeMismatch, v)}
 	case nil:
 		return 0, nil
 	default:
-		return 0, &JSONError{Op: "Float64", Err: fmt.Errorf("cannot convert %T to float64", v)}
+		return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert %T to float64", ErrTypeMismatch, v)}
 	}
 }
 
@@ -440,8 +746,14 @@ func (j JSONValue) Float64Or(defaultValue float64) float64 {
 	return defaultValue
 }
 
-// Bool returns the value as bool
+// Bool returns the value as bool. Strings are accepted as the package-level
+// BoolOptions allow (see SetBoolOptions); by default, those strconv.ParseBool
+// accepts.
 func (j JSONValue) Bool() (bool, error) {
+	return j.boolWith("Bool", boolOptions())
+}
+
+func (j JSONValue) boolWith(op string, o BoolOptions) (bool, error) {
 	if j.err != nil {
 		return false, j.err
 	}
@@ -450,16 +762,22 @@ func (j JSONValue) Bool() (bool, error) {
 	case bool:
 		return v, nil
 	case string:
-		if b, err := strconv.ParseBool(v); err == nil {
+		if b, ok := o.parse(v); ok {
 			return b, nil
 		}
-		return false, &JSONError{Op: "Bool", Err: fmt.Errorf("cannot convert string %q to bool", v)}
+		return false, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert string %q to bool", ErrTypeMismatch, v)}
 	case float64:
 		return v != 0, nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return false, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert number %q to bool", ErrTypeMismatch, v)}
+		}
+		return f != 0, nil
 	case nil:
 		return false, nil
 	default:
-		return false, &JSONError{Op: "Bool", Err: fmt.Errorf("cannot convert %T to bool", v)}
+		return false, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert %T to bool", ErrTypeMismatch, v)}
 	}
 }
 
@@ -479,12 +797,12 @@ func (j JSONValue) Array() ([]JSONValue, error) {
 
 	arr, ok := j.data.([]interface{})
 	if !ok {
-		return nil, &JSONError{Op: "Array", Err: fmt.Errorf("value is not an array, got %T", j.data)}
+		return nil, &JSONError{Op: "Array", Err: notKind("an array", j.data)}
 	}
 
 	result := make([]JSONValue, len(arr))
 	for i, item := range arr {
-		result[i] = JSONValue{data: item}
+		result[i] = JSONValue{data: item, raw: j.raw.child(i)}
 	}
 	return result, nil
 }
@@ -497,12 +815,12 @@ func (j JSONValue) Object() (map[string]JSONValue, error) {
 
 	obj, ok := j.data.(map[string]interface{})
 	if !ok {
-		return nil, &JSONError{Op: "Object", Err: fmt.Errorf("value is not an object, got %T", j.data)}
+		return nil, &JSONError{Op: "Object", Err: notKind("an object", j.data)}
 	}
 
 	result := make(map[string]JSONValue, len(obj))
 	for key, value := range obj {
-		result[key] = JSONValue{data: value}
+		result[key] = JSONValue{data: value, raw: j.raw.child(key)}
 	}
 	return result, nil
 }
@@ -515,6 +833,20 @@ func (j JSONValue) Raw() interface{} {
 	return j.data
 }
 
+// Result returns the underlying Go value and the error of the chain, for
+// code that prefers two-value returns to IsValid checks:
+//
+//	v, err := obj.Get("user", "address").Result()
+//
+// The typed conversions (String, Int, Float64, Bool, ...) already return a
+// value and an error the same way.
+func (j JSONValue) Result() (interface{}, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	return j.data, nil
+}
+
 // IsNull checks if the value is null
 func (j JSONValue) IsNull() bool {
 	return j.err == nil && j.data == nil
@@ -531,7 +863,7 @@ func (j JSONValue) Type() string {
 		return "null"
 	case bool:
 		return "boolean"
-	case float64:
+	case float64, json.Number:
 		return "number"
 	case string:
 		return "string"
@@ -573,26 +905,38 @@ func (j JSONValue) To(dest interface{}) error {
 		}
 	}
 
-	// Fall back to JSON marshal/unmarshal for complex types
+	// Bind the tree directly with the cached plan for the destination type;
+	// other codecs, and anything the plan leaves to encoding/json, take the
+	// marshal/unmarshal round trip. Registered decoders and field namers
+	// only exist in the plans, so they force the binder for any codec.
+	if (usingStdCodec() || customDecoding()) && destElem.CanSet() {
+		err := bindValue(destElem, j.data)
+		if err == nil {
+			return nil
+		}
+		var decErr *decoderError
+		if errors.As(err, &decErr) {
+			return &JSONError{Op: "To", Err: decErr.err}
+		}
+	}
+
 	buffer := getBytesBuffer()
 	defer putBytesBuffer(buffer)
 
 	// Reset buffer
 	*buffer = (*buffer)[:0]
 
-	// Use a bytes writer for better performance
-	encoder := json.NewEncoder(&bytesWriter{buffer})
-	if err := encoder.Encode(j.data); err != nil {
-		return &JSONError{Op: "To", Err: fmt.Errorf("failed to marshal data: %w", err)}
+	// The codec knows untagged fields by their Go names
+	data := j.data
+	if fieldNamer() != nil {
+		data = codecNames(destElem.Type(), data)
 	}
-
-	// Remove trailing newline
-	if len(*buffer) > 0 && (*buffer)[len(*buffer)-1] == '\n' {
-		*buffer = (*buffer)[:len(*buffer)-1]
+	if err := encodeInto(buffer, data); err != nil {
+		return &JSONError{Op: "To", Err: fmt.Errorf("failed to marshal data: %w", err)}
 	}
 
 	// Unmarshal into the destination
-	if err := json.Unmarshal(*buffer, dest); err != nil {
+	if err := activeCodec().Unmarshal(*buffer, dest); err != nil {
 		return &JSONError{Op: "To", Err: fmt.Errorf("failed to unmarshal into destination: %w", err)}
 	}
 
@@ -617,6 +961,9 @@ func convertToIndex(key interface{}) (int, error) {
 		return strconv.Atoi(v)
 	case float64:
 		return int(v), nil
+	case json.Number:
+		i, err := v.Int64()
+		return int(i), err
 	default:
 		return 0, fmt.Errorf("cannot convert %T to array index", key)
 	}
@@ -650,20 +997,9 @@ func (j JSONValue) Clone() JSONValue {
 		return j
 	}
 
-	// Use buffer pool for better performance
-	buffer := getBytesBuffer()
-	defer putBytesBuffer(buffer)
-
-	*buffer = (*buffer)[:0]
-	encoder := json.NewEncoder(&bytesWriter{buffer})
-	if err := encoder.Encode(j.data); err != nil {
+	data, err := normalize(j.data)
+	if err != nil {
 		return JSONValue{err: &JSONError{Op: "Clone", Err: err}}
 	}
-
-	// Remove trailing newline
-	if len(*buffer) > 0 && (*buffer)[len(*buffer)-1] == '\n' {
-		*buffer = (*buffer)[:len(*buffer)-1]
-	}
-
-	return Parse(*buffer)
-}
\ No newline at end of file
+	return JSONValue{data: data, raw: j.raw}
+}
@@ -0,0 +1,73 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// StringMap returns a flat object as map[string]string, for labels, headers
+// and tags. Strings are used as-is; numbers and booleans are formatted and
+// null becomes "". Nested arrays or objects are an error.
+func (j JSONValue) StringMap() (map[string]string, error) {
+	obj, err := j.flatObject("StringMap")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(obj))
+	for key, value := range obj {
+		switch v := value.(type) {
+		case string:
+			result[key] = v
+		case json.Number:
+			result[key] = v.String()
+		case float64:
+			result[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			result[key] = strconv.FormatBool(v)
+		case nil:
+			result[key] = ""
+		default:
+			return nil, &JSONError{Op: "StringMap", Err: fmt.Errorf("%w: value at key %q is %s, not a scalar", ErrTypeMismatch, key, JSONValue{data: v}.Type())}
+		}
+	}
+	return result, nil
+}
+
+// Float64Map returns a flat object as map[string]float64, for metrics and
+// weights. Values are converted with the same rules as Float64.
+func (j JSONValue) Float64Map() (map[string]float64, error) {
+	obj, err := j.flatObject("Float64Map")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]float64, len(obj))
+	for key, value := range obj {
+		f, err := JSONValue{data: value}.Float64()
+		if err != nil {
+			var jsonErr *JSONError
+			if errors.As(err, &jsonErr) {
+				err = jsonErr.Err
+			}
+			return nil, &JSONError{Op: "Float64Map", Err: fmt.Errorf("key %q: %w", key, err)}
+		}
+		result[key] = f
+	}
+	return result, nil
+}
+
+// flatObject returns the underlying object map for the typed map helpers
+func (j JSONValue) flatObject(op string) (map[string]interface{}, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	obj, ok := j.data.(map[string]interface{})
+	if !ok {
+		return nil, &JSONError{Op: op, Err: notKind("an object", j.data)}
+	}
+	return obj, nil
+}
@@ -0,0 +1,53 @@
+package jsjson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestStringMap(t *testing.T) {
+	obj := JSON.Parse(`{
+		"labels": {"app": "web", "replicas": 3, "ratio": 0.5, "canary": true, "owner": null},
+		"nested": {"a": {"b": 1}},
+		"list": ["a"]
+	}`)
+
+	got, err := obj.Get("labels").StringMap()
+	want := map[string]string{"app": "web", "replicas": "3", "ratio": "0.5", "canary": "true", "owner": ""}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v (err: %v)", want, got, err)
+	}
+
+	for _, key := range []string{"nested", "list", "missing"} {
+		t.Run(key, func(t *testing.T) {
+			if _, err := obj.Get(key).StringMap(); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+	if _, err := obj.Get("nested").StringMap(); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got %v", err)
+	}
+}
+
+func TestFloat64Map(t *testing.T) {
+	obj := JSON.Parse(`{"metrics": {"cpu": 0.75, "mem": "512", "disk": 10}, "bad": {"cpu": "high"}}`)
+
+	got, err := obj.Get("metrics").Float64Map()
+	want := map[string]float64{"cpu": 0.75, "mem": 512, "disk": 10}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v (err: %v)", want, got, err)
+	}
+
+	if _, err := obj.Get("bad").Float64Map(); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got %v", err)
+	}
+
+	precise := JSON.Parse(`{"n": 9007199254740993}`, JSON.ParseWithNumbers())
+	if got, err := precise.StringMap(); err != nil || got["n"] != "9007199254740993" {
+		t.Errorf("Expected original literal, got %v (err: %v)", got, err)
+	}
+}
@@ -0,0 +1,29 @@
+package jsjson
+
+// -------------------- encoding/json Integration --------------------
+
+// MarshalJSON encodes the wrapped value, so a JSONValue can be a field of an
+// ordinary struct (Payload jsjson.JSONValue) and be written by encoding/json,
+// Stringify or any framework that honors json.Marshaler. The zero JSONValue
+// encodes as null; a JSONValue holding an error fails with that error.
+func (j JSONValue) MarshalJSON() ([]byte, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	var b []byte
+	if err := encodeInto(&b, j.data); err != nil {
+		return nil, &JSONError{Op: "MarshalJSON", Err: err}
+	}
+	return b, nil
+}
+
+// UnmarshalJSON parses data into j, replacing its previous value and error.
+// null gives a valid JSONValue for which IsNull reports true.
+func (j *JSONValue) UnmarshalJSON(data []byte) error {
+	tree, err := parseTree(data, &parseOptions{}, false)
+	if err != nil {
+		return &JSONError{Op: "UnmarshalJSON", Err: err}
+	}
+	*j = JSONValue{data: tree}
+	return nil
+}
@@ -0,0 +1,90 @@
+package jsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+type envelope struct {
+	Kind    string                    `json:"kind"`
+	Payload JSON.JSONValue            `json:"payload"`
+	Extra   map[string]JSON.JSONValue `json:"extra,omitempty"`
+	Ptr     *JSON.JSONValue           `json:"ptr,omitempty"`
+}
+
+func TestJSONValueEncodingJSON(t *testing.T) {
+	const input = `{"kind":"event","payload":{"id":7,"tags":["a","b"]},"extra":{"n":null}}`
+
+	var env envelope
+	if err := json.Unmarshal([]byte(input), &env); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if env.Payload.Get("tags", 1).StringOr("") != "b" {
+		t.Errorf("Unexpected payload %v", env.Payload.Raw())
+	}
+	if n := env.Extra["n"]; !n.IsValid() || !n.IsNull() {
+		t.Errorf("Expected a valid null, got %v", n.Error())
+	}
+
+	out, err := json.Marshal(env)
+	if err != nil || string(out) != input {
+		t.Errorf("json.Marshal:\nexpected %s\ngot      %s (%v)", input, out, err)
+	}
+
+	var zero envelope
+	if out, _ := json.Marshal(zero); string(out) != `{"kind":"","payload":null}` {
+		t.Errorf("Expected the zero JSONValue to encode as null, got %s", out)
+	}
+
+	var bad JSON.JSONValue
+	if err := json.Unmarshal([]byte(`{"a": }`), &bad); err == nil {
+		t.Error("Expected a syntax error")
+	}
+	if err := bad.UnmarshalJSON([]byte(`{"a": }`)); !errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Expected ErrSyntax, got %v", err)
+	}
+
+	env.Payload = JSON.Parse(`{bad`)
+	if _, err := json.Marshal(env); err == nil {
+		t.Error("Expected the payload's error")
+	}
+}
+
+func TestJSONValueField(t *testing.T) {
+	const input = `{"kind":"event","payload":[1,{"x":true}],"ptr":"p"}`
+
+	var viaTo envelope
+	if err := JSON.Parse(input).To(&viaTo); err != nil {
+		t.Fatalf("To failed: %v", err)
+	}
+	var viaInto envelope
+	if err := JSON.ParseInto(input, &viaInto); err != nil {
+		t.Fatalf("ParseInto failed: %v", err)
+	}
+	for _, env := range []envelope{viaTo, viaInto} {
+		if !env.Payload.Get(1, "x").BoolOr(false) || env.Ptr == nil || env.Ptr.StringOr("") != "p" {
+			t.Errorf("Unexpected result %+v", env)
+		}
+	}
+
+	got, err := JSON.Stringify(viaTo)
+	if err != nil || got != input {
+		t.Errorf("Stringify:\nexpected %s\ngot      %s (%v)", input, got, err)
+	}
+	JSON.SetNamingStrategy(JSON.SnakeCase)
+	t.Cleanup(func() { JSON.SetNamingStrategy(JSON.GoNames) })
+	if got, _ := JSON.Stringify(viaTo); got != input {
+		t.Errorf("Stringify with a naming strategy: got %s", got)
+	}
+	if JSON.Parse(viaTo).Get("payload", 1, "x").BoolOr(false) != true {
+		t.Error("Parse of a struct did not encode the payload")
+	}
+
+	var jv JSON.JSONValue
+	if err := JSON.Parse(input).Get("payload").To(&jv); err != nil || jv.Get(0).IntOr(0) != 1 {
+		t.Errorf("To a JSONValue failed: %v", err)
+	}
+}
@@ -0,0 +1,464 @@
+package jsjson
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+// -------------------- Field Naming --------------------
+
+// NamingStrategy derives the JSON key of a struct field that has no json
+// tag (or a tag without a name) from its Go name
+type NamingStrategy int
+
+const (
+	// GoNames keeps Go field names as keys, as encoding/json does
+	GoNames NamingStrategy = iota
+	// SnakeCase turns UserID into user_id
+	SnakeCase
+	// CamelCase turns UserID into userId
+	CamelCase
+	// PascalCase turns UserID into UserId
+	PascalCase
+	// KebabCase turns UserID into user-id
+	KebabCase
+)
+
+// Name returns the key s gives the Go field name goField. Words are split
+// at case changes, with runs of capitals such as HTTP or ID kept together.
+func (s NamingStrategy) Name(goField string) string {
+	if s == GoNames {
+		return goField
+	}
+	words := splitWords(goField)
+	for i, w := range words {
+		switch {
+		case s == SnakeCase || s == KebabCase || (s == CamelCase && i == 0):
+			words[i] = strings.ToLower(w)
+		default:
+			r, size := utf8.DecodeRuneInString(w)
+			words[i] = string(unicode.ToUpper(r)) + strings.ToLower(w[size:])
+		}
+	}
+	switch s {
+	case SnakeCase:
+		return strings.Join(words, "_")
+	case KebabCase:
+		return strings.Join(words, "-")
+	default:
+		return strings.Join(words, "")
+	}
+}
+
+// splitWords breaks a Go identifier into words: HTTPServerID becomes
+// HTTP, Server, ID. Digits and underscores stay with the preceding word.
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		boundary := unicode.IsUpper(cur) && (!unicode.IsUpper(prev) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+		if boundary {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+// namerBox gives atomic.Value a single concrete type to store
+type namerBox struct{ fn func(string) string }
+
+var activeNamerBox atomic.Value
+
+// SetNamingStrategy sets how untagged struct fields are named for the whole
+// package: Stringify, StringifyPretty and Parse encode them under the
+// strategy's names, and ParseInto, To and Parse with a destination decode
+// them from those names. Explicit json tags always win. GoNames restores
+// the encoding/json behavior. Like SetCodec, set it once during program
+// initialization.
+func SetNamingStrategy(s NamingStrategy) {
+	if s == GoNames {
+		setFieldNamer(nil)
+		return
+	}
+	setFieldNamer(s.Name)
+}
+
+// SetFieldNamer installs a custom function deriving keys for untagged
+// struct fields from their Go names, for conventions the built-in
+// strategies do not cover:
+//
+//	SetFieldNamer(func(f string) string { return "x_" + strings.ToUpper(f) })
+//
+// It is used wherever SetNamingStrategy's names would be, and replaces any
+// strategy set before; nil restores Go field names. fn must be pure: its
+// results are cached per type.
+func SetFieldNamer(fn func(goField string) string) {
+	setFieldNamer(fn)
+}
+
+func setFieldNamer(fn func(string) string) {
+	activeNamerBox.Store(namerBox{fn})
+	resetTypeCaches()
+}
+
+// fieldNamer returns the active namer, or nil when fields keep Go names
+func fieldNamer() func(string) string {
+	box, _ := activeNamerBox.Load().(namerBox)
+	return box.fn
+}
+
+// resetTypeCaches drops every per-type plan, since field names are baked
+// into them
+func resetTypeCaches() {
+	for _, m := range []*sync.Map{&bindPlans, &tagPlans, &fieldSets, &encodeFields} {
+		m.Range(func(k, _ interface{}) bool {
+			m.Delete(k)
+			return true
+		})
+	}
+}
+
+// -------------------- Encoding with a Namer --------------------
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encodeField is a struct field as written by appendNamed
+type encodeField struct {
+	key       []byte // quoted key followed by ':'
+	index     []int
+	omitEmpty bool
+	quoted    bool
+}
+
+var encodeFields sync.Map // reflect.Type -> []encodeField
+
+func encodeFieldsFor(t reflect.Type) []encodeField {
+	if fs, ok := encodeFields.Load(t); ok {
+		return fs.([]encodeField)
+	}
+	var fields []encodeField
+	for _, f := range structFields(t) {
+		key, _ := json.Marshal(f.name)
+		_, opts, _ := strings.Cut(t.FieldByIndex(f.index).Tag.Get("json"), ",")
+		fields = append(fields, encodeField{
+			key:       append(key, ':'),
+			index:     f.index,
+			omitEmpty: hasTagOption(opts, "omitempty"),
+			quoted:    f.quoted,
+		})
+	}
+	actual, _ := encodeFields.LoadOrStore(t, fields)
+	return actual.([]encodeField)
+}
+
+// marshalValue encodes v with the active codec, applying the active namer
+// and registered encoders if there are any
+func marshalValue(v interface{}) ([]byte, error) {
+	if customEncoding() {
+		return appendNamed(nil, reflect.ValueOf(v))
+	}
+	return activeCodec().Marshal(v)
+}
+
+// appendNamed appends the encoding of v to b. Structs, types with a
+// registered encoder, and the containers that may hold either are written
+// here so field names follow the namer; everything else, including types
+// with their own MarshalJSON or MarshalText, is encoded by the active codec.
+func appendNamed(b []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return append(b, "null"...), nil
+	}
+	t := v.Type()
+	if enc := encoderFor(t); enc != nil {
+		out, err := enc(v)
+		if err != nil {
+			return nil, err
+		}
+		return appendNamed(b, reflect.ValueOf(out))
+	}
+	if t == jsonValueType {
+		jv := v.Interface().(JSONValue)
+		if jv.err != nil {
+			return nil, jv.err
+		}
+		return appendNamed(b, reflect.ValueOf(jv.data))
+	}
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		if t.Kind() == reflect.Pointer && v.IsNil() {
+			return append(b, "null"...), nil
+		}
+		return appendCodec(b, v.Interface())
+	}
+	if v.CanAddr() && (reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		return appendCodec(b, v.Addr().Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return append(b, "null"...), nil
+		}
+		return appendNamed(b, v.Elem())
+	case reflect.Struct:
+		return appendStruct(b, v)
+	case reflect.Map:
+		if v.IsNil() || !mayHoldStruct(t.Elem()) {
+			return appendCodec(b, v.Interface())
+		}
+		return appendMap(b, v)
+	case reflect.Slice:
+		if v.IsNil() || !mayHoldStruct(t.Elem()) {
+			return appendCodec(b, v.Interface())
+		}
+		fallthrough
+	case reflect.Array:
+		if !mayHoldStruct(t.Elem()) {
+			return appendCodec(b, v.Interface())
+		}
+		b = append(b, '[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			var err error
+			if b, err = appendNamed(b, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ']'), nil
+	default:
+		return appendCodec(b, v.Interface())
+	}
+}
+
+func appendCodec(b []byte, v interface{}) ([]byte, error) {
+	enc, err := activeCodec().Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, enc...), nil
+}
+
+func appendStruct(b []byte, v reflect.Value) ([]byte, error) {
+	b = append(b, '{')
+	first := true
+	for _, f := range encodeFieldsFor(v.Type()) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// Promoted through a nil embedded pointer
+			continue
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if !first {
+			b = append(b, ',')
+		}
+		first = false
+		b = append(b, f.key...)
+
+		if f.quoted && fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				b = append(b, "null"...)
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if f.quoted {
+			enc, err := activeCodec().Marshal(fv.Interface())
+			if err != nil {
+				return nil, err
+			}
+			if b, err = appendCodec(b, string(enc)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if b, err = appendNamed(b, fv); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// appendMap writes a map whose values may hold structs, with keys sorted
+// as encoding/json sorts them
+func appendMap(b []byte, v reflect.Value) ([]byte, error) {
+	type entry struct {
+		key string
+		val reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		var key string
+		switch {
+		case k.Kind() == reflect.String:
+			key = k.String()
+		case k.Type().Implements(textMarshalerType):
+			text, err := k.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			key = string(text)
+		case k.CanInt():
+			key = strconv.FormatInt(k.Int(), 10)
+		case k.CanUint():
+			key = strconv.FormatUint(k.Uint(), 10)
+		default:
+			return nil, fmt.Errorf("json: unsupported map key type %s", k.Type())
+		}
+		entries = append(entries, entry{key, iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	b = append(b, '{')
+	for i, e := range entries {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		key, _ := json.Marshal(e.key)
+		b = append(append(b, key...), ':')
+		var err error
+		if b, err = appendNamed(b, e.val); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// mayHoldStruct reports whether a value of type t can contain a struct or
+// a type with a registered encoder, including through interfaces
+func mayHoldStruct(t reflect.Type) bool {
+	if encoderFor(t) != nil {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return mayHoldStruct(t.Elem())
+	}
+	return false
+}
+
+// isEmptyValue mirrors encoding/json's omitempty test
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// -------------------- Decoding with a Namer --------------------
+
+// unmarshalValue decodes data into dest with the active codec. With a
+// namer or registered decoders, the input is parsed and bound by To, which
+// applies them; the tree is returned for callers that inspect it.
+func unmarshalValue(data []byte, dest interface{}) (interface{}, error) {
+	if !customDecoding() {
+		return nil, locateSyntaxError(data, activeCodec().Unmarshal(data, dest))
+	}
+	tree, err := parseTree(data, &parseOptions{}, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := (JSONValue{data: tree}).To(dest); err != nil {
+		var jsonErr *JSONError
+		if errors.As(err, &jsonErr) {
+			return nil, jsonErr.Err
+		}
+		return nil, err
+	}
+	return tree, nil
+}
+
+// codecNames returns tree with the keys of untagged struct fields renamed
+// from the namer's names back to Go field names, which is how the codec
+// matches them. Objects and arrays that may hold structs are copied; the
+// tree itself is left unchanged.
+func codecNames(t reflect.Type, tree interface{}) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) ||
+		reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return tree
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := tree.(map[string]interface{})
+		if !ok {
+			return tree
+		}
+		fs := fieldSetFor(t)
+		out := make(map[string]interface{}, len(obj))
+		for k, item := range obj {
+			f, ok := fs.exact[k]
+			if !ok {
+				f, ok = fs.folded[foldName(k)]
+			}
+			if !ok {
+				out[k] = item
+				continue
+			}
+			key := k
+			if f.goName != "" {
+				key = f.goName
+			}
+			out[key] = codecNames(f.typ, item)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		items, ok := tree.([]interface{})
+		if !ok || !mayHoldStruct(t.Elem()) {
+			return tree
+		}
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			out[i] = codecNames(t.Elem(), item)
+		}
+		return out
+	case reflect.Map:
+		obj, ok := tree.(map[string]interface{})
+		if !ok || !mayHoldStruct(t.Elem()) {
+			return tree
+		}
+		out := make(map[string]interface{}, len(obj))
+		for k, item := range obj {
+			out[k] = codecNames(t.Elem(), item)
+		}
+		return out
+	}
+	return tree
+}
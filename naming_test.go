@@ -0,0 +1,176 @@
+package jsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestNamingStrategyNames(t *testing.T) {
+	tests := []struct {
+		field                       string
+		snake, camel, pascal, kebab string
+	}{
+		{"Name", "name", "name", "Name", "name"},
+		{"UserID", "user_id", "userId", "UserId", "user-id"},
+		{"HTTPServer", "http_server", "httpServer", "HttpServer", "http-server"},
+		{"APIKeyV2", "api_key_v2", "apiKeyV2", "ApiKeyV2", "api-key-v2"},
+		{"CreatedAt", "created_at", "createdAt", "CreatedAt", "created-at"},
+		{"X", "x", "x", "X", "x"},
+	}
+	for _, tt := range tests {
+		got := []string{JSON.SnakeCase.Name(tt.field), JSON.CamelCase.Name(tt.field), JSON.PascalCase.Name(tt.field), JSON.KebabCase.Name(tt.field)}
+		want := []string{tt.snake, tt.camel, tt.pascal, tt.kebab}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("%s: expected %v, got %v", tt.field, want, got)
+		}
+		if JSON.GoNames.Name(tt.field) != tt.field {
+			t.Errorf("%s: GoNames must keep the name", tt.field)
+		}
+	}
+}
+
+type namingAddress struct {
+	StreetName string
+	ZipCode    string `json:"zip"`
+}
+
+type NamingBase struct {
+	CreatedAt time.Time
+}
+
+type namingUser struct {
+	NamingBase
+	UserID    int
+	FullName  string `json:",omitempty"`
+	HomeAddr  *namingAddress
+	PastAddrs []namingAddress
+	ByLabel   map[string]namingAddress
+	Extra     interface{}
+	Count     int64  `json:",string"`
+	Email     string `json:"email" jsjson:"required"`
+}
+
+func TestSetNamingStrategy(t *testing.T) {
+	JSON.SetNamingStrategy(JSON.SnakeCase)
+	t.Cleanup(func() { JSON.SetNamingStrategy(JSON.GoNames) })
+
+	when := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	user := namingUser{
+		NamingBase: NamingBase{CreatedAt: when},
+		UserID:     7,
+		HomeAddr:   &namingAddress{StreetName: "Main", ZipCode: "123"},
+		PastAddrs:  []namingAddress{{StreetName: "Old"}},
+		ByLabel:    map[string]namingAddress{"b": {}, "a": {StreetName: "A"}},
+		Extra:      map[string]interface{}{"inner": namingAddress{StreetName: "In"}},
+		Count:      3,
+		Email:      "a@example.com",
+	}
+
+	const want = `{"created_at":"2024-05-06T07:08:09Z","user_id":7,"home_addr":{"street_name":"Main","zip":"123"},` +
+		`"past_addrs":[{"street_name":"Old","zip":""}],"by_label":{"a":{"street_name":"A","zip":""},"b":{"street_name":"","zip":""}},` +
+		`"extra":{"inner":{"street_name":"In","zip":""}},"count":"3","email":"a@example.com"}`
+	got, err := JSON.Stringify(user)
+	if err != nil || got != want {
+		t.Fatalf("Stringify:\nexpected %s\ngot      %s (%v)", want, got, err)
+	}
+	if pretty, err := JSON.StringifyPretty(&user, "  "); err != nil || !strings.Contains(pretty, `"street_name": "Main"`) {
+		t.Errorf("StringifyPretty did not use the strategy: %s (%v)", pretty, err)
+	}
+	if JSON.Parse(user).Get("home_addr", "street_name").StringOr("") != "Main" {
+		t.Error("Parse of a struct did not use the strategy")
+	}
+
+	input := `{"created_at":"2024-05-06T07:08:09Z","user_id":7,"full_name":"Ada","home_addr":{"street_name":"Main","zip":"123"},` +
+		`"past_addrs":[{"street_name":"Old"}],"by_label":{"a":{"STREET_NAME":"A"}},"count":"3","email":"a@example.com"}`
+	check := func(via string, got namingUser) {
+		t.Helper()
+		if got.UserID != 7 || got.FullName != "Ada" || !got.CreatedAt.Equal(when) || got.Count != 3 ||
+			got.HomeAddr == nil || got.HomeAddr.StreetName != "Main" || got.HomeAddr.ZipCode != "123" ||
+			len(got.PastAddrs) != 1 || got.PastAddrs[0].StreetName != "Old" || got.ByLabel["a"].StreetName != "A" {
+			t.Errorf("%s: unexpected result %+v", via, got)
+		}
+	}
+
+	var viaInto namingUser
+	if err := JSON.ParseInto(input, &viaInto, JSON.DisallowUnknownFields()); err != nil {
+		t.Fatalf("ParseInto failed: %v", err)
+	}
+	check("ParseInto", viaInto)
+
+	var viaTo namingUser
+	if err := JSON.Parse(input).To(&viaTo); err != nil {
+		t.Fatalf("To failed: %v", err)
+	}
+	check("To", viaTo)
+
+	var viaParse namingUser
+	if err := JSON.Parse(input, &viaParse).Error(); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	check("Parse", viaParse)
+
+	// Go names are no longer field names, and violations use the strategy's
+	err = JSON.ParseInto(`{"UserID": 1}`, &namingUser{}, JSON.DisallowUnknownFields())
+	var verr *JSON.ValidationError
+	if !errors.As(err, &verr) || len(verr.Violations) != 2 || verr.Violations[0].Path != "UserID" || verr.Violations[1].Path != "email" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestSetNamingStrategyCodecFallback(t *testing.T) {
+	JSON.SetNamingStrategy(JSON.CamelCase)
+	JSON.SetCodec(jsonCodec{})
+	t.Cleanup(func() {
+		JSON.SetCodec(nil)
+		JSON.SetNamingStrategy(JSON.GoNames)
+	})
+
+	var got namingUser
+	if err := JSON.ParseInto(`{"userId": 5, "homeAddr": {"streetName": "X"}, "email": "a@example.com"}`, &got); err != nil {
+		t.Fatalf("ParseInto failed: %v", err)
+	}
+	if got.UserID != 5 || got.HomeAddr == nil || got.HomeAddr.StreetName != "X" {
+		t.Errorf("Unexpected result %+v", got)
+	}
+
+	JSON.SetNamingStrategy(JSON.GoNames)
+	if s, _ := JSON.Stringify(namingAddress{StreetName: "X"}); s != `{"StreetName":"X","zip":""}` {
+		t.Errorf("GoNames did not restore the default names: %s", s)
+	}
+}
+
+// jsonCodec is a non-standard codec, so decoding takes the codec path
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+func TestSetFieldNamer(t *testing.T) {
+	JSON.SetFieldNamer(func(f string) string { return "X_" + strings.ToUpper(f) })
+	t.Cleanup(func() { JSON.SetFieldNamer(nil) })
+
+	s, err := JSON.Stringify(namingAddress{StreetName: "Main", ZipCode: "1"})
+	if err != nil || s != `{"X_STREETNAME":"Main","zip":"1"}` {
+		t.Errorf("Unexpected encoding %s (%v)", s, err)
+	}
+
+	var got namingAddress
+	if err := JSON.ParseInto(`{"X_STREETNAME": "Main", "StreetName": "ignored"}`, &got); err != nil || got.StreetName != "Main" {
+		t.Errorf("Unexpected decoding %+v (%v)", got, err)
+	}
+
+	// A later strategy replaces the namer, and nil restores Go names
+	JSON.SetNamingStrategy(JSON.KebabCase)
+	if s, _ := JSON.Stringify(namingAddress{}); s != `{"street-name":"","zip":""}` {
+		t.Errorf("Expected the strategy to replace the namer, got %s", s)
+	}
+	JSON.SetFieldNamer(nil)
+	if s, _ := JSON.Stringify(namingAddress{}); s != `{"StreetName":"","zip":""}` {
+		t.Errorf("Expected Go names, got %s", s)
+	}
+}
@@ -0,0 +1,105 @@
+package jsjson
+
+import "math"
+
+// -------------------- NaN and Infinity --------------------
+
+// ParseWithNonFinite accepts the literals NaN, Infinity and -Infinity, as
+// written by Python's json module and JSON5 producers, and reads them as the
+// corresponding float64 values. It applies to Parse, ParseNoCopy,
+// ParseReader and ParseFile with the standard codec; ParseInto and Decoder
+// still reject them.
+func ParseWithNonFinite() ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.nonFinite = true })
+}
+
+// NonFiniteMode selects how Stringify writes NaN and infinite floats, which
+// JSON has no literal for
+type NonFiniteMode int
+
+const (
+	// NonFiniteError fails with encoding/json's unsupported value error
+	NonFiniteError NonFiniteMode = iota
+	// NonFiniteNull writes null
+	NonFiniteNull
+	// NonFiniteString writes the strings "NaN", "Infinity" and "-Infinity"
+	NonFiniteString
+)
+
+// WithNonFinite sets how NaN and infinite floats are written; the default
+// is NonFiniteError. The replacement applies to values in a dynamic tree
+// (a JSONValue, or maps and slices of interface{}); fields of Go structs
+// are encoded by encoding/json and still fail.
+func WithNonFinite(mode NonFiniteMode) WriteOption {
+	return func(o *writeOptions) { o.nonFinite = mode }
+}
+
+// replaceNonFinite returns v with its NaN and infinite floats replaced as
+// o.nonFinite asks. Containers are copied only if something in them is
+// replaced.
+func (o *writeOptions) replaceNonFinite(v interface{}) interface{} {
+	if o.nonFinite == NonFiniteError {
+		return v
+	}
+	if jv, ok := v.(JSONValue); ok && jv.err == nil {
+		v = jv.data
+	}
+	if out, changed := replaceNonFiniteTree(v, o.nonFinite); changed {
+		return out
+	}
+	return v
+}
+
+func replaceNonFiniteTree(v interface{}, mode NonFiniteMode) (interface{}, bool) {
+	switch c := v.(type) {
+	case float64:
+		return nonFiniteValue(c, mode)
+	case float32:
+		return nonFiniteValue(float64(c), mode)
+	case map[string]interface{}:
+		var copied map[string]interface{}
+		for k, child := range c {
+			if out, changed := replaceNonFiniteTree(child, mode); changed {
+				if copied == nil {
+					copied = make(map[string]interface{}, len(c))
+					for k2, v2 := range c {
+						copied[k2] = v2
+					}
+				}
+				copied[k] = out
+			}
+		}
+		if copied != nil {
+			return copied, true
+		}
+	case []interface{}:
+		var copied []interface{}
+		for i, child := range c {
+			if out, changed := replaceNonFiniteTree(child, mode); changed {
+				if copied == nil {
+					copied = append([]interface{}(nil), c...)
+				}
+				copied[i] = out
+			}
+		}
+		if copied != nil {
+			return copied, true
+		}
+	}
+	return v, false
+}
+
+func nonFiniteValue(f float64, mode NonFiniteMode) (interface{}, bool) {
+	switch {
+	case !math.IsNaN(f) && !math.IsInf(f, 0):
+		return f, false
+	case mode == NonFiniteNull:
+		return nil, true
+	case math.IsNaN(f):
+		return "NaN", true
+	case f > 0:
+		return "Infinity", true
+	default:
+		return "-Infinity", true
+	}
+}
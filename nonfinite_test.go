@@ -0,0 +1,82 @@
+package jsjson_test
+
+import (
+	"math"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestParseWithNonFinite(t *testing.T) {
+	const input = `{"a": NaN, "b": Infinity, "c": [-Infinity, -1]}`
+
+	if err := JSON.Parse(input).Error(); err == nil {
+		t.Fatal("Expected NaN to be rejected by default")
+	}
+
+	for name, v := range map[string]JSON.JSONValue{
+		"Parse":       JSON.Parse(input, JSON.ParseWithNonFinite()),
+		"ParseNoCopy": JSON.ParseNoCopy([]byte(input), JSON.ParseWithNonFinite()),
+	} {
+		if err := v.Error(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if f, _ := v.Get("a").Float64(); !math.IsNaN(f) {
+			t.Errorf("%s: expected NaN, got %v", name, f)
+		}
+		if f, _ := v.Get("b").Float64(); !math.IsInf(f, 1) {
+			t.Errorf("%s: expected +Inf, got %v", name, f)
+		}
+		if f, _ := v.Get("c", 0).Float64(); !math.IsInf(f, -1) {
+			t.Errorf("%s: expected -Inf, got %v", name, f)
+		}
+		if f, _ := v.Get("c", 1).Float64(); f != -1 {
+			t.Errorf("%s: expected -1, got %v", name, f)
+		}
+	}
+
+	if err := JSON.Parse(`[Inf]`, JSON.ParseWithNonFinite()).Error(); err == nil {
+		t.Error("Expected an incomplete literal to be rejected")
+	}
+}
+
+func TestStringifyNonFinite(t *testing.T) {
+	v := JSON.Parse(`{"a": NaN, "b": [Infinity, -Infinity, 1.5]}`, JSON.ParseWithNonFinite())
+
+	if _, err := JSON.Stringify(v); err == nil {
+		t.Error("Expected an error by default")
+	}
+	if _, err := JSON.Stringify(v, JSON.WithNonFinite(JSON.NonFiniteError)); err == nil {
+		t.Error("Expected an error with NonFiniteError")
+	}
+
+	tests := []struct {
+		name string
+		mode JSON.NonFiniteMode
+		want string
+	}{
+		{"null", JSON.NonFiniteNull, `{"a":null,"b":[null,null,1.5]}`},
+		{"string", JSON.NonFiniteString, `{"a":"NaN","b":["Infinity","-Infinity",1.5]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSON.Stringify(v, JSON.WithNonFinite(tt.mode))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	// The value itself is not modified
+	if f, _ := v.Get("a").Float64(); !math.IsNaN(f) {
+		t.Errorf("Expected the original to keep NaN, got %v", f)
+	}
+
+	got, err := JSON.StringifyPretty(map[string]interface{}{"x": math.Inf(1)}, "", JSON.WithNonFinite(JSON.NonFiniteNull))
+	if err != nil || got != "{\n\"x\": null\n}" {
+		t.Errorf("StringifyPretty: got %q (%v)", got, err)
+	}
+}
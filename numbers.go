@@ -0,0 +1,493 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// numberLiteral returns the decimal literal of a numeric value: the original
+// text for json.Number (ParseWithNumbers) and numeric strings, or the shortest
+// exact representation of a float64
+func numberLiteral(op string, data interface{}) (string, error) {
+	switch v := data.(type) {
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case string:
+		if _, err := strconv.ParseFloat(v, 64); err != nil && !isRangeError(err) {
+			return "", &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert string %q to number", ErrTypeMismatch, v)}
+		}
+		return v, nil
+	default:
+		return "", &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert %T to number", ErrTypeMismatch, v)}
+	}
+}
+
+// isRangeError reports whether err is strconv's out-of-range error, which
+// still means the input was a syntactically valid number
+func isRangeError(err error) bool {
+	numErr, ok := err.(*strconv.NumError)
+	return ok && numErr.Err == strconv.ErrRange
+}
+
+// maxBigIntBits bounds the size of integers produced from exponent notation
+const maxBigIntBits = 1 << 20
+
+// BigInt returns the value as an arbitrary-precision integer. Use it together
+// with ParseWithNumbers so large literals are read from the original text
+// without passing through float64. Numeric strings are accepted as well.
+func (j JSONValue) BigInt() (*big.Int, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	lit, err := numberLiteral("BigInt", j.data)
+	if err != nil {
+		return nil, err
+	}
+	if i, ok := new(big.Int).SetString(lit, 10); ok {
+		return i, nil
+	}
+
+	// Literals with a fraction or exponent (1e20, 5.0) are accepted when
+	// they denote an integer
+	f, _, err := big.ParseFloat(lit, 10, literalPrecision(lit), big.ToNearestEven)
+	if err != nil {
+		return nil, &JSONError{Op: "BigInt", Err: fmt.Errorf("%w: cannot convert %q to integer", ErrTypeMismatch, lit)}
+	}
+	if !f.IsInt() {
+		return nil, &JSONError{Op: "BigInt", Err: fmt.Errorf("%w: %q is not an integer", ErrTypeMismatch, lit)}
+	}
+	if f.MantExp(nil) > maxBigIntBits {
+		// Guards against exponent bombs such as 1e100000000
+		return nil, &JSONError{Op: "BigInt", Err: fmt.Errorf("%w: %q exceeds %d bits", ErrLimitExceeded, lit, maxBigIntBits)}
+	}
+	i, _ := f.Int(nil)
+	return i, nil
+}
+
+// BigIntOr returns the value as *big.Int or default if error/conversion fails
+func (j JSONValue) BigIntOr(defaultValue *big.Int) *big.Int {
+	if i, err := j.BigInt(); err == nil {
+		return i
+	}
+	return defaultValue
+}
+
+// BigFloat returns the value as an arbitrary-precision float. The precision
+// is chosen from the length of the literal so no significant digits are lost.
+func (j JSONValue) BigFloat() (*big.Float, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	lit, err := numberLiteral("BigFloat", j.data)
+	if err != nil {
+		return nil, err
+	}
+	f, _, err := big.ParseFloat(lit, 10, literalPrecision(lit), big.ToNearestEven)
+	if err != nil {
+		return nil, &JSONError{Op: "BigFloat", Err: fmt.Errorf("%w: cannot convert %q to float", ErrTypeMismatch, lit)}
+	}
+	return f, nil
+}
+
+// BigFloatOr returns the value as *big.Float or default if error/conversion fails
+func (j JSONValue) BigFloatOr(defaultValue *big.Float) *big.Float {
+	if f, err := j.BigFloat(); err == nil {
+		return f
+	}
+	return defaultValue
+}
+
+// literalPrecision returns a mantissa size in bits large enough to hold every
+// digit of a decimal literal (log2(10) < 4 bits per digit)
+func literalPrecision(lit string) uint {
+	return uint(len(lit))*4 + 64
+}
+
+// Number returns the value as a json.Number, deferring the int-vs-float
+// decision to the caller. Numbers parsed with ParseWithNumbers are returned
+// with their original literal; numeric strings must be valid JSON numbers.
+func (j JSONValue) Number() (json.Number, error) {
+	if j.err != nil {
+		return "", j.err
+	}
+
+	lit, err := numberLiteral("Number", j.data)
+	if err != nil {
+		return "", err
+	}
+	if !isJSONNumber(lit) {
+		return "", &JSONError{Op: "Number", Err: fmt.Errorf("%w: %q is not a valid JSON number", ErrTypeMismatch, lit)}
+	}
+	return json.Number(lit), nil
+}
+
+// NumberOr returns the value as json.Number or default if error/conversion fails
+func (j JSONValue) NumberOr(defaultValue json.Number) json.Number {
+	if n, err := j.Number(); err == nil {
+		return n
+	}
+	return defaultValue
+}
+
+// -------------------- Sized Numbers --------------------
+
+// Int32 returns the value as int32, failing with ErrOverflow when it does
+// not fit. Fractions and numeric strings are handled as by Int.
+func (j JSONValue) Int32() (int32, error) {
+	i, err := j.signedInt("Int32", "int32", 32, numberOptions())
+	return int32(i), err
+}
+
+// Int32Or returns the value as int32 or default if error/conversion fails
+func (j JSONValue) Int32Or(defaultValue int32) int32 {
+	if i, err := j.Int32(); err == nil {
+		return i
+	}
+	return defaultValue
+}
+
+// Int16 returns the value as int16, failing with ErrOverflow when it does
+// not fit
+func (j JSONValue) Int16() (int16, error) {
+	i, err := j.signedInt("Int16", "int16", 16, numberOptions())
+	return int16(i), err
+}
+
+// Int16Or returns the value as int16 or default if error/conversion fails
+func (j JSONValue) Int16Or(defaultValue int16) int16 {
+	if i, err := j.Int16(); err == nil {
+		return i
+	}
+	return defaultValue
+}
+
+// Int8 returns the value as int8, failing with ErrOverflow when it does not
+// fit
+func (j JSONValue) Int8() (int8, error) {
+	i, err := j.signedInt("Int8", "int8", 8, numberOptions())
+	return int8(i), err
+}
+
+// Int8Or returns the value as int8 or default if error/conversion fails
+func (j JSONValue) Int8Or(defaultValue int8) int8 {
+	if i, err := j.Int8(); err == nil {
+		return i
+	}
+	return defaultValue
+}
+
+// Uint returns the value as uint, failing with ErrOverflow when it is
+// negative or does not fit. Fractions and numeric strings are handled as by
+// Int.
+func (j JSONValue) Uint() (uint, error) {
+	u, err := j.unsignedInt("Uint", "uint", strconv.IntSize, numberOptions())
+	return uint(u), err
+}
+
+// UintOr returns the value as uint or default if error/conversion fails
+func (j JSONValue) UintOr(defaultValue uint) uint {
+	if u, err := j.Uint(); err == nil {
+		return u
+	}
+	return defaultValue
+}
+
+// Uint64 returns the value as uint64, failing with ErrOverflow when it is
+// negative or does not fit. Numbers parsed with ParseWithNumbers are
+// converted exactly up to 2^64-1.
+func (j JSONValue) Uint64() (uint64, error) {
+	return j.unsignedInt("Uint64", "uint64", 64, numberOptions())
+}
+
+// Uint64Or returns the value as uint64 or default if error/conversion fails
+func (j JSONValue) Uint64Or(defaultValue uint64) uint64 {
+	if u, err := j.Uint64(); err == nil {
+		return u
+	}
+	return defaultValue
+}
+
+// Uint32 returns the value as uint32, failing with ErrOverflow when it is
+// negative or does not fit
+func (j JSONValue) Uint32() (uint32, error) {
+	u, err := j.unsignedInt("Uint32", "uint32", 32, numberOptions())
+	return uint32(u), err
+}
+
+// Uint32Or returns the value as uint32 or default if error/conversion fails
+func (j JSONValue) Uint32Or(defaultValue uint32) uint32 {
+	if u, err := j.Uint32(); err == nil {
+		return u
+	}
+	return defaultValue
+}
+
+// Uint16 returns the value as uint16, failing with ErrOverflow when it is
+// negative or does not fit
+func (j JSONValue) Uint16() (uint16, error) {
+	u, err := j.unsignedInt("Uint16", "uint16", 16, numberOptions())
+	return uint16(u), err
+}
+
+// Uint16Or returns the value as uint16 or default if error/conversion fails
+func (j JSONValue) Uint16Or(defaultValue uint16) uint16 {
+	if u, err := j.Uint16(); err == nil {
+		return u
+	}
+	return defaultValue
+}
+
+// Uint8 returns the value as uint8, failing with ErrOverflow when it is
+// negative or does not fit
+func (j JSONValue) Uint8() (uint8, error) {
+	u, err := j.unsignedInt("Uint8", "uint8", 8, numberOptions())
+	return uint8(u), err
+}
+
+// Uint8Or returns the value as uint8 or default if error/conversion fails
+func (j JSONValue) Uint8Or(defaultValue uint8) uint8 {
+	if u, err := j.Uint8(); err == nil {
+		return u
+	}
+	return defaultValue
+}
+
+// Float32 returns the value as float32, failing with ErrOverflow when its
+// magnitude exceeds math.MaxFloat32. Precision beyond float32 is rounded
+// away, and infinities (see ParseWithNonFinite) are kept.
+func (j JSONValue) Float32() (float32, error) {
+	f, err := j.float64With("Float32", numberOptions())
+	if err != nil {
+		return 0, err
+	}
+	if math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+		return 0, overflowError("Float32", "float32", f)
+	}
+	return float32(f), nil
+}
+
+// Float32Or returns the value as float32 or default if error/conversion fails
+func (j JSONValue) Float32Or(defaultValue float32) float32 {
+	if f, err := j.Float32(); err == nil {
+		return f
+	}
+	return defaultValue
+}
+
+// signedInt converts the value to a signed integer of the given size, as
+// Int describes
+func (j JSONValue) signedInt(op, typ string, bits int, o NumberOptions) (int64, error) {
+	if j.err != nil {
+		return 0, j.err
+	}
+
+	switch v := j.data.(type) {
+	case float64, json.Number, int, int64:
+		min, max := int64(-1)<<(bits-1), int64(1)<<(bits-1)-1
+		return integerValue(op, typ, v, min, max, o.Exact)
+	case string:
+		i, err := strconv.ParseInt(o.normalize(v), 10, bits)
+		if err == nil {
+			return i, nil
+		}
+		if isRangeError(err) {
+			return 0, overflowError(op, typ, v)
+		}
+		return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert string %q to %s", ErrTypeMismatch, v, typ)}
+	case nil:
+		return 0, nil
+	default:
+		return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert %T to %s", ErrTypeMismatch, v, typ)}
+	}
+}
+
+// unsignedInt converts the value to an unsigned integer of the given size,
+// as Uint describes
+func (j JSONValue) unsignedInt(op, typ string, bits int, o NumberOptions) (uint64, error) {
+	if j.err != nil {
+		return 0, j.err
+	}
+
+	switch v := j.data.(type) {
+	case float64, json.Number, int, int64:
+		return unsignedValue(op, typ, v, uint64(1)<<(bits-1)<<1-1, o.Exact)
+	case string:
+		lit := o.normalize(v)
+		u, err := strconv.ParseUint(lit, 10, bits)
+		if err == nil {
+			return u, nil
+		}
+		// Negative integers are out of range rather than malformed
+		if _, ierr := strconv.ParseInt(lit, 10, 64); isRangeError(err) || ierr == nil || isRangeError(ierr) {
+			return 0, overflowError(op, typ, v)
+		}
+		return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert string %q to %s", ErrTypeMismatch, v, typ)}
+	case nil:
+		return 0, nil
+	default:
+		return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert %T to %s", ErrTypeMismatch, v, typ)}
+	}
+}
+
+// integerValue converts a numeric tree value to an integer of the named
+// type. Values outside [min, max] fail with ErrOverflow instead of wrapping;
+// fractions are truncated, or rejected if exact is set. Number literals are
+// read exactly, so 2^53+1 stays exact and 1e3 is the integer 1000.
+func integerValue(op, typ string, v interface{}, min, max int64, exact bool) (int64, error) {
+	var i int64
+	switch n := v.(type) {
+	case float64:
+		if exact && n != math.Trunc(n) {
+			return 0, fractionError(op, typ, n)
+		}
+		// float64(max)+1 is exact or rounds to the next power of two,
+		// which is the bound either way
+		if t := math.Trunc(n); math.IsNaN(t) || t < float64(min) || t >= float64(max)+1 {
+			return 0, overflowError(op, typ, n)
+		}
+		i = int64(n)
+	case json.Number:
+		lit := n.String()
+		if parsed, err := strconv.ParseInt(lit, 10, 64); err == nil {
+			i = parsed
+			break
+		}
+		f, _, err := big.ParseFloat(lit, 10, literalPrecision(lit), big.ToNearestEven)
+		if err != nil {
+			return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert number %q to %s", ErrTypeMismatch, lit, typ)}
+		}
+		if exact && !f.IsInt() {
+			return 0, fractionError(op, typ, lit)
+		}
+		// |f| < 2^exp, and the check avoids expanding exponent bombs
+		if f.MantExp(nil) > 64 {
+			return 0, overflowError(op, typ, lit)
+		}
+		whole, _ := f.Int(nil)
+		if !whole.IsInt64() {
+			return 0, overflowError(op, typ, lit)
+		}
+		i = whole.Int64()
+	case int:
+		i = int64(n)
+	case int64:
+		i = n
+	default:
+		return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert %T to %s", ErrTypeMismatch, v, typ)}
+	}
+	if i < min || i > max {
+		return 0, overflowError(op, typ, v)
+	}
+	return i, nil
+}
+
+// unsignedValue is integerValue for unsigned types, whose range is
+// [0, max]
+func unsignedValue(op, typ string, v interface{}, max uint64, exact bool) (uint64, error) {
+	switch n := v.(type) {
+	case float64:
+		if exact && n != math.Trunc(n) {
+			return 0, fractionError(op, typ, n)
+		}
+		if t := math.Trunc(n); math.IsNaN(t) || t < 0 || t >= float64(max)+1 {
+			return 0, overflowError(op, typ, n)
+		}
+		if u := uint64(n); u <= max {
+			return u, nil
+		}
+	case json.Number:
+		lit := n.String()
+		if u, err := strconv.ParseUint(lit, 10, 64); err == nil {
+			if u <= max {
+				return u, nil
+			}
+			break
+		}
+		f, _, err := big.ParseFloat(lit, 10, literalPrecision(lit), big.ToNearestEven)
+		if err != nil {
+			return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert number %q to %s", ErrTypeMismatch, lit, typ)}
+		}
+		if exact && !f.IsInt() {
+			return 0, fractionError(op, typ, lit)
+		}
+		if f.MantExp(nil) > 64 {
+			break
+		}
+		if whole, _ := f.Int(nil); whole.Sign() >= 0 && whole.IsUint64() && whole.Uint64() <= max {
+			return whole.Uint64(), nil
+		}
+	case int:
+		if n >= 0 && uint64(n) <= max {
+			return uint64(n), nil
+		}
+	case int64:
+		if n >= 0 && uint64(n) <= max {
+			return uint64(n), nil
+		}
+	default:
+		return 0, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert %T to %s", ErrTypeMismatch, v, typ)}
+	}
+	return 0, overflowError(op, typ, v)
+}
+
+func fractionError(op, typ string, v interface{}) error {
+	return &JSONError{Op: op, Err: fmt.Errorf("%w: %v has a fractional part, cannot convert to %s", ErrTypeMismatch, v, typ)}
+}
+
+func overflowError(op, typ string, v interface{}) error {
+	return &JSONError{Op: op, Err: fmt.Errorf("%w: %w converting %v to %s", ErrTypeMismatch, ErrOverflow, v, typ)}
+}
+
+// isJSONNumber reports whether s matches the JSON number grammar
+// -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+func isJSONNumber(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(s) && s[i] == '0':
+		i++
+	case i < len(s) && s[i] >= '1' && s[i] <= '9':
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	default:
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	return i == len(s)
+}
@@ -0,0 +1,320 @@
+package jsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestParseWithNumbers(t *testing.T) {
+	input := `{"id":9007199254740993,"price":19.99,"count":3,"big":1e400,"ids":[9223372036854775807]}`
+
+	t.Run("float mode loses precision", func(t *testing.T) {
+		obj := JSON.Parse(input)
+		if id, _ := obj.Get("id").Int64(); id == 9007199254740993 {
+			t.Error("Expected float64 parsing to round the id")
+		}
+	})
+
+	obj := JSON.Parse(input, JSON.ParseWithNumbers())
+	if !obj.IsValid() {
+		t.Fatalf("Parse failed: %v", obj.Error())
+	}
+
+	t.Run("exact int64", func(t *testing.T) {
+		if id, err := obj.Get("id").Int64(); err != nil || id != 9007199254740993 {
+			t.Errorf("Expected 9007199254740993, got %d (err: %v)", id, err)
+		}
+		if id := obj.Get("ids", 0).Int64Or(0); id != 9223372036854775807 {
+			t.Errorf("Expected max int64, got %d", id)
+		}
+	})
+
+	t.Run("int and float conversions", func(t *testing.T) {
+		if n, err := obj.Get("count").Int(); err != nil || n != 3 {
+			t.Errorf("Expected 3, got %d (err: %v)", n, err)
+		}
+		if f, err := obj.Get("price").Float64(); err != nil || f != 19.99 {
+			t.Errorf("Expected 19.99, got %v (err: %v)", f, err)
+		}
+		if n := obj.Get("price").IntOr(-1); n != 19 {
+			t.Errorf("Expected truncation to 19, got %d", n)
+		}
+		if b := obj.Get("count").BoolOr(false); !b {
+			t.Error("Expected non-zero number to be true")
+		}
+	})
+
+	t.Run("string and type", func(t *testing.T) {
+		if s, _ := obj.Get("price").String(); s != "19.99" {
+			t.Errorf("Expected literal 19.99, got %s", s)
+		}
+		if typ := obj.Get("id").Type(); typ != "number" {
+			t.Errorf("Expected type number, got %s", typ)
+		}
+		if _, ok := obj.Get("id").Raw().(json.Number); !ok {
+			t.Errorf("Expected json.Number, got %T", obj.Get("id").Raw())
+		}
+	})
+
+	t.Run("round trip keeps literals", func(t *testing.T) {
+		out, err := JSON.Stringify(obj.Get("ids"))
+		if err != nil || out != `[9223372036854775807]` {
+			t.Errorf("Expected exact round trip, got %s (err: %v)", out, err)
+		}
+	})
+
+	t.Run("round trip keeps formatting", func(t *testing.T) {
+		// Already compact with sorted keys, so the output must be identical
+		formatted := `{"a":1e3,"b":[2.50,-0.0,1E+2,0.10],"c":100}`
+		doc := JSON.Parse(formatted, JSON.ParseWithNumbers())
+		if out, err := JSON.Stringify(doc); err != nil || out != formatted {
+			t.Errorf("Expected %s, got %s (err: %v)", formatted, out, err)
+		}
+		if out, err := JSON.Stringify(doc.Clone().Get("b")); err != nil || out != `[2.50,-0.0,1E+2,0.10]` {
+			t.Errorf("Expected literals kept through Clone, got %s (err: %v)", out, err)
+		}
+		if out, _ := JSON.Stringify(JSON.Parse(formatted)); out == formatted {
+			t.Error("Expected float mode to rewrite the literals")
+		}
+	})
+
+	t.Run("clone keeps numbers", func(t *testing.T) {
+		if id := obj.Clone().Get("id").Int64Or(0); id != 9007199254740993 {
+			t.Errorf("Clone lost precision, got %d", id)
+		}
+	})
+
+	t.Run("struct binding", func(t *testing.T) {
+		var dest struct {
+			ID int64 `json:"id"`
+		}
+		if err := obj.To(&dest); err != nil || dest.ID != 9007199254740993 {
+			t.Errorf("Expected exact struct binding, got %d (err: %v)", dest.ID, err)
+		}
+	})
+}
+
+func TestParseWithNumbersErrors(t *testing.T) {
+	for _, input := range []string{`{"a":1} {"b":2}`, `{"a":}`, `[1,2`} {
+		if JSON.Parse(input, JSON.ParseWithNumbers()).IsValid() {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestDecoderWithNumbers(t *testing.T) {
+	dec := JSON.NewDecoder(strings.NewReader(`{"id":9007199254740993}`), JSON.ParseWithNumbers())
+	v, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if id := v.Get("id").Int64Or(0); id != 9007199254740993 {
+		t.Errorf("Expected exact id, got %d", id)
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	obj := JSON.Parse(`{
+		"wei": 123456789012345678901234567890,
+		"str": "98765432109876543210",
+		"exp": 1e21,
+		"whole": 5.0,
+		"frac": 1.5,
+		"bomb": 1e100000000,
+		"text": "abc",
+		"flag": true
+	}`, JSON.ParseWithNumbers())
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{"wei", "123456789012345678901234567890", false},
+		{"str", "98765432109876543210", false},
+		{"exp", "1000000000000000000000", false},
+		{"whole", "5", false},
+		{"frac", "", true},
+		{"bomb", "", true},
+		{"text", "", true},
+		{"flag", "", true},
+		{"missing", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := obj.Get(tt.key).BigInt()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil || got.String() != tt.want {
+				t.Errorf("Expected %s, got %v (err: %v)", tt.want, got, err)
+			}
+		})
+	}
+
+	if got := obj.Get("frac").BigIntOr(big.NewInt(-1)); got.Int64() != -1 {
+		t.Errorf("Expected default, got %v", got)
+	}
+}
+
+func TestBigFloat(t *testing.T) {
+	obj := JSON.Parse(`{"amount": 12345678901234567890.123456789, "small": 0.5}`, JSON.ParseWithNumbers())
+
+	f, err := obj.Get("amount").BigFloat()
+	if err != nil {
+		t.Fatalf("BigFloat failed: %v", err)
+	}
+	if got := f.Text('f', 9); got != "12345678901234567890.123456789" {
+		t.Errorf("Expected full precision, got %s", got)
+	}
+
+	// Float mode converts from the float64 value
+	if f := JSON.Parse(`{"small": 0.5}`).Get("small").BigFloatOr(nil); f == nil || f.String() != "0.5" {
+		t.Errorf("Expected 0.5, got %v", f)
+	}
+	if _, err := obj.Get("missing").BigFloat(); err == nil {
+		t.Error("Expected error for missing key")
+	}
+}
+
+func TestNumber(t *testing.T) {
+	obj := JSON.Parse(`{"int":42,"float":1.5,"big":1e21,"str":"12.50","bad":"12,5","inf":"Inf","flag":true}`)
+
+	tests := []struct {
+		key     string
+		want    json.Number
+		wantErr bool
+	}{
+		{"int", "42", false},
+		{"float", "1.5", false},
+		{"big", "1e+21", false},
+		{"str", "12.50", false},
+		{"bad", "", true},
+		{"inf", "", true},
+		{"flag", "", true},
+		{"missing", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := obj.Get(tt.key).Number()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %q, got %q (err: %v)", tt.want, got, err)
+			}
+		})
+	}
+
+	// Literal mode returns the original text
+	lit := JSON.Parse(`{"big":1E21,"id":9007199254740993}`, JSON.ParseWithNumbers())
+	if n := lit.Get("big").NumberOr(""); n != "1E21" {
+		t.Errorf("Expected original literal 1E21, got %q", n)
+	}
+	if n, _ := lit.Get("id").Number(); n.String() != "9007199254740993" {
+		t.Errorf("Expected exact id, got %q", n)
+	}
+	if n := obj.Get("bad").NumberOr("0"); n != "0" {
+		t.Errorf("Expected default, got %q", n)
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	input := `{"huge":1e20,"neg":-1e19,"max":9223372036854775807,"over":9223372036854775808,"str":"99999999999999999999"}`
+	floats := JSON.Parse(input)
+	numbers := JSON.Parse(input, JSON.ParseWithNumbers())
+
+	for _, key := range []string{"huge", "neg", "over", "str"} {
+		for mode, obj := range map[string]JSON.JSONValue{"float": floats, "number": numbers} {
+			t.Run(key+"/"+mode, func(t *testing.T) {
+				got, err := obj.Get(key).Int64()
+				if !errors.Is(err, JSON.ErrOverflow) || !errors.Is(err, JSON.ErrTypeMismatch) {
+					t.Errorf("Expected ErrOverflow, got %d (err: %v)", got, err)
+				}
+				if _, err := obj.Get(key).Int(); !errors.Is(err, JSON.ErrOverflow) {
+					t.Errorf("Expected ErrOverflow from Int, got %v", err)
+				}
+			})
+		}
+	}
+
+	if got, err := numbers.Get("max").Int64(); err != nil || got != 9223372036854775807 {
+		t.Errorf("Expected max int64, got %d (err: %v)", got, err)
+	}
+	_, err := numbers.Get("over").Int64()
+	if want := "integer overflow converting 9223372036854775808 to int64"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected message containing %q, got %v", want, err)
+	}
+	// Exponent bombs are rejected without expanding them
+	bomb := JSON.Parse(`1e100000000`, JSON.ParseWithNumbers())
+	if _, err := bomb.Int64(); !errors.Is(err, JSON.ErrOverflow) {
+		t.Errorf("Expected ErrOverflow for 1e100000000, got %v", err)
+	}
+	if got := floats.Get("huge").Int64Or(-1); got != -1 {
+		t.Errorf("Expected default on overflow, got %d", got)
+	}
+}
+
+func TestSizedNumbers(t *testing.T) {
+	obj := JSON.Parse(`{"small":127,"byte":255,"neg":-1,"big":40000,"str":"-32768","max":18446744073709551615,"float":3.4e38,"huge":1e39}`)
+	numbers := JSON.Parse(`{"max":18446744073709551615,"over":18446744073709551616}`, JSON.ParseWithNumbers())
+
+	tests := []struct {
+		name string
+		conv func() (interface{}, error)
+		want interface{}
+	}{
+		{"int8", func() (interface{}, error) { return obj.Get("small").Int8() }, int8(127)},
+		{"int8 overflow", func() (interface{}, error) { return obj.Get("byte").Int8() }, nil},
+		{"int16 string", func() (interface{}, error) { return obj.Get("str").Int16() }, int16(-32768)},
+		{"int16 overflow", func() (interface{}, error) { return obj.Get("big").Int16() }, nil},
+		{"int32", func() (interface{}, error) { return obj.Get("big").Int32() }, int32(40000)},
+		{"uint8", func() (interface{}, error) { return obj.Get("byte").Uint8() }, uint8(255)},
+		{"uint8 overflow", func() (interface{}, error) { return obj.Get("big").Uint8() }, nil},
+		{"uint16", func() (interface{}, error) { return obj.Get("big").Uint16() }, uint16(40000)},
+		{"uint negative", func() (interface{}, error) { return obj.Get("neg").Uint() }, nil},
+		{"uint32 negative string", func() (interface{}, error) { return obj.Get("str").Uint32() }, nil},
+		{"uint64 exact", func() (interface{}, error) { return numbers.Get("max").Uint64() }, uint64(18446744073709551615)},
+		{"uint64 overflow", func() (interface{}, error) { return numbers.Get("over").Uint64() }, nil},
+		{"uint64 float overflow", func() (interface{}, error) { return obj.Get("max").Uint64() }, nil},
+		{"float32", func() (interface{}, error) { return obj.Get("float").Float32() }, float32(3.4e38)},
+		{"float32 overflow", func() (interface{}, error) { return obj.Get("huge").Float32() }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.conv()
+			if tt.want == nil {
+				if !errors.Is(err, JSON.ErrOverflow) {
+					t.Errorf("Expected ErrOverflow, got %v (err: %v)", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %v, got %v (err: %v)", tt.want, got, err)
+			}
+		})
+	}
+
+	if got := obj.Get("big").Int8Or(-1); got != -1 {
+		t.Errorf("Expected default on overflow, got %d", got)
+	}
+	if got, err := JSON.GetAs[uint16](obj, "big"); err != nil || got != 40000 {
+		t.Errorf("Expected GetAs to use Uint16, got %d (err: %v)", got, err)
+	}
+	if _, err := JSON.GetAs[int8](obj, "big"); !errors.Is(err, JSON.ErrOverflow) {
+		t.Errorf("Expected GetAs[int8] to overflow, got %v", err)
+	}
+}
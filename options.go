@@ -0,0 +1,90 @@
+package jsjson
+
+// ParseOption configures how Parse, ParseInto and Decoder read their input.
+// Options are passed after the input (and optional struct destination):
+//
+//	Parse(data, WithMaxBytes(1<<20))
+//	Parse(data, &dest, WithMaxArrayElements(1000))
+type ParseOption interface {
+	applyParse(*parseOptions)
+}
+
+// parseOptionFunc adapts a plain function to the ParseOption interface
+type parseOptionFunc func(*parseOptions)
+
+func (f parseOptionFunc) applyParse(o *parseOptions) { f(o) }
+
+// parseOptions holds the resolved settings for a single parse call
+type parseOptions struct {
+	maxBytes          int64
+	maxStringLen      int
+	maxArrayElements  int
+	workers           int
+	useNumber         bool
+	keepRaw           bool
+	useArena          bool
+	interner          *interner
+	disallowUnknown   bool
+	joinSurrogates    bool
+	rejectInvalidUTF8 bool
+	nonFinite         bool
+
+	// arena is the arena taken from the pool for this call, if useArena
+	arena *arena
+}
+
+// hasTokenLimits reports whether the input must be scanned for per-token limits
+func (o *parseOptions) hasTokenLimits() bool {
+	return o.maxStringLen > 0 || o.maxArrayElements > 0
+}
+
+// WithMaxBytes rejects inputs larger than n bytes. For a Decoder the limit
+// applies to each value read from the stream, and the decoder stops reading
+// as soon as a value grows past it.
+func WithMaxBytes(n int64) ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.maxBytes = n })
+}
+
+// WithMaxStringLen rejects inputs containing a string (key or value) whose
+// encoded length exceeds n bytes
+func WithMaxStringLen(n int) ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.maxStringLen = n })
+}
+
+// WithMaxArrayElements rejects inputs containing an array with more than n elements
+func WithMaxArrayElements(n int) ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.maxArrayElements = n })
+}
+
+// splitArgs separates struct destinations from ParseOption values in the
+// variadic tail accepted by Parse
+func splitArgs(args []interface{}) (dests []interface{}, opts parseOptions) {
+	for _, arg := range args {
+		if opt, ok := arg.(ParseOption); ok {
+			opt.applyParse(&opts)
+			continue
+		}
+		dests = append(dests, arg)
+	}
+	return dests, opts
+}
+
+// newParseOptions resolves a list of options into a parseOptions value
+func newParseOptions(opts []ParseOption) parseOptions {
+	var o parseOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyParse(&o)
+		}
+	}
+	return o
+}
+
+// ParseWithNumbers keeps numbers as json.Number instead of float64, so large
+// integers (such as int64 IDs above 2^53) and decimal literals survive parsing
+// exactly. Int, Int64 and Float64 convert lazily from the original literal.
+// Stringify writes each literal back as it appeared, so 1e3 and 2.50 are not
+// rewritten as 1000 and 2.5 when a document is re-serialized.
+func ParseWithNumbers() ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.useNumber = true })
+}
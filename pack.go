@@ -0,0 +1,343 @@
+package jsjson
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+)
+
+// -------------------- Binary Envelope --------------------
+//
+// A packed document is laid out as:
+//
+//	magic    [4]byte  "JSJB"
+//	version  byte     packFormatVersion
+//	flags    byte     packFlagCompressed
+//	hash     [32]byte SHA-256 of the canonical JSON serialization
+//	checksum uint32   CRC-32 (IEEE) of the payload, big endian
+//	payload  []byte   tagged tree encoding, DEFLATE-compressed if flagged
+//
+// The tree encoding writes one tag byte per node followed by its contents.
+// Lengths and counts are unsigned varints; object keys are written in sorted
+// order so equal documents pack to equal bytes.
+
+const (
+	packMagic         = "JSJB"
+	packFormatVersion = 1
+	packHeaderLen     = len(packMagic) + 2 + 32 + 4
+
+	packFlagCompressed = 1 << 0
+
+	// packMaxDepth bounds nesting when unpacking untrusted input
+	packMaxDepth = 10000
+)
+
+const (
+	tagNull byte = iota
+	tagFalse
+	tagTrue
+	tagFloat
+	tagNumber
+	tagString
+	tagArray
+	tagObject
+)
+
+// PackOption configures Pack
+type PackOption func(*packOptions)
+
+type packOptions struct {
+	compress bool
+	level    int
+}
+
+// WithCompression compresses the packed payload with DEFLATE at the given
+// level (flate.BestSpeed through flate.BestCompression, or flate.DefaultCompression)
+func WithCompression(level int) PackOption {
+	return func(o *packOptions) {
+		o.compress = true
+		o.level = level
+	}
+}
+
+// Pack encodes the value into a compact, self-describing binary envelope that
+// can be stored in a cache and restored with Unpack without re-parsing JSON
+// text. The envelope carries a format version, an optional compression flag
+// and the canonical SHA-256 hash of the document.
+func (j JSONValue) Pack(opts ...PackOption) ([]byte, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	var o packOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	data, err := normalize(j.data)
+	if err != nil {
+		return nil, &JSONError{Op: "Pack", Err: err}
+	}
+	hash, err := canonicalHash(data)
+	if err != nil {
+		return nil, &JSONError{Op: "Pack", Err: err}
+	}
+	sum, _ := hex.DecodeString(hash)
+
+	payload := appendPacked(nil, data)
+	var flags byte
+	if o.compress {
+		var buf bytes.Buffer
+		w, err := flate.NewWriter(&buf, o.level)
+		if err != nil {
+			return nil, &JSONError{Op: "Pack", Err: err}
+		}
+		if _, err := w.Write(payload); err != nil {
+			return nil, &JSONError{Op: "Pack", Err: err}
+		}
+		if err := w.Close(); err != nil {
+			return nil, &JSONError{Op: "Pack", Err: err}
+		}
+		payload = buf.Bytes()
+		flags |= packFlagCompressed
+	}
+
+	out := make([]byte, 0, packHeaderLen+len(payload))
+	out = append(out, packMagic...)
+	out = append(out, packFormatVersion, flags)
+	out = append(out, sum...)
+	out = binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(payload))
+	return append(out, payload...), nil
+}
+
+// Unpack restores a JSONValue from an envelope produced by Pack.
+// WithMaxBytes limits the size of the payload after decompression, so a
+// small compressed envelope cannot expand without bound.
+func Unpack(b []byte, opts ...ParseOption) JSONValue {
+	flags, payload, err := readPackHeader(b)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "Unpack", Err: err}}
+	}
+
+	o := newParseOptions(opts)
+	if flags&packFlagCompressed != 0 {
+		r := flate.NewReader(bytes.NewReader(payload))
+		var src io.Reader = r
+		if o.maxBytes > 0 {
+			src = io.LimitReader(r, o.maxBytes+1)
+		}
+		payload, err = io.ReadAll(src)
+		r.Close()
+		if err != nil {
+			return JSONValue{err: &JSONError{Op: "Unpack", Err: err}}
+		}
+	}
+	if o.maxBytes > 0 && int64(len(payload)) > o.maxBytes {
+		return JSONValue{err: &JSONError{Op: "Unpack", Err: fmt.Errorf("%w: payload is larger than %d bytes", ErrLimitExceeded, o.maxBytes)}}
+	}
+
+	d := packDecoder{buf: payload}
+	data, err := d.value(0)
+	if err == nil && d.pos != len(d.buf) {
+		err = fmt.Errorf("%w: %d trailing bytes", ErrSyntax, len(d.buf)-d.pos)
+	}
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "Unpack", Err: err}}
+	}
+	return JSONValue{data: data}
+}
+
+// PackedHash returns the canonical document hash stored in a packed envelope
+// without decoding the payload, for use as a cache key or ETag
+func PackedHash(b []byte) (string, error) {
+	if _, _, err := readPackHeader(b); err != nil {
+		return "", &JSONError{Op: "PackedHash", Err: err}
+	}
+	start := len(packMagic) + 2
+	return hex.EncodeToString(b[start : start+32]), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the Pack envelope,
+// so a JSONValue can be stored by gob, net/rpc or a byte-oriented cache
+func (j JSONValue) MarshalBinary() ([]byte, error) {
+	return j.Pack()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for envelopes
+// produced by MarshalBinary or Pack. On error j is left unchanged.
+func (j *JSONValue) UnmarshalBinary(b []byte) error {
+	v := Unpack(b)
+	if v.err != nil {
+		return v.err
+	}
+	*j = v
+	return nil
+}
+
+// readPackHeader validates the envelope header and checksum
+func readPackHeader(b []byte) (flags byte, payload []byte, err error) {
+	if len(b) < packHeaderLen || string(b[:len(packMagic)]) != packMagic {
+		return 0, nil, fmt.Errorf("%w: not a packed document", ErrSyntax)
+	}
+	if v := b[len(packMagic)]; v != packFormatVersion {
+		return 0, nil, fmt.Errorf("unsupported pack format version %d", v)
+	}
+	flags = b[len(packMagic)+1]
+	checksum := binary.BigEndian.Uint32(b[packHeaderLen-4 : packHeaderLen])
+	payload = b[packHeaderLen:]
+	if crc32.ChecksumIEEE(payload) != checksum {
+		return 0, nil, fmt.Errorf("%w: checksum mismatch", ErrSyntax)
+	}
+	return flags, payload, nil
+}
+
+// appendPacked appends the tagged encoding of a normalized tree node
+func appendPacked(b []byte, v interface{}) []byte {
+	switch c := v.(type) {
+	case nil:
+		return append(b, tagNull)
+	case bool:
+		if c {
+			return append(b, tagTrue)
+		}
+		return append(b, tagFalse)
+	case float64:
+		b = append(b, tagFloat)
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(c))
+	case json.Number:
+		b = append(b, tagNumber)
+		b = binary.AppendUvarint(b, uint64(len(c)))
+		return append(b, c...)
+	case string:
+		b = append(b, tagString)
+		b = binary.AppendUvarint(b, uint64(len(c)))
+		return append(b, c...)
+	case []interface{}:
+		b = append(b, tagArray)
+		b = binary.AppendUvarint(b, uint64(len(c)))
+		for _, item := range c {
+			b = appendPacked(b, item)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(c))
+		for k := range c {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b = append(b, tagObject)
+		b = binary.AppendUvarint(b, uint64(len(c)))
+		for _, k := range keys {
+			b = binary.AppendUvarint(b, uint64(len(k)))
+			b = append(b, k...)
+			b = appendPacked(b, c[k])
+		}
+		return b
+	default:
+		// normalize guarantees only tree types reach here
+		panic(fmt.Sprintf("jsjson: cannot pack %T", v))
+	}
+}
+
+// packDecoder reads the tagged tree encoding
+type packDecoder struct {
+	buf []byte
+	pos int
+}
+
+var errPackTruncated = fmt.Errorf("%w: truncated packed data", ErrSyntax)
+
+func (d *packDecoder) uvarint() (int, error) {
+	n, size := binary.Uvarint(d.buf[d.pos:])
+	if size <= 0 {
+		return 0, errPackTruncated
+	}
+	d.pos += size
+	// Every length or count refers to at least one byte of remaining input,
+	// which keeps corrupt headers from triggering huge allocations
+	if n > uint64(len(d.buf)-d.pos) {
+		return 0, errPackTruncated
+	}
+	return int(n), nil
+}
+
+func (d *packDecoder) bytes() ([]byte, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *packDecoder) value(depth int) (interface{}, error) {
+	if depth > packMaxDepth {
+		return nil, fmt.Errorf("%w: nesting deeper than %d", ErrLimitExceeded, packMaxDepth)
+	}
+	if d.pos >= len(d.buf) {
+		return nil, errPackTruncated
+	}
+	tag := d.buf[d.pos]
+	d.pos++
+
+	switch tag {
+	case tagNull:
+		return nil, nil
+	case tagFalse:
+		return false, nil
+	case tagTrue:
+		return true, nil
+	case tagFloat:
+		if len(d.buf)-d.pos < 8 {
+			return nil, errPackTruncated
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(d.buf[d.pos:]))
+		d.pos += 8
+		return f, nil
+	case tagNumber:
+		b, err := d.bytes()
+		return json.Number(b), err
+	case tagString:
+		b, err := d.bytes()
+		return string(b), err
+	case tagArray:
+		n, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		arr := make([]interface{}, n)
+		for i := range arr {
+			if arr[i], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case tagObject:
+		n, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		obj := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			key, err := d.bytes()
+			if err != nil {
+				return nil, err
+			}
+			if obj[string(key)], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown tag %d at offset %d", ErrSyntax, tag, d.pos-1)
+	}
+}
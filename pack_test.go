@@ -0,0 +1,153 @@
+package jsjson_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/gob"
+	"errors"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestPackRoundTrip(t *testing.T) {
+	inputs := []string{
+		`null`, `true`, `false`, `0`, `-1.5e-7`, `"héllo \u0000 world"`, `[]`, `{}`,
+		`{"users":[{"name":"John","age":30,"tags":["a","b"],"active":true,"meta":null}],"count":1}`,
+	}
+
+	for _, input := range inputs {
+		for _, opts := range [][]JSON.PackOption{nil, {JSON.WithCompression(flate.BestCompression)}} {
+			obj := JSON.Parse(input)
+			packed, err := obj.Pack(opts...)
+			if err != nil {
+				t.Fatalf("Pack(%s) failed: %v", input, err)
+			}
+			restored := JSON.Unpack(packed)
+			if !restored.IsValid() {
+				t.Fatalf("Unpack(%s) failed: %v", input, restored.Error())
+			}
+			want, _ := JSON.Stringify(obj)
+			got, _ := JSON.Stringify(restored)
+			if got != want {
+				t.Errorf("Round trip mismatch: want %s, got %s", want, got)
+			}
+		}
+	}
+}
+
+func TestPackPreservesNumbers(t *testing.T) {
+	obj := JSON.Parse(`{"id":9007199254740993}`, JSON.ParseWithNumbers())
+	packed, err := obj.Pack()
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if id := JSON.Unpack(packed).Get("id").Int64Or(0); id != 9007199254740993 {
+		t.Errorf("Expected exact id, got %d", id)
+	}
+}
+
+func TestPackDeterministicHash(t *testing.T) {
+	a, _ := JSON.Parse(`{"b":1,"a":[1,2]}`).Pack()
+	b, _ := JSON.Parse(`{ "a": [1, 2], "b": 1 }`).Pack()
+	if !bytes.Equal(a, b) {
+		t.Error("Expected equal documents to pack to equal bytes")
+	}
+
+	hashA, err := JSON.PackedHash(a)
+	if err != nil || len(hashA) != 64 {
+		t.Fatalf("Expected 64-char hash, got %q (err: %v)", hashA, err)
+	}
+	c, _ := JSON.Parse(`{"a":[1,2],"b":2}`).Pack()
+	if hashC, _ := JSON.PackedHash(c); hashC == hashA {
+		t.Error("Expected different documents to have different hashes")
+	}
+
+	compressed, _ := JSON.Parse(`{"b":1,"a":[1,2]}`).Pack(JSON.WithCompression(flate.DefaultCompression))
+	if hash, _ := JSON.PackedHash(compressed); hash != hashA {
+		t.Error("Expected hash to be independent of compression")
+	}
+}
+
+func TestUnpackCorruptInput(t *testing.T) {
+	packed, _ := JSON.Parse(`{"name":"John","tags":["a","b"]}`).Pack()
+
+	corrupt := append([]byte(nil), packed...)
+	corrupt[len(corrupt)-1] ^= 0xff
+
+	badVersion := append([]byte(nil), packed...)
+	badVersion[4] = 99
+
+	tests := map[string][]byte{
+		"empty":       nil,
+		"not packed":  []byte(`{"name":"John"}`),
+		"truncated":   packed[:len(packed)-3],
+		"checksum":    corrupt,
+		"version":     badVersion,
+		"header only": packed[:42],
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if JSON.Unpack(input).IsValid() {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestUnpackMaxBytes(t *testing.T) {
+	doc := JSON.Parse(`{"data":"` + strings.Repeat("a", 1<<16) + `"}`)
+	for name, opts := range map[string][]JSON.PackOption{
+		"plain":      nil,
+		"compressed": {JSON.WithCompression(flate.BestCompression)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			packed, err := doc.Pack(opts...)
+			if err != nil {
+				t.Fatalf("Pack failed: %v", err)
+			}
+			if err := JSON.Unpack(packed, JSON.WithMaxBytes(1024)).Error(); !errors.Is(err, JSON.ErrLimitExceeded) {
+				t.Errorf("Expected ErrLimitExceeded, got %v", err)
+			}
+			if restored := JSON.Unpack(packed, JSON.WithMaxBytes(1<<20)); !restored.IsValid() {
+				t.Errorf("Unpack within the limit failed: %v", restored.Error())
+			}
+		})
+	}
+}
+
+type gobEntry struct {
+	Key   string
+	Doc   JSON.JSONValue
+	Empty JSON.JSONValue
+}
+
+func TestJSONValueGob(t *testing.T) {
+	const input = `{"big":12345678901234567890,"id":7,"tags":["a","b"]}`
+	in := gobEntry{Key: "k", Doc: JSON.Parse(input, JSON.ParseWithNumbers())}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var out gobEntry
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if got, _ := JSON.Stringify(out.Doc); out.Key != "k" || got != input {
+		t.Errorf("Expected %s, got %s", input, got)
+	}
+	if !out.Empty.IsValid() || !out.Empty.IsNull() {
+		t.Errorf("Expected a valid null, got %v", out.Empty.Error())
+	}
+
+	in.Doc = JSON.Parse(`{bad`)
+	if err := gob.NewEncoder(&buf).Encode(in); err == nil {
+		t.Error("Expected the value's error")
+	}
+	var jv JSON.JSONValue
+	if err := jv.UnmarshalBinary([]byte("not packed")); err == nil || !jv.IsValid() {
+		t.Errorf("Expected an error and an unchanged value, got %v", err)
+	}
+}
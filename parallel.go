@@ -0,0 +1,318 @@
+package jsjson
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// minParallelElements is the smallest array worth splitting across workers
+const minParallelElements = 64
+
+// WithWorkers sets the number of goroutines used by the parallel decoding
+// functions. Values below 1 mean runtime.GOMAXPROCS(0).
+func WithWorkers(n int) ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.workers = n })
+}
+
+// workerCount resolves the configured worker count
+func (o *parseOptions) workerCount() int {
+	if o.workers < 1 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.workers
+}
+
+// ParseIntoSlice decodes a top-level JSON array into a pointer to a slice,
+// decoding elements in parallel. It is intended for large CPU-bound ingest
+// jobs; small arrays are decoded on the calling goroutine.
+// Usage: ParseIntoSlice(data, &items, WithWorkers(8))
+func ParseIntoSlice(data interface{}, dest interface{}, opts ...ParseOption) error {
+	destValue := reflect.ValueOf(dest)
+	if dest == nil || destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return &JSONError{Op: "ParseIntoSlice", Err: fmt.Errorf("destination must be a pointer to a slice, got %T", dest)}
+	}
+
+	o := newParseOptions(opts)
+
+	var jsonBytes []byte
+	switch val := data.(type) {
+	case string:
+		jsonBytes = []byte(val)
+	case []byte:
+		jsonBytes = val
+	case JSONValue:
+		if val.err != nil {
+			return &JSONError{Op: "ParseIntoSlice", Err: val.err}
+		}
+		return val.To(dest)
+	default:
+		return ParseInto(data, dest, opts...)
+	}
+	if len(jsonBytes) == 0 {
+		return &JSONError{Op: "ParseIntoSlice", Err: fmt.Errorf("%w: empty input", ErrSyntax)}
+	}
+	if err := checkLimits(jsonBytes, &o); err != nil {
+		return &JSONError{Op: "ParseIntoSlice", Err: err}
+	}
+
+	workers := o.workerCount()
+	var elements [][]byte
+	if workers > 1 {
+		var err error
+		if elements, err = splitArray(jsonBytes); err != nil {
+			return &JSONError{Op: "ParseIntoSlice", Err: err}
+		}
+	}
+
+	if workers == 1 || len(elements) < minParallelElements {
+		if _, err := unmarshalValue(jsonBytes, dest); err != nil {
+			return &JSONError{Op: "ParseIntoSlice", Err: err}
+		}
+		return nil
+	}
+
+	sliceType := destValue.Elem().Type()
+	slice := reflect.MakeSlice(sliceType, len(elements), len(elements))
+	err := parallelEach(len(elements), workers, func(i int) error {
+		if _, err := unmarshalValue(elements[i], slice.Index(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return &JSONError{Op: "ParseIntoSlice", Err: err}
+	}
+
+	destValue.Elem().Set(slice)
+	return nil
+}
+
+// ParseParallel parses a top-level JSON array into a JSONValue, decoding its
+// elements on several goroutines. Element boundaries are found with a fast
+// structural scan, elements are decoded in batches and the results are
+// stitched back together in order. Inputs that are not arrays, and arrays too
+// small to benefit, are parsed on the calling goroutine like Parse.
+// WithArena is not supported; with WithInterning each batch has its own table.
+// Usage: ParseParallel(data, WithWorkers(8))
+func ParseParallel(data interface{}, opts ...ParseOption) JSONValue {
+	o := newParseOptions(opts)
+	if o.useArena {
+		return JSONValue{err: &JSONError{Op: "ParseParallel", Err: fmt.Errorf("WithArena is not supported")}}
+	}
+
+	var jsonBytes []byte
+	switch val := data.(type) {
+	case string:
+		jsonBytes = []byte(val)
+	case []byte:
+		jsonBytes = val
+	}
+	i := skipSpace(jsonBytes, 0)
+	if i >= len(jsonBytes) || jsonBytes[i] != '[' || o.workerCount() == 1 {
+		return parseSequential(data, opts)
+	}
+	if err := checkLimits(jsonBytes, &o); err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseParallel", Err: err}}
+	}
+
+	elements, err := splitArray(jsonBytes)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseParallel", Err: err}}
+	}
+	if len(elements) < minParallelElements {
+		return parseSequential(data, opts)
+	}
+
+	// Batches amortize per-task overhead; four per worker keeps them balanced
+	// when element sizes vary
+	workers := o.workerCount()
+	batchSize := (len(elements) + workers*4 - 1) / (workers * 4)
+	batches := (len(elements) + batchSize - 1) / batchSize
+
+	result := make([]interface{}, len(elements))
+	err = parallelEach(batches, workers, func(b int) error {
+		bo := o
+		if o.interner != nil {
+			bo.interner = &interner{table: make(map[string]string)}
+		}
+		end := min((b+1)*batchSize, len(elements))
+		for i := b * batchSize; i < end; i++ {
+			v, err := decodeTree(elements[i], &bo)
+			if err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+			result[i] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseParallel", Err: err}}
+	}
+
+	if _, borrowed := data.([]byte); borrowed && o.keepRaw {
+		jsonBytes = append([]byte(nil), jsonBytes...)
+	}
+	return JSONValue{data: result, raw: newRawRef(jsonBytes, &o)}
+}
+
+// parseSequential is the ParseParallel fallback for inputs not worth splitting
+func parseSequential(data interface{}, opts []ParseOption) JSONValue {
+	args := make([]interface{}, len(opts))
+	for i, opt := range opts {
+		args[i] = opt
+	}
+	return Parse(data, args...)
+}
+
+// parallelEach calls fn for every index in [0, n) using the given number of
+// workers. It stops handing out work after the first error and returns it.
+func parallelEach(n, workers int, fn func(i int) error) error {
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		next     int64 = -1
+		failed   atomic.Bool
+		firstErr error
+		errOnce  sync.Once
+		wg       sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for !failed.Load() {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				if err := fn(i); err != nil {
+					errOnce.Do(func() { firstErr = err })
+					failed.Store(true)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// -------------------- Array Splitting --------------------
+
+// splitArray returns the raw bytes of each element of a top-level JSON array
+// without decoding them. Only the structure needed to find element boundaries
+// is checked; element contents are validated when they are decoded.
+func splitArray(data []byte) ([][]byte, error) {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '[' {
+		return nil, fmt.Errorf("%w: input is not a JSON array", ErrSyntax)
+	}
+	i = skipSpace(data, i+1)
+
+	var elements [][]byte
+	if i < len(data) && data[i] == ']' {
+		if skipSpace(data, i+1) != len(data) {
+			return nil, fmt.Errorf("%w: unexpected data after top-level array at offset %d", ErrSyntax, i+1)
+		}
+		return elements, nil
+	}
+
+	for {
+		end, err := scanValueEnd(data, i)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, data[i:end])
+
+		i = skipSpace(data, end)
+		if i >= len(data) {
+			return nil, fmt.Errorf("%w: unexpected end of input in array", ErrSyntax)
+		}
+		switch data[i] {
+		case ',':
+			i = skipSpace(data, i+1)
+		case ']':
+			if skipSpace(data, i+1) != len(data) {
+				return nil, fmt.Errorf("%w: unexpected data after top-level array at offset %d", ErrSyntax, i+1)
+			}
+			return elements, nil
+		default:
+			return nil, fmt.Errorf("%w: invalid character %q after array element at offset %d", ErrSyntax, data[i], i)
+		}
+	}
+}
+
+// skipSpace returns the offset of the first non-whitespace byte at or after i
+func skipSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// scanValueEnd returns the offset just past the JSON value starting at i
+func scanValueEnd(data []byte, i int) (int, error) {
+	if i >= len(data) {
+		return 0, fmt.Errorf("%w: unexpected end of input", ErrSyntax)
+	}
+
+	switch data[i] {
+	case '"':
+		return scanStringEnd(data, i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case '"':
+				end, err := scanStringEnd(data, j)
+				if err != nil {
+					return 0, err
+				}
+				j = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("%w: unexpected end of input in value starting at offset %d", ErrSyntax, i)
+	case ',', ']', '}', ':':
+		return 0, fmt.Errorf("%w: invalid character %q at offset %d", ErrSyntax, data[i], i)
+	default:
+		j := i
+		for j < len(data) {
+			switch data[j] {
+			case ',', ']', '}', ' ', '\t', '\n', '\r':
+				return j, nil
+			}
+			j++
+		}
+		return j, nil
+	}
+}
+
+// scanStringEnd returns the offset just past the string starting at i
+func scanStringEnd(data []byte, i int) (int, error) {
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated string starting at offset %d", ErrSyntax, i)
+}
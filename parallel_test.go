@@ -0,0 +1,133 @@
+package jsjson_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+type sliceItem struct {
+	ID   int      `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// buildItems returns a JSON array of n items
+func buildItems(n int) string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteString(",\n")
+		}
+		fmt.Fprintf(&sb, `{"id":%d,"name":"item \"%d\"","tags":["a,b","[c]"]}`, i, i)
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+func TestParseIntoSlice(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000} {
+		t.Run(fmt.Sprintf("%d items", n), func(t *testing.T) {
+			var items []sliceItem
+			if err := JSON.ParseIntoSlice(buildItems(n), &items, JSON.WithWorkers(4)); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(items) != n {
+				t.Fatalf("Expected %d items, got %d", n, len(items))
+			}
+			for i, item := range items {
+				if item.ID != i || item.Name != fmt.Sprintf("item \"%d\"", i) || len(item.Tags) != 2 {
+					t.Fatalf("Item %d decoded incorrectly: %+v", i, item)
+				}
+			}
+		})
+	}
+}
+
+func TestParseIntoSliceErrors(t *testing.T) {
+	var items []sliceItem
+	tests := []struct {
+		name  string
+		input interface{}
+		dest  interface{}
+	}{
+		{"not an array", `{"id":1}`, &items},
+		{"non-pointer destination", buildItems(100), items},
+		{"pointer to non-slice", buildItems(100), &sliceItem{}},
+		{"element type mismatch", strings.Replace(buildItems(100), `"id":50`, `"id":"fifty"`, 1), &items},
+		{"truncated input", buildItems(100)[:500], &items},
+		{"trailing data", buildItems(100) + ` {}`, &items},
+		{"empty input", "", &items},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := JSON.ParseIntoSlice(tt.input, tt.dest, JSON.WithWorkers(4)); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestParseIntoSliceFromJSONValue(t *testing.T) {
+	var items []sliceItem
+	if err := JSON.ParseIntoSlice(JSON.Parse(buildItems(3)), &items); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(items) != 3 || items[2].ID != 2 {
+		t.Errorf("Unexpected result: %+v", items)
+	}
+}
+
+func TestParseParallel(t *testing.T) {
+	optionSets := map[string][]JSON.ParseOption{
+		"default":   {JSON.WithWorkers(4)},
+		"interning": {JSON.WithWorkers(3), JSON.WithInterning()},
+		"numbers":   {JSON.WithWorkers(4), JSON.ParseWithNumbers()},
+	}
+
+	for _, n := range []int{0, 10, 100, 1000} {
+		for name, opts := range optionSets {
+			t.Run(fmt.Sprintf("%d elements %s", n, name), func(t *testing.T) {
+				input := buildItems(n)
+				args := make([]interface{}, len(opts))
+				for i, opt := range opts {
+					args[i] = opt
+				}
+				want := JSON.Parse(input, args...)
+				got := JSON.ParseParallel(input, opts...)
+				if got.Error() != nil {
+					t.Fatalf("Unexpected error: %v", got.Error())
+				}
+				if !reflect.DeepEqual(got.Raw(), want.Raw()) {
+					t.Error("Result differs from Parse")
+				}
+			})
+		}
+	}
+}
+
+func TestParseParallelFallbackAndErrors(t *testing.T) {
+	if got := JSON.ParseParallel(`{"a":1}`, JSON.WithWorkers(4)); got.Get("a").IntOr(0) != 1 {
+		t.Errorf("Expected objects to be parsed sequentially, got %v (err: %v)", got.Raw(), got.Error())
+	}
+
+	bad := strings.Replace(buildItems(100), `"id":50`, `"id":5x`, 1)
+	if err := JSON.ParseParallel(bad, JSON.WithWorkers(4)).Error(); !strings.Contains(fmt.Sprint(err), "element 50") {
+		t.Errorf("Expected error naming element 50, got %v", err)
+	}
+	if err := JSON.ParseParallel(buildItems(100)+` {}`, JSON.WithWorkers(4)).Error(); !errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Expected ErrSyntax for trailing data, got %v", err)
+	}
+	if err := JSON.ParseParallel(buildItems(100), JSON.WithWorkers(4), JSON.WithMaxBytes(10)).Error(); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got %v", err)
+	}
+	if err := JSON.ParseParallel(buildItems(100), JSON.WithArena()).Error(); err == nil {
+		t.Error("Expected WithArena to be rejected")
+	}
+}
@@ -0,0 +1,403 @@
+package jsjson
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// -------------------- Parquet --------------------
+//
+// ToParquet writes the smallest file every Parquet reader accepts: a flat
+// schema of optional columns, one row group, and per column one
+// uncompressed data page (v1) with PLAIN values and RLE definition levels.
+// The footer is Thrift compact protocol as the format specifies.
+
+// ParquetType is the physical type of a Parquet column
+type ParquetType int
+
+const (
+	// ParquetAuto infers the type from the column's values
+	ParquetAuto ParquetType = iota
+	ParquetBoolean
+	ParquetInt64
+	ParquetDouble
+	// ParquetString is a UTF-8 BYTE_ARRAY column
+	ParquetString
+)
+
+// ParquetOptions controls ToParquet
+type ParquetOptions struct {
+	// Columns selects and orders the columns by dotted path, as in
+	// CSVOptions; when empty every path found in the rows is written
+	Columns []string
+	// Types overrides the inferred type of columns by name
+	Types map[string]ParquetType
+}
+
+// Parquet physical types, encodings and page types
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetDataPage = 0
+	parquetOptional = 1
+	parquetUTF8     = 0
+)
+
+// ToParquet writes an array of objects as a Parquet file, one row per
+// object, so API extractions can land in a data lake directly. Nested
+// objects are flattened into dotted columns as in ToCSV, and every column is
+// optional: null and missing values are stored as nulls. Inferred types are
+// boolean when every value is a boolean, int64 when every value is an
+// integer, double when every value is a number, and string otherwise, with
+// non-string values written as their JSON text.
+func (j JSONValue) ToParquet(w io.Writer, opts ParquetOptions) error {
+	if j.err != nil {
+		return j.err
+	}
+
+	rows, columns, err := flattenRows(j.data)
+	if err != nil {
+		return &JSONError{Op: "ToParquet", Err: err}
+	}
+	if len(opts.Columns) > 0 {
+		columns = opts.Columns
+	}
+
+	out := []byte("PAR1")
+	var schema, chunks thriftWriter
+	writeSchemaElement(&schema, "schema", -1, len(columns))
+	chunks.listHeader(thriftStruct, len(columns))
+	for _, col := range columns {
+		typ := opts.Types[col]
+		if typ == ParquetAuto {
+			typ = inferParquetType(rows, col)
+		}
+		values, defs, err := encodeParquetColumn(rows, col, typ)
+		if err != nil {
+			return &JSONError{Op: "ToParquet", Err: err}
+		}
+
+		page := binary.LittleEndian.AppendUint32(nil, uint32(len(defs)))
+		page = append(page, defs...)
+		page = append(page, values...)
+
+		var header thriftWriter
+		header.field(1, thriftI32)
+		header.i32(parquetDataPage)
+		header.field(2, thriftI32)
+		header.i32(int32(len(page)))
+		header.field(3, thriftI32)
+		header.i32(int32(len(page)))
+		header.field(5, thriftStruct)
+		header.push()
+		header.field(1, thriftI32)
+		header.i32(int32(len(rows)))
+		header.field(2, thriftI32)
+		header.i32(parquetPlain)
+		header.field(3, thriftI32)
+		header.i32(parquetRLE)
+		header.field(4, thriftI32)
+		header.i32(parquetRLE)
+		header.pop()
+		header.stop()
+
+		offset := int64(len(out))
+		out = append(out, header.b...)
+		out = append(out, page...)
+		size := int64(len(header.b) + len(page))
+
+		physical := parquetPhysical(typ)
+		writeSchemaElement(&schema, col, physical, 0)
+		chunks.push()
+		chunks.field(2, thriftI64)
+		chunks.i64(offset)
+		chunks.field(3, thriftStruct)
+		chunks.push()
+		chunks.field(1, thriftI32)
+		chunks.i32(int32(physical))
+		chunks.field(2, thriftList)
+		chunks.listHeader(thriftI32, 2)
+		chunks.i32(parquetPlain)
+		chunks.i32(parquetRLE)
+		chunks.field(3, thriftList)
+		chunks.listHeader(thriftBinary, 1)
+		chunks.binary(col)
+		chunks.field(4, thriftI32)
+		chunks.i32(0) // UNCOMPRESSED
+		chunks.field(5, thriftI64)
+		chunks.i64(int64(len(rows)))
+		chunks.field(6, thriftI64)
+		chunks.i64(size)
+		chunks.field(7, thriftI64)
+		chunks.i64(size)
+		chunks.field(9, thriftI64)
+		chunks.i64(offset)
+		chunks.pop()
+		chunks.pop()
+	}
+	dataSize := int64(len(out) - 4)
+
+	var footer thriftWriter
+	footer.field(1, thriftI32)
+	footer.i32(1)
+	footer.field(2, thriftList)
+	footer.listHeader(thriftStruct, len(columns)+1)
+	footer.b = append(footer.b, schema.b...)
+	footer.field(3, thriftI64)
+	footer.i64(int64(len(rows)))
+	footer.field(4, thriftList)
+	footer.listHeader(thriftStruct, 1)
+	footer.push()
+	footer.field(1, thriftList)
+	footer.b = append(footer.b, chunks.b...)
+	footer.field(2, thriftI64)
+	footer.i64(dataSize)
+	footer.field(3, thriftI64)
+	footer.i64(int64(len(rows)))
+	footer.pop()
+	footer.field(6, thriftBinary)
+	footer.binary("jsjson")
+	footer.stop()
+
+	out = append(out, footer.b...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(footer.b)))
+	out = append(out, "PAR1"...)
+	if _, err := w.Write(out); err != nil {
+		return &JSONError{Op: "ToParquet", Err: err}
+	}
+	return nil
+}
+
+// writeSchemaElement appends a SchemaElement: the root group when physical
+// is negative, an optional leaf column otherwise
+func writeSchemaElement(t *thriftWriter, name string, physical, children int) {
+	t.push()
+	if physical >= 0 {
+		t.field(1, thriftI32)
+		t.i32(int32(physical))
+		t.field(3, thriftI32)
+		t.i32(parquetOptional)
+	}
+	t.field(4, thriftBinary)
+	t.binary(name)
+	if physical < 0 {
+		t.field(5, thriftI32)
+		t.i32(int32(children))
+	}
+	if physical == parquetByteArray {
+		t.field(6, thriftI32)
+		t.i32(parquetUTF8)
+	}
+	t.pop()
+}
+
+func parquetPhysical(typ ParquetType) int {
+	switch typ {
+	case ParquetBoolean:
+		return parquetBoolean
+	case ParquetInt64:
+		return parquetInt64
+	case ParquetDouble:
+		return parquetDouble
+	}
+	return parquetByteArray
+}
+
+// inferParquetType picks the narrowest type holding every non-null value
+func inferParquetType(rows []map[string]interface{}, col string) ParquetType {
+	bools, ints, numbers, values := true, true, true, 0
+	for _, row := range rows {
+		v := row[col]
+		if v == nil {
+			continue
+		}
+		values++
+		_, isBool := v.(bool)
+		f, isNumber := numberValue(v)
+		bools = bools && isBool
+		numbers = numbers && isNumber
+		ints = ints && isNumber && parquetInteger(v, f)
+	}
+	switch {
+	case values == 0:
+		return ParquetString
+	case bools:
+		return ParquetBoolean
+	case ints:
+		return ParquetInt64
+	case numbers:
+		return ParquetDouble
+	}
+	return ParquetString
+}
+
+// parquetInteger reports whether a number fits an int64 exactly
+func parquetInteger(v interface{}, f float64) bool {
+	if s, ok := v.(interface{ String() string }); ok {
+		_, err := strconv.ParseInt(s.String(), 10, 64)
+		return err == nil
+	}
+	return f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64
+}
+
+// encodeParquetColumn returns the PLAIN values and RLE definition levels of
+// a column
+func encodeParquetColumn(rows []map[string]interface{}, col string, typ ParquetType) (values, defs []byte, err error) {
+	levels := make([]bool, len(rows))
+	var bits []bool
+	for i, row := range rows {
+		v := row[col]
+		if v == nil {
+			continue
+		}
+		levels[i] = true
+		mismatch := func() error {
+			return fmt.Errorf("%w: column %s row %d: cannot store %s as %s", ErrTypeMismatch, col, i, JSONValue{data: v}.Type(), parquetTypeName(typ))
+		}
+
+		switch typ {
+		case ParquetBoolean:
+			b, ok := v.(bool)
+			if !ok {
+				return nil, nil, mismatch()
+			}
+			bits = append(bits, b)
+		case ParquetInt64:
+			f, ok := numberValue(v)
+			if !ok || !parquetInteger(v, f) {
+				return nil, nil, mismatch()
+			}
+			n := int64(f)
+			if s, ok := v.(interface{ String() string }); ok {
+				n, _ = strconv.ParseInt(s.String(), 10, 64)
+			}
+			values = binary.LittleEndian.AppendUint64(values, uint64(n))
+		case ParquetDouble:
+			f, ok := numberValue(v)
+			if !ok {
+				return nil, nil, mismatch()
+			}
+			values = binary.LittleEndian.AppendUint64(values, math.Float64bits(f))
+		default:
+			s, err := csvCell(v)
+			if err != nil {
+				return nil, nil, err
+			}
+			values = binary.LittleEndian.AppendUint32(values, uint32(len(s)))
+			values = append(values, s...)
+		}
+	}
+
+	// Booleans are bit-packed, least significant bit first
+	if typ == ParquetBoolean {
+		values = make([]byte, (len(bits)+7)/8)
+		for i, b := range bits {
+			if b {
+				values[i/8] |= 1 << (i % 8)
+			}
+		}
+	}
+
+	// Definition levels as RLE runs of bit width 1
+	for i := 0; i < len(levels); {
+		run := 1
+		for i+run < len(levels) && levels[i+run] == levels[i] {
+			run++
+		}
+		defs = binary.AppendUvarint(defs, uint64(run)<<1)
+		if levels[i] {
+			defs = append(defs, 1)
+		} else {
+			defs = append(defs, 0)
+		}
+		i += run
+	}
+	return values, defs, nil
+}
+
+func parquetTypeName(typ ParquetType) string {
+	switch typ {
+	case ParquetBoolean:
+		return "boolean"
+	case ParquetInt64:
+		return "int64"
+	case ParquetDouble:
+		return "double"
+	}
+	return "string"
+}
+
+// -------------------- Thrift Compact Protocol --------------------
+
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter appends Thrift compact protocol values. Field ids are delta
+// encoded against the previous field of the same struct.
+type thriftWriter struct {
+	b      []byte
+	last   int
+	parent []int
+}
+
+func (t *thriftWriter) field(id int, typ byte) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.b = append(t.b, byte(delta<<4)|typ)
+	} else {
+		t.b = append(t.b, typ)
+		t.b = binary.AppendVarint(t.b, int64(id))
+	}
+	t.last = id
+}
+
+// push starts a nested struct, pop ends it
+func (t *thriftWriter) push() {
+	t.parent = append(t.parent, t.last)
+	t.last = 0
+}
+
+func (t *thriftWriter) pop() {
+	t.stop()
+	t.last = t.parent[len(t.parent)-1]
+	t.parent = t.parent[:len(t.parent)-1]
+}
+
+func (t *thriftWriter) stop() {
+	t.b = append(t.b, 0)
+}
+
+func (t *thriftWriter) i32(v int32) {
+	t.b = binary.AppendVarint(t.b, int64(v))
+}
+
+func (t *thriftWriter) i64(v int64) {
+	t.b = binary.AppendVarint(t.b, v)
+}
+
+func (t *thriftWriter) binary(s string) {
+	t.b = binary.AppendUvarint(t.b, uint64(len(s)))
+	t.b = append(t.b, s...)
+}
+
+func (t *thriftWriter) listHeader(elem byte, n int) {
+	if n < 15 {
+		t.b = append(t.b, byte(n<<4)|elem)
+		return
+	}
+	t.b = append(t.b, 0xF0|elem)
+	t.b = binary.AppendUvarint(t.b, uint64(n))
+}
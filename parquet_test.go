@@ -0,0 +1,165 @@
+package jsjson_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+// thrift decodes Thrift compact protocol values: structs become maps keyed
+// by field id, lists slices, integers int64 and binaries strings
+type thrift struct {
+	b   []byte
+	pos int
+}
+
+func (t *thrift) uvarint() uint64 {
+	v, n := binary.Uvarint(t.b[t.pos:])
+	t.pos += n
+	return v
+}
+
+func (t *thrift) value(typ byte) interface{} {
+	switch typ {
+	case 1, 2:
+		return typ == 1
+	case 3:
+		t.pos++
+		return int64(t.b[t.pos-1])
+	case 4, 5, 6:
+		v, n := binary.Varint(t.b[t.pos:])
+		t.pos += n
+		return v
+	case 8:
+		n := int(t.uvarint())
+		t.pos += n
+		return string(t.b[t.pos-n : t.pos])
+	case 9:
+		h := t.b[t.pos]
+		t.pos++
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(t.uvarint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = t.value(h & 0x0f)
+		}
+		return list
+	case 12:
+		fields := map[int]interface{}{}
+		last := 0
+		for {
+			h := t.b[t.pos]
+			t.pos++
+			if h == 0 {
+				return fields
+			}
+			id := last + int(h>>4)
+			if h>>4 == 0 {
+				v, n := binary.Varint(t.b[t.pos:])
+				t.pos += n
+				id = int(v)
+			}
+			fields[id] = t.value(h & 0x0f)
+			last = id
+		}
+	}
+	panic("unsupported thrift type")
+}
+
+func TestToParquet(t *testing.T) {
+	input := JSON.Parse(`[
+		{"id": 1, "name": "Ada", "score": 9.5, "active": true, "address": {"city": "London"}, "tags": ["a"]},
+		{"id": 12345678901234567, "name": null, "score": 7, "active": false},
+		{"id": 3, "name": "Grace", "active": true, "address": {"city": "NYC"}}
+	]`, JSON.ParseWithNumbers())
+
+	var buf bytes.Buffer
+	if err := input.ToParquet(&buf, JSON.ParquetOptions{}); err != nil {
+		t.Fatalf("ToParquet failed: %v", err)
+	}
+	file := buf.Bytes()
+	if string(file[:4]) != "PAR1" || string(file[len(file)-4:]) != "PAR1" {
+		t.Fatalf("Missing magic bytes")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := (&thrift{b: file[len(file)-8-footerLen : len(file)-8]}).value(12).(map[int]interface{})
+
+	if footer[3] != int64(3) {
+		t.Errorf("Expected 3 rows, got %v", footer[3])
+	}
+	schema := footer[2].([]interface{})
+	wantColumns := []struct {
+		name     string
+		physical int64
+	}{
+		{"active", 0}, {"address.city", 6}, {"id", 2}, {"name", 6}, {"score", 5}, {"tags", 6},
+	}
+	if len(schema) != len(wantColumns)+1 || schema[0].(map[int]interface{})[5] != int64(len(wantColumns)) {
+		t.Fatalf("Unexpected schema %v", schema)
+	}
+	for i, want := range wantColumns {
+		el := schema[i+1].(map[int]interface{})
+		if el[4] != want.name || el[1] != want.physical || el[3] != int64(1) {
+			t.Errorf("Column %d: expected %s of type %d, got %v", i, want.name, want.physical, el)
+		}
+	}
+
+	// Read each column's page back
+	chunks := footer[4].([]interface{})[0].(map[int]interface{})[1].([]interface{})
+	page := func(col int) (defs []byte, values []byte) {
+		meta := chunks[col].(map[int]interface{})[3].(map[int]interface{})
+		r := &thrift{b: file, pos: int(meta[9].(int64))}
+		header := r.value(12).(map[int]interface{})
+		body := file[r.pos : r.pos+int(header[3].(int64))]
+		n := binary.LittleEndian.Uint32(body)
+		return body[4 : 4+n], body[4+n:]
+	}
+
+	if defs, values := page(2); !bytes.Equal(defs, []byte{6, 1}) ||
+		int64(binary.LittleEndian.Uint64(values[8:])) != 12345678901234567 {
+		t.Errorf("Unexpected id page %v %v", defs, values)
+	}
+	if defs, values := page(3); !bytes.Equal(defs, []byte{2, 1, 2, 0, 2, 1}) || string(values) != "\x03\x00\x00\x00Ada\x05\x00\x00\x00Grace" {
+		t.Errorf("Unexpected name page %v %q", defs, values)
+	}
+	if _, values := page(0); !bytes.Equal(values, []byte{0b101}) {
+		t.Errorf("Unexpected boolean page %v", values)
+	}
+	if _, values := page(4); math.Float64frombits(binary.LittleEndian.Uint64(values)) != 9.5 {
+		t.Errorf("Unexpected double page %v", values)
+	}
+	if _, values := page(5); string(values) != "\x05\x00\x00\x00[\"a\"]" {
+		t.Errorf("Unexpected JSON text page %q", values)
+	}
+}
+
+func TestToParquetOptions(t *testing.T) {
+	input := JSON.Parse(`[{"a": 1, "b": "x"}, {"a": 2.5, "b": "y"}]`)
+
+	var buf bytes.Buffer
+	opts := JSON.ParquetOptions{Columns: []string{"b", "a"}, Types: map[string]JSON.ParquetType{"a": JSON.ParquetString}}
+	if err := input.ToParquet(&buf, opts); err != nil {
+		t.Fatalf("ToParquet failed: %v", err)
+	}
+	file := buf.Bytes()
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := (&thrift{b: file[len(file)-8-footerLen : len(file)-8]}).value(12).(map[int]interface{})
+	schema := footer[2].([]interface{})
+	if schema[1].(map[int]interface{})[4] != "b" || schema[2].(map[int]interface{})[1] != int64(6) {
+		t.Errorf("Options not applied: %v", schema)
+	}
+
+	err := input.ToParquet(&buf, JSON.ParquetOptions{Types: map[string]JSON.ParquetType{"a": JSON.ParquetInt64}})
+	if !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for 2.5 as int64, got %v", err)
+	}
+	if err := JSON.Parse(`[1]`).ToParquet(&buf, JSON.ParquetOptions{}); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch for a scalar row, got %v", err)
+	}
+}
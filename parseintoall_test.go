@@ -0,0 +1,106 @@
+package jsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestParseIntoAll(t *testing.T) {
+	type summary struct {
+		ID    int64  `json:"id"`
+		Title string `json:"title"`
+	}
+	type details struct {
+		Tags  []string               `json:"tags"`
+		Extra map[string]interface{} `json:"extra"`
+	}
+	input := `{"id":9007199254740993,"title":"Report","tags":["a","b"],"extra":{"n":1}}`
+
+	for name, data := range map[string]interface{}{
+		"string":    input,
+		"bytes":     []byte(input),
+		"JSONValue": JSON.Parse(input),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var s summary
+			var d details
+			var raw map[string]interface{}
+			if err := JSON.ParseIntoAll(data, &s, &d, &raw); err != nil {
+				t.Fatalf("ParseIntoAll failed: %v", err)
+			}
+			if s.Title != "Report" || !reflect.DeepEqual(d.Tags, []string{"a", "b"}) || raw["title"] != "Report" {
+				t.Errorf("Unexpected destinations: %+v %+v %v", s, d, raw)
+			}
+
+			// Destinations do not share containers
+			d.Extra["n"] = 2.0
+			if got := raw["extra"].(map[string]interface{})["n"]; got != 1.0 {
+				t.Errorf("Expected independent maps, got %v", got)
+			}
+		})
+	}
+
+	t.Run("same as ParseInto", func(t *testing.T) {
+		var all, single summary
+		var raw map[string]interface{}
+		if err := JSON.ParseIntoAll(input, &all, &raw); err != nil {
+			t.Fatal(err)
+		}
+		if err := JSON.ParseInto(input, &single); err != nil {
+			t.Fatal(err)
+		}
+		if all != single {
+			t.Errorf("Expected %+v, got %+v", single, all)
+		}
+		if _, ok := raw["id"].(float64); !ok {
+			t.Errorf("Expected float64 in the map, got %T", raw["id"])
+		}
+	})
+
+	t.Run("numbers option", func(t *testing.T) {
+		var raw map[string]interface{}
+		if err := JSON.ParseIntoAll(input, &raw, JSON.ParseWithNumbers()); err != nil {
+			t.Fatal(err)
+		}
+		if n, ok := raw["id"].(json.Number); !ok || n != "9007199254740993" {
+			t.Errorf("Expected json.Number literal, got %#v", raw["id"])
+		}
+	})
+}
+
+func TestParseIntoAllErrors(t *testing.T) {
+	type counter struct {
+		Count int `json:"count"`
+	}
+	type named struct {
+		Name string `json:"name" jsjson:"required"`
+	}
+	var c counter
+	var n named
+	var raw map[string]interface{}
+
+	if err := JSON.ParseIntoAll(`{"count":1}`); err == nil {
+		t.Error("Expected error without destinations")
+	}
+	if err := JSON.ParseIntoAll(`{"count":1}`, c); err == nil {
+		t.Error("Expected error for non-pointer destination")
+	}
+	err := JSON.ParseIntoAll(`{"count":"x"}`, &raw, &c)
+	if err == nil || !strings.Contains(err.Error(), "destination 1") {
+		t.Errorf("Expected the failing destination to be named, got %v", err)
+	}
+	if err := JSON.ParseIntoAll(`{"count":1}`, &c, &n); !errors.Is(err, JSON.ErrValidation) {
+		t.Errorf("Expected struct tags to be validated, got %v", err)
+	}
+	if err := JSON.ParseIntoAll(`{"count":1, "pad":"xxxxxxxx"}`, &c, JSON.WithMaxBytes(10)); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected options among the destinations to apply, got %v", err)
+	}
+	if err := JSON.ParseIntoAll(`{"count":`, &c); !errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Expected syntax error, got %v", err)
+	}
+}
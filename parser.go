@@ -0,0 +1,564 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// maxParseDepth matches encoding/json's nesting limit
+const maxParseDepth = 10000
+
+// ParseNoCopy parses b without copying it: strings and (with
+// ParseWithNumbers) number literals in the result point directly into b.
+// This avoids a copy per string for large request bodies, but b is borrowed
+// for as long as the result, or any string taken from it, is in use; it must
+// not be modified or returned to a pool until then. Use Parse when the
+// buffer is reused.
+func ParseNoCopy(b []byte, opts ...ParseOption) JSONValue {
+	if len(b) == 0 {
+		return JSONValue{err: &JSONError{Op: "ParseNoCopy", Err: fmt.Errorf("%w: empty byte slice", ErrSyntax)}}
+	}
+
+	o := newParseOptions(opts)
+	b, err := prepareText(b, &o)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseNoCopy", Err: err}}
+	}
+	if err := checkLimits(b, &o); err != nil {
+		return JSONValue{err: &JSONError{Op: "ParseNoCopy", Err: err}}
+	}
+	if o.useArena {
+		o.arena = getArena()
+	}
+	result, err := parseTree(b, &o, true)
+	if err != nil {
+		o.arena.discard()
+		return JSONValue{err: &JSONError{Op: "ParseNoCopy", Err: err}}
+	}
+	return JSONValue{data: result, raw: newRawRef(b, &o), arena: o.arena.handle()}
+}
+
+// parser decodes JSON text into the generic tree in a single pass. Its output
+// matches encoding/json: float64 numbers (json.Number with ParseWithNumbers),
+// last-wins duplicate keys and invalid UTF-8 replaced with U+FFFD.
+type parser struct {
+	data      []byte
+	pos       int
+	depth     int
+	useNumber bool
+	noCopy    bool
+	nonFinite bool // accept NaN, Infinity and -Infinity
+
+	// Container members are collected on stack/keys so arrays and maps are
+	// created at their final size; arena mode allocates from arena
+	arena   *arena
+	stack   []interface{}
+	keys    []string
+	scratch []byte
+
+	interner *interner
+}
+
+// parseTree parses a complete JSON document, allocating from o.arena if set
+func parseTree(data []byte, o *parseOptions, noCopy bool) (interface{}, error) {
+	p := parserPool.Get().(*parser)
+	defer putParser(p)
+	*p = parser{
+		data:      data,
+		useNumber: o.useNumber,
+		noCopy:    noCopy,
+		nonFinite: o.nonFinite,
+		arena:     o.arena,
+		interner:  o.interner,
+		stack:     p.stack,
+		keys:      p.keys,
+		scratch:   p.scratch,
+	}
+
+	p.skipSpace()
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.data) {
+		return nil, p.unexpected("after top-level value")
+	}
+	return v, nil
+}
+
+// parserPool recycles parsers so their stacks and scratch buffer are reused
+var parserPool = sync.Pool{
+	New: func() interface{} { return new(parser) },
+}
+
+// maxPooledStack bounds the stack capacity kept by a pooled parser
+const maxPooledStack = 1 << 16
+
+// putParser drops the parser's references to the document and returns it to
+// the pool. The stacks are empty after a successful parse but not after an
+// error, so they are cleared here.
+func putParser(p *parser) {
+	if cap(p.stack) > maxPooledStack || cap(p.keys) > maxPooledStack {
+		return
+	}
+	clear(p.stack)
+	clear(p.keys)
+	*p = parser{stack: p.stack[:0], keys: p.keys[:0], scratch: p.scratch[:0]}
+	parserPool.Put(p)
+}
+
+// unexpected reports the byte at the current position (or the end of input)
+func (p *parser) unexpected(context string) error {
+	if p.pos >= len(p.data) {
+		return newSyntaxError(p.data, len(p.data), "unexpected end of input", nil)
+	}
+	return newSyntaxError(p.data, p.pos, fmt.Sprintf("invalid character %q %s", p.data[p.pos], context), nil)
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the current byte, or 0 at the end of input
+func (p *parser) peek() byte {
+	if p.pos < len(p.data) {
+		return p.data[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) value() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '"':
+		s, err := p.stringValue()
+		if err != nil || p.arena == nil {
+			return s, err
+		}
+		return p.arena.boxString(stringType, s), nil
+	case c == 't':
+		return p.literal("true", true)
+	case c == 'f':
+		return p.literal("false", false)
+	case c == 'n':
+		return p.literal("null", nil)
+	case c == '-' || (c >= '0' && c <= '9'):
+		if p.nonFinite && c == '-' && p.pos+1 < len(p.data) && p.data[p.pos+1] == 'I' {
+			return p.literal("-Infinity", p.float(math.Inf(-1)))
+		}
+		return p.number()
+	case c == 'N' && p.nonFinite:
+		return p.literal("NaN", p.float(math.NaN()))
+	case c == 'I' && p.nonFinite:
+		return p.literal("Infinity", p.float(math.Inf(1)))
+	default:
+		return nil, p.unexpected("looking for beginning of value")
+	}
+}
+
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxParseDepth {
+		return fmt.Errorf("%w: nesting deeper than %d", ErrLimitExceeded, maxParseDepth)
+	}
+	return nil
+}
+
+// object parses the members onto the key/value stacks, then builds the map
+// at its exact size so it never grows while parsing
+func (p *parser) object() (interface{}, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	p.pos++ // '{'
+	base, keyBase := len(p.stack), len(p.keys)
+
+	p.skipSpace()
+	if p.peek() != '}' {
+		for {
+			if p.peek() != '"' {
+				return nil, p.unexpected("looking for beginning of object key string")
+			}
+			key, err := p.stringValue()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if p.peek() != ':' {
+				return nil, p.unexpected("after object key")
+			}
+			p.pos++
+			p.skipSpace()
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			p.keys = append(p.keys, key)
+			p.stack = append(p.stack, v)
+
+			p.skipSpace()
+			if p.peek() == ',' {
+				p.pos++
+				p.skipSpace()
+				continue
+			}
+			if p.peek() != '}' {
+				return nil, p.unexpected("after object key:value pair")
+			}
+			break
+		}
+	}
+	p.pos++
+	p.depth--
+
+	keys, values := p.keys[keyBase:], p.stack[base:]
+	obj := make(map[string]interface{}, len(keys))
+	for i, key := range keys {
+		obj[key] = values[i]
+	}
+	clear(values)
+	p.keys, p.stack = p.keys[:keyBase], p.stack[:base]
+	return obj, nil
+}
+
+// array parses the elements onto the stack, then copies them into storage
+// of the exact length (from the arena in arena mode)
+func (p *parser) array() (interface{}, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	p.pos++ // '['
+	base := len(p.stack)
+
+	p.skipSpace()
+	if p.peek() != ']' {
+		for {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			p.stack = append(p.stack, v)
+
+			p.skipSpace()
+			if p.peek() == ',' {
+				p.pos++
+				p.skipSpace()
+				continue
+			}
+			if p.peek() != ']' {
+				return nil, p.unexpected("after array element")
+			}
+			break
+		}
+	}
+	p.pos++
+	p.depth--
+
+	values := p.stack[base:]
+	if p.arena != nil {
+		arr := p.arena.ifaces.alloc(len(values))
+		copy(arr, values)
+		clear(values)
+		p.stack = p.stack[:base]
+		return p.arena.boxArray(arr), nil
+	}
+	arr := make([]interface{}, len(values))
+	copy(arr, values)
+	clear(values)
+	p.stack = p.stack[:base]
+	return arr, nil
+}
+
+func (p *parser) literal(word string, v interface{}) (interface{}, error) {
+	end := p.pos + len(word)
+	if end > len(p.data) || string(p.data[p.pos:end]) != word {
+		// Point at the first mismatching byte
+		for i := 0; i < len(word) && p.pos < len(p.data) && p.data[p.pos] == word[i]; i++ {
+			p.pos++
+		}
+		return nil, p.unexpected("in literal " + word)
+	}
+	p.pos = end
+	return v, nil
+}
+
+func (p *parser) number() (interface{}, error) {
+	start := p.pos
+	i := p.pos
+	if p.data[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(p.data) && p.data[i] == '0':
+		i++
+	case i < len(p.data) && p.data[i] >= '1' && p.data[i] <= '9':
+		for i < len(p.data) && p.data[i] >= '0' && p.data[i] <= '9' {
+			i++
+		}
+	default:
+		p.pos = i
+		return nil, p.unexpected("in numeric literal")
+	}
+
+	isInt := true
+	if i < len(p.data) && p.data[i] == '.' {
+		isInt = false
+		i++
+		if i >= len(p.data) || p.data[i] < '0' || p.data[i] > '9' {
+			p.pos = i
+			return nil, p.unexpected("after decimal point in numeric literal")
+		}
+		for i < len(p.data) && p.data[i] >= '0' && p.data[i] <= '9' {
+			i++
+		}
+	}
+	if i < len(p.data) && (p.data[i] == 'e' || p.data[i] == 'E') {
+		isInt = false
+		i++
+		if i < len(p.data) && (p.data[i] == '+' || p.data[i] == '-') {
+			i++
+		}
+		if i >= len(p.data) || p.data[i] < '0' || p.data[i] > '9' {
+			p.pos = i
+			return nil, p.unexpected("in exponent of numeric literal")
+		}
+		for i < len(p.data) && p.data[i] >= '0' && p.data[i] <= '9' {
+			i++
+		}
+	}
+	p.pos = i
+	lit := p.data[start:i]
+
+	if p.useNumber {
+		if p.arena != nil {
+			return p.arena.boxString(numberType, p.makeString(lit)), nil
+		}
+		return json.Number(p.makeString(lit)), nil
+	}
+	// Integers of up to 15 digits are exactly representable, so they skip
+	// the general float parser ("-0" is left to it to keep the sign)
+	if isInt && len(lit) <= 15 && string(lit) != "-0" {
+		digits, neg := lit, false
+		if digits[0] == '-' {
+			digits, neg = digits[1:], true
+		}
+		var n int64
+		for _, c := range digits {
+			n = n*10 + int64(c-'0')
+		}
+		if neg {
+			n = -n
+		} else if n < int64(len(smallInts)) {
+			return smallInts[n], nil
+		}
+		return p.float(float64(n)), nil
+	}
+	f, err := strconv.ParseFloat(unsafeString(lit), 64)
+	if err != nil {
+		return nil, &json.UnmarshalTypeError{Value: "number " + string(lit), Type: reflect.TypeOf(f), Offset: int64(start)}
+	}
+	return p.float(f), nil
+}
+
+// smallInts holds boxed float64 values for small non-negative integers
+// (counts, ids, flags), shared to avoid an allocation per occurrence
+var smallInts = func() (boxed [1024]interface{}) {
+	for i := range boxed {
+		boxed[i] = float64(i)
+	}
+	return boxed
+}()
+
+// float returns f as a tree value
+func (p *parser) float(f float64) interface{} {
+	if p.arena != nil {
+		return p.arena.boxFloat(f)
+	}
+	return f
+}
+
+// stringValue parses a string literal starting at the opening quote. Plain
+// strings (no escapes, valid UTF-8) are sliced straight from the input.
+func (p *parser) stringValue() (string, error) {
+	start := p.pos + 1
+	for i := start; i < len(p.data); {
+		c := p.data[i]
+		switch {
+		case c == '"':
+			p.pos = i + 1
+			return p.str(p.data[start:i], true), nil
+		case c == '\\' || c < 0x20:
+			return p.slowString(start, i)
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRune(p.data[i:])
+			if r == utf8.RuneError && size == 1 {
+				return p.slowString(start, i)
+			}
+			i += size
+		default:
+			i++
+		}
+	}
+	p.pos = len(p.data)
+	return "", p.unexpected("in string literal")
+}
+
+// slowString decodes a string containing escapes or invalid UTF-8; i is the
+// offset of the first byte that needs handling
+func (p *parser) slowString(start, i int) (string, error) {
+	buf := append(p.scratch[:0], p.data[start:i]...)
+	defer func() { p.scratch = buf }()
+
+	for i < len(p.data) {
+		c := p.data[i]
+		switch {
+		case c == '"':
+			p.pos = i + 1
+			// buf is reused for the next string, so it is never borrowed
+			return p.str(buf, false), nil
+		case c < 0x20:
+			p.pos = i
+			return "", p.unexpected("in string literal")
+		case c == '\\':
+			if i+1 >= len(p.data) {
+				p.pos = len(p.data)
+				return "", p.unexpected("in string escape code")
+			}
+			switch e := p.data[i+1]; e {
+			case '"', '\\', '/':
+				buf = append(buf, e)
+				i += 2
+			case 'b':
+				buf = append(buf, '\b')
+				i += 2
+			case 'f':
+				buf = append(buf, '\f')
+				i += 2
+			case 'n':
+				buf = append(buf, '\n')
+				i += 2
+			case 'r':
+				buf = append(buf, '\r')
+				i += 2
+			case 't':
+				buf = append(buf, '\t')
+				i += 2
+			case 'u':
+				r := hex4(p.data, i+2)
+				if r < 0 {
+					p.pos = i + 2
+					return "", p.unexpected("in \\u hexadecimal character escape")
+				}
+				i += 6
+				if utf16.IsSurrogate(r) {
+					// A valid pair is combined; a lone surrogate becomes U+FFFD
+					// and the following escape is decoded on its own
+					if i+1 < len(p.data) && p.data[i] == '\\' && p.data[i+1] == 'u' {
+						if dec := utf16.DecodeRune(r, hex4(p.data, i+2)); dec != unicode.ReplacementChar {
+							buf = utf8.AppendRune(buf, dec)
+							i += 6
+							continue
+						}
+					}
+					r = unicode.ReplacementChar
+				}
+				buf = utf8.AppendRune(buf, r)
+			default:
+				p.pos = i + 1
+				return "", p.unexpected("in string escape code")
+			}
+		case c < utf8.RuneSelf:
+			buf = append(buf, c)
+			i++
+		default:
+			r, size := utf8.DecodeRune(p.data[i:])
+			if r == utf8.RuneError && size == 1 {
+				buf = utf8.AppendRune(buf, unicode.ReplacementChar)
+			} else {
+				buf = append(buf, p.data[i:i+size]...)
+			}
+			i += size
+		}
+	}
+	p.pos = len(p.data)
+	return "", p.unexpected("in string literal")
+}
+
+// hex4 decodes the four hex digits at data[i:], or returns -1
+func hex4(data []byte, i int) rune {
+	if i+4 > len(data) {
+		return -1
+	}
+	var r rune
+	for _, c := range data[i : i+4] {
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return -1
+		}
+		r = r*16 + rune(c)
+	}
+	return r
+}
+
+// str returns decoded string bytes as a string, interning short strings when
+// enabled. Interned strings are always heap copies, since the table can
+// outlive both the input buffer and the arena. borrowable is false when b is
+// scratch space.
+func (p *parser) str(b []byte, borrowable bool) string {
+	if p.interner != nil && len(b) <= maxInternLen {
+		return p.interner.intern(b)
+	}
+	if borrowable {
+		return p.makeString(b)
+	}
+	if p.arena != nil {
+		return p.arena.copyString(b)
+	}
+	return string(b)
+}
+
+// makeString returns b as a string, borrowing the input in no-copy mode
+func (p *parser) makeString(b []byte) string {
+	switch {
+	case p.noCopy:
+		return unsafeString(b)
+	case p.arena != nil:
+		return p.arena.copyString(b)
+	default:
+		return string(b)
+	}
+}
+
+// unsafeString converts b to a string without copying; b must not change
+// while the string is in use
+func unsafeString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
@@ -0,0 +1,165 @@
+package jsjson_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+	"unsafe"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+// parserCorpus holds documents the custom parser must decode exactly like
+// encoding/json
+var parserCorpus = []string{
+	`null`, `true`, `false`, `0`, `-0`, `42`, `-17`, `3.25`, `1e3`, `-2.5E-3`,
+	`123456789012345`, `1234567890123456789`, `9007199254740993`, `0.1`,
+	`""`, `"plain"`, `"café \"quoted\" \\ \/ \b\f\n\r\t"`, `"😀 😀"`,
+	`"lone \ud800 surrogate"`, `"bad pair \ud800A"`, "\"invalid \xff utf8\"", `"日本語"`,
+	`[]`, `{}`, `[1, "two", [3], {"four": 4}]`, ` { "a" : { "b" : [ null , true ] } } `,
+	`{"dup": 1, "dup": 2}`, `{"":""}`,
+}
+
+func TestParseNoCopyMatchesParse(t *testing.T) {
+	for _, input := range parserCorpus {
+		t.Run(input, func(t *testing.T) {
+			want := JSON.Parse(input)
+			got := JSON.ParseNoCopy([]byte(input))
+			if want.Error() != nil || got.Error() != nil {
+				t.Fatalf("Unexpected errors: Parse %v, ParseNoCopy %v", want.Error(), got.Error())
+			}
+			if !reflect.DeepEqual(got.Raw(), want.Raw()) {
+				t.Errorf("Expected %#v, got %#v", want.Raw(), got.Raw())
+			}
+
+			wantNum := JSON.Parse(input, JSON.ParseWithNumbers())
+			gotNum := JSON.ParseNoCopy([]byte(input), JSON.ParseWithNumbers())
+			if !reflect.DeepEqual(gotNum.Raw(), wantNum.Raw()) {
+				t.Errorf("With numbers: expected %#v, got %#v", wantNum.Raw(), gotNum.Raw())
+			}
+		})
+	}
+}
+
+func TestParseNoCopyErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{``, JSON.ErrSyntax},
+		{`   `, JSON.ErrSyntax},
+		{`{`, JSON.ErrSyntax},
+		{`{"a" 1}`, JSON.ErrSyntax},
+		{`{"a":1,}`, JSON.ErrSyntax},
+		{`[1,]`, JSON.ErrSyntax},
+		{`[1 2]`, JSON.ErrSyntax},
+		{`01`, JSON.ErrSyntax},
+		{`1.`, JSON.ErrSyntax},
+		{`1e`, JSON.ErrSyntax},
+		{`-`, JSON.ErrSyntax},
+		{`tru`, JSON.ErrSyntax},
+		{`nul1`, JSON.ErrSyntax},
+		{`"unterminated`, JSON.ErrSyntax},
+		{"\"tab\there\"", JSON.ErrSyntax},
+		{`"bad \x escape"`, JSON.ErrSyntax},
+		{`"bad \u12g4"`, JSON.ErrSyntax},
+		{`{} {}`, JSON.ErrSyntax},
+		{strings.Repeat("[", 10001) + strings.Repeat("]", 10001), JSON.ErrLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		name := tt.input
+		if len(name) > 20 {
+			name = name[:20]
+		}
+		t.Run(name, func(t *testing.T) {
+			err := JSON.ParseNoCopy([]byte(tt.input)).Error()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// Out-of-range numbers are type errors, as with encoding/json
+	if err := JSON.ParseNoCopy([]byte(`1e400`)).Error(); err == nil || errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Expected a range error, got %v", err)
+	}
+}
+
+func TestParseNoCopyBorrowsInput(t *testing.T) {
+	buf := []byte(`{"name":"alice"}`)
+	v := JSON.ParseNoCopy(buf)
+	name := v.Get("name").StringOr("")
+	if name != "alice" {
+		t.Fatalf("Expected alice, got %q", name)
+	}
+
+	if unsafe.StringData(name) != &buf[9] {
+		t.Error("Expected the string to borrow the input buffer")
+	}
+
+	// Limits still apply
+	if err := JSON.ParseNoCopy([]byte(`[1,2,3]`), JSON.WithMaxArrayElements(2)).Error(); !errors.Is(err, JSON.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got %v", err)
+	}
+}
+
+// FuzzParse checks that Parse accepts exactly what encoding/json accepts and
+// builds the same tree. Input Parse reads as UTF-16 or with a byte order
+// mark is transcoded for encoding/json first.
+func FuzzParse(f *testing.F) {
+	for _, input := range parserCorpus {
+		f.Add([]byte(input))
+	}
+	f.Add([]byte("0\x00"))
+	f.Add([]byte("\xef\xbb\xbf[1]"))
+	f.Add([]byte("[\x001\x00]\x00"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var want interface{}
+		text, wantErr := fuzzText(data)
+		if wantErr == nil {
+			wantErr = json.Unmarshal(text, &want)
+		}
+		got := JSON.Parse(data)
+		if (wantErr != nil) != (got.Error() != nil) {
+			t.Fatalf("encoding/json error %v, Parse error %v", wantErr, got.Error())
+		}
+		if wantErr == nil && !reflect.DeepEqual(got.Raw(), want) {
+			t.Fatalf("Expected %#v, got %#v", want, got.Raw())
+		}
+	})
+}
+
+// fuzzText returns data as the UTF-8 text Parse reads, following the rules
+// in textencoding.go: a UTF-8 or UTF-16 byte order mark, or the zero bytes
+// of two ASCII characters in UTF-16
+func fuzzText(data []byte) ([]byte, error) {
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(data, []byte("\xef\xbb\xbf")):
+		return data[3:], nil
+	case bytes.HasPrefix(data, []byte{0xfe, 0xff}):
+		order, data = binary.BigEndian, data[2:]
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe}):
+		order, data = binary.LittleEndian, data[2:]
+	case len(data) >= 4 && data[0] == 0 && data[1] != 0 && data[2] == 0 && data[3] != 0:
+		order = binary.BigEndian
+	case len(data) >= 4 && data[0] != 0 && data[1] == 0 && data[2] != 0 && data[3] == 0:
+		order = binary.LittleEndian
+	default:
+		return data, nil
+	}
+	if len(data)%2 != 0 {
+		return nil, errors.New("odd number of bytes")
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return []byte(string(utf16.Decode(units))), nil
+}
@@ -0,0 +1,311 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// -------------------- JSON Patch (RFC 6902) --------------------
+
+// PatchOperation is a single RFC 6902 JSON Patch operation
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Patch is an ordered list of RFC 6902 operations
+type Patch []PatchOperation
+
+// MarshalJSON always emits "value" for operations that require it, even when
+// the value is null
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	switch op.Op {
+	case "add", "replace", "test":
+		return json.Marshal(struct {
+			Op    string      `json:"op"`
+			Path  string      `json:"path"`
+			Value interface{} `json:"value"`
+		}{op.Op, op.Path, op.Value})
+	case "move", "copy":
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			From string `json:"from"`
+			Path string `json:"path"`
+		}{op.Op, op.From, op.Path})
+	default:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+}
+
+// ParsePatch parses an RFC 6902 patch document from any input accepted by Parse
+func ParsePatch(v interface{}) (Patch, error) {
+	var p Patch
+	if err := ParseInto(v, &p); err != nil {
+		return nil, &JSONError{Op: "ParsePatch", Err: err}
+	}
+	return p, nil
+}
+
+// ApplyPatch applies an RFC 6902 patch and returns the resulting document.
+// The receiver is left unchanged; operations are applied atomically, so on
+// error no partial result is returned.
+func (j JSONValue) ApplyPatch(p Patch) JSONValue {
+	if j.err != nil {
+		return j
+	}
+
+	doc, err := applyPatch(deepCopy(j.data), p)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "ApplyPatch", Err: err}}
+	}
+	return JSONValue{data: doc}
+}
+
+// applyPatch applies p to doc in place, returning the new root
+func applyPatch(doc interface{}, p Patch) (interface{}, error) {
+	for i, op := range p {
+		var err error
+		doc, err = applyOperation(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// applyOperation applies a single operation to doc in place
+func applyOperation(doc interface{}, op PatchOperation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		value, err := normalize(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+
+	case "remove":
+		doc, _, err = pointerRemove(doc, path)
+		return doc, err
+
+	case "replace":
+		value, err := normalize(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, _, err = pointerRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+
+	case "move":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %q into its own child", op.From)
+		}
+		doc, value, err := pointerRemove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+
+	case "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := pointerGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, deepCopy(value))
+
+	case "test":
+		value, err := normalize(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		current, err := pointerGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(current, value) {
+			return nil, fmt.Errorf("test failed: value at %q does not match", op.Path)
+		}
+		return doc, nil
+
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// -------------------- JSON Pointer (RFC 6901) --------------------
+
+// parsePointer splits a JSON Pointer into its unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// pointerIndex parses an array reference token; "-" refers to the end of the
+// array and is only accepted when allowEnd is set
+func pointerIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	limit := length
+	if allowEnd {
+		limit++
+	}
+	if idx >= limit {
+		return 0, fmt.Errorf("array index %d out of bounds (length: %d)", idx, length)
+	}
+	return idx, nil
+}
+
+// pointerGet returns the value referenced by path
+func pointerGet(doc interface{}, path []string) (interface{}, error) {
+	current := doc
+	for _, token := range path {
+		switch c := current.(type) {
+		case map[string]interface{}:
+			v, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", token)
+			}
+			current = v
+		case []interface{}:
+			idx, err := pointerIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			current = c[idx]
+		default:
+			return nil, fmt.Errorf("cannot access %q on type %T", token, current)
+		}
+	}
+	return current, nil
+}
+
+// pointerUpdate replaces the container holding the last token of path with
+// the result of fn, rebuilding slices on the way back up
+func pointerUpdate(doc interface{}, path []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	switch c := doc.(type) {
+	case map[string]interface{}:
+		child, ok := c[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", path[0])
+		}
+		updated, err := pointerUpdate(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		c[path[0]] = updated
+		return c, nil
+	case []interface{}:
+		idx, err := pointerIndex(path[0], len(c), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := pointerUpdate(c[idx], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		c[idx] = updated
+		return c, nil
+	default:
+		return nil, fmt.Errorf("cannot access %q on type %T", path[0], doc)
+	}
+}
+
+// pointerAdd inserts value at path following RFC 6902 "add" semantics
+func pointerAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return pointerUpdate(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch c := parent.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			idx, err := pointerIndex(token, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[idx+1:], c[idx:])
+			c[idx] = value
+			return c, nil
+		default:
+			return nil, fmt.Errorf("cannot add %q to type %T", token, parent)
+		}
+	})
+}
+
+// pointerRemove deletes the value at path and returns it
+func pointerRemove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the document root")
+	}
+	var removed interface{}
+	doc, err := pointerUpdate(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch c := parent.(type) {
+		case map[string]interface{}:
+			v, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", token)
+			}
+			removed = v
+			delete(c, token)
+			return c, nil
+		case []interface{}:
+			idx, err := pointerIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = c[idx]
+			return append(c[:idx], c[idx+1:]...), nil
+		default:
+			return nil, fmt.Errorf("cannot remove %q from type %T", token, parent)
+		}
+	})
+	return doc, removed, err
+}
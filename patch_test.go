@@ -0,0 +1,97 @@
+package jsjson_test
+
+import (
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr bool
+	}{
+		{"add object member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`, false},
+		{"add array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`, false},
+		{"append with dash", `{"foo":[1]}`, `[{"op":"add","path":"/foo/-","value":2}]`, `{"foo":[1,2]}`, false},
+		{"remove object member", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`, false},
+		{"remove array element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`, false},
+		{"replace value", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`, false},
+		{"replace root", `{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`, false},
+		{"move value", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`, false},
+		{"move array element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`, false},
+		{"copy value", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"}]`, `{"a":{"b":1},"c":{"b":1}}`, false},
+		{"test success", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`, false},
+		{"escaped pointer", `{"a/b":1,"m~n":2}`, `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`, `{"a/b":3}`, false},
+		{"add null value", `{}`, `[{"op":"add","path":"/a","value":null}]`, `{"a":null}`, false},
+		{"test failure", `{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ``, true},
+		{"remove missing", `{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, ``, true},
+		{"add out of bounds", `{"foo":[1]}`, `[{"op":"add","path":"/foo/5","value":2}]`, ``, true},
+		{"leading zero index", `{"foo":[1,2]}`, `[{"op":"remove","path":"/foo/01"}]`, ``, true},
+		{"missing parent", `{}`, `[{"op":"add","path":"/a/b","value":1}]`, ``, true},
+		{"move into child", `{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]`, ``, true},
+		{"unknown op", `{}`, `[{"op":"frobnicate","path":"/a"}]`, ``, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := JSON.ParsePatch(tt.patch)
+			if err != nil {
+				t.Fatalf("ParsePatch failed: %v", err)
+			}
+			result := JSON.Parse(tt.doc).ApplyPatch(p)
+			if tt.wantErr {
+				if result.IsValid() {
+					t.Errorf("Expected error but got: %v", result.Raw())
+				}
+				return
+			}
+			if !result.IsValid() {
+				t.Fatalf("Expected no error but got: %v", result.Error())
+			}
+			got, _ := JSON.Stringify(result)
+			want, _ := JSON.Stringify(JSON.Parse(tt.want))
+			if got != want {
+				t.Errorf("Expected %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestApplyPatchLeavesOriginalUnchanged(t *testing.T) {
+	doc := JSON.Parse(`{"list":[1,2,3],"obj":{"a":1}}`)
+	patch := JSON.Patch{
+		{Op: "remove", Path: "/list/0"},
+		{Op: "add", Path: "/obj/b", Value: 2},
+	}
+
+	result := doc.ApplyPatch(patch)
+	if !result.IsValid() {
+		t.Fatalf("Expected no error, got: %v", result.Error())
+	}
+
+	if got, _ := JSON.Stringify(doc); got != `{"list":[1,2,3],"obj":{"a":1}}` {
+		t.Errorf("Original document was modified: %s", got)
+	}
+	if result.Get("obj", "b").IntOr(0) != 2 {
+		t.Errorf("Expected obj.b to be 2, got: %v", result.Get("obj", "b").Raw())
+	}
+}
+
+func TestApplyPatchAtomic(t *testing.T) {
+	doc := JSON.Parse(`{"a":1}`)
+	patch := JSON.Patch{
+		{Op: "replace", Path: "/a", Value: 2},
+		{Op: "test", Path: "/a", Value: 3},
+	}
+
+	if result := doc.ApplyPatch(patch); result.IsValid() {
+		t.Error("Expected failing test operation to fail the whole patch")
+	}
+	if doc.Get("a").IntOr(0) != 1 {
+		t.Errorf("Original document was modified: %v", doc.Raw())
+	}
+}
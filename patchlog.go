@@ -0,0 +1,181 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PatchLogEntry records one patch applied through a PatchLog
+type PatchLogEntry struct {
+	Seq        int       `json:"seq"`
+	ID         string    `json:"id"`          // idempotency key, empty for entries recorded by Apply
+	PatchHash  string    `json:"patch_hash"`  // SHA-256 of the canonical patch
+	ResultHash string    `json:"result_hash"` // SHA-256 of the canonical document after the patch
+	Patch      Patch     `json:"patch"`
+	AppliedAt  time.Time `json:"applied_at"`
+}
+
+// PatchLog is an append-only record of JSON Patches applied to a document.
+// Each entry is keyed by an idempotency ID so retried deliveries of the same
+// change are applied only once, and the log can be replayed against a base
+// snapshot to rebuild (and verify) the current document.
+// A PatchLog is safe for concurrent use.
+type PatchLog struct {
+	mu      sync.RWMutex
+	entries []PatchLogEntry
+	ids     map[string]int // ID -> index into entries
+}
+
+// NewPatchLog returns an empty PatchLog
+func NewPatchLog() *PatchLog {
+	return &PatchLog{ids: make(map[string]int)}
+}
+
+// Apply applies p to doc and records it without an idempotency ID. Every
+// call appends an entry, even when an identical patch was recorded before,
+// since separate events may carry the same patch; use ApplyWithID to skip
+// retried deliveries.
+func (l *PatchLog) Apply(doc JSONValue, p Patch) (result JSONValue, applied bool, err error) {
+	return l.ApplyWithID(doc, "", p)
+}
+
+// ApplyWithID is like Apply but uses the caller's idempotency ID (for example
+// an event or request ID). If the ID was already recorded with the same
+// patch, doc is returned unchanged and applied is false; if it was recorded
+// with a different patch, the conflict is reported as an error matching
+// ErrValidation. An empty id records the patch as Apply does.
+func (l *PatchLog) ApplyWithID(doc JSONValue, id string, p Patch) (result JSONValue, applied bool, err error) {
+	if doc.err != nil {
+		return doc, false, doc.err
+	}
+
+	patchHash, err := canonicalHash(p)
+	if err != nil {
+		return doc, false, &JSONError{Op: "PatchLog.Apply", Err: err}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if i, exists := l.ids[id]; exists {
+		if l.entries[i].PatchHash != patchHash {
+			return doc, false, &JSONError{Op: "PatchLog.Apply", Err: fmt.Errorf("%w: ID %q was already recorded with a different patch", ErrValidation, id)}
+		}
+		return doc, false, nil
+	}
+
+	result = doc.ApplyPatch(p)
+	if result.err != nil {
+		return doc, false, result.err
+	}
+	resultHash, err := canonicalHash(result.data)
+	if err != nil {
+		return doc, false, &JSONError{Op: "PatchLog.Apply", Err: err}
+	}
+
+	l.append(PatchLogEntry{
+		Seq:        len(l.entries) + 1,
+		ID:         id,
+		PatchHash:  patchHash,
+		ResultHash: resultHash,
+		Patch:      p,
+		AppliedAt:  time.Now().UTC(),
+	})
+	return result, true, nil
+}
+
+// append adds an entry; the caller must hold the write lock
+func (l *PatchLog) append(e PatchLogEntry) {
+	if e.ID != "" {
+		l.ids[e.ID] = len(l.entries)
+	}
+	l.entries = append(l.entries, e)
+}
+
+// Has reports whether a patch with the given idempotency ID was recorded
+func (l *PatchLog) Has(id string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, exists := l.ids[id]
+	return exists
+}
+
+// Len returns the number of recorded entries
+func (l *PatchLog) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.entries)
+}
+
+// Entries returns a copy of all recorded entries in order
+func (l *PatchLog) Entries() []PatchLogEntry {
+	return l.Since(0)
+}
+
+// Since returns a copy of the entries with a sequence number greater than seq
+func (l *PatchLog) Since(seq int) []PatchLogEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if seq < 0 {
+		seq = 0
+	}
+	if seq >= len(l.entries) {
+		return nil
+	}
+	out := make([]PatchLogEntry, len(l.entries)-seq)
+	copy(out, l.entries[seq:])
+	return out
+}
+
+// Replay re-applies every recorded patch to base, verifying after each step
+// that the document hash matches the one recorded when the patch was first
+// applied. It returns the rebuilt document.
+func (l *PatchLog) Replay(base JSONValue) (JSONValue, error) {
+	if base.err != nil {
+		return base, base.err
+	}
+
+	doc := deepCopy(base.data)
+	for _, e := range l.Entries() {
+		var err error
+		doc, err = applyPatch(doc, e.Patch)
+		if err != nil {
+			return JSONValue{}, &JSONError{Op: "PatchLog.Replay", Err: fmt.Errorf("entry %d: %w", e.Seq, err)}
+		}
+		hash, err := canonicalHash(doc)
+		if err != nil {
+			return JSONValue{}, &JSONError{Op: "PatchLog.Replay", Err: err}
+		}
+		if hash != e.ResultHash {
+			return JSONValue{}, &JSONError{Op: "PatchLog.Replay", Err: fmt.Errorf("entry %d: result hash mismatch", e.Seq)}
+		}
+	}
+	return JSONValue{data: doc}, nil
+}
+
+// MarshalJSON serializes the log as an array of entries
+func (l *PatchLog) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Entries())
+}
+
+// UnmarshalJSON restores a log previously serialized with MarshalJSON
+func (l *PatchLog) UnmarshalJSON(b []byte) error {
+	var entries []PatchLogEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return &JSONError{Op: "PatchLog.UnmarshalJSON", Err: err}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+	l.ids = make(map[string]int, len(entries))
+	for i, e := range entries {
+		if e.Seq != i+1 {
+			return &JSONError{Op: "PatchLog.UnmarshalJSON", Err: fmt.Errorf("entry %d has sequence %d", i+1, e.Seq)}
+		}
+		l.append(e)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package jsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestPatchLogIdempotency(t *testing.T) {
+	log := JSON.NewPatchLog()
+	doc := JSON.Parse(`{"items":[]}`)
+	patch := JSON.Patch{{Op: "add", Path: "/items/-", Value: "a"}}
+
+	doc, applied, err := log.ApplyWithID(doc, "evt-1", patch)
+	if err != nil || !applied {
+		t.Fatalf("Expected first apply to succeed, applied=%v err=%v", applied, err)
+	}
+
+	// A retried delivery of the same patch must not append twice
+	doc, applied, err = log.ApplyWithID(doc, "evt-1", patch)
+	if err != nil || applied {
+		t.Fatalf("Expected retry to be skipped, applied=%v err=%v", applied, err)
+	}
+
+	items, _ := doc.Get("items").Array()
+	if len(items) != 1 {
+		t.Errorf("Expected 1 item, got %d", len(items))
+	}
+	if log.Len() != 1 {
+		t.Errorf("Expected 1 log entry, got %d", log.Len())
+	}
+}
+
+func TestPatchLogExplicitIDs(t *testing.T) {
+	log := JSON.NewPatchLog()
+	doc := JSON.Parse(`{"count":[]}`)
+	patch := JSON.Patch{{Op: "add", Path: "/count/-", Value: 1}}
+
+	doc, _, _ = log.ApplyWithID(doc, "evt-1", patch)
+	doc, applied, _ := log.ApplyWithID(doc, "evt-2", patch)
+	if !applied {
+		t.Error("Expected a new event ID to apply the same patch again")
+	}
+	if _, applied, _ = log.ApplyWithID(doc, "evt-1", patch); applied {
+		t.Error("Expected a repeated event ID to be skipped")
+	}
+	if !log.Has("evt-2") || log.Has("evt-3") {
+		t.Error("Has reported wrong membership")
+	}
+}
+
+func TestPatchLogApplyDoesNotDeduplicate(t *testing.T) {
+	log := JSON.NewPatchLog()
+	doc := JSON.Parse(`{"counter":[]}`)
+	patch := JSON.Patch{{Op: "add", Path: "/counter/-", Value: 1}}
+
+	for i := 0; i < 2; i++ {
+		var applied bool
+		var err error
+		if doc, applied, err = log.Apply(doc, patch); err != nil || !applied {
+			t.Fatalf("Apply %d: applied=%v err=%v", i+1, applied, err)
+		}
+	}
+	if items, _ := doc.Get("counter").Array(); len(items) != 2 || log.Len() != 2 {
+		t.Errorf("Expected 2 items and 2 entries, got %d and %d", len(items), log.Len())
+	}
+}
+
+func TestPatchLogIDConflict(t *testing.T) {
+	log := JSON.NewPatchLog()
+	doc := JSON.Parse(`{"name":"a"}`)
+
+	doc, _, _ = log.ApplyWithID(doc, "evt-1", JSON.Patch{{Op: "replace", Path: "/name", Value: "b"}})
+	got, applied, err := log.ApplyWithID(doc, "evt-1", JSON.Patch{{Op: "replace", Path: "/name", Value: "c"}})
+	if !errors.Is(err, JSON.ErrValidation) || applied {
+		t.Fatalf("Expected an ErrValidation conflict, applied=%v err=%v", applied, err)
+	}
+	if name := got.Get("name").StringOr(""); name != "b" || log.Len() != 1 {
+		t.Errorf("Expected the document and log unchanged, got name %q and %d entries", name, log.Len())
+	}
+}
+
+func TestPatchLogFailedPatchNotRecorded(t *testing.T) {
+	log := JSON.NewPatchLog()
+	doc := JSON.Parse(`{}`)
+
+	_, applied, err := log.Apply(doc, JSON.Patch{{Op: "remove", Path: "/missing"}})
+	if err == nil || applied {
+		t.Fatalf("Expected failure, applied=%v err=%v", applied, err)
+	}
+	if log.Len() != 0 {
+		t.Errorf("Failed patch should not be recorded, got %d entries", log.Len())
+	}
+}
+
+func TestPatchLogReplay(t *testing.T) {
+	base := JSON.Parse(`{"name":"a","tags":[]}`)
+	log := JSON.NewPatchLog()
+
+	doc := base
+	doc, _, _ = log.Apply(doc, JSON.Patch{{Op: "replace", Path: "/name", Value: "b"}})
+	doc, _, _ = log.Apply(doc, JSON.Patch{{Op: "add", Path: "/tags/-", Value: "x"}})
+
+	// Persist and restore the log, then rebuild the document from the base
+	data, err := json.Marshal(log)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	restored := JSON.NewPatchLog()
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	replayed, err := restored.Replay(base)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	want, _ := JSON.Stringify(doc)
+	got, _ := JSON.Stringify(replayed)
+	if got != want {
+		t.Errorf("Expected replay %s, got %s", want, got)
+	}
+
+	if entries := restored.Since(1); len(entries) != 1 || entries[0].Seq != 2 {
+		t.Errorf("Expected one entry after seq 1, got %v", entries)
+	}
+
+	// Replaying against the wrong base must be detected
+	if _, err := restored.Replay(JSON.Parse(`{"name":"z","tags":["q"]}`)); err == nil {
+		t.Error("Expected hash mismatch when replaying against a different base")
+	}
+}
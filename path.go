@@ -0,0 +1,104 @@
+package jsjson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Path is a precompiled sequence of keys for repeated lookups. Keys are
+// converted once, so Get does no per-call allocation or key conversion.
+type Path struct {
+	keys []interface{}
+	segs []pathSegment
+}
+
+// pathSegment is one compiled key. A string key is used for objects and, if
+// it is a valid integer, also as an array index, matching Get.
+type pathSegment struct {
+	key      string
+	index    int
+	isKey    bool
+	hasIndex bool
+}
+
+// CompilePath compiles keys (strings for object keys, integers for array
+// indices) into a reusable Path:
+//
+//	email := jsjson.CompilePath("users", 0, "profile", "email")
+//	for _, doc := range docs {
+//		addr := email.Get(doc).StringOr("")
+//	}
+//
+// Keys accepted by Get are accepted here; one that cannot be used at its
+// position is reported by Get on the Path, exactly as JSONValue.Get would.
+func CompilePath(keys ...interface{}) *Path {
+	p := &Path{keys: append([]interface{}(nil), keys...), segs: make([]pathSegment, len(keys))}
+	for i, key := range keys {
+		seg := &p.segs[i]
+		seg.key, seg.isKey = key.(string)
+		if idx, err := convertToIndex(key); err == nil {
+			seg.index, seg.hasIndex = idx, true
+		}
+	}
+	return p
+}
+
+// Get returns the value at the path, with the same results and errors as
+// j.Get(keys...)
+func (p *Path) Get(j JSONValue) JSONValue {
+	if j.err != nil {
+		return j
+	}
+
+	current := j.data
+	for i := range p.segs {
+		seg := &p.segs[i]
+		switch c := current.(type) {
+		case map[string]interface{}:
+			if !seg.isKey {
+				return accessError("Get", p.keys, i, fmt.Errorf("%w: key must be string for object access, got %T", ErrTypeMismatch, p.keys[i]))
+			}
+			var exists bool
+			if current, exists = c[seg.key]; !exists {
+				return accessError("Get", p.keys, i, ErrKeyNotFound)
+			}
+		case []interface{}:
+			if !seg.hasIndex {
+				_, err := convertToIndex(p.keys[i])
+				return accessError("Get", p.keys, i, fmt.Errorf("%w: invalid array index %v: %v", ErrTypeMismatch, p.keys[i], err))
+			}
+			if seg.index < 0 || seg.index >= len(c) {
+				return accessError("Get", p.keys, i, fmt.Errorf("%w: index %d (length: %d)", ErrIndexOutOfRange, seg.index, len(c)))
+			}
+			current = c[seg.index]
+		case nil:
+			return accessError("Get", p.keys, i, fmt.Errorf("%w: cannot access key %v on %w", ErrTypeMismatch, p.keys[i], ErrNilValue))
+		default:
+			return accessError("Get", p.keys, i, fmt.Errorf("%w: cannot access key %v on type %T", ErrTypeMismatch, p.keys[i], current))
+		}
+	}
+
+	return JSONValue{data: current, raw: j.raw.child(p.keys...)}
+}
+
+// String returns the path in a readable form such as users[0].profile.email
+func (p *Path) String() string {
+	return formatPath(p.keys)
+}
+
+// formatPath writes keys in the form Path.String describes
+func formatPath(keys []interface{}) string {
+	var b strings.Builder
+	for i, key := range keys {
+		switch k := key.(type) {
+		case string:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(k)
+		default:
+			fmt.Fprintf(&b, "[%v]", k)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package jsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestCompilePath(t *testing.T) {
+	obj := JSON.Parse(`{
+		"users": [
+			{"profile": {"email": "a@example.com"}, "tags": ["x", "y"]},
+			{"profile": null}
+		],
+		"count": 2
+	}`)
+
+	tests := []struct {
+		name    string
+		keys    []interface{}
+		wantErr error
+	}{
+		{"nested", []interface{}{"users", 0, "profile", "email"}, nil},
+		{"string index", []interface{}{"users", "1", "profile"}, nil},
+		{"float index", []interface{}{"users", 0.0, "tags", json.Number("1")}, nil},
+		{"empty", nil, nil},
+		{"missing key", []interface{}{"users", 0, "name"}, JSON.ErrKeyNotFound},
+		{"out of range", []interface{}{"users", 5}, JSON.ErrIndexOutOfRange},
+		{"int on object", []interface{}{0}, JSON.ErrTypeMismatch},
+		{"bad index", []interface{}{"users", "first"}, JSON.ErrTypeMismatch},
+		{"unsupported key", []interface{}{"users", true}, JSON.ErrTypeMismatch},
+		{"through null", []interface{}{"users", 1, "profile", "email"}, JSON.ErrTypeMismatch},
+		{"through scalar", []interface{}{"count", "x"}, JSON.ErrTypeMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := JSON.CompilePath(tt.keys...).Get(obj)
+			want := obj.Get(tt.keys...)
+			if tt.wantErr != nil {
+				if !errors.Is(got.Error(), tt.wantErr) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, got.Error())
+				}
+				if got.Error().Error() != want.Error().Error() {
+					t.Errorf("Expected error %q, got %q", want.Error(), got.Error())
+				}
+				return
+			}
+			if got.Error() != nil || !reflect.DeepEqual(got.Raw(), want.Raw()) {
+				t.Errorf("Expected %v, got %v (err: %v)", want.Raw(), got.Raw(), got.Error())
+			}
+		})
+	}
+}
+
+func TestCompilePathPropagatesErrors(t *testing.T) {
+	bad := JSON.Parse(`{invalid`)
+	if got := JSON.CompilePath("a").Get(bad); got.Error() != bad.Error() {
+		t.Errorf("Expected the parse error to propagate, got %v", got.Error())
+	}
+}
+
+func TestCompilePathRawBytes(t *testing.T) {
+	obj := JSON.Parse(`{"a": [1, {"b":  true}]}`, JSON.ParseWithRaw())
+	raw, err := JSON.CompilePath("a", 1).Get(obj).RawBytes()
+	if err != nil || string(raw) != `{"b":  true}` {
+		t.Errorf("Expected original bytes, got %q (err: %v)", raw, err)
+	}
+}
+
+func TestCompilePathString(t *testing.T) {
+	if got := JSON.CompilePath("users", 0, "profile", "email").String(); got != "users[0].profile.email" {
+		t.Errorf("Expected users[0].profile.email, got %s", got)
+	}
+}
+
+func TestCompilePathAllocations(t *testing.T) {
+	obj := JSON.Parse(`{"users":[{"profile":{"email":"a@example.com"}}]}`)
+	path := JSON.CompilePath("users", 0, "profile", "email")
+	if allocs := testing.AllocsPerRun(100, func() { path.Get(obj) }); allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
@@ -0,0 +1,84 @@
+package jsjson
+
+import (
+	"path"
+	"strings"
+)
+
+// -------------------- Path Patterns --------------------
+//
+// A path pattern is a dotted list of segments matched against the keys
+// from the root to a value: "profile.email" is the email key of the
+// top-level profile object. Array elements are matched by their index, so
+// "users.0.name" is the name of the first user. A segment may use the glob
+// syntax of path.Match (*, ? and [...]) to match several keys or indices,
+// and the segment ** matches any number of levels, including none.
+
+// splitPatterns splits dotted path patterns into their segments
+func splitPatterns(patterns []string) [][]string {
+	out := make([][]string, len(patterns))
+	for i, p := range patterns {
+		out[i] = strings.Split(p, ".")
+	}
+	return out
+}
+
+// matchPath reports whether pattern matches the whole of path
+func matchPath(pattern, keys []string) bool {
+	if len(pattern) == 0 {
+		return len(keys) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(keys); i++ {
+			if matchPath(pattern[1:], keys[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(keys) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], keys[0]); !ok {
+		return false
+	}
+	return matchPath(pattern[1:], keys[1:])
+}
+
+// matchAny reports whether any pattern matches path
+func matchAny(patterns [][]string, keys []string) bool {
+	for _, p := range patterns {
+		if matchPath(p, keys) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPrefix reports whether pattern could match path or a value below it
+func matchPrefix(pattern, keys []string) bool {
+	if len(keys) == 0 {
+		return true
+	}
+	if len(pattern) == 0 {
+		return false
+	}
+	if pattern[0] == "**" {
+		return true
+	}
+	if ok, _ := path.Match(pattern[0], keys[0]); !ok {
+		return false
+	}
+	return matchPrefix(pattern[1:], keys[1:])
+}
+
+// matchAnyPrefix reports whether any pattern could match path or a value
+// below it
+func matchAnyPrefix(patterns [][]string, keys []string) bool {
+	for _, p := range patterns {
+		if matchPrefix(p, keys) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package jsjson
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// -------------------- Problem Details --------------------
+
+// ProblemContentType is the media type of RFC 7807 problem documents
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details document. It implements error, so
+// handlers can return one, and json.Marshaler, so WriteJSON sends it as the
+// document JSONValue returns.
+type Problem struct {
+	// Type is a URI identifying the problem type; "about:blank" means the
+	// problem is described by its status alone
+	Type   string
+	Title  string
+	Status int
+	Detail string
+	// Instance is a URI identifying this occurrence of the problem
+	Instance string
+	// Extensions holds every other member of the document
+	Extensions map[string]interface{}
+}
+
+// NewProblem returns a problem document with type about:blank:
+//
+//	jsjson.NewProblem(404, "Not Found", "no user with id 7")
+//	// {"type":"about:blank","title":"Not Found","status":404,"detail":"no user with id 7"}
+//
+// An empty title is the status text of status, and an empty detail is left
+// out. Send it with WriteJSON after setting Content-Type to
+// ProblemContentType.
+func NewProblem(status int, title, detail string) JSONValue {
+	return Problem{Status: status, Title: title, Detail: detail}.JSONValue()
+}
+
+// JSONValue returns p as a problem document. Type defaults to about:blank
+// and Title to the status text; other empty members, and extensions that
+// would replace a standard member, are left out.
+func (p Problem) JSONValue() JSONValue {
+	doc := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		doc[k] = v
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	doc["type"] = p.Type
+	for k, v := range map[string]string{"title": p.Title, "detail": p.Detail, "instance": p.Instance} {
+		if v != "" {
+			doc[k] = v
+		} else {
+			delete(doc, k)
+		}
+	}
+	if p.Status != 0 {
+		doc["status"] = float64(p.Status)
+	} else {
+		delete(doc, "status")
+	}
+	return JSONValue{data: doc}
+}
+
+// MarshalJSON implements json.Marshaler
+func (p Problem) MarshalJSON() ([]byte, error) {
+	return p.JSONValue().MarshalJSON()
+}
+
+// Error implements the error interface
+func (p Problem) Error() string {
+	msg := p.Title
+	if msg == "" {
+		msg = http.StatusText(p.Status)
+	}
+	if p.Status != 0 {
+		msg = strconv.Itoa(p.Status) + " " + msg
+	}
+	if p.Detail != "" {
+		msg += ": " + p.Detail
+	}
+	return msg
+}
+
+// ParseProblem reads a problem document. As RFC 7807 requires, a standard
+// member of the wrong type is ignored rather than rejected, and a missing
+// type is about:blank. Members other than the standard five are returned in
+// Extensions. It fails only if jv is not an object.
+func ParseProblem(jv JSONValue) (Problem, error) {
+	if jv.err != nil {
+		return Problem{}, jv.err
+	}
+	data, err := normalize(jv.data)
+	if err != nil {
+		return Problem{}, &JSONError{Op: "ParseProblem", Err: err}
+	}
+	obj, ok := data.(map[string]interface{})
+	if !ok {
+		return Problem{}, &JSONError{Op: "ParseProblem", Err: fmt.Errorf("%w: expected an object, got %s", ErrTypeMismatch, jv.Type())}
+	}
+
+	p := Problem{Type: "about:blank"}
+	for k, v := range obj {
+		s, isString := v.(string)
+		switch k {
+		case "type":
+			if isString {
+				p.Type = s
+			}
+		case "title":
+			p.Title = s
+		case "detail":
+			p.Detail = s
+		case "instance":
+			p.Instance = s
+		case "status":
+			if f, ok := numberValue(v); ok && f == float64(int(f)) {
+				p.Status = int(f)
+			}
+		default:
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]interface{})
+			}
+			p.Extensions[k] = v
+		}
+	}
+	return p, nil
+}
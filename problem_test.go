@@ -0,0 +1,90 @@
+package jsjson_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestNewProblem(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		title  string
+		detail string
+		want   string
+	}{
+		{"full", 404, "Not Found", "no user with id 7", `{"detail":"no user with id 7","status":404,"title":"Not Found","type":"about:blank"}`},
+		{"default title", 409, "", "", `{"status":409,"title":"Conflict","type":"about:blank"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSON.Stringify(JSON.NewProblem(tt.status, tt.title, tt.detail))
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %s, got %s (%v)", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestParseProblem(t *testing.T) {
+	p, err := JSON.ParseProblem(JSON.Parse(`{
+		"type": "https://example.com/probs/out-of-credit",
+		"title": "You do not have enough credit.",
+		"status": 403,
+		"detail": "Your current balance is 30, but that costs 50.",
+		"instance": "/account/12345/msgs/abc",
+		"balance": 30
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := JSON.Problem{
+		Type:       "https://example.com/probs/out-of-credit",
+		Title:      "You do not have enough credit.",
+		Status:     403,
+		Detail:     "Your current balance is 30, but that costs 50.",
+		Instance:   "/account/12345/msgs/abc",
+		Extensions: map[string]interface{}{"balance": float64(30)},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("Expected %+v, got %+v", want, p)
+	}
+	if got := p.Error(); got != "403 You do not have enough credit.: Your current balance is 30, but that costs 50." {
+		t.Errorf("Unexpected message %q", got)
+	}
+
+	// Members of the wrong type are ignored
+	p, err = JSON.ParseProblem(JSON.Parse(`{"status": "500", "title": 1}`))
+	if err != nil || !reflect.DeepEqual(p, JSON.Problem{Type: "about:blank"}) {
+		t.Errorf("Expected an about:blank problem, got %+v (%v)", p, err)
+	}
+
+	if _, err := JSON.ParseProblem(JSON.Parse(`[]`)); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got %v", err)
+	}
+}
+
+func TestProblemRoundTrip(t *testing.T) {
+	want := JSON.Problem{Type: "about:blank", Title: "Bad Request", Status: 400, Extensions: map[string]interface{}{"field": "email"}}
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", JSON.ProblemContentType)
+	if err := JSON.WriteJSON(w, want.Status, want); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", w.Body)
+	r.Header.Set("Content-Type", w.Header().Get("Content-Type"))
+	jv, err := JSON.DecodeRequest(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, err := JSON.ParseProblem(jv)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v (%v)", want, got, err)
+	}
+}
@@ -0,0 +1,129 @@
+package jsjson
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// -------------------- Projection --------------------
+
+// Pick returns a copy of an object or array holding only the values matched
+// by the given path patterns, keeping the objects and arrays on the way to
+// them, so API responses can be shaped without defining new structs:
+//
+//	user.Pick("id", "name", "profile.email", "roles.*.name")
+//
+// Patterns are dotted paths from the root whose segments match object keys
+// or array indices, with path.Match globs and ** for any number of levels.
+// Unlike Redact, a pattern without a dot matches only a top-level key. A
+// matched object or array is kept whole. Array elements with nothing picked
+// are dropped, so the remaining elements close up.
+func (j JSONValue) Pick(patterns ...string) JSONValue {
+	data, err := j.projectionRoot("Pick")
+	if err != nil {
+		return JSONValue{err: err}
+	}
+	picked, _ := pickTree(data, nil, splitPatterns(patterns))
+	if picked == nil {
+		picked = emptyLike(data)
+	}
+	return JSONValue{data: picked}
+}
+
+// Omit returns a copy of an object or array without the values matched by
+// the given path patterns, which work as in Pick:
+//
+//	public := user.Omit("password_hash", "sessions", "**.internal_*")
+//
+// Matched array elements are removed, so the remaining elements close up.
+func (j JSONValue) Omit(patterns ...string) JSONValue {
+	data, err := j.projectionRoot("Omit")
+	if err != nil {
+		return JSONValue{err: err}
+	}
+	return JSONValue{data: omitTree(data, nil, splitPatterns(patterns))}
+}
+
+// projectionRoot returns a private copy of the value, which must be an
+// object or array
+func (j JSONValue) projectionRoot(op string) (interface{}, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	data, err := normalize(j.data)
+	if err != nil {
+		return nil, &JSONError{Op: op, Err: err}
+	}
+	switch data.(type) {
+	case map[string]interface{}, []interface{}:
+		return data, nil
+	}
+	return nil, &JSONError{Op: op, Err: fmt.Errorf("%w: expected object or array, got %s", ErrTypeMismatch, JSONValue{data: data}.Type())}
+}
+
+// pickTree returns the parts of v matched by patterns, and false if there
+// are none
+func pickTree(v interface{}, keys []string, patterns [][]string) (interface{}, bool) {
+	if len(keys) > 0 && matchAny(patterns, keys) {
+		return v, true
+	}
+	if !matchAnyPrefix(patterns, keys) {
+		return nil, false
+	}
+	switch c := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{})
+		for k, child := range c {
+			if picked, ok := pickTree(child, append(keys, k), patterns); ok {
+				out[k] = picked
+			}
+		}
+		return out, len(out) > 0
+	case []interface{}:
+		out := make([]interface{}, 0)
+		for i, child := range c {
+			if picked, ok := pickTree(child, append(keys, strconv.Itoa(i)), patterns); ok {
+				out = append(out, picked)
+			}
+		}
+		return out, len(out) > 0
+	}
+	return nil, false
+}
+
+// omitTree removes the parts of v matched by patterns, reusing v's maps
+func omitTree(v interface{}, keys []string, patterns [][]string) interface{} {
+	if !matchAnyPrefix(patterns, keys) {
+		return v
+	}
+	switch c := v.(type) {
+	case map[string]interface{}:
+		for k, child := range c {
+			childKeys := append(keys, k)
+			if matchAny(patterns, childKeys) {
+				delete(c, k)
+				continue
+			}
+			c[k] = omitTree(child, childKeys, patterns)
+		}
+	case []interface{}:
+		out := c[:0]
+		for i, child := range c {
+			childKeys := append(keys, strconv.Itoa(i))
+			if matchAny(patterns, childKeys) {
+				continue
+			}
+			out = append(out, omitTree(child, childKeys, patterns))
+		}
+		return out
+	}
+	return v
+}
+
+// emptyLike returns an empty object or array of the same kind as v
+func emptyLike(v interface{}) interface{} {
+	if _, ok := v.([]interface{}); ok {
+		return []interface{}{}
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,103 @@
+package jsjson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+const projectionInput = `{
+	"id": 7,
+	"name": "Ann",
+	"password_hash": "x",
+	"profile": {"email": "ann@example.com", "phone": "555", "internal_score": 3},
+	"roles": [{"name": "admin", "internal_id": 1}, {"name": "dev", "internal_id": 2}],
+	"tags": ["a", "b", "c"]
+}`
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     string
+	}{
+		{"top-level keys", []string{"id", "name"}, `{"id": 7, "name": "Ann"}`},
+		{"nested path", []string{"id", "profile.email"}, `{"id": 7, "profile": {"email": "ann@example.com"}}`},
+		{"whole subtree", []string{"profile"}, `{"profile": {"email": "ann@example.com", "phone": "555", "internal_score": 3}}`},
+		{"array wildcard", []string{"roles.*.name"}, `{"roles": [{"name": "admin"}, {"name": "dev"}]}`},
+		{"array index", []string{"tags.1"}, `{"tags": ["b"]}`},
+		{"double star", []string{"**.internal_*"}, `{"profile": {"internal_score": 3}, "roles": [{"internal_id": 1}, {"internal_id": 2}]}`},
+		{"bare key is anchored", []string{"email"}, `{}`},
+		{"missing path", []string{"profile.fax"}, `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jv := JSON.Parse(projectionInput)
+			got := jv.Pick(tt.patterns...)
+			if !reflect.DeepEqual(got.Raw(), JSON.Parse(tt.want).Raw()) {
+				s, _ := JSON.Stringify(got)
+				t.Errorf("Unexpected result %s", s)
+			}
+			if !reflect.DeepEqual(jv.Raw(), JSON.Parse(projectionInput).Raw()) {
+				t.Error("Expected the original to be unchanged")
+			}
+		})
+	}
+
+	t.Run("array root", func(t *testing.T) {
+		got := JSON.Parse(`[{"id": 1, "x": 2}, {"id": 3}]`).Pick("*.id")
+		if !reflect.DeepEqual(got.Raw(), JSON.Parse(`[{"id": 1}, {"id": 3}]`).Raw()) {
+			s, _ := JSON.Stringify(got)
+			t.Errorf("Unexpected result %s", s)
+		}
+	})
+}
+
+func TestOmit(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     string
+	}{
+		{"top-level keys", []string{"password_hash", "roles", "tags"}, `{
+			"id": 7, "name": "Ann",
+			"profile": {"email": "ann@example.com", "phone": "555", "internal_score": 3}
+		}`},
+		{"nested and globbed", []string{"profile.phone", "**.internal_*", "tags.1", "password_hash"}, `{
+			"id": 7, "name": "Ann",
+			"profile": {"email": "ann@example.com"},
+			"roles": [{"name": "admin"}, {"name": "dev"}],
+			"tags": ["a", "c"]
+		}`},
+		{"nothing matched", []string{"nope", "profile.nope"}, projectionInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jv := JSON.Parse(projectionInput)
+			got := jv.Omit(tt.patterns...)
+			if !reflect.DeepEqual(got.Raw(), JSON.Parse(tt.want).Raw()) {
+				s, _ := JSON.Stringify(got)
+				t.Errorf("Unexpected result %s", s)
+			}
+			if !reflect.DeepEqual(jv.Raw(), JSON.Parse(projectionInput).Raw()) {
+				t.Error("Expected the original to be unchanged")
+			}
+		})
+	}
+}
+
+func TestProjectionErrors(t *testing.T) {
+	if err := JSON.Parse(`"text"`).Pick("a").Error(); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got %v", err)
+	}
+	if err := JSON.Parse(`1`).Omit("a").Error(); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got %v", err)
+	}
+	if err := JSON.Parse(`{`).Pick("a").Error(); err == nil {
+		t.Error("Expected the original error")
+	}
+}
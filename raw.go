@@ -0,0 +1,165 @@
+package jsjson
+
+import (
+	"errors"
+	"fmt"
+)
+
+// rawRef locates a value inside the original document text: the bytes of the
+// root value and the keys leading from it. The span itself is only computed
+// when RawBytes is called, so retaining it costs Get one small allocation.
+type rawRef struct {
+	src  []byte
+	path []interface{}
+}
+
+// errRawNotRetained is returned by RawBytes for values without source text
+var errRawNotRetained = errors.New("original bytes not retained; parse with ParseWithRaw")
+
+// ParseWithRaw keeps a reference to the input text so RawBytes can return the
+// exact original bytes of any subtree. Parse copies a []byte input first;
+// ParseNoCopy borrows it as usual.
+func ParseWithRaw() ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.keepRaw = true })
+}
+
+// newRawRef returns the reference for a root document, or nil if raw
+// retention is off
+func newRawRef(data []byte, o *parseOptions) *rawRef {
+	if !o.keepRaw {
+		return nil
+	}
+	start := skipSpace(data, 0)
+	end := len(data)
+	for end > start && isSpace(data[end-1]) {
+		end--
+	}
+	return &rawRef{src: data[start:end]}
+}
+
+// child returns the reference for the value at keys below r
+func (r *rawRef) child(keys ...interface{}) *rawRef {
+	if r == nil {
+		return nil
+	}
+	path := make([]interface{}, len(r.path)+len(keys))
+	copy(path, r.path)
+	copy(path[len(r.path):], keys)
+	return &rawRef{src: r.src, path: path}
+}
+
+// RawBytes returns the exact original bytes of the value, as they appeared in
+// the input, so a subtree can be forwarded or signature-verified without
+// re-serialization differences. The input must have been parsed with
+// ParseWithRaw. The returned slice shares memory with the retained input and
+// must not be modified.
+func (j JSONValue) RawBytes() ([]byte, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	if j.raw == nil {
+		return nil, &JSONError{Op: "RawBytes", Err: errRawNotRetained}
+	}
+
+	b, err := rawLookup(j.raw.src, j.raw.path)
+	if err != nil {
+		return nil, &JSONError{Op: "RawBytes", Err: err}
+	}
+	return b, nil
+}
+
+// rawLookup returns the bytes of the value at path within data, following
+// the same rules as Get (including last-wins duplicate keys)
+func rawLookup(data []byte, path []interface{}) ([]byte, error) {
+	start := skipSpace(data, 0)
+	end, err := scanValueEnd(data, start)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range path {
+		var found bool
+		switch data[start] {
+		case '{':
+			keyStr, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: key must be string for object access, got %T", ErrTypeMismatch, key)
+			}
+			start, end, found, err = rawObjectMember(data, start, keyStr)
+			if err == nil && !found {
+				err = fmt.Errorf("%w: %q", ErrKeyNotFound, keyStr)
+			}
+		case '[':
+			idx, convErr := convertToIndex(key)
+			if convErr != nil {
+				return nil, fmt.Errorf("%w: invalid array index %v: %v", ErrTypeMismatch, key, convErr)
+			}
+			start, end, found, err = rawArrayElement(data, start, idx)
+			if err == nil && !found {
+				err = fmt.Errorf("%w: index %d", ErrIndexOutOfRange, idx)
+			}
+		default:
+			err = fmt.Errorf("%w: cannot access key %v on a scalar", ErrTypeMismatch, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data[start:end], nil
+}
+
+// rawObjectMember finds the value for key in the object starting at i
+func rawObjectMember(data []byte, i int, key string) (start, end int, found bool, err error) {
+	i = skipSpace(data, i+1)
+	for i < len(data) && data[i] != '}' {
+		p := parser{data: data, pos: i}
+		k, err := p.stringValue()
+		if err != nil {
+			return 0, 0, false, err
+		}
+		i = skipSpace(data, p.pos)
+		if i >= len(data) || data[i] != ':' {
+			return 0, 0, false, fmt.Errorf("%w: expected ':' after object key at offset %d", ErrSyntax, i)
+		}
+		i = skipSpace(data, i+1)
+		valueEnd, err := scanValueEnd(data, i)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if k == key {
+			// Keep scanning: the last duplicate wins, as in the parsed tree
+			start, end, found = i, valueEnd, true
+		}
+		i = skipSpace(data, valueEnd)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	return start, end, found, nil
+}
+
+// rawArrayElement finds element idx of the array starting at i
+func rawArrayElement(data []byte, i, idx int) (start, end int, found bool, err error) {
+	if idx < 0 {
+		return 0, 0, false, nil
+	}
+	i = skipSpace(data, i+1)
+	for n := 0; i < len(data) && data[i] != ']'; n++ {
+		valueEnd, err := scanValueEnd(data, i)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if n == idx {
+			return i, valueEnd, true, nil
+		}
+		i = skipSpace(data, valueEnd)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	return 0, 0, false, nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
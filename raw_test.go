@@ -0,0 +1,78 @@
+package jsjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestRawBytes(t *testing.T) {
+	input := ` {
+		"id": 7,
+		"payload": {"b": 2,  "a": [1.50, "xA"]},
+		"list": [ {"k": true}, "two" , 3e2 ],
+		"esc\"aped": "v",
+		"dup": 1, "dup": {"last": null}
+	} `
+	doc := JSON.Parse(input, JSON.ParseWithRaw())
+
+	tests := []struct {
+		name string
+		get  JSON.JSONValue
+		want string
+	}{
+		{"root", doc, strings.TrimSpace(input)},
+		{"object", doc.Get("payload"), `{"b": 2,  "a": [1.50, "xA"]}`},
+		{"nested", doc.Get("payload", "a"), `[1.50, "xA"]`},
+		{"number literal", doc.Get("payload", "a", 0), `1.50`},
+		{"chained Get", doc.Get("payload").Get("a").Get(1), `"xA"`},
+		{"array element", doc.Get("list", 0), `{"k": true}`},
+		{"exponent", doc.Get("list", 2), `3e2`},
+		{"escaped key", doc.Get(`esc"aped`), `"v"`},
+		{"last duplicate", doc.Get("dup"), `{"last": null}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get.RawBytes()
+			if err != nil || string(got) != tt.want {
+				t.Errorf("Expected %s, got %s (err: %v)", tt.want, got, err)
+			}
+		})
+	}
+
+	items, _ := doc.Get("list").Array()
+	if got, _ := items[1].RawBytes(); string(got) != `"two"` {
+		t.Errorf("Expected \"two\" from Array, got %s", got)
+	}
+	fields, _ := doc.Get("payload").Object()
+	if got, _ := fields["b"].RawBytes(); string(got) != `2` {
+		t.Errorf("Expected 2 from Object, got %s", got)
+	}
+}
+
+func TestRawBytesRequiresOption(t *testing.T) {
+	if _, err := JSON.Parse(`{"a":1}`).Get("a").RawBytes(); err == nil {
+		t.Error("Expected error without ParseWithRaw")
+	}
+	if _, err := JSON.Parse(`{"a":1}`, JSON.ParseWithRaw()).Get("b").RawBytes(); !errors.Is(err, JSON.ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestRawBytesCopiesParseInput(t *testing.T) {
+	buf := []byte(`{"sig":"abc"}`)
+	doc := JSON.Parse(buf, JSON.ParseWithRaw())
+	copy(buf, `{"sig":"xyz"}`)
+
+	if got, _ := doc.Get("sig").RawBytes(); string(got) != `"abc"` {
+		t.Errorf("Expected Parse to retain a private copy, got %s", got)
+	}
+
+	noCopy := JSON.ParseNoCopy([]byte(`{"sig":"abc"}`), JSON.ParseWithRaw())
+	if got, _ := noCopy.Get("sig").RawBytes(); string(got) != `"abc"` {
+		t.Errorf("Expected \"abc\" from ParseNoCopy, got %s", got)
+	}
+}
@@ -0,0 +1,52 @@
+package jsjson
+
+import "strconv"
+
+// -------------------- Redaction --------------------
+
+// RedactedValue replaces the values removed by Redact
+const RedactedValue = "[REDACTED]"
+
+// Redact returns a copy of the value with every value matched by a pattern
+// replaced by "[REDACTED]", so payloads can be logged safely:
+//
+//	log.Print(body.Redact("password", "token", "*.ssn", "cards.*.number"))
+//
+// Patterns are dotted paths whose segments match object keys or array
+// indices, with path.Match globs and ** for any number of levels. A pattern
+// without a dot matches that key at any depth, so "password" covers
+// user.password and accounts[3].password alike. A matched object or array
+// is replaced as a whole.
+func (j JSONValue) Redact(patterns ...string) JSONValue {
+	if j.err != nil {
+		return j
+	}
+	data, err := normalize(j.data)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "Redact", Err: err}}
+	}
+	pats := splitPatterns(patterns)
+	for i, p := range pats {
+		if len(p) == 1 {
+			pats[i] = []string{"**", p[0]}
+		}
+	}
+	return JSONValue{data: redactTree(data, nil, pats)}
+}
+
+func redactTree(v interface{}, keys []string, patterns [][]string) interface{} {
+	if len(keys) > 0 && matchAny(patterns, keys) {
+		return RedactedValue
+	}
+	switch c := v.(type) {
+	case map[string]interface{}:
+		for k, child := range c {
+			c[k] = redactTree(child, append(keys, k), patterns)
+		}
+	case []interface{}:
+		for i, child := range c {
+			c[i] = redactTree(child, append(keys, strconv.Itoa(i)), patterns)
+		}
+	}
+	return v
+}
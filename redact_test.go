@@ -0,0 +1,70 @@
+package jsjson_test
+
+import (
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestRedact(t *testing.T) {
+	input := `{
+		"password": "hunter2",
+		"user": {"name": "Ann", "ssn": "123-45-6789", "password": "x"},
+		"cards": [{"number": "4111", "brand": "visa"}, {"number": "5500", "brand": "mc"}],
+		"auth": {"token": {"value": "abc", "exp": 1}},
+		"deep": {"a": {"ssn": "keep"}}
+	}`
+	tests := []struct {
+		name     string
+		patterns []string
+		want     string
+	}{
+		{"bare key at any depth", []string{"password"}, `{
+			"password": "[REDACTED]",
+			"user": {"name": "Ann", "ssn": "123-45-6789", "password": "[REDACTED]"},
+			"cards": [{"number": "4111", "brand": "visa"}, {"number": "5500", "brand": "mc"}],
+			"auth": {"token": {"value": "abc", "exp": 1}},
+			"deep": {"a": {"ssn": "keep"}}
+		}`},
+		{"wildcards", []string{"*.ssn", "cards.*.number"}, `{
+			"password": "hunter2",
+			"user": {"name": "Ann", "ssn": "[REDACTED]", "password": "x"},
+			"cards": [{"number": "[REDACTED]", "brand": "visa"}, {"number": "[REDACTED]", "brand": "mc"}],
+			"auth": {"token": {"value": "abc", "exp": 1}},
+			"deep": {"a": {"ssn": "keep"}}
+		}`},
+		{"whole subtree", []string{"token"}, `{
+			"password": "hunter2",
+			"user": {"name": "Ann", "ssn": "123-45-6789", "password": "x"},
+			"cards": [{"number": "4111", "brand": "visa"}, {"number": "5500", "brand": "mc"}],
+			"auth": {"token": "[REDACTED]"},
+			"deep": {"a": {"ssn": "keep"}}
+		}`},
+		{"double star and index", []string{"deep.**.ssn", "cards.1"}, `{
+			"password": "hunter2",
+			"user": {"name": "Ann", "ssn": "123-45-6789", "password": "x"},
+			"cards": [{"number": "4111", "brand": "visa"}, "[REDACTED]"],
+			"auth": {"token": {"value": "abc", "exp": 1}},
+			"deep": {"a": {"ssn": "[REDACTED]"}}
+		}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jv := JSON.Parse(input)
+			got := jv.Redact(tt.patterns...)
+			if !reflect.DeepEqual(got.Raw(), JSON.Parse(tt.want).Raw()) {
+				s, _ := JSON.Stringify(got)
+				t.Errorf("Unexpected result %s", s)
+			}
+			if !reflect.DeepEqual(jv.Raw(), JSON.Parse(input).Raw()) {
+				t.Error("Expected the original to be unchanged")
+			}
+		})
+	}
+
+	if err := JSON.Parse(`{`).Redact("a").Error(); err == nil {
+		t.Error("Expected the original error")
+	}
+}
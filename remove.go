@@ -0,0 +1,55 @@
+package jsjson
+
+// -------------------- Conditional Removal --------------------
+
+// RemoveMatching returns a copy of the value without every object member
+// and array element for which match returns true, searching the whole tree,
+// for example to clean vendor noise out of a webhook payload before storing
+// it:
+//
+//	clean := payload.RemoveMatching(func(path []interface{}, v jsjson.JSONValue) bool {
+//		key, _ := path[len(path)-1].(string)
+//		return strings.HasPrefix(key, "x-vendor-") || v.IsNull()
+//	})
+//
+// path holds the keys (strings) and array indices (ints) from the root to
+// v, with indices as in the original value; match must not keep it after
+// returning. The root itself is never removed, and removed values are not
+// searched further. For removal by path pattern alone, Omit is simpler:
+// Omit("metadata.*.internal_*") or Omit("**.internal_*").
+func (j JSONValue) RemoveMatching(match func(path []interface{}, v JSONValue) bool) JSONValue {
+	if j.err != nil {
+		return j
+	}
+	data, err := normalize(j.data)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "RemoveMatching", Err: err}}
+	}
+	return JSONValue{data: removeTree(data, nil, match)}
+}
+
+// removeTree removes the children of v that match, reusing v's maps
+func removeTree(v interface{}, path []interface{}, match func([]interface{}, JSONValue) bool) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		for k, child := range c {
+			childPath := append(path, k)
+			if match(childPath, JSONValue{data: child}) {
+				delete(c, k)
+				continue
+			}
+			c[k] = removeTree(child, childPath, match)
+		}
+	case []interface{}:
+		out := c[:0]
+		for i, child := range c {
+			childPath := append(path, i)
+			if match(childPath, JSONValue{data: child}) {
+				continue
+			}
+			out = append(out, removeTree(child, childPath, match))
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,65 @@
+package jsjson_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestRemoveMatching(t *testing.T) {
+	input := `{
+		"id": "evt_1",
+		"x-vendor-trace": "abc",
+		"data": {"amount": 5, "note": null, "x-vendor-flags": {"a": 1}},
+		"items": [{"sku": "A", "x-vendor-rank": 1}, null, {"sku": "B"}]
+	}`
+
+	vendorOrNull := func(path []interface{}, v JSON.JSONValue) bool {
+		key, _ := path[len(path)-1].(string)
+		return strings.HasPrefix(key, "x-vendor-") || v.IsNull()
+	}
+	jv := JSON.Parse(input)
+	got := jv.RemoveMatching(vendorOrNull)
+	want := `{"id": "evt_1", "data": {"amount": 5}, "items": [{"sku": "A"}, {"sku": "B"}]}`
+	if !reflect.DeepEqual(got.Raw(), JSON.Parse(want).Raw()) {
+		s, _ := JSON.Stringify(got)
+		t.Errorf("Unexpected result %s", s)
+	}
+	if !reflect.DeepEqual(jv.Raw(), JSON.Parse(input).Raw()) {
+		t.Error("Expected the original to be unchanged")
+	}
+
+	t.Run("paths use original indices", func(t *testing.T) {
+		var paths []string
+		JSON.Parse(`{"a": [1, 2, {"b": 3}]}`).RemoveMatching(func(path []interface{}, v JSON.JSONValue) bool {
+			paths = append(paths, JSON.CompilePath(path...).String())
+			return reflect.DeepEqual(path, []interface{}{"a", 0})
+		})
+		want := map[string]bool{"a": true, "a[0]": true, "a[1]": true, "a[2]": true, "a[2].b": true}
+		if len(paths) != len(want) {
+			t.Fatalf("Unexpected paths %v", paths)
+		}
+		for _, p := range paths {
+			if !want[p] {
+				t.Errorf("Unexpected path %s", p)
+			}
+		}
+	})
+
+	t.Run("removed values are not searched", func(t *testing.T) {
+		calls := 0
+		JSON.Parse(`{"a": {"b": {"c": 1}}}`).RemoveMatching(func(path []interface{}, v JSON.JSONValue) bool {
+			calls++
+			return true
+		})
+		if calls != 1 {
+			t.Errorf("Expected 1 call, got %d", calls)
+		}
+	})
+
+	if err := JSON.Parse(`{`).RemoveMatching(vendorOrNull).Error(); err == nil {
+		t.Error("Expected the original error")
+	}
+}
@@ -0,0 +1,67 @@
+package jsjson
+
+import "fmt"
+
+// -------------------- Key Renaming --------------------
+
+// RenameKeys returns a copy of the value with object keys renamed at every
+// depth, for adapting payloads between API versions that renamed fields:
+//
+//	v2 := v1.RenameKeys(map[string]string{"user_id": "userId", "created": "createdAt"})
+//
+// Renames apply to the original keys of each object, so swapping two names
+// works. If renaming would leave an object with two equal keys, the result
+// holds an error naming the object's path.
+func (j JSONValue) RenameKeys(renames map[string]string) JSONValue {
+	if j.err != nil {
+		return j
+	}
+	data, err := normalize(j.data)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "RenameKeys", Err: err}}
+	}
+	data, err = renameTree(data, nil, renames)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "RenameKeys", Err: err}}
+	}
+	return JSONValue{data: data}
+}
+
+func renameTree(v interface{}, keys []interface{}, renames map[string]string) (interface{}, error) {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(c))
+		for k, child := range c {
+			child, err := renameTree(child, append(keys, k), renames)
+			if err != nil {
+				return nil, err
+			}
+			name, ok := renames[k]
+			if !ok {
+				name = k
+			}
+			if _, dup := out[name]; dup {
+				return nil, fmt.Errorf("renaming keys of %s gives two %q keys", renamePath(keys), name)
+			}
+			out[name] = child
+		}
+		return out, nil
+	case []interface{}:
+		for i, child := range c {
+			child, err := renameTree(child, append(keys, i), renames)
+			if err != nil {
+				return nil, err
+			}
+			c[i] = child
+		}
+	}
+	return v, nil
+}
+
+// renamePath formats the path of an object for errors
+func renamePath(keys []interface{}) string {
+	if len(keys) == 0 {
+		return "the root object"
+	}
+	return CompilePath(keys...).String()
+}
@@ -0,0 +1,64 @@
+package jsjson_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestRenameKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		renames map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "nested objects and arrays",
+			input:   `{"user_id": 1, "items": [{"item_id": 2, "meta": {"user_id": 3}}], "note": "user_id"}`,
+			renames: map[string]string{"user_id": "userId", "item_id": "itemId"},
+			want:    `{"userId": 1, "items": [{"itemId": 2, "meta": {"userId": 3}}], "note": "user_id"}`,
+		},
+		{
+			name:    "swap",
+			input:   `{"a": 1, "b": 2}`,
+			renames: map[string]string{"a": "b", "b": "a"},
+			want:    `{"a": 2, "b": 1}`,
+		},
+		{
+			name:    "array root",
+			input:   `[{"old": 1}, 2]`,
+			renames: map[string]string{"old": "new"},
+			want:    `[{"new": 1}, 2]`,
+		},
+		{
+			name:    "conflict",
+			input:   `{"list": [{"user_id": 1, "userId": 2}]}`,
+			renames: map[string]string{"user_id": "userId"},
+			wantErr: `list[0] gives two "userId" keys`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jv := JSON.Parse(tt.input)
+			got := jv.RenameKeys(tt.renames)
+			if tt.wantErr != "" {
+				if err := got.Error(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got.Raw(), JSON.Parse(tt.want).Raw()) {
+				s, _ := JSON.Stringify(got)
+				t.Errorf("Unexpected result %s", s)
+			}
+			if !reflect.DeepEqual(jv.Raw(), JSON.Parse(tt.input).Raw()) {
+				t.Error("Expected the original to be unchanged")
+			}
+		})
+	}
+}
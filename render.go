@@ -0,0 +1,75 @@
+package jsjson
+
+import (
+	"strings"
+	"text/template"
+)
+
+// -------------------- Templates --------------------
+
+// Render executes tmpl as a text/template with the value as its data, so
+// reports and emails can be generated from parsed API data directly. Fields
+// are reachable as usual ({{.user.name}}, {{range .items}}), and the
+// TemplateFuncs helpers add Get-style access:
+//
+//	{{get . "users" 0 "name"}}         value at the keys, nil if missing
+//	{{path . "users.0.name"}}          the same with a dotted path
+//	{{has . "user" "email"}}           whether the keys exist
+//	{{get . "nick" | default "anon"}}  fallback for nil or empty values
+//	{{json .user}}                     the value as compact JSON
+func (j JSONValue) Render(tmpl string) (string, error) {
+	if j.err != nil {
+		return "", j.err
+	}
+
+	t, err := template.New("jsjson").Funcs(TemplateFuncs()).Parse(tmpl)
+	if err != nil {
+		return "", &JSONError{Op: "Render", Err: err}
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, j.data); err != nil {
+		return "", &JSONError{Op: "Render", Err: err}
+	}
+	return sb.String(), nil
+}
+
+// TemplateFuncs returns the helpers Render provides, for use with templates
+// parsed elsewhere. Each accepts the template's data, any value within it,
+// or a JSONValue.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"get": func(v interface{}, keys ...interface{}) interface{} {
+			return templateValue(v).Get(keys...).Raw()
+		},
+		"path": func(v interface{}, path string) interface{} {
+			if path == "" {
+				return templateValue(v).Raw()
+			}
+			keys := make([]interface{}, 0, strings.Count(path, ".")+1)
+			for _, k := range strings.Split(path, ".") {
+				keys = append(keys, k)
+			}
+			return templateValue(v).Get(keys...).Raw()
+		},
+		"has": func(v interface{}, keys ...interface{}) bool {
+			return templateValue(v).Has(keys...)
+		},
+		"default": func(def, v interface{}) interface{} {
+			if v == nil || v == "" {
+				return def
+			}
+			return v
+		},
+		"json": func(v interface{}) (string, error) {
+			return Stringify(templateValue(v))
+		},
+	}
+}
+
+// templateValue wraps a template argument for navigation
+func templateValue(v interface{}) JSONValue {
+	if jv, ok := v.(JSONValue); ok {
+		return jv
+	}
+	return JSONValue{data: v}
+}
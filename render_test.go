@@ -0,0 +1,61 @@
+package jsjson_test
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestRender(t *testing.T) {
+	data := JSON.Parse(`{
+		"user": {"name": "Ada", "email": "ada@example.com", "nick": ""},
+		"orders": [{"id": 1, "total": 12.5}, {"id": 2, "total": 3}]
+	}`)
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"fields", `Hi {{.user.name}}`, "Hi Ada"},
+		{"range", `{{range .orders}}#{{.id}}={{.total}} {{end}}`, "#1=12.5 #2=3 "},
+		{"get", `{{get . "orders" 1 "id"}}`, "2"},
+		{"path", `{{path . "orders.0.total"}}`, "12.5"},
+		{"has", `{{if has . "user" "email"}}yes{{end}}{{if has . "user" "phone"}}no{{end}}`, "yes"},
+		{"default", `{{get . "user" "nick" | default "anon"}} {{get . "missing" | default 0}}`, "anon 0"},
+		{"json", `{{json .user}}`, `{"email":"ada@example.com","name":"Ada","nick":""}`},
+		{"nested helpers", `{{with get . "orders" 0}}{{get . "id"}}{{end}}`, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := data.Render(tt.tmpl)
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %q, got %q (%v)", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := JSON.Parse(`{}`).Render(`{{.a`); err == nil {
+		t.Error("Expected a template parse error")
+	}
+	if _, err := JSON.Parse(`{"a": 1}`).Render(`{{.a.b.c}}`); err == nil {
+		t.Error("Expected an execution error")
+	}
+	invalid := JSON.Parse(`{bad`)
+	if _, err := invalid.Render(`x`); err != invalid.Error() {
+		t.Errorf("Expected the value's error, got %v", err)
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("t").Funcs(JSON.TemplateFuncs()).Parse(`{{get .Doc "a" 1}}`))
+	var sb strings.Builder
+	err := tmpl.Execute(&sb, struct{ Doc JSON.JSONValue }{JSON.Parse(`{"a": [1, "two"]}`)})
+	if err != nil || sb.String() != "two" {
+		t.Errorf("Expected two, got %q (%v)", sb.String(), err)
+	}
+}
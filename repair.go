@@ -0,0 +1,434 @@
+package jsjson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// -------------------- Repair --------------------
+
+// Fix is one change Repair made to its input
+type Fix struct {
+	// Offset is the byte offset in the input where the change applies
+	Offset int
+	// Line and Column are 1-based, as in SyntaxError
+	Line   int
+	Column int
+	// Description says what was changed, e.g. "quoted key name"
+	Description string
+}
+
+// String returns the fix in the form "line 2, column 5: quoted key name"
+func (f Fix) String() string {
+	return fmt.Sprintf("line %d, column %d: %s", f.Line, f.Column, f.Description)
+}
+
+// Repair parses almost-valid JSON, such as the output of a language model or
+// a hand-edited file, fixing common mistakes and reporting each change:
+//
+//	jv, fixes, err := jsjson.Repair("```json\n{name: 'Ann', admin: True, tags: ['a', 'b',]\n```")
+//	// jv is {"name": "Ann", "admin": true, "tags": ["a", "b"]}
+//	for _, f := range fixes {
+//	    log.Print(f) // "line 1, column 1: removed Markdown code fence", ...
+//	}
+//
+// It fixes unquoted keys, single-quoted strings, Python and JavaScript
+// literals (True, False, None, undefined), trailing and missing commas,
+// comments, raw line breaks and tabs inside strings, a byte order mark, a
+// surrounding Markdown code fence and input that ends early: an unterminated
+// string is closed, an incomplete last member dropped and open arrays and
+// objects closed.
+// Valid JSON comes back unchanged with no fixes. Input it cannot repair
+// fails with a *SyntaxError locating the problem in the original text,
+// together with the fixes made before it.
+func Repair(input string) (JSONValue, []Fix, error) {
+	r := &repairer{in: input, end: len(input)}
+	if strings.HasPrefix(input, "\ufeff") {
+		r.fix(0, "removed byte order mark")
+		r.pos = len("\ufeff")
+	}
+	r.stripFence()
+	r.skipSpace()
+	if r.pos >= r.end {
+		err := &JSONError{Op: "Repair", Err: fmt.Errorf("%w: no JSON value in input", ErrSyntax)}
+		return JSONValue{err: err}, r.fixes, err
+	}
+	err := r.value()
+	if err == nil {
+		r.skipSpace()
+		if r.pos < r.end {
+			err = r.unexpected("after top-level value")
+		}
+	}
+	if errors.Is(err, errRepairEOF) {
+		err = newSyntaxError([]byte(r.in), r.end, "unexpected end of input", nil)
+	}
+	if err != nil {
+		jerr := &JSONError{Op: "Repair", Err: err}
+		return JSONValue{err: jerr}, r.fixes, jerr
+	}
+
+	jv := ParseNoCopy(r.out)
+	if jv.err != nil {
+		err := withOp("Repair", jv.err)
+		return JSONValue{err: err}, r.fixes, err
+	}
+	return jv, r.fixes, nil
+}
+
+// errRepairEOF reports input ending inside a value; containers recover from
+// it by dropping their incomplete last member
+var errRepairEOF = errors.New("unexpected end of input")
+
+// repairer rewrites in[pos:end] into valid JSON text in out
+type repairer struct {
+	in    string
+	pos   int
+	end   int
+	out   []byte
+	fixes []Fix
+}
+
+func (r *repairer) fix(offset int, description string) {
+	line := strings.Count(r.in[:offset], "\n") + 1
+	lineStart := strings.LastIndexByte(r.in[:offset], '\n') + 1
+	r.fixes = append(r.fixes, Fix{
+		Offset:      offset,
+		Line:        line,
+		Column:      utf8.RuneCountInString(r.in[lineStart:offset]) + 1,
+		Description: description,
+	})
+}
+
+func (r *repairer) unexpected(context string) error {
+	if r.pos >= r.end {
+		return errRepairEOF
+	}
+	c, _ := utf8.DecodeRuneInString(r.in[r.pos:])
+	return newSyntaxError([]byte(r.in), r.pos, fmt.Sprintf("invalid character %q %s", c, context), nil)
+}
+
+// stripFence limits the input to the inside of a Markdown code fence
+// wrapping it, as in "```json\n{...}\n```"
+func (r *repairer) stripFence() {
+	start := len(r.in) - len(strings.TrimLeft(r.in[r.pos:], " \t\r\n"))
+	if !strings.HasPrefix(r.in[start:], "```") {
+		return
+	}
+	nl := strings.IndexByte(r.in[start:], '\n')
+	if nl < 0 {
+		return
+	}
+	r.fix(start, "removed Markdown code fence")
+	r.pos = start + nl + 1
+	trimmed := strings.TrimRight(r.in, " \t\r\n")
+	if strings.HasSuffix(trimmed, "```") && len(trimmed)-3 >= r.pos {
+		r.end = len(trimmed) - 3
+	}
+}
+
+// skipSpace skips whitespace and comments
+func (r *repairer) skipSpace() {
+	for r.pos < r.end {
+		switch c := r.in[r.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			r.pos++
+		case strings.HasPrefix(r.in[r.pos:r.end], "//") || c == '#':
+			r.fix(r.pos, "removed comment")
+			if i := strings.IndexByte(r.in[r.pos:r.end], '\n'); i >= 0 {
+				r.pos += i + 1
+			} else {
+				r.pos = r.end
+			}
+		case strings.HasPrefix(r.in[r.pos:r.end], "/*"):
+			r.fix(r.pos, "removed comment")
+			if i := strings.Index(r.in[r.pos+2:r.end], "*/"); i >= 0 {
+				r.pos += i + 4
+			} else {
+				r.pos = r.end
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (r *repairer) peek() byte {
+	if r.pos < r.end {
+		return r.in[r.pos]
+	}
+	return 0
+}
+
+// foreignLiterals maps the Python and JavaScript literals Repair accepts to
+// their JSON spelling
+var foreignLiterals = map[string]string{
+	"True":      "true",
+	"False":     "false",
+	"None":      "null",
+	"undefined": "null",
+}
+
+func (r *repairer) value() error {
+	switch c := r.peek(); {
+	case c == '{':
+		return r.container('{', '}')
+	case c == '[':
+		return r.container('[', ']')
+	case c == '"' || c == '\'':
+		r.str()
+		return nil
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return r.number()
+	case isIdentStart(c):
+		start := r.pos
+		word := r.ident()
+		switch word {
+		case "true", "false", "null":
+			r.out = append(r.out, word...)
+		default:
+			lit, ok := foreignLiterals[word]
+			if !ok {
+				r.pos = start
+				return r.unexpected("looking for beginning of value")
+			}
+			r.fix(start, fmt.Sprintf("replaced %s with %s", word, lit))
+			r.out = append(r.out, lit...)
+		}
+		return nil
+	default:
+		return r.unexpected("looking for beginning of value")
+	}
+}
+
+// container copies an object or array. Members whose separators are
+// missing or doubled are fixed, and at the end of input the incomplete last
+// member is dropped and the container closed.
+func (r *repairer) container(open, close byte) error {
+	r.pos++
+	r.out = append(r.out, open)
+	members := 0
+	for {
+		r.skipSpace()
+		if r.pos >= r.end {
+			r.fix(r.pos, fmt.Sprintf("added missing %q", close))
+			r.out = append(r.out, close)
+			return nil
+		}
+		if c := r.peek(); c == close {
+			r.pos++
+			r.out = append(r.out, close)
+			return nil
+		} else if c == ',' {
+			if members == 0 {
+				r.fix(r.pos, "removed extra comma")
+				r.pos++
+				continue
+			}
+			commaAt := r.pos
+			r.pos++
+			r.skipSpace()
+			switch r.peek() {
+			case close:
+				r.fix(commaAt, "removed trailing comma")
+				continue
+			case ',':
+				r.fix(r.pos, "removed extra comma")
+				continue
+			}
+			if r.pos < r.end {
+				r.out = append(r.out, ',')
+			}
+			continue
+		} else if members > 0 && !bytes.HasSuffix(r.out, []byte{','}) {
+			if open == '[' && !startsValue(c) {
+				return r.unexpected("after array element")
+			}
+			if open == '{' && !startsKey(c) {
+				return r.unexpected("after object key:value pair")
+			}
+			r.fix(r.pos, "added missing comma")
+			r.out = append(r.out, ',')
+		}
+
+		mark, markPos := len(r.out), r.pos
+		err := r.member(open == '{')
+		if errors.Is(err, errRepairEOF) {
+			r.fix(markPos, "removed incomplete member")
+			r.out = bytes.TrimSuffix(r.out[:mark], []byte{','})
+			r.pos = r.end
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		members++
+	}
+}
+
+// member copies an array element, or an object key and its value
+func (r *repairer) member(object bool) error {
+	if !object {
+		return r.value()
+	}
+	switch c := r.peek(); {
+	case c == '"' || c == '\'':
+		if closed := r.str(); !closed {
+			return errRepairEOF
+		}
+	case isIdentStart(c):
+		r.fix(r.pos, "quoted key name")
+		r.out = append(r.out, '"')
+		r.out = append(r.out, r.ident()...)
+		r.out = append(r.out, '"')
+	default:
+		return r.unexpected("looking for beginning of object key string")
+	}
+	r.skipSpace()
+	if r.peek() != ':' {
+		return r.unexpected("after object key")
+	}
+	r.pos++
+	r.out = append(r.out, ':')
+	r.skipSpace()
+	return r.value()
+}
+
+// str copies a double- or single-quoted string as a double-quoted one,
+// escaping raw control characters, and reports whether it was closed
+// before the end of input
+func (r *repairer) str() bool {
+	quote := r.in[r.pos]
+	if quote == '\'' {
+		r.fix(r.pos, "replaced single quotes")
+	}
+	r.pos++
+	r.out = append(r.out, '"')
+	for r.pos < r.end {
+		c := r.in[r.pos]
+		switch {
+		case c == quote:
+			r.pos++
+			r.out = append(r.out, '"')
+			return true
+		case c == '\\' && r.pos+1 < r.end:
+			if r.in[r.pos+1] == '\'' {
+				r.out = append(r.out, '\'')
+			} else {
+				r.out = append(r.out, c, r.in[r.pos+1])
+			}
+			r.pos += 2
+			continue
+		case c == '"':
+			r.out = append(r.out, '\\', '"')
+		case c < 0x20:
+			r.fix(r.pos, "escaped control character in string")
+			r.out = append(r.out, fmt.Sprintf(`\u%04x`, c)...)
+		default:
+			r.out = append(r.out, c)
+		}
+		r.pos++
+	}
+	r.fix(r.pos, "closed unterminated string")
+	r.out = append(r.out, '"')
+	return false
+}
+
+// number copies a number, dropping a leading + and adding the zero a
+// leading or trailing decimal point leaves out. Anything else that is not a
+// JSON number, such as 01, 1-2 or 1e, is reported where it appears in the
+// input.
+func (r *repairer) number() error {
+	start := r.pos
+	switch r.peek() {
+	case '+':
+		r.fix(start, "removed leading +")
+		r.pos++
+	case '-':
+		r.out = append(r.out, '-')
+		r.pos++
+	}
+
+	intStart := r.pos
+	r.digits()
+	switch {
+	case r.pos-intStart > 1 && r.in[intStart] == '0':
+		r.pos = intStart + 1
+		return r.unexpected("after leading zero in numeric literal")
+	case r.pos == intStart && r.peek() != '.':
+		return r.unexpected("in numeric literal")
+	case r.pos == intStart:
+		if r.pos+1 >= r.end || !isDigit(r.in[r.pos+1]) {
+			r.pos++
+			return r.unexpected("after decimal point in numeric literal")
+		}
+		r.fix(start, "added leading zero")
+		r.out = append(r.out, '0')
+	}
+	r.out = append(r.out, r.in[intStart:r.pos]...)
+
+	if r.peek() == '.' {
+		r.pos++
+		r.out = append(r.out, '.')
+		fracStart := r.pos
+		if r.digits(); r.pos == fracStart {
+			r.fix(start, "added zero after decimal point")
+			r.out = append(r.out, '0')
+		}
+		r.out = append(r.out, r.in[fracStart:r.pos]...)
+	}
+
+	if c := r.peek(); c == 'e' || c == 'E' {
+		expStart := r.pos
+		r.pos++
+		if c := r.peek(); c == '+' || c == '-' {
+			r.pos++
+		}
+		digitsStart := r.pos
+		if r.digits(); r.pos == digitsStart {
+			return r.unexpected("in exponent of numeric literal")
+		}
+		r.out = append(r.out, r.in[expStart:r.pos]...)
+	}
+
+	if c := r.peek(); c == '+' || c == '-' || c == '.' || c == 'e' || c == 'E' {
+		return r.unexpected("after numeric literal")
+	}
+	return nil
+}
+
+// digits skips a run of decimal digits
+func (r *repairer) digits() {
+	for r.pos < r.end && isDigit(r.in[r.pos]) {
+		r.pos++
+	}
+}
+
+func (r *repairer) ident() string {
+	start := r.pos
+	for r.pos < r.end && (isIdentStart(r.in[r.pos]) || isDigit(r.in[r.pos])) {
+		r.pos++
+	}
+	return r.in[start:r.pos]
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// startsValue reports whether c can begin a value Repair accepts
+func startsValue(c byte) bool {
+	return c == '{' || c == '[' || c == '"' || c == '\'' || c == '-' || c == '+' || c == '.' || isDigit(c) || isIdentStart(c)
+}
+
+// startsKey reports whether c can begin an object key Repair accepts
+func startsKey(c byte) bool {
+	return c == '"' || c == '\'' || isIdentStart(c)
+}
@@ -0,0 +1,134 @@
+package jsjson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		wantFixes []string
+	}{
+		{
+			name:  "valid input is unchanged",
+			input: `{"a": [1, -2.5e3, "x\"y"], "b": null}`,
+			want:  `{"a": [1, -2.5e3, "x\"y"], "b": null}`,
+		},
+		{
+			name:      "unquoted keys and single quotes",
+			input:     `{name: 'Ann', 'it\'s': "a \"b\""}`,
+			want:      `{"name": "Ann", "it's": "a \"b\""}`,
+			wantFixes: []string{"line 1, column 2: quoted key name", "line 1, column 8: replaced single quotes", "line 1, column 15: replaced single quotes"},
+		},
+		{
+			name:      "python literals",
+			input:     `[True, False, None, undefined]`,
+			want:      `[true, false, null, null]`,
+			wantFixes: []string{"line 1, column 2: replaced True with true", "line 1, column 8: replaced False with false", "line 1, column 15: replaced None with null", "line 1, column 21: replaced undefined with null"},
+		},
+		{
+			name:      "trailing and missing commas",
+			input:     "{\"a\": 1\n \"b\": [1, 2,],}",
+			want:      `{"a": 1, "b": [1, 2]}`,
+			wantFixes: []string{"line 2, column 2: added missing comma", "line 2, column 12: removed trailing comma", "line 2, column 14: removed trailing comma"},
+		},
+		{
+			name:      "missing closing brackets",
+			input:     `{"a": {"b": [1, 2`,
+			want:      `{"a": {"b": [1, 2]}}`,
+			wantFixes: []string{`line 1, column 18: added missing ']'`, `line 1, column 18: added missing '}'`, `line 1, column 18: added missing '}'`},
+		},
+		{
+			name:      "incomplete member is dropped",
+			input:     `{"a": 1, "b": `,
+			want:      `{"a": 1}`,
+			wantFixes: []string{"line 1, column 10: removed incomplete member", `line 1, column 15: added missing '}'`},
+		},
+		{
+			name:      "unterminated string",
+			input:     `["a", "b`,
+			want:      `["a", "b"]`,
+			wantFixes: []string{"line 1, column 9: closed unterminated string", `line 1, column 9: added missing ']'`},
+		},
+		{
+			name:      "code fence and comments",
+			input:     "```json\n{\n  // the user\n  \"a\": 1 /* one */\n}\n```\n",
+			want:      `{"a": 1}`,
+			wantFixes: []string{"line 1, column 1: removed Markdown code fence", "line 3, column 3: removed comment", "line 4, column 10: removed comment"},
+		},
+		{
+			name:      "raw newline in string",
+			input:     "{\"text\": \"two\nlines\"}",
+			want:      `{"text": "two\nlines"}`,
+			wantFixes: []string{"line 1, column 14: escaped control character in string"},
+		},
+		{
+			name:      "numbers",
+			input:     `[+1, .5, 2.]`,
+			want:      `[1, 0.5, 2.0]`,
+			wantFixes: []string{"line 1, column 2: removed leading +", "line 1, column 6: added leading zero", "line 1, column 10: added zero after decimal point"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes, err := JSON.Repair(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Raw(), JSON.Parse(tt.want).Raw()) {
+				t.Errorf("Expected %s, got %v", tt.want, got.Raw())
+			}
+			var gotFixes []string
+			for _, f := range fixes {
+				gotFixes = append(gotFixes, f.String())
+			}
+			if !reflect.DeepEqual(gotFixes, tt.wantFixes) {
+				t.Errorf("Expected fixes %q, got %q", tt.wantFixes, gotFixes)
+			}
+		})
+	}
+}
+
+func TestRepairFailure(t *testing.T) {
+	_, _, err := JSON.Repair("{\"a\": 1,\n \"b\": @}")
+	var serr *JSON.SyntaxError
+	if !errors.As(err, &serr) {
+		t.Fatalf("Expected a *SyntaxError, got %v", err)
+	}
+	if serr.Line != 2 || serr.Column != 7 {
+		t.Errorf("Expected line 2, column 7, got line %d, column %d", serr.Line, serr.Column)
+	}
+
+	if _, _, err := JSON.Repair("  "); !errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Expected ErrSyntax for empty input, got %v", err)
+	}
+
+	// Malformed numbers are located in the original text, not the rewrite
+	numbers := []struct {
+		input  string
+		column int
+	}{
+		{`{"a": 01}`, 8},
+		{`{'a': 1-2}`, 8},
+		{`[+1e]`, 5},
+		{`[1, -x]`, 6},
+		{`[.]`, 3},
+	}
+	for _, tt := range numbers {
+		_, _, err := JSON.Repair(tt.input)
+		if !errors.As(err, &serr) {
+			t.Errorf("%s: expected a *SyntaxError, got %v", tt.input, err)
+			continue
+		}
+		if serr.Line != 1 || serr.Column != tt.column {
+			t.Errorf("%s: expected column %d, got line %d, column %d (%v)", tt.input, tt.column, serr.Line, serr.Column, err)
+		}
+	}
+}
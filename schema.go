@@ -0,0 +1,202 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"math"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// -------------------- Schema Inference --------------------
+
+// schemaDialect is the JSON Schema version InferSchema produces
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// shape accumulates what has been seen at one position of one or more
+// sample documents
+type shape struct {
+	types map[string]bool
+
+	// objects
+	objects  int
+	props    map[string]*shape
+	keyCount map[string]int
+
+	// arrays
+	items *shape
+
+	// strings: the format shared by every string seen, "" if none or mixed
+	format  string
+	strings int
+}
+
+// InferSchema returns a best-effort JSON Schema (draft 2020-12) describing
+// the value, for documenting payloads known only from examples. Objects list
+// their properties, and keys present in every object seen at that position
+// are marked required. Array items are merged into a single schema, integral
+// numbers are "integer", and strings that all look like timestamps, dates,
+// emails, UUIDs or URIs get a "format". The result can be serialized with
+// Stringify like any other value.
+func (j JSONValue) InferSchema() JSONValue {
+	if j.err != nil {
+		return j
+	}
+	s := &shape{}
+	s.add(j.data)
+	schema := s.schema()
+	schema["$schema"] = schemaDialect
+	return JSONValue{data: schema}
+}
+
+// add merges one value into the shape
+func (s *shape) add(v interface{}) {
+	if s.types == nil {
+		s.types = make(map[string]bool)
+	}
+
+	switch c := v.(type) {
+	case nil:
+		s.types["null"] = true
+	case bool:
+		s.types["boolean"] = true
+	case float64:
+		s.addNumber(c == math.Trunc(c))
+	case int, int64:
+		s.addNumber(true)
+	case json.Number:
+		s.addNumber(!strings.ContainsAny(string(c), ".eE"))
+	case string:
+		s.types["string"] = true
+		s.addString(c)
+	case []interface{}:
+		s.types["array"] = true
+		if s.items == nil && len(c) > 0 {
+			s.items = &shape{}
+		}
+		for _, item := range c {
+			s.items.add(item)
+		}
+	case map[string]interface{}:
+		s.types["object"] = true
+		if s.props == nil {
+			s.props = make(map[string]*shape)
+			s.keyCount = make(map[string]int)
+		}
+		s.objects++
+		for k, val := range c {
+			p := s.props[k]
+			if p == nil {
+				p = &shape{}
+				s.props[k] = p
+			}
+			p.add(val)
+			s.keyCount[k]++
+		}
+	}
+}
+
+func (s *shape) addNumber(integral bool) {
+	if integral {
+		s.types["integer"] = true
+	} else {
+		s.types["number"] = true
+	}
+}
+
+func (s *shape) addString(v string) {
+	if f := stringFormat(v); s.strings == 0 {
+		s.format = f
+	} else if f != s.format {
+		s.format = ""
+	}
+	s.strings++
+}
+
+// stringFormat returns the JSON Schema format a string matches, if any
+func stringFormat(v string) string {
+	switch {
+	case len(v) >= 20 && v[4] == '-' && v[10] == 'T' && parsesAs(time.RFC3339Nano, v):
+		return "date-time"
+	case len(v) == 10 && v[4] == '-' && parsesAs("2006-01-02", v):
+		return "date"
+	case strings.Contains(v, "@") && isEmail(v):
+		return "email"
+	case len(v) == 36 && v[8] == '-':
+		if _, ok := parseUUID(v); ok {
+			return "uuid"
+		}
+	case strings.Contains(v, "://"):
+		if u, err := url.Parse(v); err == nil && u.Scheme != "" && u.Host != "" {
+			return "uri"
+		}
+	}
+	return ""
+}
+
+func parsesAs(layout, v string) bool {
+	_, err := time.Parse(layout, v)
+	return err == nil
+}
+
+// isEmail reports whether v is a bare address such as name@example.com
+func isEmail(v string) bool {
+	addr, err := mail.ParseAddress(v)
+	return err == nil && addr.Address == v && addr.Name == ""
+}
+
+// schema renders the shape as a JSON Schema object
+func (s *shape) schema() map[string]interface{} {
+	out := make(map[string]interface{})
+
+	types := make([]string, 0, len(s.types))
+	for t := range s.types {
+		if t == "integer" && s.types["number"] {
+			continue
+		}
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	switch len(types) {
+	case 0:
+	case 1:
+		out["type"] = types[0]
+	default:
+		list := make([]interface{}, len(types))
+		for i, t := range types {
+			list[i] = t
+		}
+		out["type"] = list
+	}
+
+	if s.format != "" {
+		out["format"] = s.format
+	}
+
+	if s.types["object"] {
+		props := make(map[string]interface{}, len(s.props))
+		var required []string
+		for k, p := range s.props {
+			props[k] = p.schema()
+			if s.keyCount[k] == s.objects {
+				required = append(required, k)
+			}
+		}
+		out["properties"] = props
+		if len(required) > 0 {
+			sort.Strings(required)
+			list := make([]interface{}, len(required))
+			for i, k := range required {
+				list[i] = k
+			}
+			out["required"] = list
+		}
+	}
+
+	if s.items != nil {
+		out["items"] = s.items.schema()
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package jsjson_test
+
+import (
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestInferSchema(t *testing.T) {
+	sample := JSON.Parse(`{
+		"id": 42,
+		"price": 9.99,
+		"name": "Widget",
+		"active": true,
+		"created": "2023-06-15T10:30:00Z",
+		"contact": "sales@example.com",
+		"ref": "123e4567-e89b-12d3-a456-426614174000",
+		"homepage": "https://example.com/widget",
+		"tags": ["a", "b"],
+		"variants": [
+			{"sku": "A-1", "stock": 3, "note": null},
+			{"sku": "A-2", "stock": 0.5}
+		],
+		"empty": [],
+		"parent": null
+	}`)
+
+	schema := sample.InferSchema()
+	if !schema.IsValid() {
+		t.Fatalf("InferSchema failed: %v", schema.Error())
+	}
+
+	tests := []struct {
+		path []interface{}
+		want string
+	}{
+		{[]interface{}{"$schema"}, `"https://json-schema.org/draft/2020-12/schema"`},
+		{[]interface{}{"type"}, `"object"`},
+		{[]interface{}{"properties", "id"}, `{"type":"integer"}`},
+		{[]interface{}{"properties", "price"}, `{"type":"number"}`},
+		{[]interface{}{"properties", "active"}, `{"type":"boolean"}`},
+		{[]interface{}{"properties", "created"}, `{"format":"date-time","type":"string"}`},
+		{[]interface{}{"properties", "contact"}, `{"format":"email","type":"string"}`},
+		{[]interface{}{"properties", "ref"}, `{"format":"uuid","type":"string"}`},
+		{[]interface{}{"properties", "homepage"}, `{"format":"uri","type":"string"}`},
+		{[]interface{}{"properties", "name"}, `{"type":"string"}`},
+		{[]interface{}{"properties", "tags"}, `{"items":{"type":"string"},"type":"array"}`},
+		{[]interface{}{"properties", "empty"}, `{"type":"array"}`},
+		{[]interface{}{"properties", "parent"}, `{"type":"null"}`},
+		{[]interface{}{"properties", "variants", "items", "required"}, `["sku","stock"]`},
+		{[]interface{}{"properties", "variants", "items", "properties", "stock"}, `{"type":"number"}`},
+		{[]interface{}{"properties", "variants", "items", "properties", "note"}, `{"type":"null"}`},
+		{[]interface{}{"required"}, `["active","contact","created","empty","homepage","id","name","parent","price","ref","tags","variants"]`},
+	}
+
+	for _, tt := range tests {
+		got, err := JSON.Stringify(schema.Get(tt.path...))
+		if err != nil || got != tt.want {
+			t.Errorf("%v: expected %s, got %s (err: %v)", tt.path, tt.want, got, err)
+		}
+	}
+}
+
+func TestInferSchemaMixedTypes(t *testing.T) {
+	schema := JSON.Parse(`[1, "x", null, "2023-01-01", {"a": 1}, {"b": true}]`).InferSchema()
+
+	items := schema.Get("items")
+	if got, _ := JSON.Stringify(items.Get("type")); got != `["integer","null","object","string"]` {
+		t.Errorf("Expected a type union, got %s", got)
+	}
+	if items.Has("format") {
+		t.Error("Expected no format for strings with different formats")
+	}
+	if items.Has("required") {
+		t.Error("Expected no required keys when no key is in every object")
+	}
+}
+
+func TestInferSchemaPropagatesErrors(t *testing.T) {
+	if JSON.Parse(`{`).InferSchema().IsValid() {
+		t.Error("Expected the parse error to propagate")
+	}
+}
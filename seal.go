@@ -0,0 +1,111 @@
+package jsjson
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// -------------------- Sealed Documents --------------------
+//
+// A sealed document is a 5-byte header ("JSJS" and a format version), a
+// 12-byte random nonce, and the AES-GCM encryption of the canonical JSON
+// text (sorted keys, as hashed by Pack) with the header as additional data.
+
+var sealMagic = []byte("JSJS\x01")
+
+const sealNonceSize = 12
+
+// SealTo encrypts the value with AES-GCM under key and writes it to w, so
+// small secret stores and cached tokens can be kept on disk safely:
+//
+//	key := make([]byte, 32) // from a KMS or secret manager
+//	err := tokens.SealTo(f, key)
+//	...
+//	tokens := jsjson.OpenSealed(f, key)
+//
+// key must be 16, 24 or 32 bytes for AES-128, AES-192 or AES-256. Every call
+// uses a fresh random nonce, so sealing the same value twice gives different
+// output. The encryption authenticates the data: OpenSealed rejects any
+// modified or truncated output rather than returning a corrupted document.
+func (j JSONValue) SealTo(w io.Writer, key []byte) error {
+	if j.err != nil {
+		return j.err
+	}
+	data, err := normalize(j.data)
+	if err != nil {
+		return &JSONError{Op: "SealTo", Err: err}
+	}
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return &JSONError{Op: "SealTo", Err: err}
+	}
+	aead, err := newSealCipher(key)
+	if err != nil {
+		return &JSONError{Op: "SealTo", Err: err}
+	}
+
+	out := make([]byte, len(sealMagic)+sealNonceSize, len(sealMagic)+sealNonceSize+len(plaintext)+aead.Overhead())
+	copy(out, sealMagic)
+	nonce := out[len(sealMagic):]
+	if _, err := rand.Read(nonce); err != nil {
+		return &JSONError{Op: "SealTo", Err: err}
+	}
+	out = aead.Seal(out, nonce, plaintext, sealMagic)
+	if _, err := w.Write(out); err != nil {
+		return &JSONError{Op: "SealTo", Err: err}
+	}
+	return nil
+}
+
+// OpenSealed reads a document written by SealTo, decrypts it with key and
+// parses it. opts apply as in Parse; WithMaxBytes limits the decrypted size.
+// A wrong key or modified input fails without revealing any of the content.
+func OpenSealed(r io.Reader, key []byte, opts ...ParseOption) JSONValue {
+	fail := func(err error) JSONValue {
+		return JSONValue{err: withOp("OpenSealed", err)}
+	}
+	aead, err := newSealCipher(key)
+	if err != nil {
+		return fail(err)
+	}
+
+	o := newParseOptions(opts)
+	src := r
+	if o.maxBytes > 0 {
+		src = io.LimitReader(r, int64(len(sealMagic)+sealNonceSize+aead.Overhead())+o.maxBytes+1)
+	}
+	sealed, err := io.ReadAll(src)
+	if err != nil {
+		return fail(err)
+	}
+	if !bytes.HasPrefix(sealed, sealMagic) || len(sealed) < len(sealMagic)+sealNonceSize+aead.Overhead() {
+		return fail(fmt.Errorf("%w: not a sealed document", ErrSyntax))
+	}
+	if o.maxBytes > 0 && int64(len(sealed)-len(sealMagic)-sealNonceSize-aead.Overhead()) > o.maxBytes {
+		return fail(fmt.Errorf("%w: input is larger than %d bytes", ErrLimitExceeded, o.maxBytes))
+	}
+
+	nonce := sealed[len(sealMagic) : len(sealMagic)+sealNonceSize]
+	plaintext, err := aead.Open(nil, nonce, sealed[len(sealMagic)+sealNonceSize:], sealMagic)
+	if err != nil {
+		return fail(fmt.Errorf("wrong key or modified document: %w", err))
+	}
+	jv := ParseNoCopy(plaintext, opts...)
+	if jv.err != nil {
+		return fail(jv.err)
+	}
+	return jv
+}
+
+func newSealCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,84 @@
+package jsjson_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestSealRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	doc := JSON.Parse(`{"token": "s3cr3t", "expires": 1700000000, "scopes": ["read", "write"]}`)
+
+	var first, second bytes.Buffer
+	if err := doc.SealTo(&first, key); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := doc.SealTo(&second, key); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("Expected a fresh nonce per seal")
+	}
+	if bytes.Contains(first.Bytes(), []byte("s3cr3t")) {
+		t.Error("Expected the content to be encrypted")
+	}
+
+	got := JSON.OpenSealed(bytes.NewReader(first.Bytes()), key)
+	if err := got.Error(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got.Raw(), doc.Raw()) {
+		t.Errorf("Expected %v, got %v", doc.Raw(), got.Raw())
+	}
+}
+
+func TestOpenSealedErrors(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 16)
+	var buf bytes.Buffer
+	if err := JSON.Parse(`{"items": [1, 2, 3]}`).SealTo(&buf, key); err != nil {
+		t.Fatal(err)
+	}
+	sealed := buf.Bytes()
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name    string
+		input   []byte
+		key     []byte
+		opts    []JSON.ParseOption
+		wantErr error
+		wantMsg string
+	}{
+		{"wrong key", sealed, bytes.Repeat([]byte{8}, 16), nil, nil, "wrong key"},
+		{"tampered", tampered, key, nil, nil, "wrong key"},
+		{"truncated", sealed[:20], key, nil, JSON.ErrSyntax, ""},
+		{"plain JSON", []byte(`{"items": []}`), key, nil, JSON.ErrSyntax, ""},
+		{"bad key size", sealed, []byte("short"), nil, nil, "key size"},
+		{"limit", sealed, key, []JSON.ParseOption{JSON.WithMaxBytes(8)}, JSON.ErrLimitExceeded, ""},
+		{"token limit", sealed, key, []JSON.ParseOption{JSON.WithMaxArrayElements(2)}, JSON.ErrLimitExceeded, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JSON.OpenSealed(bytes.NewReader(tt.input), tt.key, tt.opts...).Error()
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Expected %q in %v", tt.wantMsg, err)
+			}
+		})
+	}
+
+	if err := JSON.Parse(`1`).SealTo(&buf, []byte("short")); err == nil {
+		t.Error("Expected an error for a bad key size")
+	}
+}
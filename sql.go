@@ -0,0 +1,51 @@
+package jsjson
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// -------------------- database/sql Integration --------------------
+
+// Value implements driver.Valuer, so a JSONValue can be written straight to
+// a Postgres jsonb or MySQL JSON column. The value is sent as its JSON text;
+// the zero JSONValue and JSON null are stored as SQL NULL. A JSONValue
+// holding an error fails with that error.
+func (j JSONValue) Value() (driver.Value, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	if j.data == nil {
+		return nil, nil
+	}
+	var b []byte
+	if err := encodeInto(&b, j.data); err != nil {
+		return nil, &JSONError{Op: "Value", Err: err}
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner for JSON columns. It accepts the []byte or
+// string a driver returns and parses it, copying whatever it keeps, so the
+// driver may reuse its buffer. SQL NULL gives a null JSONValue.
+func (j *JSONValue) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*j = JSONValue{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return &JSONError{Op: "Scan", Err: fmt.Errorf("%w: cannot scan %T into JSONValue", ErrTypeMismatch, src)}
+	}
+
+	tree, err := parseTree(data, &parseOptions{}, false)
+	if err != nil {
+		return &JSONError{Op: "Scan", Err: err}
+	}
+	*j = JSONValue{data: tree}
+	return nil
+}
@@ -0,0 +1,65 @@
+package jsjson_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+var (
+	_ driver.Valuer = JSON.JSONValue{}
+	_ sql.Scanner   = (*JSON.JSONValue)(nil)
+)
+
+func TestJSONValueValue(t *testing.T) {
+	tests := []struct {
+		name string
+		in   JSON.JSONValue
+		want driver.Value
+	}{
+		{"object", JSON.Parse(`{"a": [1, true]}`), `{"a":[1,true]}`},
+		{"string", JSON.Parse(`"x"`), `"x"`},
+		{"null", JSON.Parse(`null`), nil},
+		{"zero", JSON.JSONValue{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.in.Value()
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %v, got %v (%v)", tt.want, got, err)
+			}
+		})
+	}
+
+	if _, err := JSON.Parse(`{bad`).Value(); err == nil {
+		t.Error("Expected the value's error")
+	}
+}
+
+func TestJSONValueScan(t *testing.T) {
+	buf := []byte(`{"name": "db", "n": 3}`)
+	var jv JSON.JSONValue
+	if err := jv.Scan(buf); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	copy(buf, `{"name": "XX"`)
+	if jv.Get("name").StringOr("") != "db" || jv.Get("n").IntOr(0) != 3 {
+		t.Errorf("Scan kept the driver's buffer: %v", jv.Raw())
+	}
+
+	if err := jv.Scan(`[1, 2]`); err != nil || jv.Get(1).IntOr(0) != 2 {
+		t.Errorf("Scan of a string failed: %v", err)
+	}
+	if err := jv.Scan(nil); err != nil || !jv.IsValid() || !jv.IsNull() {
+		t.Errorf("Expected a valid null for SQL NULL, got %v", err)
+	}
+	if err := jv.Scan(int64(1)); !errors.Is(err, JSON.ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got %v", err)
+	}
+	if err := jv.Scan([]byte(`{bad`)); !errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Expected ErrSyntax, got %v", err)
+	}
+}
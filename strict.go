@@ -0,0 +1,115 @@
+package jsjson
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// -------------------- Unknown Fields --------------------
+
+// DisallowUnknownFields makes ParseInto reject object keys that match no
+// field of the destination struct, so typos in user-supplied config are
+// caught instead of silently ignored. Every unknown key is reported with
+// its path, e.g. server.prot, in a *ValidationError together with any
+// failed validation tags. Keys are matched the way decoding matches them,
+// exactly or else case-insensitively; maps, interface{} fields and types
+// with their own UnmarshalJSON or UnmarshalText accept any key.
+func DisallowUnknownFields() ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.disallowUnknown = true })
+}
+
+// fieldSet maps the JSON keys a struct type accepts to its fields
+type fieldSet struct {
+	exact  map[string]*setField
+	folded map[string]*setField
+}
+
+type setField struct {
+	typ reflect.Type
+	// goName is the Go name the codec knows an untagged field by, if it
+	// differs from the key
+	goName string
+}
+
+var fieldSets sync.Map // reflect.Type -> *fieldSet
+
+func fieldSetFor(t reflect.Type) *fieldSet {
+	if fs, ok := fieldSets.Load(t); ok {
+		return fs.(*fieldSet)
+	}
+
+	fields := structFields(t)
+	fs := &fieldSet{
+		exact:  make(map[string]*setField, len(fields)),
+		folded: make(map[string]*setField, len(fields)),
+	}
+	for _, f := range fields {
+		sf := t.FieldByIndex(f.index)
+		field := &setField{typ: sf.Type}
+		if !f.tagged && f.name != sf.Name {
+			field.goName = sf.Name
+		}
+		fs.exact[f.name] = field
+		if key := foldName(f.name); fs.folded[key] == nil {
+			fs.folded[key] = field
+		}
+	}
+	actual, _ := fieldSets.LoadOrStore(t, fs)
+	return actual.(*fieldSet)
+}
+
+// collectUnknown appends a violation for every key in tree that a value of
+// type t would not decode
+func collectUnknown(t reflect.Type, tree interface{}, path string, violations *[]*Violation) {
+	for {
+		if t.Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) ||
+			t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+			return
+		}
+		if t.Kind() != reflect.Pointer {
+			break
+		}
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := tree.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fs := fieldSetFor(t)
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			f, ok := fs.exact[k]
+			if !ok {
+				f, ok = fs.folded[foldName(k)]
+			}
+			if !ok {
+				*violations = append(*violations, &Violation{Path: joinKey(path, k), Rule: "unknown", Message: "is not a known field"})
+				continue
+			}
+			collectUnknown(f.typ, obj[k], joinKey(path, k), violations)
+		}
+	case reflect.Slice, reflect.Array:
+		items, _ := tree.([]interface{})
+		for i, item := range items {
+			collectUnknown(t.Elem(), item, joinIndex(path, i), violations)
+		}
+	case reflect.Map:
+		obj, _ := tree.(map[string]interface{})
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectUnknown(t.Elem(), obj[k], joinKey(path, k), violations)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package jsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+type strictServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port" jsjson:"min=1"`
+}
+
+type strictConfig struct {
+	BindEmbedded
+	Name    string                  `json:"name"`
+	Server  *strictServer           `json:"server"`
+	Mirrors []strictServer          `json:"mirrors"`
+	ByZone  map[string]strictServer `json:"by_zone"`
+	Extra   map[string]interface{}  `json:"extra"`
+	Any     interface{}             `json:"any"`
+	Raw     json.RawMessage         `json:"raw"`
+	Skipped string                  `json:"-"`
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // violations as "path rule"
+	}{
+		{"known keys", `{"name": "a", "shared": "s", "Level": 1, "server": {"host": "h", "port": 1},
+			"extra": {"anything": 1}, "any": {"x": 1}, "raw": {"y": 2}}`, nil},
+		{"case-insensitive", `{"NAME": "a", "Server": {"HOST": "h"}}`, nil},
+		{"top-level typo", `{"nmae": "a", "Skipped": "x"}`, []string{"Skipped unknown", "nmae unknown"}},
+		{"nested paths", `{"server": {"prot": 80}, "mirrors": [{}, {"hots": "x"}], "by_zone": {"eu": {"port": 1, "tls": true}}}`,
+			[]string{"by_zone.eu.tls unknown", "mirrors[1].hots unknown", "server.prot unknown"}},
+		{"combined with tags", `{"server": {"port": 0, "tls": true}}`, []string{"server.tls unknown", "server.port min=1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, via := range []string{"string", "JSONValue"} {
+				var dest strictConfig
+				var err error
+				if via == "string" {
+					err = JSON.ParseInto(tt.input, &dest, JSON.DisallowUnknownFields())
+				} else {
+					err = JSON.ParseInto(JSON.Parse(tt.input), &dest, JSON.DisallowUnknownFields())
+				}
+
+				var got []string
+				var verr *JSON.ValidationError
+				if errors.As(err, &verr) {
+					for _, v := range verr.Violations {
+						got = append(got, v.Path+" "+v.Rule)
+					}
+				} else if err != nil {
+					t.Fatalf("%s: unexpected error %v", via, err)
+				}
+				if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+					t.Errorf("%s: expected %v, got %v", via, tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestUnknownFieldsAllowedByDefault(t *testing.T) {
+	var dest strictConfig
+	if err := JSON.ParseInto(`{"nmae": "a", "name": "b"}`, &dest); err != nil || dest.Name != "b" {
+		t.Errorf("Expected unknown keys to be ignored, got %v %+v", err, dest)
+	}
+	err := JSON.ParseInto(`{"nmae": "a"}`, &dest, JSON.DisallowUnknownFields())
+	if !errors.Is(err, JSON.ErrValidation) || !strings.Contains(err.Error(), "nmae: is not a known field") {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
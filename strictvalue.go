@@ -0,0 +1,143 @@
+package jsjson
+
+import (
+	"errors"
+	"time"
+)
+
+// -------------------- Deferred Errors --------------------
+
+// StrictValue reads a document without checking an error at every step:
+// conversions return the zero value on failure and record the error, and a
+// single Resolve at the end reports them all, much like the final Flush of a
+// bufio.Writer:
+//
+//	s := obj.Strict()
+//	name := s.Get("user", "name").String()
+//	age := s.Get("user", "age").Int()
+//	tags := s.Get("user", "tags").Array()
+//	if err := s.Resolve(); err != nil {
+//	    return err
+//	}
+//
+// Values returned by Get share the recorded errors with the StrictValue they
+// came from. A StrictValue is not safe for concurrent use.
+type StrictValue struct {
+	v    JSONValue
+	errs *[]error
+}
+
+// Strict returns a StrictValue for reading j with deferred errors. An error
+// j already holds is recorded at the first conversion.
+func (j JSONValue) Strict() StrictValue {
+	return StrictValue{v: j, errs: new([]error)}
+}
+
+// Get returns the value at keys. A failed access is recorded when the
+// result is converted, so chained Gets on it record a single error.
+func (s StrictValue) Get(keys ...interface{}) StrictValue {
+	return StrictValue{v: s.v.Get(keys...), errs: s.errs}
+}
+
+// record adds err, if any, to the recorded errors and reports whether there
+// was none
+func (s StrictValue) record(err error) bool {
+	if err == nil {
+		return true
+	}
+	*s.errs = append(*s.errs, err)
+	return false
+}
+
+// Value returns the JSONValue, recording its error if it has one
+func (s StrictValue) Value() JSONValue {
+	s.record(s.v.err)
+	return s.v
+}
+
+// String converts the value as JSONValue.String does
+func (s StrictValue) String() string {
+	v, err := s.v.String()
+	s.record(err)
+	return v
+}
+
+// Int converts the value as JSONValue.Int does
+func (s StrictValue) Int() int {
+	v, err := s.v.Int()
+	s.record(err)
+	return v
+}
+
+// Int64 converts the value as JSONValue.Int64 does
+func (s StrictValue) Int64() int64 {
+	v, err := s.v.Int64()
+	s.record(err)
+	return v
+}
+
+// Float64 converts the value as JSONValue.Float64 does
+func (s StrictValue) Float64() float64 {
+	v, err := s.v.Float64()
+	s.record(err)
+	return v
+}
+
+// Bool converts the value as JSONValue.Bool does
+func (s StrictValue) Bool() bool {
+	v, err := s.v.Bool()
+	s.record(err)
+	return v
+}
+
+// Time converts the value as JSONValue.Time does
+func (s StrictValue) Time() time.Time {
+	v, err := s.v.Time()
+	s.record(err)
+	return v
+}
+
+// Array returns the elements of an array, or nil if the value is not one
+func (s StrictValue) Array() []StrictValue {
+	items, err := s.v.Array()
+	if !s.record(err) {
+		return nil
+	}
+	result := make([]StrictValue, len(items))
+	for i, item := range items {
+		result[i] = StrictValue{v: item, errs: s.errs}
+	}
+	return result
+}
+
+// Object returns the members of an object, or nil if the value is not one
+func (s StrictValue) Object() map[string]StrictValue {
+	members, err := s.v.Object()
+	if !s.record(err) {
+		return nil
+	}
+	result := make(map[string]StrictValue, len(members))
+	for k, member := range members {
+		result[k] = StrictValue{v: member, errs: s.errs}
+	}
+	return result
+}
+
+// To decodes the value into dest as JSONValue.To does
+func (s StrictValue) To(dest interface{}) {
+	s.record(s.v.To(dest))
+}
+
+// Resolve returns the errors recorded so far joined into one, in the order
+// they occurred, or nil if there were none. Each can be matched with
+// errors.Is and errors.As.
+func (s StrictValue) Resolve() error {
+	return errors.Join(*s.errs...)
+}
+
+// Must panics with the recorded errors, if any
+func (s StrictValue) Must() {
+	if err := s.Resolve(); err != nil {
+		panic(err)
+	}
+}
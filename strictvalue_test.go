@@ -0,0 +1,55 @@
+package jsjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestStrictValue(t *testing.T) {
+	s := JSON.Parse(`{"user": {"name": "Ann", "age": "thirty", "tags": ["a", "b"]}}`).Strict()
+
+	name := s.Get("user", "name").String()
+	age := s.Get("user", "age").Int()
+	email := s.Get("user", "email", "address").String()
+	var tags []string
+	for _, tag := range s.Get("user", "tags").Array() {
+		tags = append(tags, tag.String())
+	}
+
+	if name != "Ann" || age != 0 || email != "" || strings.Join(tags, ",") != "a,b" {
+		t.Errorf("Unexpected values: name=%q age=%d email=%q tags=%v", name, age, email, tags)
+	}
+
+	err := s.Resolve()
+	if !errors.Is(err, JSON.ErrTypeMismatch) || !errors.Is(err, JSON.ErrKeyNotFound) {
+		t.Fatalf("Expected both errors to be reported, got %v", err)
+	}
+	if got := strings.Count(err.Error(), "\n") + 1; got != 2 {
+		t.Errorf("Expected 2 errors, got %d: %v", got, err)
+	}
+}
+
+func TestStrictValueResolveNil(t *testing.T) {
+	s := JSON.Parse(`{"n": 1}`).Strict()
+	if s.Get("n").Int() != 1 {
+		t.Error("Expected 1")
+	}
+	if err := s.Resolve(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	s.Must()
+}
+
+func TestStrictValueMust(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected Must to panic")
+		}
+	}()
+	s := JSON.Parse(`{}`).Strict()
+	s.Get("missing").Bool()
+	s.Must()
+}
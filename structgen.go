@@ -0,0 +1,294 @@
+package jsjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// -------------------- Struct Generation --------------------
+
+// GenerateStruct returns gofmt-formatted Go type declarations matching a
+// sample document, with json tags for every field:
+//
+//	src, err := jsjson.GenerateStruct(sample, "User")
+//	// type User struct {
+//	//     Address Address `json:"address"`
+//	//     ID      int64   `json:"id"`
+//	// ...
+//
+// Nested objects get their own struct types, named after their field. Every
+// element of an array contributes to its element type: a key missing from
+// some elements gets omitempty, and a key that is sometimes null becomes a
+// pointer. Numbers are int64 unless one has a fraction, and values of mixed
+// types are interface{}. A top-level array generates a slice type named
+// typeName over its element struct. Fields are in sorted key order.
+func GenerateStruct(jv JSONValue, typeName string) (string, error) {
+	if jv.err != nil {
+		return "", jv.err
+	}
+	if !isExportedIdent(typeName) {
+		return "", &JSONError{Op: "GenerateStruct", Err: fmt.Errorf("invalid type name %q", typeName)}
+	}
+
+	data, err := normalize(jv.data)
+	if err != nil {
+		return "", &JSONError{Op: "GenerateStruct", Err: err}
+	}
+	root := &genType{}
+	root.merge(data)
+
+	g := &structGen{used: map[string]bool{}}
+	switch root.kind {
+	case "object":
+		g.used[typeName] = true
+		g.emit(typeName, root)
+	case "array":
+		elem := root.elem
+		if elem == nil || elem.kind != "object" {
+			return "", &JSONError{Op: "GenerateStruct", Err: fmt.Errorf("%w: a top-level array must hold objects", ErrTypeMismatch)}
+		}
+		g.used[typeName] = true
+		item := g.name(singular(typeName), typeName+"Item")
+		fmt.Fprintf(&g.decls, "type %s []%s\n\n", typeName, item)
+		g.emit(item, elem)
+	default:
+		return "", &JSONError{Op: "GenerateStruct", Err: fmt.Errorf("%w: expected an object or an array of objects, got %s", ErrTypeMismatch, jv.Type())}
+	}
+
+	src, err := format.Source(append([]byte("package p\n\n"), g.decls.Bytes()...))
+	if err != nil {
+		return "", &JSONError{Op: "GenerateStruct", Err: err}
+	}
+	return string(bytes.TrimPrefix(src, []byte("package p\n\n"))), nil
+}
+
+// genType accumulates what the sample says about one position
+type genType struct {
+	kind     string // "", bool, int, float, string, object, array or mixed
+	nullable bool
+	objects  int                  // objects merged, for omitempty
+	fields   map[string]*genField // object
+	elem     *genType             // array elements; nil if all were empty
+}
+
+type genField struct {
+	typ   *genType
+	count int
+}
+
+func (t *genType) merge(v interface{}) {
+	var kind string
+	switch c := v.(type) {
+	case nil:
+		t.nullable = true
+		return
+	case bool:
+		kind = "bool"
+	case string:
+		kind = "string"
+	case float64:
+		kind = "float"
+		if c == float64(int64(c)) {
+			kind = "int"
+		}
+	case json.Number:
+		kind = "float"
+		if _, err := strconv.ParseInt(string(c), 10, 64); err == nil {
+			kind = "int"
+		}
+	case map[string]interface{}:
+		kind = "object"
+	case []interface{}:
+		kind = "array"
+	}
+
+	switch {
+	case t.kind == "" || t.kind == kind:
+		t.kind = kind
+	case (t.kind == "int" && kind == "float") || (t.kind == "float" && kind == "int"):
+		t.kind = "float"
+	default:
+		t.kind = "mixed"
+	}
+	if t.kind == "mixed" {
+		return
+	}
+
+	switch c := v.(type) {
+	case map[string]interface{}:
+		if t.fields == nil {
+			t.fields = make(map[string]*genField)
+		}
+		t.objects++
+		for k, fv := range c {
+			f := t.fields[k]
+			if f == nil {
+				f = &genField{typ: &genType{}}
+				t.fields[k] = f
+			}
+			f.count++
+			f.typ.merge(fv)
+		}
+	case []interface{}:
+		for _, item := range c {
+			if t.elem == nil {
+				t.elem = &genType{}
+			}
+			t.elem.merge(item)
+		}
+	}
+}
+
+// structGen writes struct declarations, each named type once
+type structGen struct {
+	decls   bytes.Buffer
+	used    map[string]bool
+	pending []pendingStruct
+}
+
+type pendingStruct struct {
+	name string
+	typ  *genType
+}
+
+// emit writes the struct name for t, then the structs its fields need
+func (g *structGen) emit(name string, t *genType) {
+	keys := make([]string, 0, len(t.fields))
+	for k := range t.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fieldNames := map[string]bool{}
+	fmt.Fprintf(&g.decls, "type %s struct {\n", name)
+	for _, k := range keys {
+		f := t.fields[k]
+		fieldName := uniqueName(goFieldName(k), fieldNames)
+		tag := k
+		if f.count < t.objects {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.decls, "\t%s %s `json:%s`\n", fieldName, g.goType(f.typ, fieldName, name), strconv.Quote(tag))
+	}
+	g.decls.WriteString("}\n\n")
+
+	pending := g.pending
+	g.pending = nil
+	for _, p := range pending {
+		g.emit(p.name, p.typ)
+	}
+}
+
+// goType returns the Go type for t, queueing a struct named after field if
+// t is an object
+func (g *structGen) goType(t *genType, field, parent string) string {
+	var base string
+	switch t.kind {
+	case "bool":
+		base = "bool"
+	case "int":
+		base = "int64"
+	case "float":
+		base = "float64"
+	case "string":
+		base = "string"
+	case "object":
+		base = g.name(field, parent+field)
+		g.pending = append(g.pending, pendingStruct{name: base, typ: t})
+	case "array":
+		if t.elem == nil {
+			return "[]interface{}"
+		}
+		return "[]" + g.goType(t.elem, singular(field), parent)
+	default:
+		return "interface{}"
+	}
+	if t.nullable {
+		return "*" + base
+	}
+	return base
+}
+
+// name reserves a type name: preferred if free, else fallback, else
+// fallback with a number
+func (g *structGen) name(preferred, fallback string) string {
+	for _, n := range []string{preferred, fallback} {
+		if !g.used[n] {
+			g.used[n] = true
+			return n
+		}
+	}
+	return uniqueName(fallback, g.used)
+}
+
+// uniqueName returns name, or name followed by the first free number, and
+// marks it used
+func uniqueName(name string, used map[string]bool) string {
+	n := name
+	for i := 2; used[n]; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	used[n] = true
+	return n
+}
+
+// commonInitialisms are written in upper case in Go names, as golint does
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"LHS": true, "QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true,
+	"SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true,
+	"UID": true, "URI": true, "URL": true, "UTF8": true, "UUID": true, "VM": true, "XML": true,
+}
+
+// goFieldName turns a JSON key into an exported Go identifier:
+// user_id becomes UserID, created-at CreatedAt
+func goFieldName(key string) string {
+	parts := strings.FieldsFunc(key, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	var sb strings.Builder
+	for _, part := range parts {
+		for _, w := range splitWords(part) {
+			if up := strings.ToUpper(w); commonInitialisms[up] {
+				sb.WriteString(up)
+				continue
+			}
+			r := []rune(strings.ToLower(w))
+			r[0] = unicode.ToUpper(r[0])
+			sb.WriteString(string(r))
+		}
+	}
+	name := sb.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) || !unicode.IsUpper([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// singular guesses the singular of an English plural for element type names
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") &&
+		!strings.HasSuffix(name, "us") && !strings.HasSuffix(name, "is") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+func isExportedIdent(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != "" && unicode.IsUpper([]rune(s)[0])
+}
@@ -0,0 +1,94 @@
+package jsjson_test
+
+import (
+	"errors"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestGenerateStruct(t *testing.T) {
+	sample := JSON.Parse(`{
+		"id": 7,
+		"user_name": "ada",
+		"score": 9.5,
+		"api-key": null,
+		"active": true,
+		"address": {"city": "London", "zip": null},
+		"orders": [
+			{"id": 1, "total": 3, "note": "gift"},
+			{"id": 2, "total": 4.25, "items": []}
+		],
+		"tags": ["a", "b"],
+		"misc": [1, "two"],
+		"nothing": []
+	}`)
+
+	const want = "type User struct {\n" +
+		"\tActive   bool          `json:\"active\"`\n" +
+		"\tAddress  Address       `json:\"address\"`\n" +
+		"\tAPIKey   interface{}   `json:\"api-key\"`\n" +
+		"\tID       int64         `json:\"id\"`\n" +
+		"\tMisc     []interface{} `json:\"misc\"`\n" +
+		"\tNothing  []interface{} `json:\"nothing\"`\n" +
+		"\tOrders   []Order       `json:\"orders\"`\n" +
+		"\tScore    float64       `json:\"score\"`\n" +
+		"\tTags     []string      `json:\"tags\"`\n" +
+		"\tUserName string        `json:\"user_name\"`\n" +
+		"}\n\n" +
+		"type Address struct {\n" +
+		"\tCity string      `json:\"city\"`\n" +
+		"\tZip  interface{} `json:\"zip\"`\n" +
+		"}\n\n" +
+		"type Order struct {\n" +
+		"\tID    int64         `json:\"id\"`\n" +
+		"\tItems []interface{} `json:\"items,omitempty\"`\n" +
+		"\tNote  string        `json:\"note,omitempty\"`\n" +
+		"\tTotal float64       `json:\"total\"`\n" +
+		"}\n"
+
+	got, err := JSON.GenerateStruct(sample, "User")
+	if err != nil || got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s (%v)", want, got, err)
+	}
+}
+
+func TestGenerateStructArrays(t *testing.T) {
+	sample := JSON.Parse(`[
+		{"name": "a", "parent": {"name": "p"}, "size": 1},
+		{"name": "b", "parent": null, "size": null}
+	]`)
+	const want = "type Categories []Category\n\n" +
+		"type Category struct {\n" +
+		"\tName   string  `json:\"name\"`\n" +
+		"\tParent *Parent `json:\"parent\"`\n" +
+		"\tSize   *int64  `json:\"size\"`\n" +
+		"}\n\n" +
+		"type Parent struct {\n" +
+		"\tName string `json:\"name\"`\n" +
+		"}\n"
+	got, err := JSON.GenerateStruct(sample, "Categories")
+	if err != nil || got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s (%v)", want, got, err)
+	}
+
+	// Name clashes fall back to the parent's name
+	got, _ = JSON.GenerateStruct(JSON.Parse(`{"node": {"node": {"x": 1}}}`), "Node")
+	const clash = "type Node struct {\n\tNode NodeNode `json:\"node\"`\n}\n\n" +
+		"type NodeNode struct {\n\tNode NodeNodeNode `json:\"node\"`\n}\n\n" +
+		"type NodeNodeNode struct {\n\tX int64 `json:\"x\"`\n}\n"
+	if got != clash {
+		t.Errorf("Expected:\n%s\ngot:\n%s", clash, got)
+	}
+}
+
+func TestGenerateStructErrors(t *testing.T) {
+	for _, input := range []string{`1`, `[1, 2]`, `[]`} {
+		if _, err := JSON.GenerateStruct(JSON.Parse(input), "T"); !errors.Is(err, JSON.ErrTypeMismatch) {
+			t.Errorf("%s: expected ErrTypeMismatch, got %v", input, err)
+		}
+	}
+	if _, err := JSON.GenerateStruct(JSON.Parse(`{}`), "lower"); err == nil {
+		t.Error("Expected an error for an unexported type name")
+	}
+}
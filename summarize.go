@@ -0,0 +1,66 @@
+package jsjson
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// -------------------- Summaries --------------------
+
+// Summarize returns a shrunken copy of the value for logging payload shapes
+// within log size limits. Strings longer than maxStringLen characters keep
+// their first maxStringLen characters followed by a marker, and arrays with
+// more than maxArrayElems elements keep their first maxArrayElems elements
+// followed by a marker string:
+//
+//	log.Print(body.Summarize(16, 2))
+//	// {"items":[{"id":1},{"id":2},"...(+998 more)"],"note":"The quick brown ...(+27 more)"}
+//
+// Objects keep all their keys. A limit of zero or less turns that kind of
+// truncation off.
+func (j JSONValue) Summarize(maxStringLen, maxArrayElems int) JSONValue {
+	if j.err != nil {
+		return j
+	}
+	data, err := normalize(j.data)
+	if err != nil {
+		return JSONValue{err: &JSONError{Op: "Summarize", Err: err}}
+	}
+	return JSONValue{data: summarizeTree(data, maxStringLen, maxArrayElems)}
+}
+
+// summarizeTree truncates v in place and returns it
+func summarizeTree(v interface{}, maxStringLen, maxArrayElems int) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		for k, child := range c {
+			c[k] = summarizeTree(child, maxStringLen, maxArrayElems)
+		}
+	case []interface{}:
+		keep := c
+		if maxArrayElems > 0 && len(c) > maxArrayElems {
+			keep = c[:maxArrayElems]
+		}
+		for i, child := range keep {
+			keep[i] = summarizeTree(child, maxStringLen, maxArrayElems)
+		}
+		if len(keep) < len(c) {
+			return append(keep, summaryMarker(len(c)-len(keep)))
+		}
+		return c
+	case string:
+		if maxStringLen > 0 && utf8.RuneCountInString(c) > maxStringLen {
+			cut := 0
+			for n := 0; n < maxStringLen; n++ {
+				_, size := utf8.DecodeRuneInString(c[cut:])
+				cut += size
+			}
+			return c[:cut] + summaryMarker(utf8.RuneCountInString(c[cut:]))
+		}
+	}
+	return v
+}
+
+func summaryMarker(more int) string {
+	return fmt.Sprintf("...(+%d more)", more)
+}
@@ -0,0 +1,61 @@
+package jsjson_test
+
+import (
+	"reflect"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestSummarize(t *testing.T) {
+	input := `{
+		"note": "The quick brown fox jumps over the lazy dog",
+		"name": "Ann",
+		"items": [{"id": 1, "tags": ["a", "b", "c"]}, {"id": 2}, {"id": 3}, {"id": 4}],
+		"short": [1, 2],
+		"unicode": "héllo wörld"
+	}`
+	tests := []struct {
+		name          string
+		maxStringLen  int
+		maxArrayElems int
+		want          string
+	}{
+		{"both limits", 16, 2, `{
+			"note": "The quick brown ...(+27 more)",
+			"name": "Ann",
+			"items": [{"id": 1, "tags": ["a", "b", "...(+1 more)"]}, {"id": 2}, "...(+2 more)"],
+			"short": [1, 2],
+			"unicode": "héllo wörld"
+		}`},
+		{"characters not bytes", 4, 0, `{
+			"note": "The ...(+39 more)",
+			"name": "Ann",
+			"items": [{"id": 1, "tags": ["a", "b", "c"]}, {"id": 2}, {"id": 3}, {"id": 4}],
+			"short": [1, 2],
+			"unicode": "héll...(+7 more)"
+		}`},
+		{"marker survives tiny string limit", 1, 1, `{
+			"note": "T...(+42 more)",
+			"name": "A...(+2 more)",
+			"items": [{"id": 1, "tags": ["a", "...(+2 more)"]}, "...(+3 more)"],
+			"short": [1, "...(+1 more)"],
+			"unicode": "h...(+10 more)"
+		}`},
+		{"no limits", 0, 0, input},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jv := JSON.Parse(input)
+			got := jv.Summarize(tt.maxStringLen, tt.maxArrayElems)
+			if !reflect.DeepEqual(got.Raw(), JSON.Parse(tt.want).Raw()) {
+				s, _ := JSON.Stringify(got)
+				t.Errorf("Unexpected result %s", s)
+			}
+			if !reflect.DeepEqual(jv.Raw(), JSON.Parse(input).Raw()) {
+				t.Error("Expected the original to be unchanged")
+			}
+		})
+	}
+}
@@ -0,0 +1,478 @@
+package jsjson
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// -------------------- Struct Tags --------------------
+//
+// ParseInto honors a jsjson tag listing validation rules for a field:
+//
+//	Port  int    `json:"port" jsjson:"required,min=1,max=65535"`
+//	Email string `json:"email" jsjson:"required,email"`
+//
+// Rules are checked after the input has been bound. Whether a required key
+// is present is judged from the input itself, so a zero value that was sent
+// explicitly still counts as present. Nested structs, pointers to structs and
+// slices, arrays and maps of structs are checked too. The rules of each type
+// are compiled once and cached; types without tags cost nothing.
+//
+// A default tag supplies the value of a field whose key is absent from the
+// input:
+//
+//	Port    int           `json:"port" default:"8080"`
+//	Timeout time.Duration `json:"timeout" default:"30s"`
+//	Tags    []string      `json:"tags" default:"[\"a\",\"b\"]"`
+//
+// Strings take the tag text as is, durations are parsed with
+// time.ParseDuration, types implementing encoding.TextUnmarshaler decode
+// the text themselves, and anything else is decoded as JSON. Defaults only
+// fill fields still holding their zero value, and reach into nested structs
+// whose key is absent as well.
+
+// tagPlan holds the tag rules of one struct type
+type tagPlan struct {
+	fields []tagField
+	err    error // invalid tag, reported whenever the type is checked
+}
+
+// tagField is a field that has rules or a default, or holds structs that do
+type tagField struct {
+	name  string // JSON key
+	index []int
+
+	required bool
+	min, max string // bound as written in the tag, "" if none
+	minVal   float64
+	maxVal   float64
+	email    bool
+
+	def        string // default tag
+	hasDefault bool
+
+	elem *tagPlan // plan of the struct type the field holds, if it has rules
+}
+
+var (
+	tagPlans   sync.Map // reflect.Type -> *tagPlan, nil when the type has no rules
+	tagPlansMu sync.Mutex
+)
+
+// checkStructTags applies the tags of dest's type to the value just decoded
+// into dest, and reports unknown keys if o disallows them. tree is the
+// decoded input; when it is nil, data is parsed to obtain it.
+func checkStructTags(dest interface{}, tree interface{}, data []byte, o *parseOptions) error {
+	v := reflect.ValueOf(dest).Elem()
+	var p *tagPlan
+	if st := structElem(v.Type()); st != nil {
+		p = tagPlanFor(st)
+	}
+	if p == nil && !o.disallowUnknown {
+		return nil
+	}
+
+	if tree == nil && data != nil {
+		var err error
+		if tree, err = parseTree(data, &parseOptions{}, false); err != nil {
+			return err
+		}
+	}
+
+	var violations []*Violation
+	if o.disallowUnknown {
+		collectUnknown(v.Type(), tree, "", &violations)
+	}
+	if p != nil {
+		if err := p.checkNested("", tree, v, &violations); err != nil {
+			return err
+		}
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// tagPlanFor returns the plan for struct type t, or nil if neither t nor
+// any struct it holds has tag rules or defaults
+func tagPlanFor(t reflect.Type) *tagPlan {
+	if p, ok := tagPlans.Load(t); ok {
+		return p.(*tagPlan)
+	}
+
+	tagPlansMu.Lock()
+	defer tagPlansMu.Unlock()
+	building := make(map[reflect.Type]*tagPlan)
+	p := buildTagPlan(t, building)
+	for bt, bp := range building {
+		tagPlans.Store(bt, bp)
+	}
+	return p
+}
+
+func buildTagPlan(t reflect.Type, building map[reflect.Type]*tagPlan) *tagPlan {
+	if p, ok := tagPlans.Load(t); ok {
+		return p.(*tagPlan)
+	}
+	if p, ok := building[t]; ok {
+		return p
+	}
+	if !hasTagRules(t, make(map[reflect.Type]bool)) {
+		building[t] = nil
+		return nil
+	}
+
+	p := &tagPlan{}
+	building[t] = p
+	for _, f := range structFields(t) {
+		sf := t.FieldByIndex(f.index)
+		tf := tagField{name: f.name, index: f.index}
+		if err := tf.parseRules(sf); err != nil && p.err == nil {
+			p.err = fmt.Errorf("invalid jsjson tag on %s.%s: %w", t.Name(), sf.Name, err)
+		}
+		if def, ok := sf.Tag.Lookup("default"); ok {
+			tf.def, tf.hasDefault = def, true
+			if _, err := decodeDefault(sf.Type, def); err != nil && p.err == nil {
+				p.err = fmt.Errorf("invalid default tag on %s.%s: %w", t.Name(), sf.Name, err)
+			}
+		}
+		if et := structElem(sf.Type); et != nil {
+			tf.elem = buildTagPlan(et, building)
+		}
+		if tf.hasRules() || tf.hasDefault || tf.elem != nil {
+			p.fields = append(p.fields, tf)
+		}
+	}
+	return p
+}
+
+// hasTagRules reports whether t, or a struct type reachable from its
+// fields, has a field with a jsjson or default tag
+func hasTagRules(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for _, f := range structFields(t) {
+		sf := t.FieldByIndex(f.index)
+		if sf.Tag.Get("jsjson") != "" {
+			return true
+		}
+		if _, ok := sf.Tag.Lookup("default"); ok {
+			return true
+		}
+		if et := structElem(sf.Type); et != nil && hasTagRules(et, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// structElem returns the struct type t holds directly or through pointers,
+// slices, arrays and maps, or nil
+func structElem(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			return t
+		default:
+			return nil
+		}
+	}
+}
+
+// parseRules reads the jsjson tag of sf
+func (f *tagField) parseRules(sf reflect.StructField) error {
+	tag := sf.Tag.Get("jsjson")
+	if tag == "" {
+		return nil
+	}
+	ft := sf.Type
+	for ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, hasArg := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "":
+		case "required":
+			f.required = true
+		case "min", "max":
+			n, err := strconv.ParseFloat(arg, 64)
+			if !hasArg || err != nil {
+				return fmt.Errorf("rule %q needs a numeric bound", name)
+			}
+			if measure(ft.Kind()) == "" {
+				return fmt.Errorf("rule %q does not apply to %s", name, ft)
+			}
+			if name == "min" {
+				f.min, f.minVal = arg, n
+			} else {
+				f.max, f.maxVal = arg, n
+			}
+		case "email":
+			if ft.Kind() != reflect.String {
+				return fmt.Errorf("rule %q does not apply to %s", name, ft)
+			}
+			f.email = true
+		default:
+			return fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	return nil
+}
+
+func (f *tagField) hasRules() bool {
+	return f.required || f.min != "" || f.max != "" || f.email
+}
+
+// measure names what min and max bound for a kind: the value, the length
+// in characters, or the number of items
+func measure(k reflect.Kind) string {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "value"
+	case reflect.String:
+		return "characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	}
+	return ""
+}
+
+// check validates the struct v, decoded from tree
+func (p *tagPlan) check(path string, tree interface{}, v reflect.Value, violations *[]*Violation) error {
+	if p.err != nil {
+		return p.err
+	}
+	obj, _ := tree.(map[string]interface{})
+
+	for i := range p.fields {
+		f := &p.fields[i]
+		fpath := f.name
+		if path != "" {
+			fpath = path + "." + f.name
+		}
+
+		item, present := lookupKey(obj, f.name)
+		if !present {
+			if f.hasDefault {
+				if err := f.applyDefault(v); err != nil {
+					return err
+				}
+				continue
+			}
+			if f.elem != nil {
+				if err := f.elem.applyNestedDefaults(v, f.index); err != nil {
+					return err
+				}
+			}
+		}
+		if !present || item == nil {
+			if f.required {
+				*violations = append(*violations, &Violation{Path: fpath, Rule: "required", Message: "is required"})
+			}
+			continue
+		}
+
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+		f.checkValue(fpath, fv, violations)
+		if f.elem != nil {
+			if err := f.elem.checkNested(fpath, item, fv, violations); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkNested validates the structs held by v, decoded from tree
+func (p *tagPlan) checkNested(path string, tree interface{}, v reflect.Value, violations *[]*Violation) error {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return p.check(path, tree, v, violations)
+	case reflect.Slice, reflect.Array:
+		items, _ := tree.([]interface{})
+		for i := 0; i < v.Len(); i++ {
+			var item interface{}
+			if i < len(items) {
+				item = items[i]
+			}
+			if err := p.checkNested(fmt.Sprintf("%s[%d]", path, i), item, v.Index(i), violations); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		obj, _ := tree.(map[string]interface{})
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			mpath := key
+			if path != "" {
+				mpath = path + "." + key
+			}
+			if err := p.checkNested(mpath, obj[key], iter.Value(), violations); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyDefault sets the field of struct v to its default unless it already
+// holds a value
+func (f *tagField) applyDefault(v reflect.Value) error {
+	fv, err := fieldByIndex(v, f.index)
+	if err != nil || !fv.IsZero() {
+		return nil
+	}
+	def, err := decodeDefault(fv.Type(), f.def)
+	if err != nil {
+		return err
+	}
+	fv.Set(def)
+	return nil
+}
+
+// applyNestedDefaults applies the defaults of p to the struct field of v at
+// index, whose key was absent from the input. Only struct values are
+// filled; absent pointers, slices and maps stay empty.
+func (p *tagPlan) applyNestedDefaults(v reflect.Value, index []int) error {
+	if p.err != nil {
+		return p.err
+	}
+	fv, err := v.FieldByIndexErr(index)
+	if err != nil || fv.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := range p.fields {
+		f := &p.fields[i]
+		if f.hasDefault {
+			if err := f.applyDefault(fv); err != nil {
+				return err
+			}
+		} else if f.elem != nil {
+			if err := f.elem.applyNestedDefaults(fv, f.index); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeDefault converts the text of a default tag to a value of type t
+func decodeDefault(t reflect.Type, text string) (reflect.Value, error) {
+	if t.Kind() == reflect.Pointer {
+		elem, err := decodeDefault(t.Elem(), text)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(elem)
+		return p, nil
+	}
+
+	v := reflect.New(t)
+	var err error
+	switch {
+	case t == reflect.TypeOf(time.Duration(0)):
+		var d time.Duration
+		d, err = time.ParseDuration(text)
+		v.Elem().SetInt(int64(d))
+	case v.Type().Implements(textUnmarshalerType):
+		err = v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	case t.Kind() == reflect.String:
+		v.Elem().SetString(text)
+	default:
+		err = json.Unmarshal([]byte(text), v.Interface())
+	}
+	return v.Elem(), err
+}
+
+// checkValue applies the value rules of f to the decoded field fv
+func (f *tagField) checkValue(path string, fv reflect.Value, violations *[]*Violation) {
+	for fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return
+		}
+		fv = fv.Elem()
+	}
+
+	if f.min != "" || f.max != "" {
+		var n float64
+		unit := measure(fv.Kind())
+		switch fv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = float64(fv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			n = float64(fv.Uint())
+		case reflect.Float32, reflect.Float64:
+			n = fv.Float()
+		case reflect.String:
+			n = float64(utf8.RuneCountInString(fv.String()))
+		default:
+			n = float64(fv.Len())
+		}
+
+		if f.min != "" && n < f.minVal {
+			*violations = append(*violations, &Violation{Path: path, Rule: "min=" + f.min, Message: boundMessage("at least", f.min, unit)})
+		}
+		if f.max != "" && n > f.maxVal {
+			*violations = append(*violations, &Violation{Path: path, Rule: "max=" + f.max, Message: boundMessage("at most", f.max, unit)})
+		}
+	}
+
+	if f.email && !isEmail(fv.String()) {
+		*violations = append(*violations, &Violation{Path: path, Rule: "email", Message: "must be a valid email address"})
+	}
+}
+
+func boundMessage(relation, bound, unit string) string {
+	switch unit {
+	case "characters":
+		return fmt.Sprintf("must be %s %s characters long", relation, bound)
+	case "items":
+		return fmt.Sprintf("must contain %s %s items", relation, bound)
+	default:
+		return fmt.Sprintf("must be %s %s", relation, bound)
+	}
+}
+
+// lookupKey finds the member for a field the way encoding/json matches
+// keys: exactly, or else case-insensitively
+func lookupKey(obj map[string]interface{}, name string) (interface{}, bool) {
+	if item, ok := obj[name]; ok {
+		return item, true
+	}
+	for k, item := range obj {
+		if strings.EqualFold(k, name) {
+			return item, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,203 @@
+package jsjson_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+type tagAddress struct {
+	City string `json:"city" jsjson:"required"`
+	Zip  string `json:"zip" jsjson:"min=5,max=5"`
+}
+
+type tagUser struct {
+	Name    string                `json:"name" jsjson:"required,min=2"`
+	Email   string                `json:"email" jsjson:"email"`
+	Age     int                   `json:"age" jsjson:"min=0,max=150"`
+	Count   *int                  `json:"count" jsjson:"required"`
+	Tags    []string              `json:"tags" jsjson:"max=2"`
+	Home    tagAddress            `json:"home"`
+	Work    *tagAddress           `json:"work"`
+	Others  []tagAddress          `json:"others"`
+	ByLabel map[string]tagAddress `json:"by_label"`
+}
+
+type tagBadRule struct {
+	Name string `json:"name" jsjson:"requird"`
+}
+
+type tagBadKind struct {
+	Ok bool `json:"ok" jsjson:"min=1"`
+}
+
+func TestParseIntoValidationTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // violations as "path rule"
+	}{
+		{"valid", `{"name": "Ada", "email": "ada@example.com", "age": 36, "count": 0,
+			"home": {"city": "London", "zip": "12345"}}`, nil},
+		{"explicit zero counts as present", `{"name": "Al", "count": 0, "home": {"city": ""}}`, nil},
+		{"missing and null", `{"name": null, "home": {}}`, []string{"name required", "count required", "home.city required"}},
+		{"bounds", `{"name": "A", "age": 200, "count": 1, "tags": ["a", "b", "c"], "home": {"city": "x", "zip": "1"}}`,
+			[]string{"name min=2", "age max=150", "tags max=2", "home.zip min=5"}},
+		{"email", `{"name": "Ada", "email": "not-an-email", "count": 1, "home": {"city": "x"}}`, []string{"email email"}},
+		{"nested collections", `{"name": "Ada", "count": 1, "home": {"city": "x"},
+			"work": {"zip": "123456"}, "others": [{"city": "a"}, {}], "by_label": {"old": {"city": null}}}`,
+			[]string{"work.city required", "work.zip max=5", "others[1].city required", "by_label.old.city required"}},
+		{"case-insensitive keys", `{"NAME": "Ada", "Count": 1, "HOME": {"CITY": "x"}}`, nil},
+		{"runes not bytes", `{"name": "é", "count": 1, "home": {"city": "x"}}`, []string{"name min=2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, via := range []string{"bytes", "JSONValue"} {
+				var dest tagUser
+				var err error
+				if via == "bytes" {
+					err = JSON.ParseInto([]byte(tt.input), &dest)
+				} else {
+					err = JSON.ParseInto(JSON.Parse(tt.input), &dest)
+				}
+
+				if tt.want == nil {
+					if err != nil {
+						t.Errorf("%s: unexpected error %v", via, err)
+					}
+					continue
+				}
+
+				var verr *JSON.ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("%s: expected *ValidationError, got %v", via, err)
+				}
+				got := make(map[string]bool)
+				for _, v := range verr.Violations {
+					got[v.Path+" "+v.Rule] = true
+				}
+				if len(got) != len(tt.want) {
+					t.Errorf("%s: expected violations %v, got %v", via, tt.want, err)
+				}
+				for _, w := range tt.want {
+					if !got[w] {
+						t.Errorf("%s: missing violation %q in %v", via, w, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseIntoValidationError(t *testing.T) {
+	var dest tagUser
+	err := JSON.ParseInto(`{"name": "A", "count": 1, "home": {"city": "x"}}`, &dest)
+
+	if !errors.Is(err, JSON.ErrValidation) {
+		t.Fatalf("Expected ErrValidation, got %v", err)
+	}
+	var v *JSON.Violation
+	if !errors.As(err, &v) || v.Path != "name" || v.Message != "must be at least 2 characters long" {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+	if !strings.Contains(err.Error(), "validation failed: name: must be at least 2 characters long") {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+	if dest.Name != "A" {
+		t.Errorf("Expected the decoded value to be kept, got %+v", dest)
+	}
+
+	httpErr := JSON.ErrorToHTTP(err)
+	if httpErr.Status != http.StatusUnprocessableEntity || httpErr.Code != JSON.CodeValidation {
+		t.Errorf("Unexpected HTTP mapping %+v", httpErr)
+	}
+}
+
+func TestParseIntoInvalidTags(t *testing.T) {
+	if err := JSON.ParseInto(`{"name": "x"}`, &tagBadRule{}); err == nil || !strings.Contains(err.Error(), `unknown rule "requird"`) {
+		t.Errorf("Expected unknown rule error, got %v", err)
+	}
+	if err := JSON.ParseInto(`{"ok": true}`, &tagBadKind{}); err == nil || !strings.Contains(err.Error(), "does not apply to bool") {
+		t.Errorf("Expected rule kind error, got %v", err)
+	}
+}
+
+func TestParseIntoValidationTopLevelSlice(t *testing.T) {
+	var dest []tagAddress
+	err := JSON.ParseInto(`[{"city": "a"}, {"zip": "12345"}]`, &dest)
+	var v *JSON.Violation
+	if !errors.As(err, &v) || v.Path != "[1].city" {
+		t.Errorf("Expected [1].city to be required, got %v", err)
+	}
+}
+
+type tagServer struct {
+	Host string `json:"host" default:"localhost"`
+	Port int    `json:"port" default:"8080"`
+}
+
+type tagConfig struct {
+	Name    string            `json:"name" default:"app"`
+	Debug   bool              `json:"debug" default:"true"`
+	Ratio   *float64          `json:"ratio" default:"0.5"`
+	Timeout time.Duration     `json:"timeout" default:"1m30s"`
+	Since   time.Time         `json:"since" default:"2024-01-02T03:04:05Z"`
+	Tags    []string          `json:"tags" default:"[\"a\",\"b\"]"`
+	Labels  map[string]string `json:"labels" default:"{\"env\":\"dev\"}"`
+	Retries int               `json:"retries" default:"3" jsjson:"required,min=1"`
+	Server  tagServer         `json:"server"`
+	Backup  *tagServer        `json:"backup"`
+}
+
+type tagBadDefault struct {
+	Port int `json:"port" default:"eighty"`
+}
+
+func TestParseIntoDefaultTags(t *testing.T) {
+	var cfg tagConfig
+	if err := JSON.ParseInto(`{"debug": false, "server": {"port": 9000}}`, &cfg); err != nil {
+		t.Fatalf("ParseInto failed: %v", err)
+	}
+
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	switch {
+	case cfg.Name != "app", cfg.Debug, cfg.Ratio == nil || *cfg.Ratio != 0.5,
+		cfg.Timeout != 90*time.Second, !cfg.Since.Equal(since), cfg.Retries != 3:
+		t.Errorf("Unexpected scalars %+v", cfg)
+	case !reflect.DeepEqual(cfg.Tags, []string{"a", "b"}), cfg.Labels["env"] != "dev":
+		t.Errorf("Unexpected collections %+v", cfg)
+	case cfg.Server != (tagServer{Host: "localhost", Port: 9000}), cfg.Backup != nil:
+		t.Errorf("Unexpected nested structs %+v", cfg)
+	}
+
+	// Absent nested structs get their defaults, values already in the
+	// destination are kept, and defaults do not share state between calls
+	next := tagConfig{Name: "preset"}
+	if err := JSON.ParseInto(JSON.Parse(`{"tags": null}`), &next); err != nil {
+		t.Fatalf("ParseInto failed: %v", err)
+	}
+	if next.Name != "preset" || next.Server != (tagServer{Host: "localhost", Port: 8080}) || next.Tags != nil {
+		t.Errorf("Unexpected result %+v", next)
+	}
+	cfg.Labels["env"] = "changed"
+	var other tagConfig
+	_ = JSON.ParseInto(`{}`, &other)
+	if other.Labels["env"] != "dev" {
+		t.Errorf("Defaults shared state between calls: %v", other.Labels)
+	}
+
+	// Explicit values still go through validation
+	if err := JSON.ParseInto(`{"retries": 0}`, &tagConfig{}); !errors.Is(err, JSON.ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+
+	if err := JSON.ParseInto(`{}`, &tagBadDefault{}); err == nil || !strings.Contains(err.Error(), "invalid default tag") {
+		t.Errorf("Expected invalid default error, got %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package jsjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// -------------------- Text Encodings --------------------
+//
+// JSON text is UTF-8, but files exported on Windows often start with a
+// UTF-8 byte order mark or are UTF-16. Input is recognized by its first
+// bytes: a byte order mark, or for UTF-16 without one, the pattern of zero
+// bytes in the first two characters, which are always ASCII in JSON (RFC
+// 4627, section 3): 00 xx 00 xx is big-endian and xx 00 xx 00 little-endian.
+// UTF-16 text of a single character needs a byte order mark. Byte offsets
+// in errors refer to the UTF-8 text after transcoding.
+
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// textEncoding returns the byte order of UTF-16 text starting with head, or
+// nil for UTF-8, and the length of its byte order mark
+func textEncoding(head []byte) (order binary.ByteOrder, bomLen int) {
+	switch {
+	case bytes.HasPrefix(head, utf8BOM):
+		return nil, len(utf8BOM)
+	case bytes.HasPrefix(head, []byte{0xfe, 0xff}):
+		return binary.BigEndian, 2
+	case bytes.HasPrefix(head, []byte{0xff, 0xfe}):
+		return binary.LittleEndian, 2
+	case len(head) >= 4 && head[0] == 0 && head[1] != 0 && head[2] == 0 && head[3] != 0:
+		return binary.BigEndian, 0
+	case len(head) >= 4 && head[0] != 0 && head[1] == 0 && head[2] != 0 && head[3] == 0:
+		return binary.LittleEndian, 0
+	}
+	return nil, 0
+}
+
+// decodeText returns data as UTF-8 without a byte order mark. UTF-8 input
+// is returned as is or resliced, so it still shares data's memory.
+func decodeText(data []byte) ([]byte, error) {
+	order, bomLen := textEncoding(data)
+	if order == nil {
+		return data[bomLen:], nil
+	}
+	out, err := io.ReadAll(&utf16Reader{r: bufio.NewReader(bytes.NewReader(data[bomLen:])), order: order})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ParseWithSurrogatePairs decodes UTF-16 surrogate pairs written directly
+// as UTF-8 byte sequences (CESU-8, as produced by Java's modified UTF-8 and
+// Oracle's UTF8 character set) into the characters they encode. Without it
+// each half is invalid UTF-8 and becomes U+FFFD. Escaped pairs such as
+// \ud83d\ude00 are always decoded, and unpaired surrogates still become
+// U+FFFD.
+func ParseWithSurrogatePairs() ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.joinSurrogates = true })
+}
+
+// RejectInvalidUTF8 makes parsing fail on input that is not valid UTF-8,
+// instead of replacing each invalid byte sequence with U+FFFD as
+// encoding/json does, for pipelines where corrupted data must not pass
+// unnoticed. The error is a *SyntaxError giving the offset of the first
+// invalid byte; for a Decoder the offset is within the value read.
+// Surrogate pairs decoded by ParseWithSurrogatePairs count as valid.
+func RejectInvalidUTF8() ParseOption {
+	return parseOptionFunc(func(o *parseOptions) { o.rejectInvalidUTF8 = true })
+}
+
+// prepareText returns data as the UTF-8 text the parsers read, as o asks
+func prepareText(data []byte, o *parseOptions) ([]byte, error) {
+	data, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeText(data, o)
+}
+
+// normalizeText applies the options about UTF-8 text to data
+func normalizeText(data []byte, o *parseOptions) ([]byte, error) {
+	if o.joinSurrogates {
+		data = joinSurrogates(data)
+	}
+	if o.rejectInvalidUTF8 {
+		if err := checkUTF8(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// checkUTF8 reports the first invalid UTF-8 sequence in data
+func checkUTF8(data []byte) error {
+	if utf8.Valid(data) {
+		return nil
+	}
+	for i := 0; i < len(data); {
+		if data[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return newSyntaxError(data, i, fmt.Sprintf("invalid UTF-8 byte 0x%02x", data[i]), nil)
+		}
+		i += size
+	}
+	return nil
+}
+
+// joinSurrogates replaces each UTF-8-encoded surrogate pair in data with the
+// UTF-8 encoding of its character. data is returned as is if it has none.
+func joinSurrogates(data []byte) []byte {
+	var out []byte
+	last := 0
+	for i := 0; i+6 <= len(data); i++ {
+		// A high surrogate is ED A0-AF xx, a low one ED B0-BF xx
+		if data[i] != 0xed || data[i+1]&0xf0 != 0xa0 || data[i+3] != 0xed || data[i+4]&0xf0 != 0xb0 {
+			continue
+		}
+		hi := rune(0xd000) | rune(data[i+1]&0x3f)<<6 | rune(data[i+2]&0x3f)
+		lo := rune(0xd000) | rune(data[i+4]&0x3f)<<6 | rune(data[i+5]&0x3f)
+		out = append(out, data[last:i]...)
+		out = utf8.AppendRune(out, utf16.DecodeRune(hi, lo))
+		i += 5
+		last = i + 1
+	}
+	if out == nil {
+		return data
+	}
+	return append(out, data[last:]...)
+}
+
+// textReader returns a reader giving the text of r as UTF-8 without a byte
+// order mark. The encoding is detected at the first Read, so creating the
+// reader does not block on a stream that has no data yet.
+func textReader(r io.Reader) io.Reader {
+	return &detectReader{r: bufio.NewReader(r)}
+}
+
+// detectReader recognizes the encoding of its input at the first Read
+type detectReader struct {
+	r    *bufio.Reader
+	text io.Reader // set once the encoding is known
+}
+
+func (d *detectReader) Read(p []byte) (int, error) {
+	if d.text == nil {
+		d.text = d.r
+		first, err := d.r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		// Only wait for more bytes when they may be a byte order mark or
+		// UTF-16 without one; a UTF-16 stream delivers both bytes of a unit
+		// together
+		need := d.r.Buffered()
+		switch first[0] {
+		case utf8BOM[0]:
+			need = len(utf8BOM)
+		case 0xfe, 0xff:
+			need = 2
+		case 0:
+			need = 4
+		default:
+			if b, _ := d.r.Peek(min(need, 2)); len(b) == 2 && b[1] == 0 {
+				need = 4
+			}
+		}
+		head, _ := d.r.Peek(min(need, 4))
+		order, bomLen := textEncoding(head)
+		d.r.Discard(bomLen)
+		if order != nil {
+			d.text = &utf16Reader{r: d.r, order: order}
+		}
+	}
+	return d.text.Read(p)
+}
+
+// utf16Reader transcodes UTF-16 text to UTF-8. Unpaired surrogates become
+// U+FFFD, as encoding/json does for \u escapes.
+type utf16Reader struct {
+	r     *bufio.Reader
+	order binary.ByteOrder
+	buf   []byte // transcoded text not yet read
+	off   int
+	err   error
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for u.off == len(u.buf) {
+		if u.err != nil {
+			return 0, u.err
+		}
+		u.fill()
+	}
+	n := copy(p, u.buf[u.off:])
+	u.off += n
+	return n, nil
+}
+
+// fill transcodes the next chunk of input into buf
+func (u *utf16Reader) fill() {
+	u.buf, u.off = u.buf[:0], 0
+	var unit [2]byte
+	for len(u.buf) < 4096 {
+		if _, err := io.ReadFull(u.r, unit[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = fmt.Errorf("%w: UTF-16 input has an odd number of bytes", ErrSyntax)
+			}
+			u.err = err
+			return
+		}
+		c := rune(u.order.Uint16(unit[:]))
+		if utf16.IsSurrogate(c) {
+			c2 := unicode.ReplacementChar
+			if next, err := u.r.Peek(2); err == nil {
+				c2 = utf16.DecodeRune(c, rune(u.order.Uint16(next)))
+			}
+			if c2 != unicode.ReplacementChar {
+				u.r.Discard(2)
+			}
+			c = c2
+		}
+		u.buf = utf8.AppendRune(u.buf, c)
+	}
+}
+
+// escapeNonASCII replaces every non-ASCII character in JSON text with a
+// \uXXXX escape. Such characters can only occur inside strings, so the
+// text stays valid and means the same.
+func escapeNonASCII(b []byte) []byte {
+	i := 0
+	for i < len(b) && b[i] < utf8.RuneSelf {
+		i++
+	}
+	if i == len(b) {
+		return b
+	}
+	out := make([]byte, i, len(b)+len(b)/2)
+	copy(out, b[:i])
+	for i < len(b) {
+		if b[i] < utf8.RuneSelf {
+			out = append(out, b[i])
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(b[i:])
+		i += size
+		if r1, r2 := utf16.EncodeRune(r); r1 != unicode.ReplacementChar {
+			out = appendUnicodeEscape(out, r1)
+			r = r2
+		}
+		out = appendUnicodeEscape(out, r)
+	}
+	return out
+}
+
+func appendUnicodeEscape(b []byte, r rune) []byte {
+	const hex = "0123456789abcdef"
+	return append(b, '\\', 'u', hex[r>>12&0xf], hex[r>>8&0xf], hex[r>>4&0xf], hex[r&0xf])
+}
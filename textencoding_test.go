@@ -0,0 +1,190 @@
+package jsjson_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+// encodeUTF16 returns s as UTF-16 in the given byte order, with a byte order
+// mark if bom is set
+func encodeUTF16(s string, order binary.AppendByteOrder, bom bool) []byte {
+	var b []byte
+	if bom {
+		b = order.AppendUint16(b, 0xfeff)
+	}
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = order.AppendUint16(b, u)
+	}
+	return b
+}
+
+func TestParseTextEncodings(t *testing.T) {
+	const doc = `{"name": "Zoë", "emoji": "😀", "n": 1}`
+	want := JSON.Parse(doc).Raw()
+
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"UTF-8 with BOM", append([]byte("\xef\xbb\xbf"), doc...)},
+		{"UTF-16LE with BOM", encodeUTF16(doc, binary.LittleEndian, true)},
+		{"UTF-16BE with BOM", encodeUTF16(doc, binary.BigEndian, true)},
+		{"UTF-16LE without BOM", encodeUTF16(doc, binary.LittleEndian, false)},
+		{"UTF-16BE without BOM", encodeUTF16(doc, binary.BigEndian, false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JSON.Parse(tt.input); !reflect.DeepEqual(got.Raw(), want) {
+				t.Errorf("Parse: expected %v, got %v (%v)", want, got.Raw(), got.Error())
+			}
+			if got := JSON.ParseNoCopy(append([]byte(nil), tt.input...)); !reflect.DeepEqual(got.Raw(), want) {
+				t.Errorf("ParseNoCopy: expected %v, got %v (%v)", want, got.Raw(), got.Error())
+			}
+			var dest struct{ Name string }
+			if err := JSON.ParseInto(tt.input, &dest); err != nil || dest.Name != "Zoë" {
+				t.Errorf("ParseInto: expected Zoë, got %q (%v)", dest.Name, err)
+			}
+			got, err := JSON.NewDecoder(bytes.NewReader(tt.input)).Decode()
+			if err != nil || !reflect.DeepEqual(got.Raw(), want) {
+				t.Errorf("Decoder: expected %v, got %v (%v)", want, got.Raw(), err)
+			}
+		})
+	}
+}
+
+func TestParseUTF16Errors(t *testing.T) {
+	odd := encodeUTF16(`[1]`, binary.LittleEndian, true)
+	if err := JSON.Parse(append(odd, 'x')).Error(); !errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Expected ErrSyntax for an odd length, got %v", err)
+	}
+
+	// An unpaired surrogate decodes as U+FFFD
+	b := encodeUTF16(`["`, binary.BigEndian, true)
+	b = binary.BigEndian.AppendUint16(b, 0xd800)
+	b = append(b, encodeUTF16(`x"]`, binary.BigEndian, false)...)
+	if got, _ := JSON.Parse(b).Get(0).String(); got != "�x" {
+		t.Errorf("Expected U+FFFD for an unpaired surrogate, got %q", got)
+	}
+
+	// Positions refer to the transcoded text
+	var serr *JSON.SyntaxError
+	if err := JSON.Parse(encodeUTF16("{\n \"a\": x}", binary.LittleEndian, true)).Error(); !errors.As(err, &serr) || serr.Line != 2 || serr.Column != 7 {
+		t.Errorf("Expected a syntax error at line 2, column 7, got %v", err)
+	}
+}
+
+func TestDecoderUTF16Stream(t *testing.T) {
+	records := strings.Repeat(`{"text": "ünïcödé 😀 text"}`+"\n", 500)
+	dec := JSON.NewDecoder(bytes.NewReader(encodeUTF16(records, binary.LittleEndian, true)))
+	count := 0
+	for dec.More() {
+		v, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("Decode %d: %v", count, err)
+		}
+		if s, _ := v.Get("text").String(); s != "ünïcödé 😀 text" {
+			t.Fatalf("Decode %d: got %q", count, s)
+		}
+		count++
+	}
+	if count != 500 {
+		t.Errorf("Expected 500 records, got %d", count)
+	}
+}
+
+func TestStringifyASCII(t *testing.T) {
+	v := JSON.Parse(`{"name": "Zoë", "emoji": "😀", "plain": "a\"b"}`)
+
+	tests := []struct {
+		name string
+		got  func() (string, error)
+		want string
+	}{
+		{"raw by default", func() (string, error) { return JSON.Stringify(v) }, `{"emoji":"😀","name":"Zoë","plain":"a\"b"}`},
+		{"escaped", func() (string, error) { return JSON.Stringify(v, JSON.WithASCII()) }, `{"emoji":"\ud83d\ude00","name":"Zo\u00eb","plain":"a\"b"}`},
+		{"pretty", func() (string, error) { return JSON.StringifyPretty(JSON.Parse(`["ë"]`), " ", JSON.WithASCII()) }, "[\n \"\\u00eb\"\n]"},
+		{"indent option", func() (string, error) { return JSON.Stringify(JSON.Parse(`[1]`), JSON.WithIndent("  ")) }, "[\n  1\n]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.got()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+			if !reflect.DeepEqual(JSON.Parse(got).Raw(), JSON.Parse(tt.want).Raw()) {
+				t.Errorf("Escaped output %s does not parse to the same value", got)
+			}
+		})
+	}
+}
+
+func TestParseWithSurrogatePairs(t *testing.T) {
+	// U+1F600 as a CESU-8 surrogate pair: ED A0 BD ED B8 80
+	input := []byte("[\"a\xed\xa0\xbd\xed\xb8\x80b\", \"\xed\xa0\xbd\"]")
+
+	if got, _ := JSON.Parse(input).Get(0).String(); got != "a\uFFFD\uFFFD\uFFFD\uFFFD\uFFFD\uFFFDb" {
+		t.Errorf("Expected replacement characters by default, got %q", got)
+	}
+
+	got := JSON.Parse(input, JSON.ParseWithSurrogatePairs())
+	if s, _ := got.Get(0).String(); s != "a😀b" {
+		t.Errorf("Expected a😀b, got %q", s)
+	}
+	if s, _ := got.Get(1).String(); s != "\uFFFD\uFFFD\uFFFD" {
+		t.Errorf("Expected an unpaired surrogate to stay invalid, got %q", s)
+	}
+
+	v, err := JSON.NewDecoder(bytes.NewReader(input), JSON.ParseWithSurrogatePairs()).Decode()
+	if s, _ := v.Get(0).String(); err != nil || s != "a😀b" {
+		t.Errorf("Decoder: expected a😀b, got %q (%v)", s, err)
+	}
+}
+
+func TestRejectInvalidUTF8(t *testing.T) {
+	input := []byte("{\"a\": \"ok\",\n \"b\": \"bad \xff byte\"}")
+
+	if got, _ := JSON.Parse(input).Get("b").String(); got != "bad � byte" {
+		t.Errorf("Expected U+FFFD by default, got %q", got)
+	}
+
+	errs := map[string]error{
+		"Parse":       JSON.Parse(input, JSON.RejectInvalidUTF8()).Error(),
+		"ParseNoCopy": JSON.ParseNoCopy(input, JSON.RejectInvalidUTF8()).Error(),
+		"ParseInto":   JSON.ParseInto(input, &map[string]string{}, JSON.RejectInvalidUTF8()),
+	}
+	for name, err := range errs {
+		var serr *JSON.SyntaxError
+		if !errors.As(err, &serr) {
+			t.Errorf("%s: expected a *SyntaxError, got %v", name, err)
+			continue
+		}
+		if serr.Offset != 23 || serr.Line != 2 || serr.Column != 12 {
+			t.Errorf("%s: expected offset 23 at line 2, column 12, got offset %d at line %d, column %d", name, serr.Offset, serr.Line, serr.Column)
+		}
+	}
+
+	if _, err := JSON.NewDecoder(bytes.NewReader(input), JSON.RejectInvalidUTF8()).Decode(); !errors.Is(err, JSON.ErrSyntax) {
+		t.Errorf("Decoder: expected ErrSyntax, got %v", err)
+	}
+
+	valid := []byte(`{"name": "Zoë 😀"}`)
+	if err := JSON.Parse(valid, JSON.RejectInvalidUTF8()).Error(); err != nil {
+		t.Errorf("Unexpected error for valid UTF-8: %v", err)
+	}
+	cesu := []byte("\"\xed\xa0\xbd\xed\xb8\x80\"")
+	if err := JSON.Parse(cesu, JSON.RejectInvalidUTF8(), JSON.ParseWithSurrogatePairs()).Error(); err != nil {
+		t.Errorf("Unexpected error for a joined surrogate pair: %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// isoLayouts are the RFC 3339 / ISO 8601 forms accepted by Time, tried in
+// order. Layouts without a zone are interpreted as UTC.
+var isoLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// EpochUnit selects how numeric timestamps are interpreted
+type EpochUnit int
+
+const (
+	// EpochAuto detects the unit from the magnitude of the value: seconds
+	// below 1e11, milliseconds below 1e14, microseconds below 1e17 and
+	// nanoseconds above that. Seconds are unambiguous until the year 5138.
+	EpochAuto EpochUnit = iota
+	EpochSeconds
+	EpochMilliseconds
+	EpochMicroseconds
+	EpochNanoseconds
+)
+
+// TimeOptions controls how Time and TimeWith parse timestamps
+type TimeOptions struct {
+	// Layouts are tried, in order, before the built-in RFC 3339 / ISO 8601
+	// layouts. Set OnlyLayouts to disable the built-in ones.
+	Layouts     []string
+	OnlyLayouts bool
+	// Epoch selects the unit for numeric timestamps (and numeric strings)
+	Epoch EpochUnit
+	// Location is used for layouts without a zone; nil means UTC
+	Location *time.Location
+}
+
+// defaultTimeOptions holds the package-level options used by Time
+var defaultTimeOptions atomic.Pointer[TimeOptions]
+
+// SetTimeOptions sets the package-level options used by Time and TimeOr.
+// It is safe to call concurrently with parsing.
+func SetTimeOptions(o TimeOptions) {
+	defaultTimeOptions.Store(&o)
+}
+
+// timeOptions returns the current package-level options
+func timeOptions() TimeOptions {
+	if o := defaultTimeOptions.Load(); o != nil {
+		return *o
+	}
+	return TimeOptions{}
+}
+
+// Time returns the value as a time.Time using the package-level options (see
+// SetTimeOptions). Strings are parsed as RFC 3339 / ISO 8601 timestamps (with
+// or without fractional seconds, zone, or time part); numbers and numeric
+// strings are Unix epoch values whose unit is detected automatically.
+// Epoch values are returned in UTC.
+func (j JSONValue) Time() (time.Time, error) {
+	return j.timeWith("Time", timeOptions())
+}
+
+// TimeOr returns the value as time.Time or default if error/conversion fails
+func (j JSONValue) TimeOr(defaultValue time.Time) time.Time {
+	if t, err := j.Time(); err == nil {
+		return t
+	}
+	return defaultValue
+}
+
+// TimeLayout parses a string value with a single custom layout (see the time
+// package for layout syntax). Numeric values are still read as epoch
+// timestamps using the package-level options.
+func (j JSONValue) TimeLayout(layout string) (time.Time, error) {
+	o := timeOptions()
+	o.Layouts = []string{layout}
+	o.OnlyLayouts = true
+	return j.timeWith("TimeLayout", o)
+}
+
+// TimeWith returns the value as time.Time using the given options instead of
+// the package-level ones
+func (j JSONValue) TimeWith(o TimeOptions) (time.Time, error) {
+	return j.timeWith("TimeWith", o)
+}
+
+func (j JSONValue) timeWith(op string, o TimeOptions) (time.Time, error) {
+	if j.err != nil {
+		return time.Time{}, j.err
+	}
+
+	switch v := j.data.(type) {
+	case string:
+		loc := o.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		for _, layout := range o.Layouts {
+			if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+				return t, nil
+			}
+		}
+		if !o.OnlyLayouts {
+			for _, layout := range isoLayouts {
+				if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+					return t, nil
+				}
+			}
+		}
+		if isJSONNumber(v) {
+			f, _ := strconv.ParseFloat(v, 64)
+			return epochTime(f, o.Epoch), nil
+		}
+		return time.Time{}, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot parse %q as a timestamp", ErrTypeMismatch, v)}
+	case float64, int, int64, json.Number:
+		f, err := epochValue(v)
+		if err != nil {
+			return time.Time{}, &JSONError{Op: op, Err: err}
+		}
+		return epochTime(f, o.Epoch), nil
+	default:
+		return time.Time{}, &JSONError{Op: op, Err: fmt.Errorf("%w: cannot convert %T to time", ErrTypeMismatch, v)}
+	}
+}
+
+// epochValue converts a numeric tree value to float64
+func epochValue(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: cannot convert number %q to time", ErrTypeMismatch, n)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("%w: cannot convert %T to time", ErrTypeMismatch, v)
+	}
+}
+
+// epochTime converts an epoch value in the given unit to a UTC time
+func epochTime(v float64, unit EpochUnit) time.Time {
+	if unit == EpochAuto {
+		switch abs := math.Abs(v); {
+		case abs < 1e11:
+			unit = EpochSeconds
+		case abs < 1e14:
+			unit = EpochMilliseconds
+		case abs < 1e17:
+			unit = EpochMicroseconds
+		default:
+			unit = EpochNanoseconds
+		}
+	}
+
+	var sec float64
+	switch unit {
+	case EpochMilliseconds:
+		sec = v / 1e3
+	case EpochMicroseconds:
+		sec = v / 1e6
+	case EpochNanoseconds:
+		return time.Unix(0, int64(v)).UTC()
+	default:
+		sec = v
+	}
+	whole, frac := math.Modf(sec)
+	return time.Unix(int64(whole), int64(math.Round(frac*1e9))).UTC()
+}
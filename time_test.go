@@ -0,0 +1,142 @@
+package jsjson_test
+
+import (
+	"testing"
+	"time"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestTime(t *testing.T) {
+	obj := JSON.Parse(`{
+		"rfc3339": "2023-06-15T10:30:00Z",
+		"nano": "2023-06-15T10:30:00.123456789+02:00",
+		"basicOffset": "2023-06-15T10:30:00+0200",
+		"local": "2023-06-15T10:30:00",
+		"space": "2023-06-15 10:30:00",
+		"date": "2023-06-15",
+		"epoch": 1686825000,
+		"epochFrac": 1686825000.5,
+		"invalid": "yesterday",
+		"flag": true
+	}`)
+
+	base := time.Date(2023, 6, 15, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		key     string
+		want    time.Time
+		wantErr bool
+	}{
+		{"rfc3339", base, false},
+		{"nano", time.Date(2023, 6, 15, 8, 30, 0, 123456789, time.UTC), false},
+		{"basicOffset", base.Add(-2 * time.Hour), false},
+		{"local", base, false},
+		{"space", base, false},
+		{"date", time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC), false},
+		{"epoch", base, false},
+		{"epochFrac", base.Add(500 * time.Millisecond), false},
+		{"invalid", time.Time{}, true},
+		{"flag", time.Time{}, true},
+		{"missing", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := obj.Get(tt.key).Time()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil || !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v (err: %v)", tt.want, got, err)
+			}
+		})
+	}
+
+	def := time.Unix(0, 0)
+	if got := obj.Get("invalid").TimeOr(def); !got.Equal(def) {
+		t.Errorf("Expected default, got %v", got)
+	}
+}
+
+func TestTimeWithNumbers(t *testing.T) {
+	obj := JSON.Parse(`{"ts":1686825000}`, JSON.ParseWithNumbers())
+	got, err := obj.Get("ts").Time()
+	if err != nil || got.Unix() != 1686825000 {
+		t.Errorf("Expected epoch 1686825000, got %v (err: %v)", got, err)
+	}
+}
+
+func TestTimeEpochUnits(t *testing.T) {
+	base := time.Date(2023, 6, 15, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		input string
+		unit  JSON.EpochUnit
+	}{
+		{"auto seconds", `1686825000`, JSON.EpochAuto},
+		{"auto millis", `1686825000000`, JSON.EpochAuto},
+		{"auto micros", `1686825000000000`, JSON.EpochAuto},
+		{"auto nanos", `1686825000000000000`, JSON.EpochAuto},
+		{"auto numeric string", `"1686825000000"`, JSON.EpochAuto},
+		{"explicit millis", `1686825000000`, JSON.EpochMilliseconds},
+		{"explicit seconds", `1686825000`, JSON.EpochSeconds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSON.Parse(tt.input).TimeWith(JSON.TimeOptions{Epoch: tt.unit})
+			if err != nil || !got.Equal(base) {
+				t.Errorf("Expected %v, got %v (err: %v)", base, got, err)
+			}
+		})
+	}
+
+	// An explicit unit overrides detection
+	got, _ := JSON.Parse(`1686825000`).TimeWith(JSON.TimeOptions{Epoch: JSON.EpochMilliseconds})
+	if want := time.UnixMilli(1686825000).UTC(); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestTimeLayout(t *testing.T) {
+	obj := JSON.Parse(`{"us":"06/15/2023 10:30","iso":"2023-06-15T10:30:00Z","ts":1686825000}`)
+	want := time.Date(2023, 6, 15, 10, 30, 0, 0, time.UTC)
+
+	if got, err := obj.Get("us").TimeLayout("01/02/2006 15:04"); err != nil || !got.Equal(want) {
+		t.Errorf("Expected %v, got %v (err: %v)", want, got, err)
+	}
+	if _, err := obj.Get("iso").TimeLayout("01/02/2006 15:04"); err == nil {
+		t.Error("Expected error for a string not matching the layout")
+	}
+	if got, err := obj.Get("ts").TimeLayout("01/02/2006 15:04"); err != nil || !got.Equal(want) {
+		t.Errorf("Expected epoch to be accepted, got %v (err: %v)", got, err)
+	}
+
+	// Custom layouts are tried before the built-in ones, in the given location
+	loc := time.FixedZone("EST", -5*3600)
+	got, err := obj.Get("us").TimeWith(JSON.TimeOptions{Layouts: []string{"01/02/2006 15:04"}, Location: loc})
+	if err != nil || !got.Equal(want.Add(5*time.Hour)) {
+		t.Errorf("Expected %v, got %v (err: %v)", want.Add(5*time.Hour), got, err)
+	}
+}
+
+func TestSetTimeOptions(t *testing.T) {
+	defer JSON.SetTimeOptions(JSON.TimeOptions{})
+
+	obj := JSON.Parse(`{"us":"06/15/2023 10:30","ts":1686825000}`)
+	if _, err := obj.Get("us").Time(); err == nil {
+		t.Fatal("Expected error before registering the layout")
+	}
+
+	JSON.SetTimeOptions(JSON.TimeOptions{Layouts: []string{"01/02/2006 15:04"}, Epoch: JSON.EpochMilliseconds})
+	want := time.Date(2023, 6, 15, 10, 30, 0, 0, time.UTC)
+	if got, err := obj.Get("us").Time(); err != nil || !got.Equal(want) {
+		t.Errorf("Expected %v, got %v (err: %v)", want, got, err)
+	}
+	if got := obj.Get("ts").TimeOr(time.Time{}); !got.Equal(time.UnixMilli(1686825000)) {
+		t.Errorf("Expected package-level epoch unit to apply, got %v", got)
+	}
+}
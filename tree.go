@@ -0,0 +1,213 @@
+package jsjson
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// -------------------- Tree Helpers --------------------
+
+// deepCopy returns a copy of a decoded JSON tree that shares no maps or slices
+// with the original
+func deepCopy(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for k, val := range c {
+			m[k] = deepCopy(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(c))
+		for i, val := range c {
+			s[i] = deepCopy(val)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// floatTree is deepCopy for a tree parsed with ParseWithNumbers, converting
+// its json.Number values to float64 as the default parse would. It reports
+// false if a number is out of float64 range.
+func floatTree(v interface{}) (interface{}, bool) {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for k, val := range c {
+			f, ok := floatTree(val)
+			if !ok {
+				return nil, false
+			}
+			m[k] = f
+		}
+		return m, true
+	case []interface{}:
+		s := make([]interface{}, len(c))
+		for i, val := range c {
+			f, ok := floatTree(val)
+			if !ok {
+				return nil, false
+			}
+			s[i] = f
+		}
+		return s, true
+	case json.Number:
+		f, err := strconv.ParseFloat(string(c), 64)
+		return f, err == nil
+	default:
+		return v, true
+	}
+}
+
+// mergeTrees deep-merges src over dst and returns the result: objects are
+// merged key by key, and any other src value replaces the dst value. Maps in
+// dst are updated in place; nothing from src is shared with the result.
+func mergeTrees(dst, src interface{}) interface{} {
+	d, ok := dst.(map[string]interface{})
+	s, ok2 := src.(map[string]interface{})
+	if !ok || !ok2 {
+		return deepCopy(src)
+	}
+	for k, v := range s {
+		d[k] = mergeTrees(d[k], v)
+	}
+	return d
+}
+
+// normalize converts an arbitrary Go value into the generic tree
+// representation used by JSONValue (maps, slices, float64, string, bool, nil)
+func normalize(v interface{}) (interface{}, error) {
+	switch c := v.(type) {
+	case nil, bool, float64, string, json.Number:
+		return c, nil
+	case JSONValue:
+		if c.err != nil {
+			return nil, c.err
+		}
+		return c.data, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for k, val := range c {
+			n, err := normalize(val)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = n
+		}
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, len(c))
+		for i, val := range c {
+			n, err := normalize(val)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = n
+		}
+		return s, nil
+	default:
+		b, err := json.Marshal(c)
+		if err != nil {
+			return nil, err
+		}
+		var result interface{}
+		if err := json.Unmarshal(b, &result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+// jsonEqual reports whether two decoded JSON trees are semantically equal,
+// comparing numbers by value regardless of their Go representation
+func jsonEqual(a, b interface{}) bool {
+	if fa, ok := numberValue(a); ok {
+		fb, ok := numberValue(b)
+		return ok && fa == fb
+	}
+
+	switch ca := a.(type) {
+	case map[string]interface{}:
+		cb, ok := b.(map[string]interface{})
+		if !ok || len(ca) != len(cb) {
+			return false
+		}
+		for k, va := range ca {
+			vb, exists := cb[k]
+			if !exists || !jsonEqual(va, vb) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		cb, ok := b.([]interface{})
+		if !ok || len(ca) != len(cb) {
+			return false
+		}
+		for i := range ca {
+			if !jsonEqual(ca[i], cb[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// numberValue returns the float64 value of any numeric tree node
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// canonicalHash returns the hex SHA-256 of the canonical serialization of v.
+// encoding/json sorts object keys, which makes the output stable.
+func canonicalHash(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// decodeTree decodes raw JSON into the generic tree representation using the
+// built-in parser. A codec set with SetCodec is used instead, except for the
+// modes only the built-in parser supports (ParseWithNumbers, WithArena and
+// WithInterning). With WithArena an arena is taken from the pool and left in
+// o.arena.
+func decodeTree(data []byte, o *parseOptions) (interface{}, error) {
+	c := activeCodec()
+	if _, std := c.(stdCodec); !std && !o.useNumber && !o.useArena && o.interner == nil {
+		var result interface{}
+		if err := c.Unmarshal(data, &result); err != nil {
+			return nil, locateSyntaxError(data, err)
+		}
+		return result, nil
+	}
+
+	if o.useArena {
+		o.arena = getArena()
+	}
+	result, err := parseTree(data, o, false)
+	if err != nil {
+		o.arena.discard()
+		o.arena = nil
+	}
+	return result, err
+}
@@ -0,0 +1,85 @@
+package jsjson
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// UUID returns a string value as the 16 bytes of a UUID. The canonical
+// hyphenated form is accepted in either case, as are the braced
+// ({...}), URN (urn:uuid:...) and 32-digit unhyphenated forms.
+func (j JSONValue) UUID() ([16]byte, error) {
+	if j.err != nil {
+		return [16]byte{}, j.err
+	}
+
+	s, ok := j.data.(string)
+	if !ok {
+		return [16]byte{}, &JSONError{Op: "UUID", Err: fmt.Errorf("%w: cannot convert %T to UUID", ErrTypeMismatch, j.data)}
+	}
+	u, ok := parseUUID(s)
+	if !ok {
+		return [16]byte{}, &JSONError{Op: "UUID", Err: fmt.Errorf("%w: invalid UUID %q", ErrTypeMismatch, truncate(s, 64))}
+	}
+	return u, nil
+}
+
+// UUIDString returns a UUID value in canonical form: lowercase, hyphenated
+// 8-4-4-4-12 hex digits
+func (j JSONValue) UUIDString() (string, error) {
+	u, err := j.UUID()
+	if err != nil {
+		return "", err
+	}
+	return formatUUID(u), nil
+}
+
+// UUIDStringOr returns the canonical UUID string or default if error/conversion fails
+func (j JSONValue) UUIDStringOr(defaultValue string) string {
+	if s, err := j.UUIDString(); err == nil {
+		return s
+	}
+	return defaultValue
+}
+
+// parseUUID decodes the accepted textual UUID forms
+func parseUUID(s string) (u [16]byte, ok bool) {
+	switch {
+	case len(s) == 45 && strings.EqualFold(s[:9], "urn:uuid:"):
+		s = s[9:]
+	case len(s) == 38 && s[0] == '{' && s[37] == '}':
+		s = s[1:37]
+	}
+
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, false
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return u, false
+	}
+
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, false
+	}
+	return u, true
+}
+
+// formatUUID writes u in canonical form
+func formatUUID(u [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
@@ -0,0 +1,58 @@
+package jsjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestUUID(t *testing.T) {
+	const canonical = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"canonical", `"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`, false},
+		{"uppercase", `"6BA7B810-9DAD-11D1-80B4-00C04FD430C8"`, false},
+		{"braced", `"{6ba7b810-9dad-11d1-80b4-00c04fd430c8}"`, false},
+		{"urn", `"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8"`, false},
+		{"unhyphenated", `"6ba7b8109dad11d180b400c04fd430c8"`, false},
+		{"misplaced hyphen", `"6ba7b81-09dad-11d1-80b4-00c04fd430c8"`, true},
+		{"bad digit", `"6ba7b810-9dad-11d1-80b4-00c04fd430cg"`, true},
+		{"short", `"6ba7b810-9dad-11d1-80b4"`, true},
+		{"number", `42`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSON.Parse(tt.input).UUIDString()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q", got)
+				} else if !errors.Is(err, JSON.ErrTypeMismatch) {
+					t.Errorf("Expected ErrTypeMismatch, got %v", err)
+				}
+				return
+			}
+			if err != nil || got != canonical {
+				t.Errorf("Expected %q, got %q (err: %v)", canonical, got, err)
+			}
+		})
+	}
+
+	u, err := JSON.Parse(`"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`).UUID()
+	if err != nil || u[0] != 0x6b || u[15] != 0xc8 {
+		t.Errorf("Unexpected bytes %x (err: %v)", u, err)
+	}
+
+	_, err = JSON.Parse(`{"id":"nope"}`).Get("id").UUID()
+	if err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Errorf("Expected error naming the offending value, got %v", err)
+	}
+	if got := JSON.Parse(`"nope"`).UUIDStringOr("none"); got != "none" {
+		t.Errorf("Expected default, got %q", got)
+	}
+}
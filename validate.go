@@ -0,0 +1,297 @@
+package jsjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// -------------------- Validation Rules --------------------
+
+// Rule applies checks to the values found at a path; build one with RuleAt
+type Rule struct {
+	keys   []string
+	checks []Check
+}
+
+// Check is one test applied by a Rule, such as RuleRequired() or RuleMin(1)
+type Check struct {
+	rule string
+	// test returns a message describing the failure, or "" if the value
+	// passes. present is false when the path does not exist.
+	test func(v interface{}, present bool) string
+}
+
+// RuleAt returns a rule applying checks to the values at a dotted path such
+// as "server.port". A "*" segment matches every element of an array or
+// member of an object, so "users.*.email" checks each user's email; a
+// numeric segment indexes an array. The empty path is the value itself.
+func RuleAt(path string, checks ...Check) Rule {
+	var keys []string
+	if path != "" {
+		keys = strings.Split(path, ".")
+	}
+	return Rule{keys: keys, checks: checks}
+}
+
+// Validate applies the rules and reports every failing check in a single
+// error that matches ErrValidation and unwraps into *Violation values,
+// one per failing path and check:
+//
+//	err := cfg.Validate(
+//	    RuleAt("server.port", RuleRequired(), RuleOfType("integer"),
+//	        RuleMin(1), RuleMax(65535)),
+//	    RuleAt("users.*.email", RuleOfType("string"), RuleMatches(`^[^@]+@[^@]+$`)),
+//	)
+//
+// Checks other than RuleRequired skip paths that are absent, and checks other
+// than RuleRequired and RuleOfType skip null values. Violation paths name the
+// concrete location, e.g. users[2].email.
+func (j JSONValue) Validate(rules ...Rule) error {
+	if j.err != nil {
+		return j.err
+	}
+
+	var violations []*Violation
+	var targets []ruleTarget
+	for _, r := range rules {
+		targets = collectTargets(j.data, "", r.keys, targets[:0])
+		for _, t := range targets {
+			for _, c := range r.checks {
+				if msg := c.test(t.value, t.present); msg != "" {
+					violations = append(violations, &Violation{Path: t.path, Rule: c.rule, Message: msg})
+				}
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &JSONError{Op: "Validate", Err: &ValidationError{Violations: violations}}
+	}
+	return nil
+}
+
+// ruleTarget is one value a rule's path resolved to
+type ruleTarget struct {
+	path    string
+	value   interface{}
+	present bool
+}
+
+// collectTargets resolves keys against data, appending every match to out.
+// A path that breaks off is reported once, as absent.
+func collectTargets(data interface{}, path string, keys []string, out []ruleTarget) []ruleTarget {
+	if len(keys) == 0 {
+		return append(out, ruleTarget{path: path, value: data, present: true})
+	}
+	key, rest := keys[0], keys[1:]
+
+	switch c := data.(type) {
+	case map[string]interface{}:
+		if key == "*" {
+			names := make([]string, 0, len(c))
+			for k := range c {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+			for _, k := range names {
+				out = collectTargets(c[k], joinKey(path, k), rest, out)
+			}
+			return out
+		}
+		if v, ok := c[key]; ok {
+			return collectTargets(v, joinKey(path, key), rest, out)
+		}
+	case []interface{}:
+		if key == "*" {
+			for i, v := range c {
+				out = collectTargets(v, joinIndex(path, i), rest, out)
+			}
+			return out
+		}
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(c) {
+			return collectTargets(c[i], joinIndex(path, i), rest, out)
+		}
+	}
+
+	if key == "*" {
+		return out
+	}
+	missing := joinKey(path, key)
+	for _, k := range rest {
+		missing = joinKey(missing, k)
+	}
+	return append(out, ruleTarget{path: missing})
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func joinIndex(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// RuleRequired fails when the path is absent or null
+func RuleRequired() Check {
+	return Check{rule: "required", test: func(v interface{}, present bool) string {
+		if !present || v == nil {
+			return "is required"
+		}
+		return ""
+	}}
+}
+
+// RuleOfType fails unless the value has one of the given JSON types: "null",
+// "boolean", "number", "integer", "string", "array" or "object"
+func RuleOfType(types ...string) Check {
+	return Check{rule: "type=" + strings.Join(types, "|"), test: func(v interface{}, present bool) string {
+		if !present {
+			return ""
+		}
+		actual := Valid(v).Type()
+		for _, t := range types {
+			if t == actual || (t == "integer" && actual == "number" && isIntegral(v)) {
+				return ""
+			}
+		}
+		return "must be of type " + strings.Join(types, " or ")
+	}}
+}
+
+// RuleMin fails when a number is below n, or a string, array or object is
+// shorter than n characters or items
+func RuleMin(n float64) Check {
+	bound := strconv.FormatFloat(n, 'g', -1, 64)
+	return Check{rule: "min=" + bound, test: func(v interface{}, present bool) string {
+		size, unit, ok := measureValue(v)
+		switch {
+		case !present || v == nil:
+			return ""
+		case !ok:
+			return "must be a number, string, array or object"
+		case size < n:
+			return boundMessage("at least", bound, unit)
+		}
+		return ""
+	}}
+}
+
+// RuleMax fails when a number is above n, or a string, array or object is
+// longer than n characters or items
+func RuleMax(n float64) Check {
+	bound := strconv.FormatFloat(n, 'g', -1, 64)
+	return Check{rule: "max=" + bound, test: func(v interface{}, present bool) string {
+		size, unit, ok := measureValue(v)
+		switch {
+		case !present || v == nil:
+			return ""
+		case !ok:
+			return "must be a number, string, array or object"
+		case size > n:
+			return boundMessage("at most", bound, unit)
+		}
+		return ""
+	}}
+}
+
+// RuleMatches fails unless the value is a string matching the regular
+// expression. It panics if pattern does not compile, like
+// regexp.MustCompile.
+func RuleMatches(pattern string) Check {
+	re := regexp.MustCompile(pattern)
+	return Check{rule: "match=" + pattern, test: func(v interface{}, present bool) string {
+		if !present || v == nil {
+			return ""
+		}
+		s, ok := v.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if !re.MatchString(s) {
+			return "must match " + pattern
+		}
+		return ""
+	}}
+}
+
+// RuleEmail fails unless the value is a bare email address
+func RuleEmail() Check {
+	return Check{rule: "email", test: func(v interface{}, present bool) string {
+		if !present || v == nil {
+			return ""
+		}
+		if s, ok := v.(string); !ok || !isEmail(s) {
+			return "must be a valid email address"
+		}
+		return ""
+	}}
+}
+
+// RuleOneOf fails unless the value equals one of the given values. Numbers
+// compare by value, so RuleOneOf(1, 2) accepts the JSON number 1.
+func RuleOneOf(values ...interface{}) Check {
+	names := make([]string, len(values))
+	for i, want := range values {
+		names[i] = fmt.Sprint(want)
+	}
+	return Check{rule: "oneof", test: func(v interface{}, present bool) string {
+		if !present || v == nil {
+			return ""
+		}
+		for _, want := range values {
+			if jsonEqual(v, want) {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(names, ", ")
+	}}
+}
+
+// RuleFunc adapts a custom test into a Check reported under the given rule
+// name. fn is called for every present value, including null, and the
+// message of the error it returns becomes the violation message.
+func RuleFunc(rule string, fn func(JSONValue) error) Check {
+	return Check{rule: rule, test: func(v interface{}, present bool) string {
+		if !present {
+			return ""
+		}
+		if err := fn(Valid(v)); err != nil {
+			return err.Error()
+		}
+		return ""
+	}}
+}
+
+// measureValue returns what RuleMin and RuleMax compare for v: a number's
+// value, a string's length in characters, or the number of items
+func measureValue(v interface{}) (float64, string, bool) {
+	switch c := v.(type) {
+	case string:
+		return float64(utf8.RuneCountInString(c)), "characters", true
+	case []interface{}:
+		return float64(len(c)), "items", true
+	case map[string]interface{}:
+		return float64(len(c)), "items", true
+	}
+	if f, ok := numberValue(v); ok {
+		return f, "value", true
+	}
+	return 0, "", false
+}
+
+func isIntegral(v interface{}) bool {
+	if n, ok := v.(json.Number); ok {
+		return !strings.ContainsAny(string(n), ".eE")
+	}
+	f, ok := numberValue(v)
+	return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+}
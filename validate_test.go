@@ -0,0 +1,98 @@
+package jsjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestValidate(t *testing.T) {
+	config := JSON.Parse(`{
+		"server": {"port": 80.5, "host": ""},
+		"mode": "fast",
+		"users": [
+			{"email": "ada@example.com", "roles": ["admin"]},
+			{"email": "bob", "roles": []},
+			{"roles": null}
+		],
+		"labels": {"a": "x", "b": 7}
+	}`)
+
+	tests := []struct {
+		name string
+		rule JSON.Rule
+		want []string // violations as "path rule"
+	}{
+		{"passes", JSON.RuleAt("server.host", JSON.RuleRequired(), JSON.RuleOfType("string")), nil},
+		{"integer type", JSON.RuleAt("server.port", JSON.RuleOfType("integer")), []string{"server.port type=integer"}},
+		{"numeric bounds", JSON.RuleAt("server.port", JSON.RuleMin(1), JSON.RuleMax(65535)), nil},
+		{"missing path", JSON.RuleAt("database.url", JSON.RuleRequired()), []string{"database.url required"}},
+		{"absent skips other checks", JSON.RuleAt("database.url", JSON.RuleOfType("string"), JSON.RuleMin(1)), nil},
+		{"string length", JSON.RuleAt("server.host", JSON.RuleMin(1)), []string{"server.host min=1"}},
+		{"wildcard array", JSON.RuleAt("users.*.email", JSON.RuleRequired(), JSON.RuleEmail()),
+			[]string{"users[1].email email", "users[2].email required"}},
+		{"wildcard items", JSON.RuleAt("users.*.roles", JSON.RuleMin(1)), []string{"users[1].roles min=1"}},
+		{"wildcard object", JSON.RuleAt("labels.*", JSON.RuleOfType("string")), []string{"labels.b type=string"}},
+		{"index", JSON.RuleAt("users.0.email", JSON.RuleMatches(`@example\.com$`)), nil},
+		{"regexp", JSON.RuleAt("users.*.email", JSON.RuleMatches(`@example\.com$`)), []string{"users[1].email match=@example\\.com$"}},
+		{"one of", JSON.RuleAt("mode", JSON.RuleOneOf("safe", "slow")), []string{"mode oneof"}},
+		{"one of number", JSON.RuleAt("labels.b", JSON.RuleOneOf(1, 7)), nil},
+		{"custom", JSON.RuleAt("mode", JSON.RuleFunc("lowercase", func(v JSON.JSONValue) error {
+			if s := v.StringOr(""); s != strings.ToLower(s) {
+				return errors.New("must be lowercase")
+			}
+			return nil
+		})), nil},
+		{"null is typed", JSON.RuleAt("users.2.roles", JSON.RuleOfType("array"), JSON.RuleMax(0)), []string{"users[2].roles type=array"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config.Validate(tt.rule)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Unexpected error %v", err)
+				}
+				return
+			}
+
+			var verr *JSON.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Expected *ValidationError, got %v", err)
+			}
+			var got []string
+			for _, v := range verr.Violations {
+				got = append(got, v.Path+" "+v.Rule)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected violations %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryRule(t *testing.T) {
+	err := JSON.Parse(`{"port": 0, "name": 5}`).Validate(
+		JSON.RuleAt("port", JSON.RuleMin(1)),
+		JSON.RuleAt("name", JSON.RuleOfType("string")),
+		JSON.RuleAt("tls", JSON.RuleRequired()),
+	)
+	if !errors.Is(err, JSON.ErrValidation) {
+		t.Fatalf("Expected ErrValidation, got %v", err)
+	}
+	want := "validation failed: port: must be at least 1; name: must be of type string; tls: is required"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+	var v *JSON.Violation
+	if !errors.As(err, &v) || v.Path != "port" {
+		t.Errorf("Expected the first violation, got %+v", v)
+	}
+
+	invalid := JSON.Parse(`{bad}`)
+	if err := invalid.Validate(JSON.RuleAt("x", JSON.RuleRequired())); err != invalid.Error() {
+		t.Errorf("Expected the parse error, got %v", err)
+	}
+}
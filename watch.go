@@ -0,0 +1,104 @@
+package jsjson
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// -------------------- Watching Files --------------------
+
+// WatchOption configures Watch
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	interval  time.Duration
+	parseOpts []ParseOption
+}
+
+// WithPollInterval sets how often Watch checks the file; the default is one
+// second, which is also used for a zero or negative d
+func WithPollInterval(d time.Duration) WatchOption {
+	return func(o *watchOptions) { o.interval = d }
+}
+
+// WithWatchParseOptions sets the options each version of the file is parsed
+// with, as in ParseFile
+func WithWatchParseOptions(opts ...ParseOption) WatchOption {
+	return func(o *watchOptions) { o.parseOpts = opts }
+}
+
+// Watch parses the JSON file at path and sends it, then every changed
+// version of it, on the returned channel until ctx is done:
+//
+//	flags, err := jsjson.Watch(ctx, "flags.json")
+//	if err != nil {
+//		return err
+//	}
+//	for doc := range flags {
+//		apply(doc)
+//	}
+//
+// The file is polled: when its size, modification time or identity changes
+// (as when it is atomically replaced) it is parsed again, and the document
+// is sent only if it differs in value from the last one sent, so rewriting
+// the same content is not a change. Versions that fail to parse, such as a
+// file caught mid-write, and a missing file are skipped until a valid
+// version appears. The channel is closed when ctx is done.
+//
+// Watch fails if the file cannot be parsed when it is called.
+func Watch(ctx context.Context, path string, opts ...WatchOption) (<-chan JSONValue, error) {
+	o := watchOptions{interval: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.interval <= 0 {
+		o.interval = time.Second
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, &JSONError{Op: "Watch", Err: err}
+	}
+	current := ParseFile(path, o.parseOpts...)
+	if current.err != nil {
+		return nil, current.err
+	}
+
+	ch := make(chan JSONValue, 1)
+	ch <- current
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(o.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			next, err := os.Stat(path)
+			if err != nil || (os.SameFile(info, next) && next.ModTime().Equal(info.ModTime()) && next.Size() == info.Size()) {
+				continue
+			}
+			jv := ParseFile(path, o.parseOpts...)
+			if jv.err != nil {
+				// Try again on the next tick, whether or not the file
+				// changes in the meantime
+				continue
+			}
+			info = next
+			if jsonEqual(jv.data, current.data) {
+				continue
+			}
+			current = jv
+			select {
+			case ch <- jv:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -0,0 +1,87 @@
+package jsjson_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	JSON "github.com/ktbsomen/jsjson"
+)
+
+func TestWatch(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"flags.json": `{"beta": false}`})
+	path := filepath.Join(dir, "flags.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := JSON.Watch(ctx, path, JSON.WithPollInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	next := func() (JSON.JSONValue, bool) {
+		select {
+		case jv, ok := <-ch:
+			return jv, ok
+		case <-time.After(200 * time.Millisecond):
+			return JSON.JSONValue{}, false
+		}
+	}
+
+	if jv, ok := next(); !ok || jv.Get("beta").BoolOr(true) {
+		t.Fatalf("Expected the initial document, got %v", jv.Raw())
+	}
+
+	// Same value, different formatting: not a change
+	if err := os.WriteFile(path, []byte(`{ "beta" : false }`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if jv, ok := next(); ok {
+		t.Errorf("Expected no update for an equal document, got %v", jv.Raw())
+	}
+
+	// A partial write is skipped until the file is valid again
+	if err := os.WriteFile(path, []byte(`{"beta": tr`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if jv, ok := next(); ok {
+		t.Errorf("Expected no update for an invalid file, got %v", jv.Raw())
+	}
+	if err := JSON.Parse(`{"beta": true}`).WriteFile(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if jv, ok := next(); !ok || !jv.Get("beta").BoolOr(false) {
+		t.Errorf("Expected the updated document, got %v", jv.Raw())
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"bad.json": `{`})
+	if _, err := JSON.Watch(context.Background(), filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+	if _, err := JSON.Watch(context.Background(), filepath.Join(dir, "bad.json")); err == nil {
+		t.Error("Expected an error for an invalid file")
+	}
+
+	// A zero or negative interval means the default instead of panicking
+	dir = writeFiles(t, map[string]string{"ok.json": `{"n": 1}`})
+	for _, d := range []time.Duration{0, -time.Second} {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, err := JSON.Watch(ctx, filepath.Join(dir, "ok.json"), JSON.WithPollInterval(d))
+		if err != nil {
+			t.Fatalf("Interval %v: unexpected error: %v", d, err)
+		}
+		if jv := <-ch; jv.Get("n").IntOr(0) != 1 {
+			t.Errorf("Interval %v: expected the initial document, got %v", d, jv.Raw())
+		}
+		cancel()
+		for range ch {
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package wsjson reads and writes jsjson values as WebSocket messages, so
+// realtime services use the same JSON layer as their HTTP handlers.
+//
+// ReadJSON and WriteJSON work with message-oriented connections such as
+// *websocket.Conn from gorilla/websocket, one JSON value per message:
+//
+//	msg, err := wsjson.ReadJSON(conn, jsjson.WithMaxBytes(64<<10))
+//	...
+//	err = wsjson.WriteJSON(conn, reply)
+//
+// Codec works with any io.ReadWriter carrying a stream of values, such as
+// the net.Conn returned by nhooyr.io/websocket's NetConn, where each Write
+// is sent as one message.
+package wsjson
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+// TextMessage is the WebSocket text message type, as numbered by RFC 6455
+// and gorilla/websocket
+const TextMessage = 1
+
+// MessageConn is a connection that reads and writes whole messages. It is
+// satisfied by *websocket.Conn from gorilla/websocket.
+type MessageConn interface {
+	NextReader() (messageType int, r io.Reader, err error)
+	NextWriter(messageType int) (io.WriteCloser, error)
+}
+
+// ReadJSON reads the next message from conn and parses it as a single JSON
+// value. opts apply as in jsjson.NewDecoder, so WithMaxBytes bounds the
+// message. A message holding anything after its value is a syntax error.
+func ReadJSON(conn MessageConn, opts ...jsjson.ParseOption) (jsjson.JSONValue, error) {
+	_, r, err := conn.NextReader()
+	if err != nil {
+		return jsjson.JSONValue{}, err
+	}
+	dec := jsjson.NewDecoder(r, opts...)
+	jv, err := dec.Decode()
+	if err == io.EOF {
+		return jsjson.JSONValue{}, &jsjson.JSONError{Op: "ReadJSON", Err: fmt.Errorf("%w: empty message", jsjson.ErrSyntax)}
+	}
+	if err != nil {
+		return jv, err
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		return jsjson.JSONValue{}, &jsjson.JSONError{Op: "ReadJSON", Err: fmt.Errorf("%w: data after the JSON value", jsjson.ErrSyntax)}
+	}
+	return jv, nil
+}
+
+// WriteJSON encodes v like jsjson.Stringify and writes it to conn as one
+// text message. Nothing is sent if v cannot be encoded.
+func WriteJSON(conn MessageConn, v interface{}) error {
+	s, err := jsjson.Stringify(v)
+	if err != nil {
+		return err
+	}
+	w, err := conn.NextWriter(TextMessage)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, s); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Codec reads and writes JSON values over a stream. Values are read as they
+// arrive, however the stream splits them, and each is written with a single
+// Write followed by a newline. A Codec may be written and read concurrently,
+// but not read from, or written to, by more than one goroutine at a time.
+type Codec struct {
+	dec *jsjson.Decoder
+	w   io.Writer
+}
+
+// NewCodec returns a Codec over rw. opts apply to every value read, as in
+// jsjson.NewDecoder.
+func NewCodec(rw io.ReadWriter, opts ...jsjson.ParseOption) *Codec {
+	return &Codec{dec: jsjson.NewDecoder(rw, opts...), w: rw}
+}
+
+// ReadJSON reads the next value. It returns io.EOF when the stream ends.
+func (c *Codec) ReadJSON() (jsjson.JSONValue, error) {
+	return c.dec.Decode()
+}
+
+// WriteJSON encodes v like jsjson.Stringify and writes it
+func (c *Codec) WriteJSON(v interface{}) error {
+	s, err := jsjson.Stringify(v)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(c.w, s+"\n")
+	return err
+}
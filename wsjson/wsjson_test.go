@@ -0,0 +1,121 @@
+package wsjson_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/ktbsomen/jsjson"
+	"github.com/ktbsomen/jsjson/wsjson"
+)
+
+// fakeConn is an in-memory MessageConn
+type fakeConn struct {
+	in   [][]byte
+	out  [][]byte
+	kind []int
+}
+
+func (c *fakeConn) NextReader() (int, io.Reader, error) {
+	if len(c.in) == 0 {
+		return 0, nil, io.EOF
+	}
+	msg := c.in[0]
+	c.in = c.in[1:]
+	return wsjson.TextMessage, bytes.NewReader(msg), nil
+}
+
+func (c *fakeConn) NextWriter(messageType int) (io.WriteCloser, error) {
+	c.kind = append(c.kind, messageType)
+	return &messageWriter{conn: c}, nil
+}
+
+type messageWriter struct {
+	conn *fakeConn
+	buf  bytes.Buffer
+}
+
+func (w *messageWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *messageWriter) Close() error {
+	w.conn.out = append(w.conn.out, w.buf.Bytes())
+	return nil
+}
+
+func TestReadJSON(t *testing.T) {
+	conn := &fakeConn{in: [][]byte{
+		[]byte(`{"type":"join","room":"go"}`),
+		[]byte(`{"a":1} {"b":2}`),
+		[]byte(``),
+		[]byte(`[1,2,3,4]`),
+	}}
+
+	msg, err := wsjson.ReadJSON(conn)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if room, _ := msg.Get("room").String(); room != "go" {
+		t.Errorf("Expected room go, got %v", msg)
+	}
+	if _, err := wsjson.ReadJSON(conn); !errors.Is(err, jsjson.ErrSyntax) {
+		t.Errorf("Expected ErrSyntax for two values, got %v", err)
+	}
+	if _, err := wsjson.ReadJSON(conn); !errors.Is(err, jsjson.ErrSyntax) {
+		t.Errorf("Expected ErrSyntax for an empty message, got %v", err)
+	}
+	if _, err := wsjson.ReadJSON(conn, jsjson.WithMaxArrayElements(3)); !errors.Is(err, jsjson.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got %v", err)
+	}
+	if _, err := wsjson.ReadJSON(conn); err != io.EOF {
+		t.Errorf("Expected the connection error, got %v", err)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	conn := &fakeConn{}
+	if err := wsjson.WriteJSON(conn, jsjson.Parse(`{"type": "ack"}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := wsjson.WriteJSON(conn, make(chan int)); err == nil {
+		t.Error("Expected an encoding error")
+	}
+	if len(conn.out) != 1 || string(conn.out[0]) != `{"type":"ack"}` || conn.kind[0] != wsjson.TextMessage {
+		t.Errorf("Expected one text message, got %q %v", conn.out, conn.kind)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go func() {
+		defer server.Close()
+		c := wsjson.NewCodec(server)
+		for {
+			msg, err := c.ReadJSON()
+			if err != nil {
+				return
+			}
+			n, _ := msg.Get("n").Int()
+			if err := c.WriteJSON(map[string]int{"n": n * 2}); err != nil {
+				return
+			}
+		}
+	}()
+
+	c := wsjson.NewCodec(client)
+	for i := 1; i <= 3; i++ {
+		if err := c.WriteJSON(map[string]int{"n": i}); err != nil {
+			t.Fatalf("Unexpected write error: %v", err)
+		}
+		reply, err := c.ReadJSON()
+		if err != nil {
+			t.Fatalf("Unexpected read error: %v", err)
+		}
+		if n, _ := reply.Get("n").Int(); n != i*2 {
+			t.Errorf("Expected %d, got %v", i*2, reply)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package zapjson logs jsjson values with go.uber.org/zap. Values are
+// written straight to zap's encoder, so no JSON text is built for each log
+// line:
+//
+//	logger.Info("webhook received", zapjson.Field("body", body))
+//
+// With zap's JSON encoder the field is nested JSON, identical to
+// jsjson.Stringify output; with the console encoder it is printed the same
+// way. It lives in its own package so the core package needs nothing beyond
+// the standard library.
+package zapjson
+
+import (
+	"encoding/json"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+// Field returns a zap field holding jv under key. Objects keep their keys
+// in sorted order, as jsjson.Stringify writes them. A JSONValue holding an
+// error is logged as that error.
+func Field(key string, jv jsjson.JSONValue) zap.Field {
+	if err := jv.Error(); err != nil {
+		return zap.NamedError(key, err)
+	}
+	switch v := jv.Raw().(type) {
+	case map[string]interface{}:
+		return zap.Object(key, object(v))
+	case []interface{}:
+		return zap.Array(key, array(v))
+	case string:
+		return zap.String(key, v)
+	case bool:
+		return zap.Bool(key, v)
+	case float64:
+		return zap.Float64(key, v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return zap.Int64(key, n)
+		}
+		f, _ := v.Float64()
+		return zap.Float64(key, f)
+	default:
+		return zap.Reflect(key, v)
+	}
+}
+
+// object writes a decoded JSON object to a zap encoder
+type object map[string]interface{}
+
+func (o object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var err error
+		switch v := o[k].(type) {
+		case map[string]interface{}:
+			err = enc.AddObject(k, object(v))
+		case []interface{}:
+			err = enc.AddArray(k, array(v))
+		case string:
+			enc.AddString(k, v)
+		case bool:
+			enc.AddBool(k, v)
+		case float64:
+			enc.AddFloat64(k, v)
+		case json.Number:
+			if n, nerr := v.Int64(); nerr == nil {
+				enc.AddInt64(k, n)
+			} else {
+				f, _ := v.Float64()
+				enc.AddFloat64(k, f)
+			}
+		default:
+			err = enc.AddReflected(k, v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// array writes a decoded JSON array to a zap encoder
+type array []interface{}
+
+func (a array) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, elem := range a {
+		var err error
+		switch v := elem.(type) {
+		case map[string]interface{}:
+			err = enc.AppendObject(object(v))
+		case []interface{}:
+			err = enc.AppendArray(array(v))
+		case string:
+			enc.AppendString(v)
+		case bool:
+			enc.AppendBool(v)
+		case float64:
+			enc.AppendFloat64(v)
+		case json.Number:
+			if n, nerr := v.Int64(); nerr == nil {
+				enc.AppendInt64(n)
+			} else {
+				f, _ := v.Float64()
+				enc.AppendFloat64(f)
+			}
+		default:
+			err = enc.AppendReflected(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package zapjson_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ktbsomen/jsjson"
+	"github.com/ktbsomen/jsjson/zapjson"
+)
+
+func newLogger(buf *bytes.Buffer) *zap.Logger {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+	return zap.New(zapcore.NewCore(enc, zapcore.AddSync(buf), zapcore.DebugLevel))
+}
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		name  string
+		value jsjson.JSONValue
+		want  string
+	}{
+		{
+			name:  "object",
+			value: jsjson.Parse(`{"z": [1, "two", null, {"b": true}], "a": {"x": 1.5}, "n": null}`),
+			want:  `{"msg":"m","body":{"a":{"x":1.5},"n":null,"z":[1,"two",null,{"b":true}]}}`,
+		},
+		{
+			name:  "array",
+			value: jsjson.Parse(`[[1], {"k": "v"}]`),
+			want:  `{"msg":"m","body":[[1],{"k":"v"}]}`,
+		},
+		{
+			name:  "scalar",
+			value: jsjson.Parse(`"text"`),
+			want:  `{"msg":"m","body":"text"}`,
+		},
+		{
+			name:  "numbers",
+			value: jsjson.Parse(`{"big": 12345678901234567, "f": 0.25}`, jsjson.ParseWithNumbers()),
+			want:  `{"msg":"m","body":{"big":12345678901234567,"f":0.25}}`,
+		},
+		{
+			name:  "error",
+			value: jsjson.Invalid(errors.New("boom")),
+			want:  `{"msg":"m","body":"jsonjs.Invalid: boom"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			newLogger(&buf).Info("m", zapjson.Field("body", tt.value))
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
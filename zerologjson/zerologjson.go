@@ -0,0 +1,113 @@
+// Package zerologjson logs jsjson values with github.com/rs/zerolog. Values
+// are written straight to the event, so no JSON text is built for each log
+// line:
+//
+//	log.Info().Func(zerologjson.Field("body", body)).Msg("webhook received")
+//	log.Info().EmbedObject(zerologjson.Object(body)).Msg("webhook received")
+//
+// The logged JSON is identical to jsjson.Stringify output. It lives in its
+// own package so the core package needs nothing beyond the standard library.
+package zerologjson
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+var null = []byte("null")
+
+// Field returns a function for zerolog's Event.Func that adds jv under key.
+// Objects keep their keys in sorted order, as jsjson.Stringify writes them.
+// A JSONValue holding an error is logged as that error.
+func Field(key string, jv jsjson.JSONValue) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		if err := jv.Error(); err != nil {
+			e.AnErr(key, err)
+			return
+		}
+		addField(e, key, jv.Raw())
+	}
+}
+
+// Object returns a zerolog.LogObjectMarshaler for an object value, for use
+// with Event.Object, Event.EmbedObject and Context.Object. Any other value,
+// or an error, adds no fields.
+func Object(jv jsjson.JSONValue) zerolog.LogObjectMarshaler {
+	m, _ := jv.Raw().(map[string]interface{})
+	return object(m)
+}
+
+func addField(e *zerolog.Event, key string, v interface{}) {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		e.Object(key, object(c))
+	case []interface{}:
+		e.Array(key, array(c))
+	case string:
+		e.Str(key, c)
+	case bool:
+		e.Bool(key, c)
+	case float64:
+		e.Float64(key, c)
+	case json.Number:
+		if n, err := c.Int64(); err == nil {
+			e.Int64(key, n)
+		} else {
+			f, _ := c.Float64()
+			e.Float64(key, f)
+		}
+	case nil:
+		e.RawJSON(key, null)
+	default:
+		e.Interface(key, c)
+	}
+}
+
+// object writes a decoded JSON object to an event
+type object map[string]interface{}
+
+func (o object) MarshalZerologObject(e *zerolog.Event) {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		addField(e, k, o[k])
+	}
+}
+
+// array writes a decoded JSON array to an event
+type array []interface{}
+
+func (a array) MarshalZerologArray(arr *zerolog.Array) {
+	for _, elem := range a {
+		switch v := elem.(type) {
+		case map[string]interface{}:
+			arr.Object(object(v))
+		case string:
+			arr.Str(v)
+		case bool:
+			arr.Bool(v)
+		case float64:
+			arr.Float64(v)
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				arr.Int64(n)
+			} else {
+				f, _ := v.Float64()
+				arr.Float64(f)
+			}
+		case nil:
+			arr.RawJSON(null)
+		default:
+			// zerolog arrays cannot nest arrays directly, so nested arrays
+			// are encoded by zerolog's InterfaceMarshalFunc
+			arr.Interface(v)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package zerologjson_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ktbsomen/jsjson"
+	"github.com/ktbsomen/jsjson/zerologjson"
+)
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		name  string
+		value jsjson.JSONValue
+		want  string
+	}{
+		{
+			name:  "object",
+			value: jsjson.Parse(`{"z": [1, "two", null, {"b": true}, [3]], "a": {"x": 1.5}, "n": null}`),
+			want:  `{"body":{"a":{"x":1.5},"n":null,"z":[1,"two",null,{"b":true},[3]]},"message":"m"}`,
+		},
+		{
+			name:  "scalar",
+			value: jsjson.Parse(`"text"`),
+			want:  `{"body":"text","message":"m"}`,
+		},
+		{
+			name:  "numbers",
+			value: jsjson.Parse(`{"big": 12345678901234567, "f": 0.25}`, jsjson.ParseWithNumbers()),
+			want:  `{"body":{"big":12345678901234567,"f":0.25},"message":"m"}`,
+		},
+		{
+			name:  "error",
+			value: jsjson.Invalid(errors.New("boom")),
+			want:  `{"body":"jsonjs.Invalid: boom","message":"m"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+			logger.Log().Func(zerologjson.Field("body", tt.value)).Msg("m")
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestObject(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	body := jsjson.Parse(`{"id": 7, "tags": ["a"]}`)
+	logger.Log().EmbedObject(zerologjson.Object(body)).Msg("m")
+	if got, want := strings.TrimSpace(buf.String()), `{"id":7,"tags":["a"],"message":"m"}`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	buf.Reset()
+	logger.Log().EmbedObject(zerologjson.Object(jsjson.Parse(`[1]`))).Msg("m")
+	if got, want := strings.TrimSpace(buf.String()), `{"message":"m"}`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
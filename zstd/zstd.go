@@ -0,0 +1,37 @@
+// Package zstd adds Zstandard support to jsjson. Importing it registers the
+// format, after which ParseReader and ParseFile decompress zstd input and
+// WriteFile compresses files ending in .zst:
+//
+//	import _ "github.com/ktbsomen/jsjson/zstd"
+//
+//	logs := jsjson.ParseFile("events-2024-05.json.zst")
+//
+// It lives in its own package so the core package needs nothing beyond the
+// standard library.
+package zstd
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/ktbsomen/jsjson"
+)
+
+func init() {
+	jsjson.RegisterCompression(jsjson.Compression{
+		Name:  "zstd",
+		Ext:   ".zst",
+		Magic: []byte{0x28, 0xb5, 0x2f, 0xfd},
+		NewReader: func(r io.Reader) (io.ReadCloser, error) {
+			d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return nil, err
+			}
+			return d.IOReadCloser(), nil
+		},
+		NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		},
+	})
+}
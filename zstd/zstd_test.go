@@ -0,0 +1,42 @@
+package zstd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/ktbsomen/jsjson"
+	_ "github.com/ktbsomen/jsjson/zstd"
+)
+
+func TestParseReader(t *testing.T) {
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := enc.EncodeAll([]byte(`{"level": "info", "msg": "started"}`), nil)
+
+	jv := jsjson.ParseReader(bytes.NewReader(input))
+	if msg, _ := jv.Get("msg").String(); msg != "started" {
+		t.Errorf("Expected started, got %v (%v)", jv.Raw(), jv.Error())
+	}
+}
+
+func TestFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json.zst")
+	if err := jsjson.Parse(`{"events": [{"id": 1}, {"id": 2}]}`).WriteFile(path, 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil || !bytes.HasPrefix(raw, []byte{0x28, 0xb5, 0x2f, 0xfd}) {
+		t.Fatalf("Expected a zstd frame, got %q (%v)", raw, err)
+	}
+
+	jv := jsjson.ParseFile(path)
+	if id, _ := jv.Get("events", 1, "id").Int(); id != 2 {
+		t.Errorf("Expected id 2, got %v (%v)", jv.Raw(), jv.Error())
+	}
+}